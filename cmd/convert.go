@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"labours-go/internal/progress"
+	"labours-go/internal/readers"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert a hercules file between the YAML and Protobuf formats",
+	Long: "Reads the file given with --input and writes its analysis results to --output " +
+		"(standard output if omitted or \"-\") as hercules Protobuf or YAML. " +
+		"The target format is given with --to or inferred from the output extension.",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runConvert,
+}
+
+func init() {
+	convertCmd.Flags().String("to", "", "Output format: pb or yaml (default: from the output extension)")
+	rootCmd.AddCommand(convertCmd)
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	input, output := viper.GetString("input"), viper.GetString("output")
+	to, _ := cmd.Flags().GetString("to")
+	format, err := convertFormat(to, output)
+	if err != nil {
+		return err
+	}
+
+	// Progress bars would corrupt a conversion written to stdout
+	defer progress.SuppressBars()()
+	reader, err := readers.DetectAndReadInput(input, viper.GetString("input-format"))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", input, err)
+	}
+
+	if err := writeResults(reader, output, format); err != nil {
+		return fmt.Errorf("failed to convert %s: %w", input, err)
+	}
+	return nil
+}
+
+// writeResults writes the analysis results of the reader as hercules Protobuf or YAML to
+// output, or to stdout if output is empty or "-"
+func writeResults(reader readers.Reader, output, format string) error {
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if output != "" && output != "-" {
+		var err error
+		if file, err = os.Create(output); err != nil {
+			return fmt.Errorf("failed to create %s: %v", output, err)
+		}
+		defer file.Close()
+		w = file
+	}
+	write := readers.WriteYAML
+	if format == "pb" {
+		write = readers.WriteProtobuf
+	}
+	if err := write(w, reader); err != nil {
+		return err
+	}
+	if file != nil {
+		return file.Close()
+	}
+	return nil
+}
+
+// convertFormat resolves the target format of the convert command
+func convertFormat(to, output string) (string, error) {
+	if to == "" {
+		switch strings.ToLower(filepath.Ext(output)) {
+		case ".pb":
+			to = "pb"
+		case ".yaml", ".yml":
+			to = "yaml"
+		default:
+			return "", fmt.Errorf("cannot infer the output format of %q, use --to pb or --to yaml", output)
+		}
+	}
+	switch strings.ToLower(to) {
+	case "pb", "protobuf":
+		return "pb", nil
+	case "yaml", "yml":
+		return "yaml", nil
+	}
+	return "", fmt.Errorf("unknown output format %q, use pb or yaml", to)
+}
@@ -1,15 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/araddon/dateparse"
 	"github.com/spf13/viper"
+	"labours-go/internal/burndown"
+	"labours-go/internal/graphics"
+	"labours-go/internal/logging"
+	"labours-go/internal/progress"
 	"labours-go/internal/readers"
 )
 
@@ -36,7 +43,7 @@ func parseDates() (startTime *time.Time, endTime *time.Time) {
 	if startTimeStr := viper.GetString("start-date"); startTimeStr != "" {
 		parsedStartTime, err := parseFlexibleDate(startTimeStr)
 		if err != nil {
-			fmt.Printf("Error parsing start date: %v\n", err)
+			slog.Error("failed to parse start date", "error", err)
 			os.Exit(1)
 		}
 		startTime = &parsedStartTime
@@ -45,7 +52,7 @@ func parseDates() (startTime *time.Time, endTime *time.Time) {
 	if endTimeStr := viper.GetString("end-date"); endTimeStr != "" {
 		parsedEndTime, err := parseFlexibleDate(endTimeStr)
 		if err != nil {
-			fmt.Printf("Error parsing end date: %v\n", err)
+			slog.Error("failed to parse end date", "error", err)
 			os.Exit(1)
 		}
 		endTime = &parsedEndTime
@@ -61,15 +68,166 @@ func validateDateRange(startTime, endTime *time.Time) {
 	}
 }
 
-func detectAndReadInput(input, inputFormat string) readers.Reader {
+// detectAndReadInput reads the input, crops it to the --start-date/--end-date window if
+// given and applies the identity options. It returns the window the modes plot, which
+// starts at a burndown band boundary.
+func detectAndReadInput(input, inputFormat string, startTime, endTime *time.Time) (readers.Reader, *time.Time, *time.Time) {
 	reader, err := readers.DetectAndReadInput(input, inputFormat)
 	if err != nil {
-		fmt.Printf("Error detecting or reading input: %v\n", err)
+		slog.Error("failed to read input", "input", input, "error", err)
 		os.Exit(1)
 	}
+	if startTime != nil || endTime != nil {
+		window, err := readers.NewTimeWindowReader(reader, startTime, endTime)
+		if err != nil {
+			slog.Error("failed to apply the time window", "input", input, "error", err)
+			os.Exit(1)
+		}
+		begin, end := window.GetHeader()
+		windowStart, windowEnd := time.Unix(begin, 0), time.Unix(end, 0)
+		reader, startTime, endTime = window, &windowStart, &windowEnd
+	}
+	return applyIdentityOptions(reader), startTime, endTime
+}
+
+// applyIdentityOptions wraps the reader with identity merging, anonymization and team
+// aggregation if --people-map, --anonymize or --teams were given.
+func applyIdentityOptions(reader readers.Reader) readers.Reader {
+	peopleMapPath := viper.GetString("people-map")
+	teamsPath := viper.GetString("teams")
+	anonymize := viper.GetBool("anonymize")
+	// Team aggregation replaces people with team names, and pseudonyms would prevent
+	// matching members against the team file.
+	if anonymize && teamsPath != "" {
+		slog.Error("--anonymize cannot be combined with --teams, team aggregation already hides developer names")
+		os.Exit(1)
+	}
+
+	if peopleMapPath != "" || anonymize {
+		var peopleMap *readers.PeopleMap
+		if peopleMapPath != "" {
+			var err error
+			peopleMap, err = readers.LoadPeopleMap(peopleMapPath)
+			if err != nil {
+				slog.Error("failed to load people map", "error", err)
+				os.Exit(1)
+			}
+			if !viper.GetBool("quiet") {
+				fmt.Printf("Loaded %d developer aliases from %s\n", peopleMap.Len(), peopleMapPath)
+			}
+		}
+		reader = readers.NewIdentityReader(reader, peopleMap, anonymize)
+	}
+
+	if teamsPath != "" {
+		teamMap, err := readers.LoadTeamMap(teamsPath)
+		if err != nil {
+			slog.Error("failed to load team map", "error", err)
+			os.Exit(1)
+		}
+		if !viper.GetBool("quiet") {
+			fmt.Printf("Aggregating people into %d teams from %s\n", len(teamMap.Teams()), teamsPath)
+		}
+		reader = readers.NewTeamReader(reader, teamMap)
+	}
 	return reader
 }
 
+// configureLogging sets up the slog logger from --log-format and --log-level and the
+// progress reporting from --progress.
+func configureLogging() {
+	level := viper.GetString("log-level")
+	if level == "" && viper.GetBool("verbose") {
+		level = "debug"
+	}
+	if err := logging.Setup(os.Stderr, viper.GetString("log-format"), level); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := progress.SetFormat(viper.GetString("progress")); err != nil {
+		slog.Error("invalid progress format", "error", err)
+		os.Exit(1)
+	}
+}
+
+// configureAnnotations loads the --annotations file so that its events are marked on
+// every time-based chart.
+func configureAnnotations() {
+	path := viper.GetString("annotations")
+	if path == "" {
+		return
+	}
+	annotations, err := graphics.LoadAnnotations(path)
+	if err != nil {
+		slog.Error("failed to load annotations", "error", err)
+		os.Exit(1)
+	}
+	graphics.SetAnnotations(annotations)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Loaded %d annotations from %s\n", len(annotations), path)
+	}
+}
+
+// configureAxes applies the --log-y, --y-min, --y-max, --x-range and --smooth options
+// to every time series chart.
+func configureAxes() {
+	options, err := graphics.ParseAxisOptions(viper.GetBool("log-y"), viper.GetString("y-min"),
+		viper.GetString("y-max"), viper.GetString("x-range"), viper.GetString("smooth"))
+	if err != nil {
+		slog.Error("invalid axis options", "error", err)
+		os.Exit(1)
+	}
+	graphics.SetAxisOptions(options)
+}
+
+// configureInterpolation applies the --interpolation, --float32 and --jobs options to the
+// burndown interpolation.
+func configureInterpolation() {
+	options, err := burndown.ParseInterpolationOptions(viper.GetString("interpolation"),
+		viper.GetBool("float32"), viper.GetInt("jobs"))
+	if err != nil {
+		slog.Error("invalid interpolation options", "error", err)
+		os.Exit(1)
+	}
+	burndown.SetInterpolationOptions(options)
+}
+
+// gitTagAnnotations returns an annotation for every tag of the repository, dated by the
+// tagger date for annotated tags and by the commit date for lightweight ones.
+func gitTagAnnotations(repoPath string) ([]graphics.Annotation, error) {
+	cmd := exec.Command("git", "-C", repoPath, "for-each-ref", "--sort=creatordate",
+		"--format=%(creatordate:unix) %(refname:short)", "refs/tags")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %v", err)
+	}
+	return parseGitTags(string(output)), nil
+}
+
+// parseGitTags parses "<unix time> <tag>" lines, skipping malformed ones.
+func parseGitTags(output string) []graphics.Annotation {
+	var annotations []graphics.Annotation
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		annotations = append(annotations, graphics.Annotation{Time: time.Unix(timestamp, 0), Label: fields[1]})
+	}
+	return annotations
+}
+
+// allModeNames are the modes run by the 'all' meta-mode, matching Python's composition
+var allModeNames = []string{
+	"burndown-project", "overwrites-matrix", "ownership",
+	"couples-files", "couples-people", "couples-shotness",
+	"shotness", "devs", "devs-efforts",
+}
+
 func resolveModes() []string {
 	modes := viper.GetStringSlice("modes")
 	if len(modes) == 0 {
@@ -81,7 +239,7 @@ func resolveModes() []string {
 		fmt.Println("  couples-files, couples-people, couples-shotness")
 		fmt.Println("  devs, devs-efforts, shotness")
 		fmt.Println("  old-vs-new, languages, devs-parallel")
-		fmt.Println("  run-times, sentiment")
+		fmt.Println("  run-times, run-times-trend (with --runs), sentiment, anomalies, team-dynamics")
 		fmt.Println("  all (runs default set of analyses)")
 		fmt.Println("Use --modes to specify what to run.")
 		os.Exit(1)
@@ -104,12 +262,7 @@ func resolveModes() []string {
 	modes = resolvedModes
 
 	if contains(modes, "all") {
-		// Match Python's "all" mode composition exactly
-		modes = []string{
-			"burndown-project", "overwrites-matrix", "ownership",
-			"couples-files", "couples-people", "couples-shotness", 
-			"shotness", "devs", "devs-efforts",
-		}
+		modes = append([]string(nil), allModeNames...)
 	}
 	return modes
 }
@@ -167,6 +320,11 @@ func detectOutputFormat(outputPath string) string {
 
 // generateOutputPath generates the output path with the appropriate file extension
 func generateOutputPath(basePath string, format string) string {
+	// Terminal output is not a file
+	if graphics.IsTerminalOutput(basePath) {
+		return basePath
+	}
+	
 	ext := "." + format
 	
 	// If basePath already has the correct extension, use it as-is
@@ -179,16 +337,52 @@ func generateOutputPath(basePath string, format string) string {
 	return nameWithoutExt + ext
 }
 
+// directoryModes write several charts into their output directory
+var directoryModes = map[string]bool{
+	"couples-files":    true,
+	"couples-shotness": true,
+	"devs-efforts":     true,
+	"devs-parallel":    true,
+	"old-vs-new":       true,
+	"run-times":        true,
+	"shotness":         true,
+}
+
+// modeOutputPath is the output of one of several modes run together. Like Python labours'
+// get_plot_path, "out.png" becomes "out/<mode>.png", or the directory "out/<mode>" for
+// directoryModes, so that the modes, which may run concurrently, do not write the same
+// file.
+func modeOutputPath(output, mode, format string) string {
+	root := strings.TrimSuffix(output, filepath.Ext(output))
+	if directoryModes[mode] {
+		return filepath.Join(root, mode)
+	}
+	return filepath.Join(root, mode+"."+format)
+}
+
 // mapModesToHerculesAnalyses maps labours-go modes to hercules analysis types
 func mapModesToHerculesAnalyses(modes []string) []string {
 	analysisMap := make(map[string]bool)
 	
+	// A dashboard needs the analyses of the modes in its cells
+	if contains(modes, "dashboard") {
+		if layout, err := graphics.LoadDashboardLayout(viper.GetString("dashboard")); err == nil {
+			modes = append([]string(nil), modes...)
+			for _, cell := range layout.Cells {
+				modes = append(modes, cell.Mode)
+			}
+		}
+	}
+
 	for _, mode := range modes {
 		switch {
 		case strings.HasPrefix(mode, "burndown"):
 			analysisMap["burndown"] = true
 		case mode == "devs" || mode == "devs-efforts":
 			analysisMap["devs"] = true
+		case mode == "team-dynamics":
+			analysisMap["devs"] = true
+			analysisMap["burndown"] = true
 		case strings.HasPrefix(mode, "couples"):
 			analysisMap["couples"] = true
 		case mode == "ownership":
@@ -212,7 +406,7 @@ func mapModesToHerculesAnalyses(modes []string) []string {
 }
 
 // runHerculesAndVisualize runs hercules analysis and then visualizes with labours-go
-func runHerculesAndVisualize(herculesPath, repoPath, analysis string) error {
+func runHerculesAndVisualize(ctx context.Context, herculesPath, repoPath, analysis string) error {
 	// Generate temporary file for hercules output
 	outputFile := fmt.Sprintf("/tmp/hercules_%s.yaml", analysis)
 	
@@ -242,7 +436,7 @@ func runHerculesAndVisualize(herculesPath, repoPath, analysis string) error {
 	fmt.Printf("Running hercules %s analysis...\n", analysis)
 	
 	// Execute hercules
-	cmd := exec.Command(herculesPath, herculesFlags...)
+	cmd := exec.CommandContext(ctx, herculesPath, herculesFlags...)
 	output, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("hercules command failed: %v", err)
@@ -295,10 +489,10 @@ func runHerculesAndVisualize(herculesPath, repoPath, analysis string) error {
 		fmt.Printf("Creating %s visualization...\n", mode)
 		
 		// Read the hercules output and create visualization
-		reader := detectAndReadInput(outputFile, "yaml")
 		startDate, endDate := parseDates()
+		reader, startDate, endDate := detectAndReadInput(outputFile, "yaml", startDate, endDate)
 		
-		executeModes([]string{mode}, reader, outputPath, startDate, endDate)
+		executeModes(ctx, []string{mode}, reader, outputPath, startDate, endDate, nil)
 		
 		fmt.Printf("Saved: %s\n", outputPath)
 	}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"labours-go/internal/progress"
+	"labours-go/internal/readers"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Summarize a hercules file and list the modes it can run",
+	Long: "Reads the file given with --input and prints the repository, the analysed time span, " +
+		"the hercules build, the burndown parameters, which data the file provides and " +
+		"which modes can therefore run.",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runInfo,
+}
+
+func init() {
+	infoCmd.Flags().Bool("json", false, "Print the summary as JSON")
+	rootCmd.AddCommand(infoCmd)
+}
+
+// modeRequirements lists the Reader getters each mode plots. A mode can run if every
+// getter of one of its alternatives has data; without it some modes fall back to
+// synthetic data, which info does not count as runnable.
+var modeRequirements = map[string][][]string{
+	"burndown-project":  {{"GetProjectBurndown"}},
+	"burndown-file":     {{"GetFilesBurndown"}},
+	"burndown-person":   {{"GetPeopleBurndown"}},
+	"overwrites-matrix": {{"GetPeopleInteraction"}},
+	"ownership":         {{"GetOwnershipBurndown"}},
+	"couples-files":     {{"GetFileCooccurrence"}},
+	"couples-people":    {{"GetPeopleCooccurrence"}},
+	"couples-shotness":  {{"GetShotnessCooccurrence"}},
+	"shotness":          {{"GetShotnessRecords"}},
+	"devs":              {{"GetDeveloperStats"}},
+	"devs-efforts":      {{"GetDeveloperStats"}},
+	"devs-parallel":     {{"GetDeveloperTimeSeriesData"}},
+	"old-vs-new":        {{"GetDeveloperStats"}, {"GetProjectBurndown"}},
+	"languages":         {{"GetLanguageStats"}},
+	"run-times":         {{"GetRuntimeStats"}},
+	"sentiment":         {{"GetCommentSentiment"}},
+	"anomalies":         {{"GetDeveloperTimeSeriesData"}},
+	"team-dynamics":     {{"GetDeveloperTimeSeriesData"}},
+	"dashboard": {{"GetProjectBurndown"}, {"GetOwnershipBurndown"}, {"GetPeopleInteraction"},
+		{"GetLanguageStats"}, {"GetRuntimeStats"}, {"GetDeveloperStats"}},
+}
+
+// modeAvailability tells whether a mode has the data it plots
+type modeAvailability struct {
+	Mode     string `json:"mode"`
+	Runnable bool   `json:"runnable"`
+	Missing  string `json:"missing,omitempty"` // data the mode needs, if not runnable
+}
+
+// fileInfo is the JSON output of the info command
+type fileInfo struct {
+	Input string `json:"input"`
+	*readers.Summary
+	Modes []modeAvailability `json:"modes"`
+}
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	input, inputFormat := viper.GetString("input"), viper.GetString("input-format")
+	// Progress bars share stdout with the summary
+	defer progress.SuppressBars()()
+	reader, err := readers.DetectAndReadInput(input, inputFormat)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", input, err)
+	}
+	summary := readers.Summarize(applyIdentityOptions(reader))
+	info := fileInfo{Input: input, Summary: summary, Modes: availableModes(summary)}
+
+	if asJSON {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding the summary: %v", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printFileInfo(info)
+	return nil
+}
+
+// availableModes checks the requirements of every mode against the data of the summary
+func availableModes(summary *readers.Summary) []modeAvailability {
+	names := make([]string, 0, len(modeHandlers))
+	for name := range modeHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]modeAvailability, 0, len(names))
+	for _, name := range names {
+		availability := modeAvailability{Mode: name, Runnable: true}
+		if alternatives, ok := modeRequirements[name]; ok {
+			availability.Runnable = false
+			var missing []string
+			for _, getters := range alternatives {
+				var absent []string
+				for _, getter := range getters {
+					if !summary.HasData(getter) {
+						absent = append(absent, getter)
+					}
+				}
+				if len(absent) == 0 {
+					availability.Runnable = true
+					break
+				}
+				missing = append(missing, strings.Join(absent, " and "))
+			}
+			if !availability.Runnable {
+				availability.Missing = strings.Join(missing, " or ")
+			}
+		}
+		result = append(result, availability)
+	}
+	return result
+}
+
+func printFileInfo(info fileInfo) {
+	summary := info.Summary
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Input:\t%s\n", info.Input)
+	fmt.Fprintf(w, "Repository:\t%s\n", summary.Repository)
+	if summary.BeginUnixTime > 0 && summary.EndUnixTime > 0 {
+		fmt.Fprintf(w, "Commits:\t%d, %s to %s\n", summary.Commits,
+			time.Unix(summary.BeginUnixTime, 0).UTC().Format(time.DateTime),
+			time.Unix(summary.EndUnixTime, 0).UTC().Format(time.DateTime))
+	} else {
+		fmt.Fprintf(w, "Commits:\t%d\n", summary.Commits)
+	}
+	hercules := fmt.Sprintf("version %d", summary.Version)
+	if summary.HerculesHash != "" {
+		hercules += ", build " + summary.HerculesHash
+	}
+	if summary.RunTime > 0 {
+		hercules += fmt.Sprintf(", ran %s", summary.RunTime)
+	}
+	fmt.Fprintf(w, "Hercules:\t%s\n", hercules)
+	if summary.Granularity > 0 {
+		fmt.Fprintf(w, "Burndown:\tgranularity %d, sampling %d\n", summary.Granularity, summary.Sampling)
+	}
+	if summary.TickSize > 0 {
+		fmt.Fprintf(w, "Tick size:\t%s\n", time.Duration(summary.TickSize*float64(time.Second)))
+	}
+	fmt.Fprintf(w, "Contents:\t%d files, %d people, %d shotness records\n",
+		summary.Files, summary.People, summary.ShotnessRecords)
+
+	fmt.Fprintln(w, "\nDATA\tAVAILABLE\tDETAIL")
+	for _, source := range summary.Data {
+		available := "no"
+		if source.Available {
+			available = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", source.Getter, available, firstErrorLine(source.Detail))
+	}
+
+	fmt.Fprintln(w, "\nMODE\tRUNNABLE\tMISSING")
+	for _, mode := range info.Modes {
+		runnable := "no"
+		if mode.Runnable {
+			runnable = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", mode.Mode, runnable, mode.Missing)
+	}
+	w.Flush()
+}
+
+// firstErrorLine keeps table rows on a single line
+func firstErrorLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -1,21 +1,28 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	"labours-go/internal/burndown"
+	"labours-go/internal/graphics"
+	"labours-go/internal/logging"
 	"labours-go/internal/modes"
 	"labours-go/internal/progress"
 	"labours-go/internal/readers"
+	"labours-go/internal/workers"
 )
 
 // Map of mode names to their handlers
-var modeHandlers = map[string]func(reader readers.Reader, output string, startTime, endTime *time.Time) error{
+var modeHandlers = map[string]modeFunc{
 	"burndown-project":  burndownProject,
 	"burndown-file":     burndownFile,
 	"burndown-person":   burndownPerson,
@@ -31,237 +38,371 @@ var modeHandlers = map[string]func(reader readers.Reader, output string, startTi
 	"languages":         languages,
 	"devs-parallel":     devsParallel,
 	"run-times":         runTimes,
+	"run-times-trend":   runTimesTrend,
 	"sentiment":         sentiment,
-	"all":               runAllModes,
+	"anomalies":         anomalies,
+	"team-dynamics":     teamDynamics,
+	"dashboard":         dashboard,
 }
 
-func executeModes(modes []string, reader readers.Reader, output string, startTime, endTime *time.Time) {
+// modeSummary lists the modes that did not complete
+type modeSummary struct {
+	Failed    []string
+	Cancelled []string
+}
+
+// record files a mode's error as a failure or, for an expired or cancelled context, as
+// a cancellation
+func (s *modeSummary) record(mode string, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		s.Cancelled = append(s.Cancelled, mode)
+	default:
+		s.Failed = append(s.Failed, mode)
+	}
+}
+
+// ok reports whether every mode completed
+func (s modeSummary) ok() bool {
+	return len(s.Failed) == 0 && len(s.Cancelled) == 0
+}
+
+// String lists the failed and cancelled modes, e.g. "failed: devs; cancelled: ownership"
+func (s modeSummary) String() string {
+	var parts []string
+	if len(s.Failed) > 0 {
+		parts = append(parts, "failed: "+strings.Join(s.Failed, ", "))
+	}
+	if len(s.Cancelled) > 0 {
+		parts = append(parts, "cancelled: "+strings.Join(s.Cancelled, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// executeModes runs the modes on --jobs workers. contents, if not nil, receives the charts
+// and tables each mode adds to the report; it is left nil for modes that did not complete.
+func executeModes(ctx context.Context, modes []string, reader readers.Reader, output string, startTime, endTime *time.Time,
+	contents []*graphics.ReportContent) modeSummary {
+	var summary modeSummary
+
 	// Check if JSON output is requested
 	jsonOutput := strings.HasSuffix(strings.ToLower(output), ".json")
-	
+	results := make(map[string]interface{})
+
+	// Terminal charts are drawn one after the other
+	jobs := min(workers.Count(viper.GetInt("jobs")), len(modes))
+	if graphics.IsTerminalOutput(output) {
+		jobs = 1
+	}
+
 	// Initialize progress tracking for multiple modes
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
-	
-	// If JSON output, collect all results and save as JSON
-	if jsonOutput {
-		results := make(map[string]interface{})
-		
-		if len(modes) > 1 {
-			progEstimator.StartMultiOperation(len(modes), "Analysis Modes")
+	if len(modes) > 1 {
+		progEstimator.StartMultiOperation(len(modes), "Analysis Modes")
+	}
+	if jobs > 1 {
+		// Concurrent modes only log once the modes before them are done, and their own
+		// progress bars would be drawn over each other. The theme is shared through
+		// graphics.UseModeTheme, and the configuration is only read from here on.
+		defer progress.SuppressBars()()
+		if !quiet {
+			fmt.Printf("Running %d modes with %d jobs: %s\n", len(modes), jobs, strings.Join(modes, ", "))
 		}
-		
-		for _, mode := range modes {
-			if len(modes) > 1 {
-				progEstimator.NextOperation(fmt.Sprintf("Running %s", mode))
-			}
-			
-			if !quiet {
-				fmt.Printf("Running mode: %s\n", mode)
+	}
+
+	buffers := make([]*logging.Buffer, len(modes))
+	run := func(ctx context.Context, i int) error {
+		mode := modes[i]
+		modeFunc, ok := modeHandlers[mode]
+		if !ok {
+			return errUnknownMode
+		}
+		if contents != nil {
+			ctx, contents[i] = graphics.WithReportContent(ctx)
+		}
+		if jobs > 1 {
+			ctx, buffers[i] = logging.WithBuffer(ctx)
+		} else if !quiet {
+			fmt.Printf("Running mode: %s\n", mode)
+		}
+
+		// For JSON output, collect data instead of generating plots
+		if jsonOutput {
+			// Create temporary directory for this mode's data
+			tempDir := filepath.Join(os.TempDir(), "labours-json-"+mode)
+			os.MkdirAll(tempDir, 0755)
+			defer os.RemoveAll(tempDir)
+			return runMode(ctx, mode, modeFunc, reader, tempDir, startTime, endTime)
+		}
+
+		// Apply format detection and generate appropriate output path
+		format := detectOutputFormat(output)
+		formattedOutput := generateOutputPath(output, format)
+		if len(modes) > 1 && !graphics.IsTerminalOutput(output) {
+			formattedOutput = modeOutputPath(output, mode, format)
+			dir := filepath.Dir(formattedOutput)
+			if directoryModes[mode] {
+				dir = formattedOutput
 			}
-			
-			// For JSON output, collect data instead of generating plots
-			if modeFunc, ok := modeHandlers[mode]; ok {
-				// Create temporary directory for this mode's data
-				tempDir := filepath.Join(os.TempDir(), "labours-json-"+mode)
-				os.MkdirAll(tempDir, 0755)
-				defer os.RemoveAll(tempDir)
-				
-				if err := modeFunc(reader, tempDir, startTime, endTime); err != nil {
-					fmt.Printf("Error in mode %s: %v\n", mode, err)
-					results[mode] = map[string]interface{}{
-						"error": err.Error(),
-					}
-				} else {
-					// Extract data from the mode (this would need to be enhanced per mode)
-					results[mode] = extractModeDataForJSON(reader, mode)
-				}
-			} else {
-				fmt.Printf("Unknown mode: %s\n", mode)
-				results[mode] = map[string]interface{}{
-					"error": "unknown mode",
-				}
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				slog.ErrorContext(ctx, "mode failed", "mode", mode, "error", err)
+				return err
 			}
 		}
-		
+		return runMode(ctx, mode, modeFunc, reader, formattedOutput, startTime, endTime)
+	}
+
+	// Results are collected in the order of the modes, whichever finishes first
+	done := func(i int, err error) {
+		mode := modes[i]
+		buffers[i].Flush()
 		if len(modes) > 1 {
-			progEstimator.FinishMultiOperation()
+			progEstimator.NextOperation(fmt.Sprintf("Finished %s", mode))
+		}
+		if errors.Is(err, errUnknownMode) {
+			slog.Error("unknown mode", "mode", mode)
+		}
+		// Failures are logged by runMode; the other modes still run
+		summary.record(mode, err)
+		if err != nil && contents != nil {
+			contents[i] = nil
+		}
+		if !jsonOutput {
+			return
 		}
-		
+		if err != nil {
+			results[mode] = map[string]interface{}{
+				"error": err.Error(),
+			}
+		} else {
+			// Extract data from the mode (this would need to be enhanced per mode)
+			results[mode] = extractModeDataForJSON(ctx, reader, mode)
+		}
+	}
+	workers.Run(ctx, len(modes), jobs, run, done)
+
+	if len(modes) > 1 {
+		progEstimator.FinishMultiOperation()
+	}
+
+	// An interrupted run leaves no results file behind
+	if jsonOutput && ctx.Err() == nil {
 		// Save results as JSON
 		if err := saveJSONResults(results, output); err != nil {
-			fmt.Printf("Error saving JSON results: %v\n", err)
+			slog.Error("failed to save JSON results", "output", output, "error", err)
 		} else if !quiet {
 			fmt.Printf("Results saved as JSON to: %s\n", output)
 		}
-	} else {
-		// Regular image output
-		if len(modes) > 1 {
-			// Start multi-mode progress tracking
-			progEstimator.StartMultiOperation(len(modes), "Analysis Modes")
-			
-			for _, mode := range modes {
-				progEstimator.NextOperation(fmt.Sprintf("Running %s", mode))
-				
-				if !quiet {
-					fmt.Printf("Running mode: %s\n", mode)
-				}
-				
-				if modeFunc, ok := modeHandlers[mode]; ok {
-					// Apply format detection and generate appropriate output path
-					format := detectOutputFormat(output)
-					formattedOutput := generateOutputPath(output, format)
-					
-					if err := modeFunc(reader, formattedOutput, startTime, endTime); err != nil {
-						fmt.Printf("Error in mode %s: %v\n", mode, err)
-					}
-				} else {
-					fmt.Printf("Unknown mode: %s\n", mode)
-				}
-			}
-			
-			progEstimator.FinishMultiOperation()
-		} else {
-			// Single mode - let the individual mode handle its own progress
-			for _, mode := range modes {
-				if !quiet {
-					fmt.Printf("Running mode: %s\n", mode)
-				}
-				
-				if modeFunc, ok := modeHandlers[mode]; ok {
-					// Apply format detection and generate appropriate output path
-					format := detectOutputFormat(output)
-					formattedOutput := generateOutputPath(output, format)
-					
-					if err := modeFunc(reader, formattedOutput, startTime, endTime); err != nil {
-						fmt.Printf("Error in mode %s: %v\n", mode, err)
-					}
-				} else {
-					fmt.Printf("Unknown mode: %s\n", mode)
-				}
-			}
+	}
+	return summary
+}
+
+// errUnknownMode is the error of modes without a handler
+var errUnknownMode = errors.New("unknown mode")
+
+// heatmapModes render matrices and additionally use the theme's "heatmap" mode override
+var heatmapModes = map[string]bool{
+	"overwrites-matrix": true,
+	"couples-files":     true,
+	"couples-shotness":  true,
+	"shotness":          true,
+}
+
+// cancelGrace is how long a cancelled mode may take to notice and return before a
+// warning says that it is still finishing its current step
+const cancelGrace = 2 * time.Second
+
+// modeFunc is the signature of the mode handlers
+type modeFunc func(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error
+
+// runMode runs a mode handler with the current theme's overrides for the mode applied.
+// The mode is stopped after --mode-timeout or when ctx is cancelled; once the handler has
+// returned, the files it wrote until then are removed and the context error is returned.
+// The theme is only restored after the handler has returned, as it draws with it.
+func runMode(ctx context.Context, mode string, handler modeFunc,
+	reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		slog.WarnContext(ctx, "mode skipped", "mode", mode, "error", context.Cause(ctx))
+		return fmt.Errorf("mode %s not started: %w", mode, err)
+	}
+	if timeout := viper.GetDuration("mode-timeout"); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	keys := []string{mode}
+	if heatmapModes[mode] {
+		keys = []string{"heatmap", mode}
+	}
+	slog.InfoContext(ctx, "mode started", "mode", mode)
+	started := time.Now()
+	restore, err := graphics.UseModeTheme(keys...)
+	defer restore()
+	if err != nil {
+		slog.ErrorContext(ctx, "mode failed", "mode", mode, "error", err, "duration", time.Since(started))
+		return err
+	}
+
+	snapshot := snapshotOutputs(output)
+	done := make(chan error, 1)
+	go func() {
+		done <- handler(ctx, reader, output, startTime, endTime)
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// Modes check the context between steps; one in the middle of a long step may
+		// still write files, so its outputs are only removed once it has returned
+		select {
+		case <-done:
+		case <-time.After(cancelGrace):
+			slog.WarnContext(ctx, "mode is still finishing its current step", "mode", mode)
+			<-done
 		}
+		err = ctx.Err()
+	}
+
+	// Errors after the cancellation are put down to it
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		removed := snapshot.removeWritten()
+		slog.ErrorContext(ctx, "mode cancelled", "mode", mode, "error", context.Cause(ctx), "duration", time.Since(started), "removed", len(removed))
+		return fmt.Errorf("mode %s cancelled: %w", mode, ctxErr)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "mode failed", "mode", mode, "error", err, "duration", time.Since(started))
+		return err
 	}
+	slog.InfoContext(ctx, "mode finished", "mode", mode, "duration", time.Since(started))
+	return nil
 }
 
-func burndownProject(reader readers.Reader, output string, startTime, endTime *time.Time) error {
+func burndownProject(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
 	relative := viper.GetBool("relative")
 	resample := viper.GetString("resample")
 	// Use Python-compatible implementation
-	return modes.GenerateBurndownProjectPython(reader, output, relative, resample)
+	return modes.GenerateBurndownProjectPython(ctx, reader, output, relative, resample)
 }
 
-func burndownFile(reader readers.Reader, output string, startTime, endTime *time.Time) error {
+func burndownFile(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
 	relative := viper.GetBool("relative")
 	resample := viper.GetString("resample")
 	// Use Python-compatible implementation  
-	return modes.GenerateBurndownFilePython(reader, output, relative, resample)
+	return modes.GenerateBurndownFilePython(ctx, reader, output, relative, resample)
 }
 
-func burndownPerson(reader readers.Reader, output string, startTime, endTime *time.Time) error {
+func burndownPerson(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
 	relative := viper.GetBool("relative")
 	resample := viper.GetString("resample")
-	return modes.BurndownPerson(reader, output, relative, startTime, endTime, resample)
+	return modes.BurndownPerson(ctx, reader, output, relative, startTime, endTime, resample)
 }
 
-func overwritesMatrix(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.OverwritesMatrix(reader, output)
+func overwritesMatrix(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.OverwritesMatrix(ctx, reader, output)
 }
 
-func ownershipBurndown(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.OwnershipBurndown(reader, output)
+func ownershipBurndown(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.OwnershipBurndown(ctx, reader, output)
 }
 
-func couplesFiles(reader readers.Reader, output string, startTime, endTime *time.Time) error {
+func couplesFiles(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
 	// Note: --disable-projector flag is supported for Python compatibility but not used
 	// Our Go implementation focuses on core coupling analysis without TensorFlow embeddings
-	return modes.CouplesFiles(reader, output)
+	return modes.CouplesFiles(ctx, reader, output)
 }
 
-func couplesPeople(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.CouplesPeople(reader, output)
+func couplesPeople(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.CouplesPeople(ctx, reader, output)
 }
 
-func couplesShotness(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.CouplesShotness(reader, output)
+func couplesShotness(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.CouplesShotness(ctx, reader, output)
 }
 
-func shotness(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.Shotness(reader, output)
+func shotness(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.Shotness(ctx, reader, output)
 }
 
-func devs(reader readers.Reader, output string, startTime, endTime *time.Time) error {
+func devs(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
 	maxPeople := viper.GetInt("max-people")
-	return modes.Devs(reader, output, maxPeople)
+	return modes.Devs(ctx, reader, output, maxPeople)
 }
 
-func devsEfforts(reader readers.Reader, output string, startTime, endTime *time.Time) error {
+func devsEfforts(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
 	maxPeople := viper.GetInt("max-people")
-	return modes.DevsEfforts(reader, output, maxPeople)
+	return modes.DevsEfforts(ctx, reader, output, maxPeople)
 }
 
-func oldVsNew(reader readers.Reader, output string, startTime, endTime *time.Time) error {
+func oldVsNew(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
 	resample := viper.GetString("resample")
-	return modes.OldVsNew(reader, output, startTime, endTime, resample)
+	return modes.OldVsNew(ctx, reader, output, startTime, endTime, resample)
 }
 
-func languages(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.Languages(reader, output)
+func languages(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.Languages(ctx, reader, output)
 }
 
-func devsParallel(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.DevsParallel(reader, output)
+func devsParallel(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.DevsParallel(ctx, reader, output)
 }
 
-func runTimes(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.RunTimes(reader, output)
+func runTimes(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.RunTimes(ctx, reader, output)
 }
 
-func sentiment(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	return modes.Sentiment(reader, output)
+func runTimesTrend(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	runs := viper.GetStringSlice("runs")
+	if len(runs) == 0 {
+		return fmt.Errorf("run-times-trend needs the result files of the runs in --runs")
+	}
+	return modes.RunTimesTrend(ctx, runs, output, viper.GetFloat64("regression-threshold"))
 }
 
-func runAllModes(reader readers.Reader, output string, startTime, endTime *time.Time) error {
-	// 'all' mode runs the most commonly used analysis modes
-	// This matches the Python labours behavior for the 'all' meta-mode
-	allModes := []func(readers.Reader, string, *time.Time, *time.Time) error{
-		burndownProject,
-		devs,
-		ownershipBurndown,
-		couplesFiles,
-		devsEfforts,
-		languages,
-	}
-	
-	modeNames := []string{
-		"burndown-project",
-		"devs", 
-		"ownership",
-		"couples-files",
-		"devs-efforts",
-		"languages",
-	}
-	
-	if !viper.GetBool("quiet") {
-		fmt.Printf("Running 'all' mode: executing %d analysis modes\n", len(allModes))
-	}
-	
-	for i, modeFunc := range allModes {
-		if !viper.GetBool("quiet") {
-			fmt.Printf("  Running %s...\n", modeNames[i])
-		}
-		
-		if err := modeFunc(reader, output, startTime, endTime); err != nil {
-			fmt.Printf("  Error in mode %s: %v\n", modeNames[i], err)
-			// Continue with other modes even if one fails
+// runsModes read the result files of several hercules runs from --runs instead of --input
+var runsModes = map[string]bool{
+	"run-times-trend": true,
+}
+
+// readsInput reports whether any of the modes reads --input
+func readsInput(modes []string) bool {
+	for _, mode := range modes {
+		if !runsModes[mode] {
+			return true
 		}
 	}
-	
+	return false
+}
+
+func sentiment(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.Sentiment(ctx, reader, output, viper.GetString("resample"))
+}
+
+func anomalies(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	threshold := viper.GetFloat64("anomaly-threshold")
+	return modes.Anomalies(ctx, reader, output, threshold)
+}
+
+func teamDynamics(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.TeamDynamics(ctx, reader, output, viper.GetString("resample"))
+}
+
+func dashboard(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time) error {
+	return modes.Dashboard(ctx, reader, output, viper.GetString("dashboard"))
+}
+
+// writeReport writes the PDF report from the charts and tables executeModes collected
+func writeReport(ctx context.Context, modeNames []string, contents []*graphics.ReportContent, reader readers.Reader, path string) error {
+	if err := modes.Report(ctx, reader, modeNames, contents, path); err != nil {
+		slog.Error("failed to write report", "output", path, "error", err)
+		return err
+	}
 	return nil
 }
 
 // extractModeDataForJSON extracts raw data from the reader for JSON output
-func extractModeDataForJSON(reader readers.Reader, mode string) interface{} {
+func extractModeDataForJSON(ctx context.Context, reader readers.Reader, mode string) interface{} {
 	switch mode {
 	case "devs":
 		if stats, err := reader.GetDeveloperStats(); err == nil {
@@ -271,11 +412,19 @@ func extractModeDataForJSON(reader readers.Reader, mode string) interface{} {
 		}
 	case "burndown-project":
 		if header, name, matrix, err := reader.GetProjectBurndownWithHeader(); err == nil {
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"header":     header,
 				"name":       name,
 				"matrix":     matrix,
 			}
+			if months := viper.GetInt("forecast"); months > 0 {
+				if processed, err := burndown.LoadBurndown(header, name, matrix, viper.GetString("resample"), false, false); err == nil {
+					if forecast, err := burndown.ForecastBurndown(processed, months); err == nil {
+						result["forecast"] = forecast
+					}
+				}
+			}
+			return result
 		}
 	case "ownership":
 		if names, matrices, err := reader.GetOwnershipBurndown(); err == nil {
@@ -317,6 +466,36 @@ func extractModeDataForJSON(reader readers.Reader, mode string) interface{} {
 				"language_stats": stats,
 			}
 		}
+	case "run-times-trend":
+		if trend, err := modes.LoadRuntimeTrend(ctx, viper.GetStringSlice("runs"), viper.GetFloat64("regression-threshold")); err == nil {
+			return map[string]interface{}{
+				"runtime_trend": trend,
+			}
+		}
+	case "sentiment":
+		if series, err := modes.LoadSentiment(reader, viper.GetString("resample")); err == nil {
+			return map[string]interface{}{
+				"sentiment": series,
+			}
+		}
+	case "devs-parallel":
+		if metrics, err := modes.LoadDevsParallel(ctx, reader); err == nil {
+			return map[string]interface{}{
+				"devs_parallel": metrics,
+			}
+		}
+	case "anomalies":
+		if anomalies, err := modes.DetectAnomalies(reader, viper.GetFloat64("anomaly-threshold")); err == nil {
+			return map[string]interface{}{
+				"anomalies": anomalies,
+			}
+		}
+	case "team-dynamics":
+		if history, err := modes.LoadTeamDynamics(ctx, reader, viper.GetString("resample")); err == nil {
+			return map[string]interface{}{
+				"team_dynamics": history,
+			}
+		}
 	}
 	
 	return map[string]interface{}{
@@ -344,6 +523,9 @@ func saveJSONResults(results map[string]interface{}, outputPath string) error {
 		},
 		"results": results,
 	}
+	if annotations := graphics.ActiveAnnotations(); len(annotations) > 0 {
+		output["annotations"] = annotations
+	}
 	
 	return encoder.Encode(output)
 }
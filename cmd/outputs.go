@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"labours-go/internal/graphics"
+)
+
+// outputSnapshot remembers the files a mode may write before it runs, so that the files
+// a cancelled mode wrote or half-wrote can be removed afterwards
+type outputSnapshot struct {
+	root      string
+	stem      string // only top-level entries named after stem belong to the mode
+	recursive bool
+	files     map[string]time.Time
+}
+
+// snapshotOutputs records the modification times of the files a mode may write: the
+// whole tree of a directory output, the files and directories named after a file output
+// ("out.png" also covers "out_a.png" and "out.png/a.png", but not "out-b.png") and the
+// working directory when no output is given. Terminal output writes no files.
+func snapshotOutputs(output string) *outputSnapshot {
+	if graphics.IsTerminalOutput(output) {
+		return nil
+	}
+	snapshot := &outputSnapshot{root: ".", files: make(map[string]time.Time)}
+	if output != "" {
+		if info, err := os.Stat(output); err == nil && info.IsDir() {
+			snapshot.root, snapshot.recursive = output, true
+		} else {
+			base := filepath.Base(output)
+			snapshot.root = filepath.Dir(output)
+			snapshot.stem = strings.TrimSuffix(base, filepath.Ext(base))
+		}
+	}
+	snapshot.walk(func(path string, info fs.FileInfo) {
+		snapshot.files[path] = info.ModTime()
+	})
+	return snapshot
+}
+
+// removeWritten removes the files created or modified since the snapshot was taken
+func (s *outputSnapshot) removeWritten() []string {
+	if s == nil {
+		return nil
+	}
+	var removed []string
+	s.walk(func(path string, info fs.FileInfo) {
+		if modTime, ok := s.files[path]; ok && modTime.Equal(info.ModTime()) {
+			return
+		}
+		if err := os.Remove(path); err != nil {
+			slog.Warn("failed to remove partial output", "path", path, "error", err)
+			return
+		}
+		removed = append(removed, path)
+	})
+	return removed
+}
+
+func (s *outputSnapshot) walk(visit func(path string, info fs.FileInfo)) {
+	filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || path == s.root {
+			return nil
+		}
+		topLevel := filepath.Dir(path) == filepath.Clean(s.root)
+		if topLevel && !s.owns(entry.Name()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			// Directories named after a file output are the mode's own
+			if strings.HasPrefix(entry.Name(), ".") || (topLevel && !s.recursive && s.stem == "") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			visit(path, info)
+		}
+		return nil
+	})
+}
+
+// owns reports whether a top-level entry is named after the output
+func (s *outputSnapshot) owns(name string) bool {
+	if s.stem == "" {
+		return true
+	}
+	rest, ok := strings.CutPrefix(name, s.stem)
+	return ok && (rest == "" || rest[0] == '_' || rest[0] == '.')
+}
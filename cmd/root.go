@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"labours-go/internal/graphics"
+	"labours-go/internal/readers"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,13 +29,20 @@ func Execute() error {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(configureLogging, initConfig)
 	initializeFlags()
 	bindFlagsToViper()
 }
 
 func initializeFlags() {
-	rootCmd.PersistentFlags().StringP("output", "o", "", "Path to output file/directory. JSON extension saves data instead of image")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "Path to output file/directory. JSON extension saves data instead of image, \"term\" draws charts in the terminal")
+	rootCmd.PersistentFlags().String("term-style", "blocks", "Terminal chart style for --output term: blocks, braille or sixel")
+	rootCmd.PersistentFlags().String("dashboard", "", "YAML layout of the dashboard mode: grid of modes composed into one image")
+	rootCmd.PersistentFlags().String("report", "", "Also write a single PDF report with a cover page, the charts and summary tables of the selected modes")
+	rootCmd.PersistentFlags().String("animate", "", "Also write an animation of burndown and ownership charts: gif or apng")
+	rootCmd.PersistentFlags().Int("animation-fps", 10, "Frames per second of animations")
+	rootCmd.PersistentFlags().Int("animation-frames", 100, "Maximum number of frames of animations")
+	rootCmd.PersistentFlags().String("animation-date-format", "2006-01-02", "Go time layout of the date shown on animation frames, empty hides it")
 	rootCmd.PersistentFlags().StringP("input", "i", "-", "Path to input file")
 	rootCmd.PersistentFlags().StringP("input-format", "f", "auto", "Input format")
 	rootCmd.PersistentFlags().Int("font-size", 12, "Size of labels and legend")
@@ -41,19 +54,42 @@ func initializeFlags() {
 	rootCmd.PersistentFlags().String("tmpdir", "", "Temporary directory for intermediate files")
 	rootCmd.PersistentFlags().StringSliceP("modes", "m", []string{}, "What to plot, can be repeated")
 	rootCmd.PersistentFlags().String("resample", "year", "Resample time series method")
+	rootCmd.PersistentFlags().String("interpolation", "stream", "Burndown interpolation engine: stream (band by band, bounded memory) or dense (full day x day matrix)")
+	rootCmd.PersistentFlags().Bool("float32", false, "Interpolate burndowns in single precision, halving the memory of the stream engine")
 	rootCmd.PersistentFlags().String("start-date", "", "Start date for time-based plots")
 	rootCmd.PersistentFlags().String("end-date", "", "End date for time-based plots")
 	rootCmd.PersistentFlags().Bool("disable-projector", false, "Do not run Tensorflow Projector")
 	rootCmd.PersistentFlags().Int("max-people", 20, "Maximum developers in matrix and people plots")
 	rootCmd.PersistentFlags().Bool("order-ownership-by-time", false, "Sort developers in the ownership plot by their first appearance in the history.")
+	rootCmd.PersistentFlags().Int("forecast", 0, "Project burndown-project this many months ahead (0 disables forecasting)")
+	rootCmd.PersistentFlags().Float64("anomaly-threshold", 3.5, "Robust z-score above which activity spikes are reported by the anomalies mode")
+	rootCmd.PersistentFlags().StringSlice("runs", []string{}, "Result files, directories or glob patterns of several hercules runs for the run-times-trend mode")
+	rootCmd.PersistentFlags().Float64("regression-threshold", 0.25, "Slowdown against the median of the previous runs reported as a regression by run-times-trend, 0.25 is 25%")
+	rootCmd.PersistentFlags().Bool("log-y", false, "Logarithmic Y axis on time series charts")
+	rootCmd.PersistentFlags().String("y-min", "", "Lower Y limit of time series charts")
+	rootCmd.PersistentFlags().String("y-max", "", "Upper Y limit of time series charts")
+	rootCmd.PersistentFlags().String("x-range", "", "Date range shown on time series charts, e.g. 2020-01-01:2021-06-30 (either side optional)")
+	rootCmd.PersistentFlags().String("smooth", "", "Smoothing of time series charts: rolling:N, ewm:alpha or none")
+	rootCmd.PersistentFlags().String("annotations", "", "YAML file with dated events (date, label, color) marked on time-based charts")
 	rootCmd.PersistentFlags().Bool("sentiment", false, "Include sentiment analysis in the output (Python compatibility)")
 
+	// Developer identity flags
+	rootCmd.PersistentFlags().String("people-map", "", "Mailmap or YAML file mapping developer aliases to canonical identities")
+	rootCmd.PersistentFlags().Bool("anonymize", false, "Replace developer names with stable pseudonyms")
+	rootCmd.PersistentFlags().String("teams", "", "YAML file mapping developers to teams; aggregates people-based modes by team")
+
 	// Progress and output control flags
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Disable progress bars and reduce output")
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output with detailed progress information")
+	rootCmd.PersistentFlags().String("log-format", "text", "Format of log events on stderr: text or json")
+	rootCmd.PersistentFlags().String("log-level", "", "Lowest level of log events: debug, info, warn or error (default warn for text, info for json; debug with --verbose)")
+	rootCmd.PersistentFlags().String("progress", "bar", "Progress reporting: bar, json (events on stderr) or none")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Cancel the remaining modes after this duration, e.g. 10m (0 for no limit)")
+	rootCmd.PersistentFlags().IntP("jobs", "j", 1, "Number of modes, report pages and per-file or per-person charts built at once (0 uses all CPUs)")
+	rootCmd.PersistentFlags().Duration("mode-timeout", 0, "Cancel a single mode after this duration, e.g. 2m (0 for no limit)")
 
 	// Theme-related flags
-	rootCmd.PersistentFlags().String("theme", "default", "Theme to use for visualization (default, dark, minimal, vibrant, matplotlib)")
+	rootCmd.PersistentFlags().String("theme", "default", "Theme to use for visualization (default, dark, minimal, vibrant, matplotlib, colorblind)")
 	rootCmd.PersistentFlags().Bool("list-themes", false, "List all available themes and exit")
 	rootCmd.PersistentFlags().String("export-theme", "", "Export a built-in theme to file for customization")
 	rootCmd.PersistentFlags().String("load-theme", "", "Load custom theme from file")
@@ -79,14 +115,14 @@ func initConfig() {
 	viper.AddConfigPath("$HOME/.labours-go")
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	} else {
-		fmt.Println("No configuration file found, using defaults.")
+		fmt.Fprintln(os.Stderr, "No configuration file found, using defaults.")
 	}
 
 	// Load user themes from standard directories
 	if err := graphics.LoadUserThemes(); err != nil {
-		fmt.Printf("Warning: failed to load user themes: %v\n", err)
+		slog.Warn("failed to load user themes", "error", err)
 	}
 }
 
@@ -105,7 +141,7 @@ func runLaboursCommand(cmd *cobra.Command, args []string) {
 	// Load custom theme if specified
 	if loadTheme := viper.GetString("load-theme"); loadTheme != "" {
 		if err := graphics.GlobalThemeManager.LoadThemeFromFile(loadTheme); err != nil {
-			fmt.Printf("Failed to load custom theme: %v\n", err)
+			slog.Error("failed to load custom theme", "path", loadTheme, "error", err)
 			os.Exit(1)
 		}
 	}
@@ -126,7 +162,7 @@ func runLaboursCommand(cmd *cobra.Command, args []string) {
 	}
 
 	if err := graphics.SetTheme(themeName); err != nil {
-		fmt.Printf("Failed to set theme '%s': %v\n", themeName, err)
+		slog.Error("failed to set theme", "theme", themeName, "error", err)
 		fmt.Printf("Available themes: %v\n", graphics.ListThemes())
 		os.Exit(1)
 	}
@@ -134,7 +170,7 @@ func runLaboursCommand(cmd *cobra.Command, args []string) {
 	// Handle matplotlib colors flag - force matplotlib theme if requested
 	if viper.GetBool("matplotlib-colors") {
 		if err := graphics.SetTheme("matplotlib"); err != nil {
-			fmt.Printf("Failed to set matplotlib theme: %v\n", err)
+			slog.Error("failed to set matplotlib theme", "error", err)
 			os.Exit(1)
 		}
 		if !viper.GetBool("quiet") {
@@ -142,9 +178,16 @@ func runLaboursCommand(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	configureAnnotations()
+	configureAxes()
+	configureInterpolation()
+
+	ctx, stop := modesContext()
+	defer stop()
+
 	// Handle hercules integration if --from-repo is specified
 	if repoPath := viper.GetString("from-repo"); repoPath != "" {
-		handleHerculesIntegration(repoPath)
+		handleHerculesIntegration(ctx, repoPath)
 		return
 	}
 
@@ -152,7 +195,6 @@ func runLaboursCommand(cmd *cobra.Command, args []string) {
 	startDate, endDate := parseDates()
 	validateDateRange(startDate, endDate)
 
-	reader := detectAndReadInput(input, inputFormat)
 	modes := resolveModes()
 
 	// Handle Python compatibility: if --sentiment flag is set, add sentiment mode
@@ -161,7 +203,62 @@ func runLaboursCommand(cmd *cobra.Command, args []string) {
 		fmt.Println("Added sentiment analysis mode (--sentiment flag)")
 	}
 
-	executeModes(modes, reader, viper.GetString("output"), startDate, endDate)
+	// Modes comparing several runs read --runs, so --input is only read if another mode needs it
+	var reader readers.Reader
+	if readsInput(modes) {
+		reader, startDate, endDate = detectAndReadInput(input, inputFormat, startDate, endDate)
+	}
+
+	// The report is written from the charts and tables the modes draw
+	reportPath := viper.GetString("report")
+	var contents []*graphics.ReportContent
+	if reportPath != "" {
+		contents = make([]*graphics.ReportContent, len(modes))
+	}
+	summary := executeModes(ctx, modes, reader, viper.GetString("output"), startDate, endDate, contents)
+	if reportPath != "" {
+		summary.record("report", writeReport(ctx, modes, contents, reader, reportPath))
+	}
+
+	if summary.ok() {
+		return
+	}
+	slog.Error("not all modes completed", "modes", summary.String())
+	if errors.Is(context.Cause(ctx), errInterrupted) {
+		os.Exit(130)
+	}
+	os.Exit(1)
+}
+
+// errInterrupted is the cancellation cause of runs stopped with Ctrl-C or SIGTERM
+var errInterrupted = errors.New("interrupted")
+
+// modesContext returns the context the modes run in: it is cancelled by the first
+// Ctrl-C or SIGTERM and after --timeout. A second Ctrl-C terminates immediately.
+func modesContext() (context.Context, func()) {
+	root, cancel := context.WithCancelCause(context.Background())
+	ctx, cancelTimeout := context.Context(root), context.CancelFunc(func() {})
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(root, timeout)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			// Restore the default handlers so that the next signal terminates
+			signal.Stop(signals)
+			slog.Warn("interrupted, stopping modes and removing partial outputs (press Ctrl-C again to quit immediately)")
+			cancel(errInterrupted)
+		case <-root.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancelTimeout()
+		cancel(nil)
+	}
 }
 
 func listThemes() {
@@ -174,13 +271,13 @@ func listThemes() {
 func handleExportTheme(themeName string) {
 	outputPath := fmt.Sprintf("%s-theme.yaml", themeName)
 	if err := graphics.GlobalThemeManager.ExportTheme(themeName, outputPath); err != nil {
-		fmt.Printf("Failed to export theme '%s': %v\n", themeName, err)
+		slog.Error("failed to export theme", "theme", themeName, "error", err)
 		os.Exit(1)
 	}
 	fmt.Printf("Theme '%s' exported to %s\n", themeName, outputPath)
 }
 
-func handleHerculesIntegration(repoPath string) {
+func handleHerculesIntegration(ctx context.Context, repoPath string) {
 	// Auto-detect hercules binary
 	herculesPath := viper.GetString("hercules")
 	if herculesPath == "" {
@@ -210,7 +307,7 @@ func handleHerculesIntegration(repoPath string) {
 
 	// Check if repository exists and is a git repo
 	if !isGitRepository(repoPath) {
-		fmt.Printf("Error: %s is not a git repository\n", repoPath)
+		slog.Error("not a git repository", "path", repoPath)
 		os.Exit(1)
 	}
 
@@ -219,12 +316,26 @@ func handleHerculesIntegration(repoPath string) {
 		modes = []string{"burndown-project", "devs"} // default modes
 	}
 
+	// Release tags are marked on the charts next to the --annotations events
+	if tags, err := gitTagAnnotations(repoPath); err != nil {
+		slog.Warn("failed to read git tags", "error", err)
+	} else if len(tags) > 0 {
+		graphics.SetAnnotations(append(graphics.ActiveAnnotations(), tags...))
+		if !viper.GetBool("quiet") {
+			fmt.Printf("Annotating charts with %d git tags\n", len(tags))
+		}
+	}
+
 	// Map labours-go modes to hercules analysis
 	herculesAnalyses := mapModesToHerculesAnalyses(modes)
 
 	for _, analysis := range herculesAnalyses {
-		if err := runHerculesAndVisualize(herculesPath, repoPath, analysis); err != nil {
-			fmt.Printf("Error running analysis '%s': %v\n", analysis, err)
+		if ctx.Err() != nil {
+			slog.Error("analysis cancelled", "analysis", analysis, "error", context.Cause(ctx))
+			continue
+		}
+		if err := runHerculesAndVisualize(ctx, herculesPath, repoPath, analysis); err != nil {
+			slog.Error("analysis failed", "analysis", analysis, "error", err)
 		}
 	}
 }
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"labours-go/internal/progress"
+	"labours-go/internal/readers"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var sliceCmd = &cobra.Command{
+	Use:   "slice",
+	Short: "Crop a hercules file to a date range and write it to a new file",
+	Long: "Reads the file given with --input, crops its burndown matrices, devs ticks and " +
+		"shotness counters to --start-date and --end-date and writes the result to --output " +
+		"as hercules Protobuf or YAML. The window starts at the burndown band boundary " +
+		"before the start date; couples and people interaction keep the whole history.",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runSlice,
+}
+
+func init() {
+	sliceCmd.Flags().String("to", "", "Output format: pb or yaml (default: from the output extension)")
+	rootCmd.AddCommand(sliceCmd)
+}
+
+func runSlice(cmd *cobra.Command, args []string) error {
+	input, output := viper.GetString("input"), viper.GetString("output")
+	to, _ := cmd.Flags().GetString("to")
+	format, err := convertFormat(to, output)
+	if err != nil {
+		return err
+	}
+	start, err := parseDateFlag("start-date")
+	if err != nil {
+		return err
+	}
+	end, err := parseDateFlag("end-date")
+	if err != nil {
+		return err
+	}
+	if start == nil && end == nil {
+		return fmt.Errorf("slice needs --start-date, --end-date or both")
+	}
+
+	defer progress.SuppressBars()()
+	reader, err := readers.DetectAndReadInput(input, viper.GetString("input-format"))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", input, err)
+	}
+	window, err := readers.NewTimeWindowReader(reader, start, end)
+	if err != nil {
+		return err
+	}
+	if err := writeResults(window, output, format); err != nil {
+		return fmt.Errorf("failed to slice %s: %w", input, err)
+	}
+	return nil
+}
+
+// parseDateFlag parses an optional date flag, returning nil if it is not set
+func parseDateFlag(flag string) (*time.Time, error) {
+	value := viper.GetString(flag)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := parseFlexibleDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %v", flag, err)
+	}
+	return &date, nil
+}
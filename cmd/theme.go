@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"labours-go/internal/graphics"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var themeCmd = &cobra.Command{
+	Use:   "theme",
+	Short: "Inspect themes and color palettes",
+}
+
+var themeCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that a theme's band colors stay distinguishable with color vision deficiencies",
+	Long: "Simulates protanopia, deuteranopia and tritanopia and reports the minimum CIEDE2000 distance " +
+		"between the band colors of the theme selected with --theme or --load-theme.",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runThemeCheck,
+}
+
+var themePalettesCmd = &cobra.Command{
+	Use:   "palettes",
+	Short: "List the built-in color palettes",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, name := range graphics.ListPalettes() {
+			kind := "qualitative"
+			if graphics.BuiltinPalettes[name].Sequential {
+				kind = "sequential"
+			}
+			fmt.Printf("  - %s (%s, %d colors)\n", name, kind, len(graphics.BuiltinPalettes[name].Colors))
+		}
+	},
+}
+
+func init() {
+	themeCheckCmd.Flags().Int("bands", 10, "Number of chart bands to check")
+	themeCheckCmd.Flags().Float64("min-distance", 0, "Fail if any vision type has a smaller CIEDE2000 distance (0 only reports)")
+	themeCmd.AddCommand(themeCheckCmd, themePalettesCmd)
+	rootCmd.AddCommand(themeCmd)
+}
+
+func runThemeCheck(cmd *cobra.Command, args []string) error {
+	bands, _ := cmd.Flags().GetInt("bands")
+	minDistance, _ := cmd.Flags().GetFloat64("min-distance")
+	if bands < 2 {
+		return fmt.Errorf("--bands must be at least 2")
+	}
+
+	if loadTheme := viper.GetString("load-theme"); loadTheme != "" {
+		if err := graphics.GlobalThemeManager.LoadThemeFromFile(loadTheme); err != nil {
+			return fmt.Errorf("failed to load custom theme: %v", err)
+		}
+	}
+	themeName := viper.GetString("theme")
+	theme, err := graphics.GetTheme(themeName)
+	if err != nil {
+		return err
+	}
+
+	reports := theme.CheckBandColors(bands)
+	fmt.Printf("Theme '%s' with %d bands (CIEDE2000 distance of the closest bands)\n", theme.Name, bands)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VISION\tMIN DISTANCE\tCLOSEST BANDS\tSTATUS")
+	var failed []string
+	for _, report := range reports {
+		status := "ok"
+		if minDistance > 0 && report.MinDistance < minDistance {
+			status = "FAIL"
+			failed = append(failed, report.Vision)
+		}
+		fmt.Fprintf(w, "%s\t%.2f\t%d, %d\t%s\n", report.Vision, report.MinDistance, report.First+1, report.Second+1, status)
+	}
+	w.Flush()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("theme '%s' has bands closer than %.2f for %v", theme.Name, minDistance, failed)
+	}
+	return nil
+}
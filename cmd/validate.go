@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"labours-go/internal/readers"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check that a hercules file is complete and consistent",
+	Long: "Reads the file given with --input and checks the hercules version, the analysis sections, " +
+		"matrix sizes, sparse matrix structure, name indexes and tick ranges. " +
+		"Exits with an error if any problem would break a mode.",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          runValidate,
+}
+
+func init() {
+	validateCmd.Flags().Bool("json", false, "Print the report as JSON")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	report, err := readers.ValidateInput(viper.GetString("input"), viper.GetString("input-format"))
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding the report: %v", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printValidationReport(report)
+	}
+
+	if report.Errors > 0 {
+		return fmt.Errorf("%s is invalid: %d errors", report.Input, report.Errors)
+	}
+	return nil
+}
+
+func printValidationReport(report *readers.ValidationReport) {
+	fmt.Printf("%s: hercules %s file, version %d", report.Input, report.Format, report.Version)
+	if report.Repository != "" {
+		fmt.Printf(", repository %s", report.Repository)
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(report.Sections) > 0 {
+		fmt.Fprintln(w, "\nSECTION\tSIZE\tCHECKED\tSUMMARY")
+		for _, section := range report.Sections {
+			size := "-"
+			if section.Bytes > 0 {
+				size = fmt.Sprintf("%d", section.Bytes)
+			}
+			checked := "no"
+			if section.Checked {
+				checked = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", section.Name, size, checked, section.Summary)
+		}
+	}
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(w, "\nSEVERITY\tSECTION\tMESSAGE")
+		for _, finding := range report.Findings {
+			section := finding.Section
+			if section == "" {
+				section = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", finding.Severity, section, finding.Message)
+		}
+	}
+	w.Flush()
+	fmt.Printf("\n%d errors, %d warnings\n", report.Errors, report.Warnings)
+}
@@ -0,0 +1,266 @@
+package burndown
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ForecastConfidenceZ is the z-score of the uncertainty bands (95% interval)
+const ForecastConfidenceZ = 1.96
+
+// forecastGrowthWindowDays is the minimum window used to estimate the recent growth rate
+const forecastGrowthWindowDays = 90
+
+// BandForecast is the projection of one age band (e.g. one year's code)
+type BandForecast struct {
+	Label           string    `json:"label"`
+	DecayRate       float64   `json:"decay_rate_per_day"`
+	DecayRateStdErr float64   `json:"decay_rate_stderr"`
+	HalfLifeDays    float64   `json:"half_life_days"`
+	Fitted          bool      `json:"fitted"` // false if the rate was borrowed from other bands
+	Values          []float64 `json:"values"`
+	Lower           []float64 `json:"lower"`
+	Upper           []float64 `json:"upper"`
+	SurvivingShare  []float64 `json:"surviving_share"` // fraction of the band's peak still alive
+}
+
+// BurndownForecast projects a processed burndown forward in time
+type BurndownForecast struct {
+	Months           int            `json:"months"`
+	Start            time.Time      `json:"start"`    // last observation the projection starts from
+	Observed         []float64      `json:"observed"` // band values at Start
+	Dates            []time.Time    `json:"dates"`
+	Bands            []BandForecast `json:"bands"`
+	NewCode          []float64      `json:"new_code"` // lines written after the last observation
+	Total            []float64      `json:"total"`
+	TotalLower       []float64      `json:"total_lower"`
+	TotalUpper       []float64      `json:"total_upper"`
+	GrowthRate       float64        `json:"growth_rate_per_day"`
+	GrowthRateStdErr float64        `json:"growth_rate_stderr"`
+}
+
+// ForecastBurndown fits an exponential decay rate to every band of the processed burndown
+// and a linear growth rate to the recent project size, then projects both the given
+// number of months past the last observation.
+func ForecastBurndown(data *ProcessedBurndown, months int) (*BurndownForecast, error) {
+	if data == nil || len(data.Matrix) == 0 || len(data.DateRange) < 2 {
+		return nil, fmt.Errorf("not enough burndown data to forecast")
+	}
+	if months <= 0 {
+		return nil, fmt.Errorf("forecast horizon must be positive, got %d months", months)
+	}
+
+	// LoadBurndown zeroes the days after the last commit, so the projection starts from
+	// the last observation with any code alive
+	totals := make([]float64, len(data.DateRange))
+	for _, row := range data.Matrix {
+		for j := 0; j < len(totals) && j < len(row); j++ {
+			totals[j] += row[j]
+		}
+	}
+	numPoints := len(totals)
+	for numPoints > 0 && totals[numPoints-1] <= 0 {
+		numPoints--
+	}
+	if numPoints < 2 {
+		return nil, fmt.Errorf("not enough burndown data to forecast")
+	}
+	totals = totals[:numPoints]
+
+	days := make([]float64, numPoints)
+	for i := 0; i < numPoints; i++ {
+		days[i] = data.DateRange[i].Sub(data.DateRange[0]).Hours() / 24
+	}
+	last := data.DateRange[numPoints-1]
+	step := data.DateRange[1].Sub(data.DateRange[0])
+	if step <= 0 {
+		return nil, fmt.Errorf("burndown dates are not increasing")
+	}
+
+	// Future dates, spaced like the observed ones
+	end := last.AddDate(0, months, 0)
+	var dates []time.Time
+	for date := last.Add(step); !date.After(end); date = date.Add(step) {
+		dates = append(dates, date)
+	}
+	horizons := make([]float64, len(dates))
+	for i, date := range dates {
+		horizons[i] = date.Sub(last).Hours() / 24
+	}
+
+	forecast := &BurndownForecast{Months: months, Start: last, Dates: dates}
+
+	// Fit band decay rates; bands without a decay phase borrow the mean fitted rate
+	// together with the pooled standard error of the fitted bands
+	var fittedSum, fittedVariance float64
+	var fittedCount int
+	bands := make([]BandForecast, len(data.Matrix))
+	for i, row := range data.Matrix {
+		label := fmt.Sprintf("Layer %d", i)
+		if i < len(data.Labels) {
+			label = data.Labels[i]
+		}
+		bands[i].Label = label
+		if len(row) > numPoints {
+			row = row[:numPoints]
+		}
+		rate, stderr, ok := fitBandDecay(days, row)
+		if ok {
+			bands[i].DecayRate, bands[i].DecayRateStdErr, bands[i].Fitted = rate, stderr, true
+			fittedSum += rate
+			fittedVariance += stderr * stderr
+			fittedCount++
+		}
+	}
+	for i := range bands {
+		if !bands[i].Fitted && fittedCount > 0 {
+			bands[i].DecayRate = fittedSum / float64(fittedCount)
+			bands[i].DecayRateStdErr = math.Sqrt(fittedVariance / float64(fittedCount))
+		}
+	}
+
+	// Project every band
+	sumBands := make([]float64, len(dates))
+	for i, row := range data.Matrix {
+		band := &bands[i]
+		if band.DecayRate > 0 {
+			band.HalfLifeDays = math.Ln2 / band.DecayRate
+		}
+		if len(row) > numPoints {
+			row = row[:numPoints]
+		}
+		current := lastValue(row)
+		peak := maxValue(row)
+		forecast.Observed = append(forecast.Observed, current)
+		band.Values = make([]float64, len(dates))
+		band.Lower = make([]float64, len(dates))
+		band.Upper = make([]float64, len(dates))
+		band.SurvivingShare = make([]float64, len(dates))
+		for k, h := range horizons {
+			band.Values[k] = current * math.Exp(-band.DecayRate*h)
+			band.Lower[k] = current * math.Exp(-(band.DecayRate+ForecastConfidenceZ*band.DecayRateStdErr)*h)
+			band.Upper[k] = current * math.Exp(-math.Max(0, band.DecayRate-ForecastConfidenceZ*band.DecayRateStdErr)*h)
+			if peak > 0 {
+				band.SurvivingShare[k] = band.Values[k] / peak
+			}
+			sumBands[k] += band.Values[k]
+		}
+	}
+	forecast.Bands = bands
+
+	// Fit the recent growth of the total project size
+	window := numPoints / 4
+	for window < numPoints && days[numPoints-1]-days[numPoints-1-window] < forecastGrowthWindowDays {
+		window++
+	}
+	if window >= numPoints {
+		window = numPoints - 1
+	}
+	slope, slopeErr, residual := linearFit(days[numPoints-1-window:], totals[numPoints-1-window:])
+	forecast.GrowthRate, forecast.GrowthRateStdErr = slope, slopeErr
+
+	currentTotal := totals[numPoints-1]
+	forecast.Total = make([]float64, len(dates))
+	forecast.TotalLower = make([]float64, len(dates))
+	forecast.TotalUpper = make([]float64, len(dates))
+	forecast.NewCode = make([]float64, len(dates))
+	for k, h := range horizons {
+		total := math.Max(0, currentTotal+slope*h)
+		spread := ForecastConfidenceZ * (slopeErr*h + residual)
+		// The project cannot shrink below its surviving old code, so the stacked
+		// bands plus new code always add up to the total
+		total = math.Max(total, sumBands[k])
+		forecast.Total[k] = total
+		forecast.TotalLower[k] = math.Max(0, total-spread)
+		forecast.TotalUpper[k] = total + spread
+		// Whatever the surviving old code does not account for has to be new code
+		forecast.NewCode[k] = math.Max(0, total-sumBands[k])
+	}
+
+	return forecast, nil
+}
+
+// fitBandDecay fits log(value) = a - rate*day on the band's values after its peak.
+// It returns false if the band has no decay phase with enough points to fit.
+func fitBandDecay(days, values []float64) (rate, stderr float64, ok bool) {
+	if len(values) == 0 {
+		return 0, 0, false
+	}
+	peakIdx := 0
+	for i, v := range values {
+		if v > values[peakIdx] {
+			peakIdx = i
+		}
+	}
+
+	var xs, ys []float64
+	for i := peakIdx; i < len(values) && i < len(days); i++ {
+		if values[i] > 0 {
+			xs = append(xs, days[i]-days[peakIdx])
+			ys = append(ys, math.Log(values[i]))
+		}
+	}
+	if len(xs) < 3 {
+		return 0, 0, false
+	}
+
+	slope, slopeErr, _ := linearFit(xs, ys)
+	return math.Max(0, -slope), slopeErr, true
+}
+
+// linearFit returns the least-squares slope, its standard error and the residual
+// standard deviation of y = a + slope*x.
+func linearFit(xs, ys []float64) (slope, slopeErr, residual float64) {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return 0, 0, 0
+	}
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var sxx, sxy float64
+	for i := range xs {
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+		sxy += (xs[i] - meanX) * (ys[i] - meanY)
+	}
+	if sxx == 0 {
+		return 0, 0, 0
+	}
+	slope = sxy / sxx
+	intercept := meanY - slope*meanX
+
+	if len(xs) > 2 {
+		var ssr float64
+		for i := range xs {
+			r := ys[i] - (intercept + slope*xs[i])
+			ssr += r * r
+		}
+		variance := ssr / (n - 2)
+		residual = math.Sqrt(variance)
+		slopeErr = math.Sqrt(variance / sxx)
+	}
+	return slope, slopeErr, residual
+}
+
+func lastValue(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
+
+func maxValue(values []float64) float64 {
+	result := 0.0
+	for _, v := range values {
+		if v > result {
+			result = v
+		}
+	}
+	return result
+}
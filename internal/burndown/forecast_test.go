@@ -0,0 +1,114 @@
+package burndown
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestForecastBurndown(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	const days = 200
+	dates := make([]time.Time, days)
+	old := make([]float64, days)
+	recent := make([]float64, days)
+	for i := range dates {
+		dates[i] = start.AddDate(0, 0, i)
+		// Old code decays with a half-life of 100 days, recent code grows by 10 lines a day
+		old[i] = 1000 * math.Exp(-math.Ln2/100*float64(i)) * (1 + 0.02*math.Sin(float64(i)))
+		recent[i] = 10 * float64(i)
+	}
+	// Trailing zeroed days after the last commit must be ignored
+	dates = append(dates, start.AddDate(0, 0, days), start.AddDate(0, 0, days+1))
+	old = append(old, 0, 0)
+	recent = append(recent, 0, 0)
+
+	data := &ProcessedBurndown{
+		Name:      "test",
+		Matrix:    [][]float64{old, recent},
+		DateRange: dates,
+		Labels:    []string{"old", "recent"},
+	}
+
+	forecast, err := ForecastBurndown(data, 6)
+	if err != nil {
+		t.Fatalf("ForecastBurndown() error = %v", err)
+	}
+
+	if !forecast.Start.Equal(start.AddDate(0, 0, days-1)) {
+		t.Errorf("forecast starts at %v, want last non-empty day", forecast.Start)
+	}
+	if got := forecast.Bands[0].HalfLifeDays; math.Abs(got-100) > 1 {
+		t.Errorf("old band half-life = %.2f, want 100", got)
+	}
+	if forecast.Bands[1].Fitted {
+		t.Error("growing band should borrow the fitted decay rate")
+	}
+	if math.Abs(forecast.Bands[1].DecayRate-forecast.Bands[0].DecayRate) > 1e-9 {
+		t.Errorf("growing band decay rate = %f, want %f", forecast.Bands[1].DecayRate, forecast.Bands[0].DecayRate)
+	}
+	if stderr := forecast.Bands[1].DecayRateStdErr; stderr <= 0 || math.Abs(stderr-forecast.Bands[0].DecayRateStdErr) > 1e-12 {
+		t.Errorf("growing band decay rate stderr = %g, want the pooled %g", stderr, forecast.Bands[0].DecayRateStdErr)
+	}
+
+	last := len(forecast.Dates) - 1
+	if last < 0 || forecast.Dates[last].After(forecast.Start.AddDate(0, 6, 0)) {
+		t.Fatalf("forecast dates exceed the horizon")
+	}
+	if forecast.GrowthRate <= 0 {
+		t.Errorf("growth rate = %f, want positive", forecast.GrowthRate)
+	}
+	for k := range forecast.Dates {
+		if forecast.TotalLower[k] > forecast.Total[k] || forecast.Total[k] > forecast.TotalUpper[k] {
+			t.Fatalf("total %f outside its interval [%f, %f] at %d", forecast.Total[k], forecast.TotalLower[k], forecast.TotalUpper[k], k)
+		}
+	}
+	for k := range forecast.Dates {
+		stacked := forecast.NewCode[k]
+		for _, band := range forecast.Bands {
+			stacked += band.Values[k]
+			if band.Lower[k] > band.Values[k] || band.Values[k] > band.Upper[k] {
+				t.Fatalf("band %s value %f outside its interval [%f, %f] at %d", band.Label, band.Values[k], band.Lower[k], band.Upper[k], k)
+			}
+		}
+		if forecast.NewCode[k] < 0 || math.Abs(stacked-forecast.Total[k]) > 1e-6 {
+			t.Fatalf("stacked bands and new code = %f, want the total %f at %d", stacked, forecast.Total[k], k)
+		}
+	}
+	if share := forecast.Bands[0].SurvivingShare[last]; share <= 0 || share >= forecast.Bands[0].Values[0]/1000 {
+		t.Errorf("surviving share of old code = %f, want decreasing below %f", share, forecast.Bands[0].Values[0]/1000)
+	}
+
+	if _, err := ForecastBurndown(data, 0); err == nil {
+		t.Error("expected error for non-positive horizon")
+	}
+}
+
+func TestForecastBurndownShrinkingTotal(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	const days = 120
+	dates := make([]time.Time, days)
+	slow := make([]float64, days)
+	fast := make([]float64, days)
+	for i := range dates {
+		dates[i] = start.AddDate(0, 0, i)
+		// The slowly decaying band outlives the steep decline of the total
+		slow[i] = 1000 * math.Exp(-0.001*float64(i))
+		fast[i] = 5000 * math.Exp(-0.05*float64(i))
+	}
+	data := &ProcessedBurndown{Name: "shrinking", Matrix: [][]float64{slow, fast}, DateRange: dates}
+
+	forecast, err := ForecastBurndown(data, 12)
+	if err != nil {
+		t.Fatalf("ForecastBurndown() error = %v", err)
+	}
+	for k := range forecast.Dates {
+		surviving := forecast.Bands[0].Values[k] + forecast.Bands[1].Values[k]
+		if forecast.Total[k] < surviving-1e-9 {
+			t.Fatalf("total %f below the surviving old code %f at %d", forecast.Total[k], surviving, k)
+		}
+		if share := (surviving + forecast.NewCode[k]) / forecast.Total[k]; share > 1+1e-9 {
+			t.Fatalf("relative stack sums to %f at %d", share, k)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package burndown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"labours-go/internal/workers"
+)
+
+// Interpolation engines accepted by --interpolation
+const (
+	EngineStream = "stream"
+	EngineDense  = "dense"
+)
+
+// activeInterpolation is used by LoadBurndown, see SetInterpolationOptions
+var activeInterpolation = InterpolationOptions{Engine: EngineStream, Jobs: 1}
+
+// InterpolationOptions select how burndown matrices are interpolated to days and resampled.
+// The dense engine materializes the whole day × day matrix like Python labours; the stream
+// engine interpolates one age band at a time and sums it straight into the resampled
+// matrix, so that it only holds granularity × days values per job.
+type InterpolationOptions struct {
+	Engine  string
+	Float32 bool // the stream engine interpolates in single precision, halving its memory
+	Jobs    int  // age bands interpolated at once, see workers.Count
+}
+
+// ParseInterpolationOptions parses the --interpolation, --float32 and --jobs values.
+func ParseInterpolationOptions(engine string, float32 bool, jobs int) (InterpolationOptions, error) {
+	switch engine {
+	case EngineStream, EngineDense:
+	case "":
+		engine = EngineStream
+	default:
+		return InterpolationOptions{}, fmt.Errorf("unknown interpolation engine '%s', available: %s, %s", engine, EngineStream, EngineDense)
+	}
+	if float32 && engine == EngineDense {
+		return InterpolationOptions{}, fmt.Errorf("single precision is only supported by the %s interpolation engine", EngineStream)
+	}
+	return InterpolationOptions{Engine: engine, Float32: float32, Jobs: jobs}, nil
+}
+
+// SetInterpolationOptions sets the interpolation used by LoadBurndown
+func SetInterpolationOptions(options InterpolationOptions) {
+	activeInterpolation = options
+}
+
+// interpolateResampled computes the resampled matrix of plan without the daily matrix:
+// every age band is interpolated into a granularity × days block whose rows are summed
+// into the bands of plan. Daily columns from lastDays on count as zero. The result
+// equals resampling the dense daily matrix up to the rounding of T and of the summation
+// order.
+func interpolateResampled[T float32 | float64](ctx context.Context, matrix [][]int, granularity, sampling, lastDays int,
+	plan *resamplePlan, jobs int) ([][]float64, error) {
+	rows, cols := len(matrix), len(matrix[0])
+	dailyRows, dailyCols := rows*granularity, cols*sampling
+
+	// The row ranges of the bands do not overlap, see planResampling
+	rowBand := make([]int, dailyRows)
+	for row := range rowBand {
+		rowBand[row] = -1
+	}
+	for band, r := range plan.rows {
+		for row := max(r[0], 0); row < r[1] && row < dailyRows; row++ {
+			rowBand[row] = band
+		}
+	}
+
+	resampled := make([][]float64, len(plan.periods))
+	for i := range resampled {
+		resampled[i] = make([]float64, len(plan.dates))
+	}
+	var mu sync.Mutex
+	blocks := sync.Pool{New: func() any {
+		block := make([][]T, granularity)
+		for i := range block {
+			block[i] = make([]T, dailyCols)
+		}
+		return block
+	}}
+
+	errs := workers.Run(ctx, rows, jobs, func(ctx context.Context, y int) error {
+		block := blocks.Get().([][]T)
+		defer blocks.Put(block)
+		for _, row := range block {
+			clear(row)
+		}
+		interpolateBand(matrix, y, granularity, sampling, block)
+
+		// Sum the block per band first, so that the shared matrix is locked briefly
+		partial := make(map[int][]float64)
+		for r, values := range block {
+			band := rowBand[y*granularity+r]
+			if band < 0 {
+				continue
+			}
+			sums, ok := partial[band]
+			if !ok {
+				sums = make([]float64, len(plan.dates))
+				partial[band] = sums
+			}
+			for k := plan.first[band]; k < len(plan.dates); k++ {
+				if column := plan.columns[k]; column >= 0 && column < dailyCols && column < lastDays {
+					sums[k] += float64(values[column])
+				}
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for band, sums := range partial {
+			for k, sum := range sums {
+				resampled[band][k] += sum
+			}
+		}
+		return nil
+	}, nil)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resampled, nil
+}
@@ -0,0 +1,123 @@
+package burndown
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// syntheticBurndown returns a burndown of bands × samples where every band appears in its
+// own sampling period and decays afterwards, like hercules output
+func syntheticBurndown(bands, samples, granularity, sampling int) (BurndownHeader, [][]int) {
+	rng := rand.New(rand.NewSource(42))
+	matrix := make([][]int, bands)
+	for y := range matrix {
+		matrix[y] = make([]int, samples)
+		for x := range matrix[y] {
+			if (x+1)*sampling <= y*granularity {
+				continue
+			}
+			age := float64(x*sampling-y*granularity) / float64(sampling)
+			matrix[y][x] = int(1000*math.Exp(-0.05*math.Max(age, 0))) + rng.Intn(50)
+		}
+	}
+	start := int64(1546300800) // 2019-01-01
+	header := BurndownHeader{
+		Start:       start,
+		Last:        start + int64(samples*sampling-10)*86400,
+		Sampling:    sampling,
+		Granularity: granularity,
+		TickSize:    86400,
+	}
+	return header, matrix
+}
+
+func loadWith(t testing.TB, options InterpolationOptions, header BurndownHeader, matrix [][]int, resample string) *ProcessedBurndown {
+	t.Helper()
+	SetInterpolationOptions(options)
+	defer SetInterpolationOptions(InterpolationOptions{Engine: EngineStream, Jobs: 1})
+	processed, err := LoadBurndown(header, "test", matrix, resample, false, false)
+	if err != nil {
+		t.Fatalf("LoadBurndown(%+v, %s) error = %v", options, resample, err)
+	}
+	return processed
+}
+
+func TestStreamInterpolationMatchesDense(t *testing.T) {
+	for _, shape := range []struct{ granularity, sampling int }{{30, 30}, {30, 15}, {15, 30}} {
+		header, matrix := syntheticBurndown(30, 30*shape.granularity/shape.sampling, shape.granularity, shape.sampling)
+		for _, resample := range []string{"year", "month", "day"} {
+			dense := loadWith(t, InterpolationOptions{Engine: EngineDense}, header, matrix, resample)
+			for _, options := range []InterpolationOptions{
+				{Engine: EngineStream, Jobs: 1},
+				{Engine: EngineStream, Jobs: 4},
+				{Engine: EngineStream, Jobs: 4, Float32: true},
+			} {
+				stream := loadWith(t, options, header, matrix, resample)
+				tolerance := 1e-9
+				if options.Float32 {
+					tolerance = 1e-5
+				}
+				compareProcessed(t, dense, stream, tolerance)
+			}
+		}
+	}
+}
+
+func compareProcessed(t *testing.T, want, got *ProcessedBurndown, tolerance float64) {
+	t.Helper()
+	if len(got.DateRange) != len(want.DateRange) || !got.DateRange[0].Equal(want.DateRange[0]) {
+		t.Fatalf("date range differs: %d dates from %v, want %d from %v",
+			len(got.DateRange), got.DateRange[0], len(want.DateRange), want.DateRange[0])
+	}
+	if len(got.Labels) != len(want.Labels) || len(got.Matrix) != len(want.Matrix) {
+		t.Fatalf("got %d bands, want %d", len(got.Matrix), len(want.Matrix))
+	}
+	for i := range want.Matrix {
+		if got.Labels[i] != want.Labels[i] {
+			t.Errorf("label %d = %s, want %s", i, got.Labels[i], want.Labels[i])
+		}
+		for k, expected := range want.Matrix[i] {
+			if diff := math.Abs(got.Matrix[i][k] - expected); diff > tolerance*math.Max(1, math.Abs(expected)) {
+				t.Fatalf("band %d day %d = %v, want %v (%s)", i, k, got.Matrix[i][k], expected, want.ResampleMode)
+			}
+		}
+	}
+}
+
+func TestStreamInterpolationCancelled(t *testing.T) {
+	header, matrix := syntheticBurndown(20, 20, 30, 30)
+	plan, err := planResampling(FloorDateTime(time.Unix(header.Start, 0), header.TickSize),
+		time.Unix(header.Start+int64(20*30*86400), 0), "year")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := interpolateResampled[float64](ctx, matrix, 30, 30, 600, plan, 2); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func BenchmarkInterpolation(b *testing.B) {
+	// Five years sampled monthly
+	header, matrix := syntheticBurndown(60, 60, 30, 30)
+	for _, bench := range []struct {
+		name    string
+		options InterpolationOptions
+	}{
+		{"dense", InterpolationOptions{Engine: EngineDense}},
+		{"stream", InterpolationOptions{Engine: EngineStream, Jobs: 1}},
+		{"stream-float32", InterpolationOptions{Engine: EngineStream, Jobs: 1, Float32: true}},
+		{"stream-parallel", InterpolationOptions{Engine: EngineStream, Jobs: 0}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				loadWith(b, bench.options, header, matrix, "year")
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package burndown
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"math"
 	"time"
 )
 
@@ -43,6 +46,12 @@ type ProcessedBurndown struct {
 // InterpolateBurndownMatrix converts sparse age-band data into a daily matrix with proper code persistence
 // This implements burndown semantics: code persists until explicitly modified/deleted
 func InterpolateBurndownMatrix(matrix [][]int, granularity, sampling int, progress bool) ([][]float64, error) {
+	return InterpolateBurndownMatrixContext(context.Background(), matrix, granularity, sampling, progress)
+}
+
+// InterpolateBurndownMatrixContext is InterpolateBurndownMatrix stopping with the context's
+// error once it is cancelled
+func InterpolateBurndownMatrixContext(ctx context.Context, matrix [][]int, granularity, sampling int, progress bool) ([][]float64, error) {
 	if len(matrix) == 0 || len(matrix[0]) == 0 {
 		return [][]float64{}, fmt.Errorf("empty matrix")
 	}
@@ -58,131 +67,143 @@ func InterpolateBurndownMatrix(matrix [][]int, granularity, sampling int, progre
 		daily[i] = make([]float64, dailyCols)
 	}
 
-	fmt.Printf("DEBUG INTERPOLATION: Converting %dx%d sparse matrix to %dx%d daily matrix\n", 
-		rows, cols, dailyRows, dailyCols)
+	slog.Debug("interpolating burndown matrix", "rows", rows, "cols", cols,
+		"daily_rows", dailyRows, "daily_cols", dailyCols)
 
 	// Restore the original complex Python interpolation algorithm that creates smooth curves
 	for y := 0; y < rows; y++ {
-		for x := 0; x < cols; x++ {
-			// Skip if the future is zeros: y * granularity > (x + 1) * sampling
-			if y*granularity > (x+1)*sampling {
-				continue
-			}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		interpolateBand(matrix, y, granularity, sampling, daily[y*granularity:(y+1)*granularity])
+	}
 
-			// Define nested decay function (creates smooth exponential decay curves)
-			decay := func(startIndex int, startVal float64) {
-				if startVal == 0 {
-					return
-				}
-				k := float64(matrix[y][x]) / startVal // k <= 1, creates decay rate
-				scale := float64((x+1)*sampling - startIndex)
-				
-				for i := y * granularity; i < (y+1)*granularity; i++ {
-					var initial float64
-					if startIndex > 0 {
-						initial = daily[i][startIndex-1]
-					}
-					// Create smooth exponential-like curves between points
-					for j := startIndex; j < (x+1)*sampling; j++ {
-						progress := float64(j-startIndex+1) / scale
-						daily[i][j] = initial * (1 + (k-1)*progress)
-					}
-				}
+	return daily, nil
+}
+
+// interpolateBand interpolates age band y of matrix into its granularity rows of the daily
+// matrix; band[r] is daily row y*granularity+r. Bands only read and write their own rows,
+// so they can be interpolated independently.
+func interpolateBand[T float32 | float64](matrix [][]int, y, granularity, sampling int, band [][]T) {
+	cols := len(matrix[0])
+	base := y * granularity
+	for x := 0; x < cols; x++ {
+		// Skip if the future is zeros: y * granularity > (x + 1) * sampling
+		if y*granularity > (x+1)*sampling {
+			continue
+		}
+
+		// Define nested decay function (creates smooth exponential decay curves)
+		decay := func(startIndex int, startVal float64) {
+			if startVal == 0 {
+				return
 			}
+			k := float64(matrix[y][x]) / startVal // k <= 1, creates decay rate
+			scale := float64((x+1)*sampling - startIndex)
 
-			// Define nested grow function (creates smooth growth curves)  
-			grow := func(finishIndex int, finishVal float64) {
+			for i := y * granularity; i < (y+1)*granularity; i++ {
 				var initial float64
-				if x > 0 {
-					initial = float64(matrix[y][x-1])
-				}
-				startIndex := x * sampling
-				if startIndex < y*granularity {
-					startIndex = y * granularity
+				if startIndex > 0 {
+					initial = float64(band[i-base][startIndex-1])
 				}
-				if finishIndex == startIndex {
-					return
+				// Create smooth exponential-like curves between points
+				for j := startIndex; j < (x+1)*sampling; j++ {
+					progress := float64(j-startIndex+1) / scale
+					band[i-base][j] = T(initial * (1 + (k-1)*progress))
 				}
-				// Average slope creates smooth linear growth
-				avg := (finishVal - initial) / float64(finishIndex-startIndex)
-				
-				// Fill triangular region with smooth interpolation
-				for j := x * sampling; j < finishIndex; j++ {
-					for i := startIndex; i <= j; i++ {
-						daily[i][j] = avg
-					}
+			}
+		}
+
+		// Define nested grow function (creates smooth growth curves)
+		grow := func(finishIndex int, finishVal float64) {
+			var initial float64
+			if x > 0 {
+				initial = float64(matrix[y][x-1])
+			}
+			startIndex := x * sampling
+			if startIndex < y*granularity {
+				startIndex = y * granularity
+			}
+			if finishIndex == startIndex {
+				return
+			}
+			// Average slope creates smooth linear growth
+			avg := T((finishVal - initial) / float64(finishIndex-startIndex))
+
+			// Fill triangular region with smooth interpolation
+			for j := x * sampling; j < finishIndex; j++ {
+				for i := startIndex; i <= j; i++ {
+					band[i-base][j] = avg
 				}
-				// Copy values to create smooth persistence
-				for j := x * sampling; j < finishIndex; j++ {
-					for i := y * granularity; i < x*sampling; i++ {
-						if j > 0 {
-							daily[i][j] = daily[i][j-1]
-						}
+			}
+			// Copy values to create smooth persistence
+			for j := x * sampling; j < finishIndex; j++ {
+				for i := y * granularity; i < x*sampling; i++ {
+					if j > 0 {
+						band[i-base][j] = band[i-base][j-1]
 					}
 				}
 			}
+		}
 
-			// Main interpolation logic with complex conditional structure for smooth curves
-			if (y+1)*granularity >= (x+1)*sampling {
-				// Case: Current age band extends beyond current time sampling
-				if y*granularity <= x*sampling {
-					grow((x+1)*sampling, float64(matrix[y][x]))
-				} else if (x+1)*sampling > y*granularity {
-					grow((x+1)*sampling, float64(matrix[y][x]))
-					// Smooth fill for overlapping region
-					avg := float64(matrix[y][x]) / float64((x+1)*sampling-y*granularity)
-					for j := y * granularity; j < (x+1)*sampling; j++ {
-						for i := y * granularity; i <= j; i++ {
-							daily[i][j] = avg
-						}
+		// Main interpolation logic with complex conditional structure for smooth curves
+		if (y+1)*granularity >= (x+1)*sampling {
+			// Case: Current age band extends beyond current time sampling
+			if y*granularity <= x*sampling {
+				grow((x+1)*sampling, float64(matrix[y][x]))
+			} else if (x+1)*sampling > y*granularity {
+				grow((x+1)*sampling, float64(matrix[y][x]))
+				// Smooth fill for overlapping region
+				avg := T(float64(matrix[y][x]) / float64((x+1)*sampling-y*granularity))
+				for j := y * granularity; j < (x+1)*sampling; j++ {
+					for i := y * granularity; i <= j; i++ {
+						band[i-base][j] = avg
 					}
 				}
-			} else if (y+1)*granularity >= x*sampling {
-				// Complex peak calculation case for smooth curves
-				var v1, v2 float64
-				if x > 0 {
-					v1 = float64(matrix[y][x-1])
+			}
+		} else if (y+1)*granularity >= x*sampling {
+			// Complex peak calculation case for smooth curves
+			var v1, v2 float64
+			if x > 0 {
+				v1 = float64(matrix[y][x-1])
+			}
+			v2 = float64(matrix[y][x])
+			delta := float64((y+1)*granularity - x*sampling)
+
+			var previous float64
+			var scale float64
+			if x > 0 && (x-1)*sampling >= y*granularity {
+				if x > 1 {
+					previous = float64(matrix[y][x-2])
 				}
-				v2 = float64(matrix[y][x])
-				delta := float64((y+1)*granularity - x*sampling)
-				
-				var previous float64
-				var scale float64
-				if x > 0 && (x-1)*sampling >= y*granularity {
-					if x > 1 {
-						previous = float64(matrix[y][x-2])
-					}
+				scale = float64(sampling)
+			} else {
+				if x == 0 {
 					scale = float64(sampling)
 				} else {
-					if x == 0 {
-						scale = float64(sampling)
-					} else {
-						scale = float64(x*sampling - y*granularity)
-					}
-				}
-				
-				// Calculate peak with smooth interpolation
-				peak := v1 + (v1-previous)/scale*delta
-				if v2 > peak {
-					if x < cols-1 {
-						k := (v2 - float64(matrix[y][x+1])) / float64(sampling)
-						peak = float64(matrix[y][x]) + k*float64((x+1)*sampling-(y+1)*granularity)
-					} else {
-						peak = v2
-					}
+					scale = float64(x*sampling - y*granularity)
 				}
-				grow((y+1)*granularity, peak)
-				decay((y+1)*granularity, peak)
-			} else {
-				// Case: Age band is completely in the past  
-				if x > 0 {
-					decay(x*sampling, float64(matrix[y][x-1]))
+			}
+
+			// Calculate peak with smooth interpolation
+			peak := v1 + (v1-previous)/scale*delta
+			if v2 > peak {
+				if x < cols-1 {
+					k := (v2 - float64(matrix[y][x+1])) / float64(sampling)
+					peak = float64(matrix[y][x]) + k*float64((x+1)*sampling-(y+1)*granularity)
+				} else {
+					peak = v2
 				}
 			}
+			grow((y+1)*granularity, peak)
+			decay((y+1)*granularity, peak)
+		} else {
+			// Case: Age band is completely in the past
+			if x > 0 {
+				decay(x*sampling, float64(matrix[y][x-1]))
+			}
 		}
 	}
-
-	return daily, nil
 }
 
 // FloorDateTime mimics Python's floor_datetime function
@@ -194,23 +215,19 @@ func FloorDateTime(dt time.Time, tickSize float64) time.Time {
 
 // LoadBurndown is the main function that replicates Python's load_burndown
 func LoadBurndown(header BurndownHeader, name string, matrix [][]int, resample string, reportSurvival bool, interpolationProgress bool) (*ProcessedBurndown, error) {
+	return LoadBurndownContext(context.Background(), header, name, matrix, resample, reportSurvival, interpolationProgress)
+}
+
+// LoadBurndownContext is LoadBurndown stopping with the context's error once it is cancelled
+func LoadBurndownContext(ctx context.Context, header BurndownHeader, name string, matrix [][]int, resample string, reportSurvival bool, interpolationProgress bool) (*ProcessedBurndown, error) {
 	if header.Sampling <= 0 || header.Granularity <= 0 {
 		return nil, fmt.Errorf("invalid sampling (%d) or granularity (%d)", header.Sampling, header.Granularity)
 	}
 
-	// DEBUG: Check raw input matrix
-	fmt.Printf("DEBUG RAW INPUT MATRIX:\n")
-	fmt.Printf("  Dimensions: %dx%d\n", len(matrix), len(matrix[0]))
-	for i := 0; i < len(matrix); i++ {
-		minVal, maxVal := matrix[i][0], matrix[i][0]
-		nonZeroCount := 0
-		for j := 0; j < len(matrix[i]); j++ {
-			if matrix[i][j] < minVal { minVal = matrix[i][j] }
-			if matrix[i][j] > maxVal { maxVal = matrix[i][j] }
-			if matrix[i][j] != 0 { nonZeroCount++ }
-		}
-		fmt.Printf("  Row %d: min=%d, max=%d, non-zeros=%d\n", i, minVal, maxVal, nonZeroCount)
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return nil, fmt.Errorf("empty burndown matrix")
 	}
+	slog.Debug("loading burndown", "name", name, "rows", len(matrix), "cols", len(matrix[0]), "resample", resample)
 
 	start := FloorDateTime(time.Unix(header.Start, 0), header.TickSize)
 	last := time.Unix(header.Last, 0)
@@ -230,52 +247,50 @@ func LoadBurndown(header BurndownHeader, name string, matrix [][]int, resample s
 	var labels []string
 	
 	if resample != "no" && resample != "raw" {
-		fmt.Printf("resampling to %s, please wait...\n", resample)
+		slog.InfoContext(ctx, "resampling burndown", "name", name, "resample", resample)
 		
-		// Interpolate the day x day matrix
-		daily, err := InterpolateBurndownMatrix(matrix, header.Granularity, header.Sampling, interpolationProgress)
-		if err != nil {
-			return nil, fmt.Errorf("interpolation failed: %v", err)
-		}
-
-		// Zero out data after 'last' timestamp
-		lastDays := int(last.Sub(start).Hours() / 24)
-		for i := range daily {
-			for j := lastDays; j < len(daily[i]); j++ {
-				daily[i][j] = 0
-			}
-		}
-
-		// DEBUG: Analyze interpolated daily matrix before resampling
-		fmt.Printf("DEBUG DAILY MATRIX ANALYSIS:\n")
-		fmt.Printf("  Daily matrix dimensions: %dx%d\n", len(daily), len(daily[0]))
-		for i := 0; i < min(len(daily), 3); i++ { // Show first 3 rows
-			nonZeroCount := 0
-			negCount := 0
-			minVal, maxVal := daily[i][0], daily[i][0]
-			for j := 0; j < len(daily[i]); j++ {
-				if daily[i][j] != 0 { nonZeroCount++ }
-				if daily[i][j] < 0 { negCount++ }
-				if daily[i][j] < minVal { minVal = daily[i][j] }
-				if daily[i][j] > maxVal { maxVal = daily[i][j] }
-			}
-			fmt.Printf("  Daily row %d: range=[%.2f to %.2f], non-zeros=%d, negatives=%d\n", 
-				i, minVal, maxVal, nonZeroCount, negCount)
-		}
-
-		// Resample the bands - convert Python's pandas logic to Go
-		dateRange, finalMatrix, labels, err = resampleBurndownData(daily, start, finish, resample)
+		plan, err := planResampling(start, finish, resample)
 		if err != nil {
 			// Try fallback resampling like Python does
 			if resample == "year" || resample == "A" {
-				fmt.Println("too loose resampling - by year, trying by month")
-				return LoadBurndown(header, name, matrix, "month", false, interpolationProgress)
+				slog.WarnContext(ctx, "too loose resampling by year, trying by month", "name", name)
+				return LoadBurndownContext(ctx, header, name, matrix, "month", false, interpolationProgress)
 			} else if resample == "month" || resample == "M" {
-				fmt.Println("too loose resampling - by month, trying by day")
-				return LoadBurndown(header, name, matrix, "day", false, interpolationProgress)
+				slog.WarnContext(ctx, "too loose resampling by month, trying by day", "name", name)
+				return LoadBurndownContext(ctx, header, name, matrix, "day", false, interpolationProgress)
 			}
 			return nil, fmt.Errorf("too loose resampling: %s. Try finer", resample)
 		}
+		// Data after the 'last' timestamp is zeroed
+		lastDays := int(last.Sub(start).Hours() / 24)
+
+		if options := activeInterpolation; options.Engine == EngineDense {
+			// Interpolate the day x day matrix
+			daily, err := InterpolateBurndownMatrixContext(ctx, matrix, header.Granularity, header.Sampling, interpolationProgress)
+			if err != nil {
+				return nil, fmt.Errorf("interpolation failed: %w", err)
+			}
+			for i := range daily {
+				for j := max(lastDays, 0); j < len(daily[i]); j++ {
+					daily[i][j] = 0
+				}
+			}
+			// Resample the bands - convert Python's pandas logic to Go
+			if dateRange, finalMatrix, labels, err = resampleBurndownData(daily, start, finish, resample); err != nil {
+				return nil, fmt.Errorf("resampling failed: %w", err)
+			}
+		} else {
+			// Interpolate and resample band by band without the day x day matrix
+			if options.Float32 {
+				finalMatrix, err = interpolateResampled[float32](ctx, matrix, header.Granularity, header.Sampling, lastDays, plan, options.Jobs)
+			} else {
+				finalMatrix, err = interpolateResampled[float64](ctx, matrix, header.Granularity, header.Sampling, lastDays, plan, options.Jobs)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("interpolation failed: %w", err)
+			}
+			dateRange, labels = plan.dates, plan.labels()
+		}
 	} else {
 		// Raw mode - show age band labels
 		finalMatrix = make([][]float64, len(matrix))
@@ -303,53 +318,20 @@ func LoadBurndown(header BurndownHeader, name string, matrix [][]int, resample s
 		resample = "M" // fake resampling type as Python does
 	}
 
-	// DEBUG: Analyze negative values without clamping to understand root cause
-	negativeCount := 0
-	minNegative := 0.0
-	maxNegative := 0.0
+	negativeCount, minNegative := 0, 0.0
 	for i := range finalMatrix {
 		for j := range finalMatrix[i] {
 			if finalMatrix[i][j] < 0 {
 				negativeCount++
-				if finalMatrix[i][j] < minNegative {
-					minNegative = finalMatrix[i][j]
-				}
-				if finalMatrix[i][j] > maxNegative || maxNegative == 0.0 {
-					maxNegative = finalMatrix[i][j]
-				}
+				minNegative = math.Min(minNegative, finalMatrix[i][j])
 			}
 		}
 	}
 	if negativeCount > 0 {
-		fmt.Printf("DEBUG NEGATIVE VALUES: Found %d negative values ranging from %.2f to %.2f\n", negativeCount, minNegative, maxNegative)
-		fmt.Printf("  This indicates mathematical issues in interpolation that need fixing\n")
-	}
-
-	// DEBUG: Print detailed matrix information
-	fmt.Printf("DEBUG FINAL RESULT:\n")
-	fmt.Printf("  Name: %s\n", name)
-	fmt.Printf("  Labels: %v\n", labels)
-	fmt.Printf("  Matrix dimensions: %dx%d\n", len(finalMatrix), len(finalMatrix[0]))
-	fmt.Printf("  DateRange: %d entries from %s to %s\n", len(dateRange), dateRange[0].Format("2006-01-02"), dateRange[len(dateRange)-1].Format("2006-01-02"))
-	for i, label := range labels {
-		nonZeroCount := 0
-		negCount := 0
-		sum := 0.0
-		minVal, maxVal := finalMatrix[i][0], finalMatrix[i][0]
-		for j := range finalMatrix[i] {
-			if finalMatrix[i][j] > 0 {
-				nonZeroCount++
-				sum += finalMatrix[i][j]
-			}
-			if finalMatrix[i][j] < 0 {
-				negCount++
-			}
-			if finalMatrix[i][j] < minVal { minVal = finalMatrix[i][j] }
-			if finalMatrix[i][j] > maxVal { maxVal = finalMatrix[i][j] }
-		}
-		fmt.Printf("  Layer %d (%s): positives=%d, negatives=%d, sum=%.2f, range=[%.2f to %.2f]\n", 
-			i, label, nonZeroCount, negCount, sum, minVal, maxVal)
+		slog.Debug("burndown has negative values", "name", name, "count", negativeCount, "min", minNegative)
 	}
+	slog.Debug("burndown loaded", "name", name, "bands", len(finalMatrix), "samples", len(dateRange),
+		"from", dateRange[0].Format("2006-01-02"), "to", dateRange[len(dateRange)-1].Format("2006-01-02"))
 
 	return &ProcessedBurndown{
 		Name:         name,
@@ -362,8 +344,20 @@ func LoadBurndown(header BurndownHeader, name string, matrix [][]int, resample s
 	}, nil
 }
 
-// resampleBurndownData implements pandas-like resampling logic
-func resampleBurndownData(daily [][]float64, start, finish time.Time, resample string) ([]time.Time, [][]float64, []string, error) {
+// resamplePlan describes how the daily matrix is resampled: which daily rows are summed
+// into each band and which daily columns are the sampled dates
+type resamplePlan struct {
+	resample string      // pandas alias: A, M or D
+	periods  []time.Time // start of each band
+	dates    []time.Time // sampled dates
+	columns  []int       // daily matrix column of each date
+	rows     [][2]int    // daily rows [istart, ifinish) summed into each band
+	first    []int       // first date of each band
+}
+
+// planResampling computes the bands and dates of resampling the daily matrix spanning
+// start to finish, following pandas
+func planResampling(start, finish time.Time, resample string) (*resamplePlan, error) {
 	// Convert resample aliases like Python does
 	aliasMap := map[string]string{
 		"year":  "A",
@@ -404,29 +398,33 @@ func resampleBurndownData(daily [][]float64, start, finish time.Time, resample s
 			dateGranularitySampling = append(dateGranularitySampling, current)
 		}
 	default:
-		return nil, nil, nil, fmt.Errorf("unsupported resample mode: %s", resample)
+		return nil, fmt.Errorf("unsupported resample mode: %s", resample)
 	}
 
 	if len(dateGranularitySampling) == 0 {
-		return nil, nil, nil, fmt.Errorf("no valid resampling periods generated")
+		return nil, fmt.Errorf("no valid resampling periods generated")
 	}
 
 	if dateGranularitySampling[0].After(finish) {
-		return nil, nil, nil, fmt.Errorf("resampling period too loose")
+		return nil, fmt.Errorf("resampling period too loose")
 	}
 
 	// Create daily date range for sampling - start from actual data start, not year start
 	dateRangeSampling := make([]time.Time, int(finish.Sub(start).Hours()/24)+1)
+	columns := make([]int, len(dateRangeSampling))
 	for i := range dateRangeSampling {
 		dateRangeSampling[i] = start.AddDate(0, 0, i)
+		columns[i] = int(dateRangeSampling[i].Sub(start).Hours() / 24)
 	}
 
-	// Fill the new resampled matrix
-	resampledMatrix := make([][]float64, len(dateGranularitySampling))
-	for i := range resampledMatrix {
-		resampledMatrix[i] = make([]float64, len(dateRangeSampling))
+	plan := &resamplePlan{
+		resample: resample,
+		periods:  dateGranularitySampling,
+		dates:    dateRangeSampling,
+		columns:  columns,
+		rows:     make([][2]int, len(dateGranularitySampling)),
+		first:    make([]int, len(dateGranularitySampling)),
 	}
-
 	for i, gdt := range dateGranularitySampling {
 		var istart, ifinish int
 		
@@ -447,23 +445,58 @@ func resampleBurndownData(daily [][]float64, start, finish time.Time, resample s
 		} else {
 			ifinish = int(finish.Sub(start).Hours() / 24)
 		}
+		plan.rows[i] = [2]int{istart, ifinish}
 		
-		fmt.Printf("DEBUG RESAMPLING: Layer %d (year %d)\n", i, gdt.Year())
-		fmt.Printf("  gdt=%s, start=%s\n", gdt.Format("2006-01-02"), start.Format("2006-01-02"))
-		fmt.Printf("  istart=%d, ifinish=%d\n", istart, ifinish)
-
-		var j int
-		for idx, sdt := range dateRangeSampling {
-			if int(sdt.Sub(start).Hours()/24) >= istart {
-				j = idx
+		for idx, column := range columns {
+			if column >= istart {
+				plan.first[i] = idx
 				break
 			}
 		}
+	}
+	return plan, nil
+}
+
+// labels names the bands (matches Python exactly)
+func (plan *resamplePlan) labels() []string {
+	var labels []string
+	switch plan.resample {
+	case "A": // Year
+		for _, dt := range plan.periods {
+			labels = append(labels, fmt.Sprintf("%d", dt.Year()))
+		}
+	case "M": // Month
+		for _, dt := range plan.periods {
+			labels = append(labels, dt.Format("2006 January"))
+		}
+	default: // Day or other
+		for _, dt := range plan.periods {
+			labels = append(labels, dt.Format("2006-01-02"))
+		}
+	}
+	return labels
+}
+
+// resampleBurndownData implements pandas-like resampling logic
+func resampleBurndownData(daily [][]float64, start, finish time.Time, resample string) ([]time.Time, [][]float64, []string, error) {
+	plan, err := planResampling(start, finish, resample)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Fill the new resampled matrix
+	resampledMatrix := make([][]float64, len(plan.periods))
+	for i := range resampledMatrix {
+		resampledMatrix[i] = make([]float64, len(plan.dates))
+	}
+
+	for i, gdt := range plan.periods {
+		istart, ifinish := plan.rows[i][0], plan.rows[i][1]
 
 		// Sum the daily matrix data for this resampling period
 		nonZeroDays := 0
-		for k := j; k < len(dateRangeSampling); k++ {
-			sdtDays := int(dateRangeSampling[k].Sub(start).Hours() / 24)
+		for k := plan.first[i]; k < len(plan.dates); k++ {
+			sdtDays := plan.columns[k]
 			
 			// Skip negative days (dates before our start time)
 			if sdtDays < 0 {
@@ -479,25 +512,9 @@ func resampleBurndownData(daily [][]float64, start, finish time.Time, resample s
 			resampledMatrix[i][k] = sum
 			if sum > 0 { nonZeroDays++ }
 		}
-		fmt.Printf("  Processed %d days, %d with non-zero values\n", len(dateRangeSampling)-j, nonZeroDays)
+		slog.Debug("resampled burndown band", "band", i, "year", gdt.Year(),
+			"from_day", istart, "to_day", ifinish, "non_zero_days", nonZeroDays)
 	}
 
-	// Generate labels based on resampling mode (matches Python exactly)
-	var labels []string
-	switch resample {
-	case "A": // Year
-		for _, dt := range dateGranularitySampling {
-			labels = append(labels, fmt.Sprintf("%d", dt.Year()))
-		}
-	case "M": // Month
-		for _, dt := range dateGranularitySampling {
-			labels = append(labels, dt.Format("2006 January"))
-		}
-	default: // Day or other
-		for _, dt := range dateGranularitySampling {
-			labels = append(labels, dt.Format("2006-01-02"))
-		}
-	}
-
-	return dateRangeSampling, resampledMatrix, labels, nil
-}
\ No newline at end of file
+	return plan.dates, resampledMatrix, plan.labels(), nil
+}
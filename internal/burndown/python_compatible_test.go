@@ -0,0 +1,23 @@
+package burndown
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLoadBurndownContextCancelled(t *testing.T) {
+	header := BurndownHeader{Start: 1577836800, Last: 1577836800 + 60*86400, Sampling: 30, Granularity: 30, TickSize: 86400}
+	matrix := [][]int{{100, 80}, {0, 50}}
+
+	if _, err := LoadBurndown(header, "test", matrix, "month", false, false); err != nil {
+		t.Fatalf("LoadBurndown() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := LoadBurndownContext(ctx, header, "test", matrix, "month", false, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancellation error, got %v", err)
+	}
+}
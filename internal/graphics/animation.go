@@ -0,0 +1,385 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gonum.org/v1/plot"
+	"labours-go/internal/burndown"
+)
+
+// Animation formats selected with --animate
+const (
+	AnimationGIF  = "gif"
+	AnimationAPNG = "apng"
+)
+
+const (
+	animationDPI       = 72
+	animationHoldDelay = 2 * time.Second // the complete chart stays visible before the loop restarts
+	gifPaletteSize     = 256
+)
+
+// AnimationConfig controls animated charts
+type AnimationConfig struct {
+	Format     string // AnimationGIF or AnimationAPNG
+	FPS        int    // frames per second
+	MaxFrames  int    // upper limit of rendered frames; ticks are sampled evenly
+	DateFormat string // layout of the frame date shown in the title, empty hides it
+}
+
+// FrameBuilder builds a chart with the time points up to and including last
+type FrameBuilder func(last int) (*plot.Plot, error)
+
+// AnimationFromConfig returns the settings of --animate, --animation-fps, --animation-frames
+// and --animation-date-format, or nil if no animation was requested.
+func AnimationFromConfig() (*AnimationConfig, error) {
+	format := strings.ToLower(viper.GetString("animate"))
+	if format == "" {
+		return nil, nil
+	}
+	if format != AnimationGIF && format != AnimationAPNG {
+		return nil, fmt.Errorf("unknown animation format '%s', available: %s, %s", format, AnimationGIF, AnimationAPNG)
+	}
+	config := &AnimationConfig{
+		Format:     format,
+		FPS:        viper.GetInt("animation-fps"),
+		MaxFrames:  viper.GetInt("animation-frames"),
+		DateFormat: viper.GetString("animation-date-format"),
+	}
+	if config.FPS <= 0 {
+		return nil, fmt.Errorf("animation frame rate must be positive, got %d", config.FPS)
+	}
+	return config, nil
+}
+
+// AnimationPath returns the file name of the animation of a chart written to output
+func AnimationPath(output, format string) string {
+	ext := ".gif"
+	if format == AnimationAPNG {
+		ext = ".png"
+	}
+	return strings.TrimSuffix(output, filepath.Ext(output)) + "_animated" + ext
+}
+
+// AnimateBurndown writes the Python-style burndown chart growing tick by tick.
+func (c *AnimationConfig) AnimateBurndown(data *burndown.ProcessedBurndown, output string, relative bool) (string, error) {
+	if data == nil || len(data.Matrix) == 0 {
+		return "", fmt.Errorf("empty burndown data")
+	}
+	return c.Write(output, data.DateRange, ChartTypeDefault, func(last int) (*plot.Plot, error) {
+		frame := *data
+		frame.DateRange = data.DateRange[:last+1]
+		frame.Matrix = truncateColumns(data.Matrix, last+1)
+		p, err := buildBurndownPythonPlot(&frame, relative)
+		if err != nil {
+			return nil, err
+		}
+		// The title describes the complete chart
+		p.Title.Text = fmt.Sprintf("%s %d x %d (granularity %d, sampling %d)",
+			data.Name, len(data.Matrix), len(data.DateRange), data.Granularity, data.Sampling)
+		return p, nil
+	})
+}
+
+// AnimateStackedBurndown writes the stacked burndown chart growing tick by tick.
+func (c *AnimationConfig) AnimateStackedBurndown(matrix [][]float64, dateRange []time.Time, output string, relative bool) (string, error) {
+	return c.Write(output, dateRange, ChartTypeDefault, func(last int) (*plot.Plot, error) {
+		return buildStackedBurndownPlot(truncateColumns(matrix, last+1), dateRange[:last+1], relative)
+	})
+}
+
+// Write renders the frames of a chart and encodes them into the animation file, whose
+// path it returns. Every frame uses the axes of the complete chart so that the data grows
+// in place, and shows the active annotations up to its date.
+func (c *AnimationConfig) Write(output string, dates []time.Time, chartType ChartType, build FrameBuilder) (string, error) {
+	if len(dates) < 2 {
+		return "", fmt.Errorf("at least two time points are needed for an animation")
+	}
+	final, err := build(len(dates) - 1)
+	if err != nil {
+		return "", err
+	}
+	xMin, xMax, yMin, yMax := final.X.Min, final.X.Max, final.Y.Min, final.Y.Max
+
+	var encoder frameEncoder = &apngEncoder{}
+	if c.Format == AnimationGIF {
+		encoder = &gifEncoder{}
+	}
+	width, height := GetPlotSize(chartType)
+	frameDelay := time.Second / time.Duration(c.FPS)
+	indices := animationFrames(len(dates), c.MaxFrames)
+	for n, last := range indices {
+		p := final
+		if last != len(dates)-1 {
+			if p, err = build(last); err != nil {
+				return "", err
+			}
+		}
+		p.X.Min, p.X.Max, p.Y.Min, p.Y.Max = xMin, xMax, yMin, yMax
+		if c.DateFormat != "" {
+			p.Title.Text += "  " + dates[last].Format(c.DateFormat)
+		}
+		if err := AddTimeAnnotations(p, annotationsUntil(dates[last])); err != nil {
+			return "", err
+		}
+
+		delay := frameDelay
+		if n == len(indices)-1 {
+			delay = animationHoldDelay
+		}
+		if err := encoder.add(renderPlotImage(p, width, height, animationDPI), delay); err != nil {
+			return "", err
+		}
+	}
+
+	path := AnimationPath(output, c.Format)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create animation file %s: %v", path, err)
+	}
+	defer file.Close()
+	if err := encoder.finish(file); err != nil {
+		return "", fmt.Errorf("failed to encode animation %s: %v", path, err)
+	}
+	return path, nil
+}
+
+// animationFrames returns the time point indices drawn as frames: at most maxFrames
+// evenly spaced indices from the second to the last point (a single point has no area).
+func animationFrames(points, maxFrames int) []int {
+	count := points - 1
+	if maxFrames > 0 && count > maxFrames {
+		count = maxFrames
+	}
+	indices := make([]int, count)
+	for i := range indices {
+		indices[i] = points - 1
+		if count > 1 {
+			indices[i] = 1 + i*(points-2)/(count-1)
+		}
+	}
+	return indices
+}
+
+// annotationsUntil returns the active annotations up to and including t
+func annotationsUntil(t time.Time) []Annotation {
+	var annotations []Annotation
+	for _, annotation := range activeAnnotations {
+		if !annotation.Time.After(t) {
+			annotations = append(annotations, annotation)
+		}
+	}
+	return annotations
+}
+
+// truncateColumns returns the first n columns of every row
+func truncateColumns(matrix [][]float64, n int) [][]float64 {
+	truncated := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		truncated[i] = row[:min(n, len(row))]
+	}
+	return truncated
+}
+
+// frameEncoder collects rendered frames and writes the animation
+type frameEncoder interface {
+	add(img image.Image, delay time.Duration) error
+	finish(w io.Writer) error
+}
+
+// gifEncoder quantizes frames to the 256 most frequent colors of the first frame it gets,
+// which is the complete chart and therefore contains every band color.
+type gifEncoder struct {
+	animation gif.GIF
+	palette   color.Palette
+	indices   map[color.RGBA]uint8
+}
+
+func (e *gifEncoder) add(img image.Image, delay time.Duration) error {
+	if e.palette == nil {
+		e.palette = frequentColors(img, gifPaletteSize)
+		e.indices = make(map[color.RGBA]uint8)
+	}
+	bounds := img.Bounds()
+	frame := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), e.palette)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := color.RGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.RGBA)
+			index, ok := e.indices[c]
+			if !ok {
+				index = uint8(e.palette.Index(c))
+				e.indices[c] = index
+			}
+			frame.Pix[y*frame.Stride+x] = index
+		}
+	}
+	e.animation.Image = append(e.animation.Image, frame)
+	e.animation.Delay = append(e.animation.Delay, int(delay/(10*time.Millisecond)))
+	return nil
+}
+
+func (e *gifEncoder) finish(w io.Writer) error {
+	return gif.EncodeAll(w, &e.animation)
+}
+
+// frequentColors returns up to n colors of the image, the most frequent first.
+func frequentColors(img image.Image, n int) color.Palette {
+	counts := make(map[color.RGBA]int)
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			counts[color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)]++
+		}
+	}
+	colors := make([]color.RGBA, 0, len(counts))
+	for c := range counts {
+		colors = append(colors, c)
+	}
+	sort.Slice(colors, func(i, j int) bool {
+		if counts[colors[i]] != counts[colors[j]] {
+			return counts[colors[i]] > counts[colors[j]]
+		}
+		a, b := colors[i], colors[j]
+		return uint32(a.R)<<24|uint32(a.G)<<16|uint32(a.B)<<8|uint32(a.A) < uint32(b.R)<<24|uint32(b.G)<<16|uint32(b.B)<<8|uint32(b.A)
+	})
+	palette := make(color.Palette, min(n, len(colors)))
+	for i := range palette {
+		palette[i] = colors[i]
+	}
+	return palette
+}
+
+// apngEncoder writes an animated PNG. Every frame is PNG-encoded on its own; its image data
+// becomes the IDAT chunk of the first frame or an fdAT chunk of the following frames.
+type apngEncoder struct {
+	header []byte   // IHDR data of the first frame
+	frames [][]byte // compressed image data of every frame
+	delays []time.Duration
+	width  int
+	height int
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+func (e *apngEncoder) add(img image.Image, delay time.Duration) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	data := buf.Bytes()[len(pngSignature):]
+	var imageData []byte
+	for len(data) >= 12 {
+		length := binary.BigEndian.Uint32(data[:4])
+		kind, body := string(data[4:8]), data[8:8+length]
+		switch kind {
+		case "IHDR":
+			if e.header == nil {
+				e.header = append([]byte(nil), body...)
+				e.width, e.height = img.Bounds().Dx(), img.Bounds().Dy()
+			}
+		case "IDAT":
+			imageData = append(imageData, body...)
+		}
+		data = data[12+length:]
+	}
+	if img.Bounds().Dx() != e.width || img.Bounds().Dy() != e.height {
+		return fmt.Errorf("animation frames must have the same size")
+	}
+	e.frames = append(e.frames, imageData)
+	e.delays = append(e.delays, delay)
+	return nil
+}
+
+func (e *apngEncoder) finish(w io.Writer) error {
+	if len(e.frames) == 0 {
+		return fmt.Errorf("no frames to encode")
+	}
+	if _, err := w.Write(pngSignature); err != nil {
+		return err
+	}
+	chunks := []struct {
+		kind string
+		data []byte
+	}{{"IHDR", e.header}}
+
+	control := make([]byte, 8) // acTL: number of frames, plays (0 loops forever)
+	binary.BigEndian.PutUint32(control, uint32(len(e.frames)))
+	chunks = append(chunks, struct {
+		kind string
+		data []byte
+	}{"acTL", control})
+
+	sequence := uint32(0)
+	for i, frame := range e.frames {
+		// fcTL: sequence, size, offset, delay as milliseconds, dispose and blend operations
+		fc := make([]byte, 26)
+		binary.BigEndian.PutUint32(fc[0:], sequence)
+		binary.BigEndian.PutUint32(fc[4:], uint32(e.width))
+		binary.BigEndian.PutUint32(fc[8:], uint32(e.height))
+		binary.BigEndian.PutUint16(fc[20:], uint16(min(e.delays[i].Milliseconds(), 65535)))
+		binary.BigEndian.PutUint16(fc[22:], 1000)
+		sequence++
+		chunks = append(chunks, struct {
+			kind string
+			data []byte
+		}{"fcTL", fc})
+
+		if i == 0 {
+			chunks = append(chunks, struct {
+				kind string
+				data []byte
+			}{"IDAT", frame})
+			continue
+		}
+		fd := make([]byte, 4, 4+len(frame))
+		binary.BigEndian.PutUint32(fd, sequence)
+		sequence++
+		chunks = append(chunks, struct {
+			kind string
+			data []byte
+		}{"fdAT", append(fd, frame...)})
+	}
+	chunks = append(chunks, struct {
+		kind string
+		data []byte
+	}{"IEND", nil})
+
+	for _, chunk := range chunks {
+		if err := writePNGChunk(w, chunk.kind, chunk.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePNGChunk writes a PNG chunk with its length and CRC.
+func writePNGChunk(w io.Writer, kind string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], kind)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+	for _, part := range [][]byte{header, data, footer} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
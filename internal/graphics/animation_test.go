@@ -0,0 +1,140 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"gonum.org/v1/plot"
+)
+
+func TestAnimationFrames(t *testing.T) {
+	tests := []struct {
+		points, maxFrames int
+		want              []int
+	}{
+		{points: 5, maxFrames: 100, want: []int{1, 2, 3, 4}},
+		{points: 11, maxFrames: 4, want: []int{1, 4, 7, 10}},
+		{points: 2, maxFrames: 10, want: []int{1}},
+		{points: 100, maxFrames: 1, want: []int{99}},
+	}
+	for _, tt := range tests {
+		if got := animationFrames(tt.points, tt.maxFrames); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("animationFrames(%d, %d) = %v, want %v", tt.points, tt.maxFrames, got, tt.want)
+		}
+	}
+}
+
+func TestAnimationFromConfig(t *testing.T) {
+	defer viper.Set("animate", "")
+	viper.Set("animation-fps", 10)
+
+	viper.Set("animate", "")
+	if config, err := AnimationFromConfig(); config != nil || err != nil {
+		t.Errorf("animations should be off by default, got %v, %v", config, err)
+	}
+	viper.Set("animate", "mp4")
+	if _, err := AnimationFromConfig(); err == nil {
+		t.Error("expected an error for an unknown animation format")
+	}
+	viper.Set("animate", "APNG")
+	if config, err := AnimationFromConfig(); err != nil || config.Format != AnimationAPNG {
+		t.Errorf("AnimationFromConfig() = %v, %v", config, err)
+	}
+	if got := AnimationPath("out/burndown.svg", AnimationAPNG); got != "out/burndown_animated.png" {
+		t.Errorf("AnimationPath() = %s", got)
+	}
+}
+
+// writeTestAnimation animates a two-band stacked burndown over five months
+func writeTestAnimation(t *testing.T, format string) string {
+	t.Helper()
+	viper.Set("size", "4,3")
+	defer viper.Set("size", "")
+
+	dates := make([]time.Time, 5)
+	for i := range dates {
+		dates[i] = time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+	}
+	matrix := [][]float64{{10, 9, 8, 7, 6}, {0, 5, 10, 15, 20}}
+	config := &AnimationConfig{Format: format, FPS: 5, MaxFrames: 3, DateFormat: "2006-01"}
+	path, err := config.AnimateStackedBurndown(matrix, dates, filepath.Join(t.TempDir(), "burndown.png"), false)
+	if err != nil {
+		t.Fatalf("AnimateStackedBurndown(%s) error = %v", format, err)
+	}
+	return path
+}
+
+func TestAnimateGIF(t *testing.T) {
+	file, err := os.Open(writeTestAnimation(t, AnimationGIF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	animation, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("invalid GIF: %v", err)
+	}
+	if len(animation.Image) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(animation.Image))
+	}
+	if animation.Delay[0] != 20 || animation.Delay[2] != 200 {
+		t.Errorf("expected 0.2s frames and a held last frame, got %v", animation.Delay)
+	}
+	if animation.Image[0].Bounds() != animation.Image[2].Bounds() {
+		t.Error("frames should have the same size")
+	}
+}
+
+func TestAnimateAPNG(t *testing.T) {
+	data, err := os.ReadFile(writeTestAnimation(t, AnimationAPNG))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Viewers without APNG support show the first frame
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("invalid PNG: %v", err)
+	}
+
+	counts := map[string]int{}
+	var order []string
+	for chunks := data[len(pngSignature):]; len(chunks) >= 12; {
+		length := binary.BigEndian.Uint32(chunks)
+		kind := string(chunks[4:8])
+		if kind == "acTL" && binary.BigEndian.Uint32(chunks[8:]) != 3 {
+			t.Errorf("acTL should announce 3 frames")
+		}
+		counts[kind]++
+		order = append(order, kind)
+		chunks = chunks[12+length:]
+	}
+	if counts["acTL"] != 1 || counts["fcTL"] != 3 || counts["fdAT"] == 0 || counts["IDAT"] == 0 {
+		t.Errorf("unexpected APNG chunks: %v", counts)
+	}
+	if order[0] != "IHDR" || order[1] != "acTL" || order[len(order)-1] != "IEND" {
+		t.Errorf("unexpected chunk order: %v", order)
+	}
+}
+
+func TestAnimationAnnotationsUntil(t *testing.T) {
+	saved := activeAnnotations
+	defer func() { activeAnnotations = saved }()
+	activeAnnotations = []Annotation{
+		{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Label: "v1"},
+		{Time: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Label: "v2"},
+	}
+	if got := annotationsUntil(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)); len(got) != 1 || got[0].Label != "v1" {
+		t.Errorf("annotationsUntil() = %v", got)
+	}
+	if _, err := (&AnimationConfig{Format: AnimationGIF, FPS: 1}).Write("x.png", nil, ChartTypeDefault, func(int) (*plot.Plot, error) { return plot.New(), nil }); err == nil {
+		t.Error("expected an error without time points")
+	}
+}
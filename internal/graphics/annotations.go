@@ -0,0 +1,313 @@
+package graphics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gopkg.in/yaml.v3"
+	"labours-go/internal/burndown"
+)
+
+// annotationLevels is the number of heights labels are staggered over so that
+// neighbouring markers do not overlap
+const annotationLevels = 4
+
+// annotationLabelGap is the minimal distance between two labels on the same level,
+// as a fraction of the X range
+const annotationLabelGap = 0.12
+
+// activeAnnotations are drawn on every time-based chart, see SetAnnotations
+var activeAnnotations []Annotation
+
+// annotationColors are the color names accepted in annotation files besides hex values
+var annotationColors = map[string]color.Color{
+	"black":  color.RGBA{A: 255},
+	"gray":   color.RGBA{R: 127, G: 127, B: 127, A: 255},
+	"grey":   color.RGBA{R: 127, G: 127, B: 127, A: 255},
+	"red":    color.RGBA{R: 214, G: 39, B: 40, A: 255},
+	"green":  color.RGBA{R: 44, G: 160, B: 44, A: 255},
+	"blue":   color.RGBA{R: 31, G: 119, B: 180, A: 255},
+	"orange": color.RGBA{R: 255, G: 127, B: 14, A: 255},
+	"purple": color.RGBA{R: 148, G: 103, B: 189, A: 255},
+	"brown":  color.RGBA{R: 140, G: 86, B: 75, A: 255},
+}
+
+// annotationDashes is the dash pattern of annotation marker lines
+var annotationDashes = []vg.Length{vg.Points(3), vg.Points(3)}
+
+// Annotation marks an event on a time axis
+type Annotation struct {
+	Time  time.Time
+	Label string
+	Color color.Color // nil uses the theme's text color
+}
+
+// annotationEntry is an annotation as written in an annotations file
+type annotationEntry struct {
+	Date  string `yaml:"date" json:"date"`
+	Label string `yaml:"label" json:"label"`
+	Color string `yaml:"color,omitempty" json:"color,omitempty"`
+}
+
+// MarshalJSON encodes the annotation in the annotations file format
+func (a Annotation) MarshalJSON() ([]byte, error) {
+	entry := annotationEntry{Date: a.Time.Format(time.RFC3339), Label: a.Label}
+	if a.Color != nil {
+		r, g, b, _ := a.Color.RGBA()
+		entry.Color = fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+	return json.Marshal(entry)
+}
+
+// SetAnnotations sets the annotations drawn on every time-based chart
+func SetAnnotations(annotations []Annotation) {
+	sorted := make([]Annotation, len(annotations))
+	copy(sorted, annotations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+	activeAnnotations = sorted
+}
+
+// ActiveAnnotations returns the annotations drawn on every time-based chart
+func ActiveAnnotations() []Annotation {
+	return activeAnnotations
+}
+
+// LoadAnnotations reads annotations from a YAML file
+func LoadAnnotations(path string) ([]Annotation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations file %s: %v", path, err)
+	}
+	annotations, err := ParseAnnotations(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse annotations file %s: %v", path, err)
+	}
+	return annotations, nil
+}
+
+// ParseAnnotations parses annotations in the format
+//
+//	annotations:
+//	  - date: 2023-01-15
+//	    label: v1.0
+//	    color: "#d62728"
+//
+// A plain list of entries without the "annotations" key is accepted as well.
+func ParseAnnotations(data []byte) ([]Annotation, error) {
+	var document struct {
+		Annotations []annotationEntry `yaml:"annotations"`
+	}
+	var entries []annotationEntry
+	if err := yaml.Unmarshal(data, &document); err == nil {
+		entries = document.Annotations
+	} else if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	annotations := make([]Annotation, 0, len(entries))
+	for i, entry := range entries {
+		if entry.Label == "" {
+			return nil, fmt.Errorf("annotation %d has no label", i+1)
+		}
+		date, err := parseAnnotationDate(entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %q: %v", entry.Label, err)
+		}
+		annotation := Annotation{Time: date, Label: entry.Label}
+		if entry.Color != "" {
+			if annotation.Color, err = parseAnnotationColor(entry.Color); err != nil {
+				return nil, fmt.Errorf("annotation %q: %v", entry.Label, err)
+			}
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, nil
+}
+
+// parseAnnotationDate accepts dates with or without a time of day.
+func parseAnnotationDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+}
+
+// parseAnnotationColor accepts "#rrggbb", "#rrggbbaa" and a few basic color names.
+func parseAnnotationColor(value string) (color.Color, error) {
+	if named, ok := annotationColors[strings.ToLower(value)]; ok {
+		return named, nil
+	}
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color %q, expected #rrggbb or a color name", value)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q, expected #rrggbb or a color name", value)
+	}
+	if len(hex) == 6 {
+		n = n<<8 | 0xff
+	}
+	return color.NRGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, nil
+}
+
+// AddTimeAnnotations draws a dashed vertical marker with a label for every annotation on a
+// plot whose X axis holds Unix timestamps. Call it after all data has been added so that
+// the markers span the final Y range; annotations outside the X range are skipped.
+func AddTimeAnnotations(p *plot.Plot, annotations []Annotation) error {
+	points := make([]float64, len(annotations))
+	for i, annotation := range annotations {
+		points[i] = float64(annotation.Time.Unix())
+	}
+	return addAnnotationMarkers(p, points, annotations)
+}
+
+// AddActiveAnnotations draws the annotations set with SetAnnotations on a plot whose X axis
+// holds Unix timestamps.
+func AddActiveAnnotations(p *plot.Plot) error {
+	if len(activeAnnotations) == 0 {
+		return nil
+	}
+	return AddTimeAnnotations(p, activeAnnotations)
+}
+
+// AddIndexedAnnotations draws the annotations set with SetAnnotations on a plot whose X axis
+// holds sample indexes: begin is mapped to first and end to last linearly.
+func AddIndexedAnnotations(p *plot.Plot, begin, end time.Time, first, last float64) error {
+	return AddIndexedTimeAnnotations(p, activeAnnotations, begin, end, first, last)
+}
+
+// AddIndexedTimeAnnotations draws annotations on a plot whose X axis holds sample indexes,
+// mapping begin to first and end to last linearly.
+func AddIndexedTimeAnnotations(p *plot.Plot, annotations []Annotation, begin, end time.Time, first, last float64) error {
+	if len(annotations) == 0 || !end.After(begin) {
+		return nil
+	}
+	span := end.Sub(begin).Seconds()
+	xs := make([]float64, len(annotations))
+	for i, annotation := range annotations {
+		xs[i] = first + annotation.Time.Sub(begin).Seconds()/span*(last-first)
+	}
+	return addAnnotationMarkers(p, xs, annotations)
+}
+
+// addAnnotationMarkers draws the annotations at the given X positions. Labels are placed on
+// the highest level that has no label too close to the left of them.
+func addAnnotationMarkers(p *plot.Plot, xs []float64, annotations []Annotation) error {
+	yMin, yMax := p.Y.Min, p.Y.Max
+	if yMax <= yMin {
+		yMax = yMin + 1
+	}
+
+	order := make([]int, len(annotations))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return xs[order[i]] < xs[order[j]] })
+
+	stagger := labelStagger{minGap: (p.X.Max - p.X.Min) * annotationLabelGap}
+
+	var labels plotter.XYLabels
+	var labelColors []color.Color
+	for _, i := range order {
+		annotation := annotations[i]
+		x := xs[i]
+		if x < p.X.Min || x > p.X.Max {
+			continue
+		}
+
+		markerColor := annotation.Color
+		if markerColor == nil {
+			markerColor = CurrentTheme.Text.Color.ToColor()
+		}
+
+		line, err := plotter.NewLine(plotter.XYs{{X: x, Y: yMin}, {X: x, Y: yMax}})
+		if err != nil {
+			return fmt.Errorf("error creating annotation marker: %v", err)
+		}
+		line.Color = markerColor
+		line.Width = vg.Points(1)
+		line.Dashes = annotationDashes
+		p.Add(line)
+
+		// Stagger labels from the top of the chart downwards
+		y := yMax - (yMax-yMin)*0.04*float64(stagger.level(x))
+		labels.XYs = append(labels.XYs, plotter.XY{X: x, Y: y})
+		labels.Labels = append(labels.Labels, annotation.Label)
+		labelColors = append(labelColors, markerColor)
+	}
+
+	if len(labels.Labels) == 0 {
+		return nil
+	}
+	l, err := plotter.NewLabels(labels)
+	if err != nil {
+		return fmt.Errorf("error creating annotation labels: %v", err)
+	}
+	for i := range l.TextStyle {
+		l.TextStyle[i].Color = labelColors[i]
+		l.TextStyle[i].Font.Size = vg.Points(CurrentTheme.Text.Size * 0.8)
+		l.TextStyle[i].XAlign = draw.XLeft
+		l.TextStyle[i].YAlign = draw.YTop
+	}
+	l.Offset = vg.Point{X: vg.Points(2)}
+	p.Add(l)
+	return nil
+}
+
+// labelStagger assigns label levels to markers visited from left to right
+type labelStagger struct {
+	minGap float64
+	ends   [annotationLevels]float64
+	used   [annotationLevels]bool
+}
+
+// level returns the highest level without a label closer than minGap to the left of x;
+// when every level is crowded, the one whose last label is furthest to the left is reused.
+func (s *labelStagger) level(x float64) int {
+	level := 0
+	for level < annotationLevels && s.used[level] && x-s.ends[level] < s.minGap {
+		level++
+	}
+	if level == annotationLevels {
+		level = 0
+		for l := 1; l < annotationLevels; l++ {
+			if s.ends[l] < s.ends[level] {
+				level = l
+			}
+		}
+	}
+	s.ends[level], s.used[level] = x, true
+	return level
+}
+
+// PlotBurndownWithAnnotations draws the Python-style burndown chart with annotation markers
+// and adds it to the report content of ctx
+func PlotBurndownWithAnnotations(ctx context.Context, data *burndown.ProcessedBurndown, annotations []Annotation, output string, relative bool) error {
+	p, err := buildBurndownPythonPlot(data, relative)
+	if err != nil {
+		return err
+	}
+	if err := AddTimeAnnotations(p, append(append([]Annotation(nil), annotations...), activeAnnotations...)); err != nil {
+		return err
+	}
+	AddReportChart(ctx, p)
+
+	width, height := GetPlotSize(ChartTypeDefault)
+	return SavePlotWithFormat(p, width, height, output)
+}
@@ -0,0 +1,134 @@
+package graphics
+
+import (
+	"context"
+	"encoding/json"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	data := []byte(`
+annotations:
+  - date: 2023-03-01
+    label: v1.0
+    color: "#d62728"
+  - date: 2023-06-15T12:00:00Z
+    label: Migration to Go modules
+    color: green
+  - date: 2023-09-01
+    label: v2.0
+`)
+	annotations, err := ParseAnnotations(data)
+	if err != nil {
+		t.Fatalf("ParseAnnotations() error = %v", err)
+	}
+	if len(annotations) != 3 {
+		t.Fatalf("expected 3 annotations, got %d", len(annotations))
+	}
+	if !annotations[0].Time.Equal(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)) || annotations[0].Label != "v1.0" {
+		t.Errorf("unexpected first annotation %+v", annotations[0])
+	}
+	if r, g, b, _ := annotations[0].Color.RGBA(); r>>8 != 0xd6 || g>>8 != 0x27 || b>>8 != 0x28 {
+		t.Errorf("hex color parsed incorrectly: %v", annotations[0].Color)
+	}
+	if annotations[1].Color != annotationColors["green"] {
+		t.Errorf("named color parsed incorrectly: %v", annotations[1].Color)
+	}
+	if annotations[2].Color != nil {
+		t.Errorf("annotation without color should use the theme color, got %v", annotations[2].Color)
+	}
+
+	// A plain list is accepted as well
+	list, err := ParseAnnotations([]byte("- date: 2023-01-01\n  label: start\n"))
+	if err != nil || len(list) != 1 {
+		t.Errorf("expected one annotation from a plain list, got %v, %v", list, err)
+	}
+
+	for _, invalid := range []string{
+		"annotations:\n  - date: 2023-01-01\n",
+		"annotations:\n  - date: yesterday\n    label: x\n",
+		"annotations:\n  - date: 2023-01-01\n    label: x\n    color: '#12'\n",
+	} {
+		if _, err := ParseAnnotations([]byte(invalid)); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestAnnotationJSON(t *testing.T) {
+	annotation := Annotation{
+		Time:  time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+		Label: "v1.0",
+		Color: color.RGBA{R: 214, G: 39, B: 40, A: 255},
+	}
+	data, err := json.Marshal(annotation)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	expected := `{"date":"2023-03-01T00:00:00Z","label":"v1.0","color":"#d62728"}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestActiveAnnotationsOnCharts(t *testing.T) {
+	defer SetAnnotations(nil)
+	begin := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetAnnotations([]Annotation{
+		{Time: begin.AddDate(0, 6, 0), Label: "v2.0"},
+		{Time: begin.AddDate(0, 2, 0), Label: "v1.0"},
+		{Time: begin.AddDate(0, 2, 1), Label: "v1.0.1"},
+		{Time: begin.AddDate(5, 0, 0), Label: "outside"},
+	})
+	if labels := ActiveAnnotations(); labels[0].Label != "v1.0" || labels[3].Label != "outside" {
+		t.Errorf("annotations should be sorted by time, got %+v", labels)
+	}
+
+	dates := make([]time.Time, 12)
+	matrix := [][]float64{make([]float64, 12), make([]float64, 12)}
+	for i := range dates {
+		dates[i] = begin.AddDate(0, i, 0)
+		matrix[0][i] = float64(100 + i)
+		matrix[1][i] = float64(10 * i)
+	}
+	output := filepath.Join(t.TempDir(), "stacked.png")
+	if err := PlotStackedBurndown(context.Background(), matrix, dates, output, false); err != nil {
+		t.Fatalf("PlotStackedBurndown() error = %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected chart %s: %v", output, err)
+	}
+
+	// Index axes map the period linearly
+	p := plot.New()
+	line, _ := plotter.NewLine(plotter.XYs{{X: 0, Y: 0}, {X: 11, Y: 10}})
+	p.Add(line)
+	if err := AddIndexedAnnotations(p, begin, dates[11], 0, 11); err != nil {
+		t.Fatalf("AddIndexedAnnotations() error = %v", err)
+	}
+	output = filepath.Join(t.TempDir(), "indexed.png")
+	if err := SavePlotWithFormat(p, 400, 300, output); err != nil {
+		t.Fatalf("SavePlotWithFormat() error = %v", err)
+	}
+}
+
+func TestLabelStagger(t *testing.T) {
+	stagger := labelStagger{minGap: 10}
+	var levels []int
+	for _, x := range []float64{0, 2, 4, 15, 16, 17, 18, 19, 40} {
+		levels = append(levels, stagger.level(x))
+	}
+	expected := []int{0, 1, 2, 0, 1, 2, 3, 0, 0}
+	for i := range expected {
+		if levels[i] != expected[i] {
+			t.Fatalf("expected levels %v, got %v", expected, levels)
+		}
+	}
+}
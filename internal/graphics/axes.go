@@ -0,0 +1,238 @@
+package graphics
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
+)
+
+// Smoothing kinds accepted by --smooth
+const (
+	SmoothNone    = ""
+	SmoothRolling = "rolling"
+	SmoothEWM     = "ewm"
+)
+
+// activeAxes are applied to every time series chart, see SetAxisOptions
+var activeAxes AxisOptions
+
+// AxisOptions are the scale, limits and smoothing of time series charts
+type AxisOptions struct {
+	LogY       bool
+	YMin, YMax *float64  // nil leaves the limit to the data
+	XMin, XMax time.Time // zero leaves the limit to the data
+	Smooth     Smoothing
+}
+
+// Smoothing is a trailing rolling mean over Window samples or an exponentially weighted
+// mean with factor Alpha. Both only look back so that animation frames agree with the
+// final chart.
+type Smoothing struct {
+	Kind   string
+	Window int
+	Alpha  float64
+}
+
+// ParseAxisOptions parses the --log-y, --y-min, --y-max, --x-range and --smooth values.
+// Empty limits are left to the data; the X range is "from:to" with either side optional,
+// e.g. "2020-01-01:" or "2020-01-01:2021-06-30".
+func ParseAxisOptions(logY bool, yMin, yMax, xRange, smooth string) (AxisOptions, error) {
+	options := AxisOptions{LogY: logY}
+	var err error
+	if options.YMin, err = parseLimit("y-min", yMin); err != nil {
+		return AxisOptions{}, err
+	}
+	if options.YMax, err = parseLimit("y-max", yMax); err != nil {
+		return AxisOptions{}, err
+	}
+	if options.YMin != nil && options.YMax != nil && *options.YMin >= *options.YMax {
+		return AxisOptions{}, fmt.Errorf("y-min %v must be below y-max %v", *options.YMin, *options.YMax)
+	}
+	if logY && options.YMin != nil && *options.YMin <= 0 {
+		return AxisOptions{}, fmt.Errorf("y-min must be positive on a logarithmic axis, got %v", *options.YMin)
+	}
+	if options.XMin, options.XMax, err = parseTimeRange(xRange); err != nil {
+		return AxisOptions{}, err
+	}
+	if options.Smooth, err = ParseSmoothing(smooth); err != nil {
+		return AxisOptions{}, err
+	}
+	return options, nil
+}
+
+func parseLimit(name, value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	limit, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(limit) || math.IsInf(limit, 0) {
+		return nil, fmt.Errorf("invalid %s '%s', expected a number", name, value)
+	}
+	return &limit, nil
+}
+
+func parseTimeRange(value string) (time.Time, time.Time, error) {
+	if value == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+	from, to, ok := strings.Cut(value, ":")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid x-range '%s', expected from:to, e.g. 2020-01-01:2021-06-30", value)
+	}
+	var bounds [2]time.Time
+	for i, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid x-range date '%s', expected YYYY-MM-DD", date)
+		}
+		bounds[i] = parsed
+	}
+	if !bounds[0].IsZero() && !bounds[1].IsZero() && !bounds[1].After(bounds[0]) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid x-range '%s', the end must be after the start", value)
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// ParseSmoothing parses "rolling:N", "ewm:alpha" or "none".
+func ParseSmoothing(value string) (Smoothing, error) {
+	if value == "" || value == "none" {
+		return Smoothing{}, nil
+	}
+	kind, param, _ := strings.Cut(value, ":")
+	switch kind {
+	case SmoothRolling:
+		window, err := strconv.Atoi(param)
+		if err != nil || window < 1 {
+			return Smoothing{}, fmt.Errorf("invalid smoothing '%s', expected rolling:N with N >= 1", value)
+		}
+		return Smoothing{Kind: SmoothRolling, Window: window}, nil
+	case SmoothEWM:
+		alpha, err := strconv.ParseFloat(param, 64)
+		if err != nil || alpha <= 0 || alpha > 1 {
+			return Smoothing{}, fmt.Errorf("invalid smoothing '%s', expected ewm:alpha with 0 < alpha <= 1", value)
+		}
+		return Smoothing{Kind: SmoothEWM, Alpha: alpha}, nil
+	}
+	return Smoothing{}, fmt.Errorf("unknown smoothing '%s', available: rolling:N, ewm:alpha, none", value)
+}
+
+// SetAxisOptions sets the axis options applied to every time series chart
+func SetAxisOptions(options AxisOptions) {
+	activeAxes = options
+}
+
+// Apply returns the smoothed series; the input is not modified.
+func (s Smoothing) Apply(values []float64) []float64 {
+	smoothed := make([]float64, len(values))
+	switch s.Kind {
+	case SmoothRolling:
+		sum := 0.0
+		for i, v := range values {
+			sum += v
+			if i >= s.Window {
+				sum -= values[i-s.Window]
+			}
+			smoothed[i] = sum / float64(min(i+1, s.Window))
+		}
+	case SmoothEWM:
+		for i, v := range values {
+			if i == 0 {
+				smoothed[i] = v
+			} else {
+				smoothed[i] = s.Alpha*v + (1-s.Alpha)*smoothed[i-1]
+			}
+		}
+	default:
+		copy(smoothed, values)
+	}
+	return smoothed
+}
+
+// SmoothSeries smooths a series with the active smoothing
+func SmoothSeries(values []float64) []float64 {
+	return activeAxes.Smooth.Apply(values)
+}
+
+// SmoothRows smooths every row of a matrix with the active smoothing
+func SmoothRows(matrix [][]float64) [][]float64 {
+	if activeAxes.Smooth.Kind == SmoothNone {
+		return matrix
+	}
+	smoothed := make([][]float64, len(matrix))
+	for i, row := range matrix {
+		smoothed[i] = activeAxes.Smooth.Apply(row)
+	}
+	return smoothed
+}
+
+// ApplyTimeAxes applies the active axis options to a plot whose X axis holds Unix
+// timestamps. Call it after all data has been added.
+func ApplyTimeAxes(p *plot.Plot) {
+	if !activeAxes.XMin.IsZero() {
+		p.X.Min = float64(activeAxes.XMin.Unix())
+	}
+	if !activeAxes.XMax.IsZero() {
+		p.X.Max = float64(activeAxes.XMax.Unix())
+	}
+	applyYAxis(p)
+}
+
+// ApplyIndexedAxes applies the active axis options to a plot whose X axis holds sample
+// indexes: begin is mapped to first and end to last linearly.
+func ApplyIndexedAxes(p *plot.Plot, begin, end time.Time, first, last float64) {
+	if end.After(begin) {
+		span := end.Sub(begin).Seconds()
+		index := func(t time.Time) float64 {
+			return first + t.Sub(begin).Seconds()/span*(last-first)
+		}
+		if !activeAxes.XMin.IsZero() {
+			p.X.Min = index(activeAxes.XMin)
+		}
+		if !activeAxes.XMax.IsZero() {
+			p.X.Max = index(activeAxes.XMax)
+		}
+	}
+	applyYAxis(p)
+}
+
+func applyYAxis(p *plot.Plot) {
+	if activeAxes.YMin != nil {
+		p.Y.Min = *activeAxes.YMin
+	}
+	if activeAxes.YMax != nil {
+		p.Y.Max = *activeAxes.YMax
+	}
+	if !activeAxes.LogY {
+		return
+	}
+	// Stacked areas start at zero, which has no place on a logarithmic axis: values
+	// below the floor are drawn at the floor
+	floor := 1.0
+	if activeAxes.YMin != nil {
+		floor = *activeAxes.YMin
+	} else if p.Y.Max > 0 && p.Y.Max < 10 {
+		floor = p.Y.Max / 1000 // relative charts
+	}
+	p.Y.Min = max(p.Y.Min, floor)
+	if p.Y.Max <= p.Y.Min {
+		p.Y.Max = p.Y.Min * 10
+	}
+	p.Y.Scale = floorLogScale{floor: floor}
+	p.Y.Tick.Marker = plot.LogTicks{Prec: -1}
+}
+
+// floorLogScale is a logarithmic scale that maps values below floor to floor
+type floorLogScale struct {
+	floor float64
+}
+
+func (s floorLogScale) Normalize(min, max, x float64) float64 {
+	return plot.LogScale{}.Normalize(math.Max(min, s.floor), math.Max(max, s.floor), math.Max(x, s.floor))
+}
@@ -0,0 +1,95 @@
+package graphics
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gonum.org/v1/plot/vg"
+)
+
+func TestParseAxisOptions(t *testing.T) {
+	options, err := ParseAxisOptions(true, "10", "", "2020-01-01:", "rolling:4")
+	if err != nil {
+		t.Fatalf("ParseAxisOptions() error = %v", err)
+	}
+	if options.YMin == nil || *options.YMin != 10 || options.YMax != nil {
+		t.Errorf("unexpected Y limits %v, %v", options.YMin, options.YMax)
+	}
+	if !options.XMin.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) || !options.XMax.IsZero() {
+		t.Errorf("unexpected X range %v - %v", options.XMin, options.XMax)
+	}
+	if options.Smooth != (Smoothing{Kind: SmoothRolling, Window: 4}) {
+		t.Errorf("unexpected smoothing %+v", options.Smooth)
+	}
+
+	for _, invalid := range [][5]string{
+		{"", "abc", "", "", ""},
+		{"", "5", "1", "", ""},
+		{"log", "0", "", "", ""},
+		{"", "", "", "2020-01-01", ""},
+		{"", "", "", "2021-01-01:2020-01-01", ""},
+		{"", "", "", "", "rolling:0"},
+		{"", "", "", "", "ewm:1.5"},
+		{"", "", "", "", "median:3"},
+	} {
+		if _, err := ParseAxisOptions(invalid[0] == "log", invalid[1], invalid[2], invalid[3], invalid[4]); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestSmoothing(t *testing.T) {
+	values := []float64{0, 10, 0, 10, 0}
+
+	rolling := Smoothing{Kind: SmoothRolling, Window: 2}.Apply(values)
+	for i, want := range []float64{0, 5, 5, 5, 5} {
+		if rolling[i] != want {
+			t.Errorf("rolling[%d] = %v, want %v", i, rolling[i], want)
+		}
+	}
+
+	ewm := Smoothing{Kind: SmoothEWM, Alpha: 0.5}.Apply(values)
+	for i, want := range []float64{0, 5, 2.5, 6.25, 3.125} {
+		if math.Abs(ewm[i]-want) > 1e-9 {
+			t.Errorf("ewm[%d] = %v, want %v", i, ewm[i], want)
+		}
+	}
+
+	if values[1] != 10 {
+		t.Error("smoothing must not modify its input")
+	}
+}
+
+func TestApplyTimeAxesLogScale(t *testing.T) {
+	yMax := 5000.0
+	SetAxisOptions(AxisOptions{LogY: true, YMax: &yMax, XMin: time.Unix(150, 0)})
+	defer SetAxisOptions(AxisOptions{})
+
+	// The line starts at zero, which the log scale has to draw at its floor
+	p := linePlot(t, 100, 200)
+	p.Y.Max = 1000
+	ApplyTimeAxes(p)
+	if p.X.Min != 150 || p.X.Max != 200 {
+		t.Errorf("unexpected X range [%v, %v]", p.X.Min, p.X.Max)
+	}
+	if p.Y.Min != 1 || p.Y.Max != 5000 {
+		t.Errorf("unexpected Y range [%v, %v]", p.Y.Min, p.Y.Max)
+	}
+	if err := p.Save(4*vg.Inch, 3*vg.Inch, filepath.Join(t.TempDir(), "log.png")); err != nil {
+		t.Fatalf("failed to draw a log scale chart: %v", err)
+	}
+}
+
+func TestApplyIndexedAxes(t *testing.T) {
+	begin := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetAxisOptions(AxisOptions{XMax: begin.AddDate(0, 0, 50)})
+	defer SetAxisOptions(AxisOptions{})
+
+	p := linePlot(t, 0, 100)
+	ApplyIndexedAxes(p, begin, begin.AddDate(0, 0, 100), 0, 100)
+	if p.X.Min != 0 || p.X.Max != 50 {
+		t.Errorf("unexpected X range [%v, %v]", p.X.Min, p.X.Max)
+	}
+}
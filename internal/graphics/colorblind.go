@@ -0,0 +1,220 @@
+package graphics
+
+import (
+	"image/color"
+	"math"
+)
+
+// Vision types for which theme colors are checked
+const (
+	VisionNormal       = "normal"
+	VisionProtanopia   = "protanopia"
+	VisionDeuteranopia = "deuteranopia"
+	VisionTritanopia   = "tritanopia"
+)
+
+// VisionTypes lists the vision types in report order
+var VisionTypes = []string{VisionNormal, VisionProtanopia, VisionDeuteranopia, VisionTritanopia}
+
+// cvdMatrices are the full-severity simulation matrices of Machado, Oliveira and Fernandes
+// (2009), applied to linear RGB.
+var cvdMatrices = map[string][3][3]float64{
+	VisionProtanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	VisionDeuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	VisionTritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// LabColor is a color in the CIE L*a*b* space (D65 white point)
+type LabColor struct {
+	L, A, B float64
+}
+
+// ColorDistanceReport is the smallest distance between any two colors as seen with a vision type
+type ColorDistanceReport struct {
+	Vision      string
+	MinDistance float64 // CIEDE2000 distance of the closest pair
+	First       int     // index of the first color of the closest pair
+	Second      int     // index of the second color of the closest pair
+}
+
+// SimulateColorVision returns c as perceived with the given vision type.
+// Unknown vision types and VisionNormal return c unchanged.
+func SimulateColorVision(c ColorRGB, vision string) ColorRGB {
+	matrix, ok := cvdMatrices[vision]
+	if !ok {
+		return c
+	}
+	linear := [3]float64{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)}
+	var simulated [3]uint8
+	for row := range matrix {
+		value := 0.0
+		for col := range linear {
+			value += matrix[row][col] * linear[col]
+		}
+		simulated[row] = linearToSRGB(value)
+	}
+	return ColorRGB{R: simulated[0], G: simulated[1], B: simulated[2], A: c.A}
+}
+
+// ToLab converts an sRGB color to CIE L*a*b*, ignoring alpha.
+func (c ColorRGB) ToLab() LabColor {
+	r, g, b := srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*b) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*b) / 1.08883
+
+	f := func(t float64) float64 {
+		const delta = 6.0 / 29.0
+		if t > delta*delta*delta {
+			return math.Cbrt(t)
+		}
+		return t/(3*delta*delta) + 4.0/29.0
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return LabColor{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+// CIEDE2000 returns the CIEDE2000 color difference between two Lab colors.
+// A difference below about 2 is hard to notice, charts need considerably more.
+func CIEDE2000(first, second LabColor) float64 {
+	const pow25to7 = 6103515625.0 // 25^7
+
+	c1 := math.Hypot(first.A, first.B)
+	c2 := math.Hypot(second.A, second.B)
+	meanC7 := math.Pow((c1+c2)/2, 7)
+	g := 0.5 * (1 - math.Sqrt(meanC7/(meanC7+pow25to7)))
+
+	a1, a2 := (1+g)*first.A, (1+g)*second.A
+	c1p, c2p := math.Hypot(a1, first.B), math.Hypot(a2, second.B)
+	h1p, h2p := hueDegrees(first.B, a1), hueDegrees(second.B, a2)
+
+	deltaL := second.L - first.L
+	deltaC := c2p - c1p
+	deltaH := 0.0
+	if c1p*c2p != 0 {
+		dh := h2p - h1p
+		if dh > 180 {
+			dh -= 360
+		} else if dh < -180 {
+			dh += 360
+		}
+		deltaH = 2 * math.Sqrt(c1p*c2p) * math.Sin(radians(dh/2))
+	}
+
+	meanL := (first.L + second.L) / 2
+	meanCp := (c1p + c2p) / 2
+	meanH := h1p + h2p
+	if c1p*c2p != 0 {
+		switch {
+		case math.Abs(h1p-h2p) <= 180:
+			meanH /= 2
+		case meanH < 360:
+			meanH = (meanH + 360) / 2
+		default:
+			meanH = (meanH - 360) / 2
+		}
+	}
+
+	t := 1 - 0.17*math.Cos(radians(meanH-30)) + 0.24*math.Cos(radians(2*meanH)) +
+		0.32*math.Cos(radians(3*meanH+6)) - 0.20*math.Cos(radians(4*meanH-63))
+	deltaTheta := 30 * math.Exp(-math.Pow((meanH-275)/25, 2))
+	meanCp7 := math.Pow(meanCp, 7)
+	rc := 2 * math.Sqrt(meanCp7/(meanCp7+pow25to7))
+	lightness := (meanL - 50) * (meanL - 50)
+	sl := 1 + 0.015*lightness/math.Sqrt(20+lightness)
+	sc := 1 + 0.045*meanCp
+	sh := 1 + 0.015*meanCp*t
+	rt := -math.Sin(radians(2*deltaTheta)) * rc
+
+	l, c, h := deltaL/sl, deltaC/sc, deltaH/sh
+	return math.Sqrt(l*l + c*c + h*h + rt*c*h)
+}
+
+// CheckColorDistances reports the closest pair of colors for every vision type.
+// Fewer than two colors cannot be confused and report an infinite distance.
+func CheckColorDistances(colors []ColorRGB) []ColorDistanceReport {
+	reports := make([]ColorDistanceReport, len(VisionTypes))
+	for i, vision := range VisionTypes {
+		report := ColorDistanceReport{Vision: vision, MinDistance: math.Inf(1), First: -1, Second: -1}
+		lab := make([]LabColor, len(colors))
+		for j, c := range colors {
+			lab[j] = SimulateColorVision(c, vision).ToLab()
+		}
+		for j := range lab {
+			for k := j + 1; k < len(lab); k++ {
+				if distance := CIEDE2000(lab[j], lab[k]); distance < report.MinDistance {
+					report.MinDistance, report.First, report.Second = distance, j, k
+				}
+			}
+		}
+		reports[i] = report
+	}
+	return reports
+}
+
+// CheckBandColors reports the color distances of a chart with n bands as they are drawn:
+// the band colors blended over the background at the theme's fill opacity.
+func (t *Theme) CheckBandColors(n int) []ColorDistanceReport {
+	background := t.Background
+	bands := t.BandColors(n)
+	colors := make([]ColorRGB, len(bands))
+	for i, band := range bands {
+		colors[i] = blendOver(band, background, t.Chart.FillOpacity)
+	}
+	return CheckColorDistances(colors)
+}
+
+// blendOver composites c with the given opacity over an opaque background.
+func blendOver(c color.Color, background ColorRGB, opacity float64) ColorRGB {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	mix := func(fg, bg uint8) uint8 {
+		return uint8(opacity*float64(fg) + (1-opacity)*float64(bg) + 0.5)
+	}
+	return ColorRGB{R: mix(nrgba.R, background.R), G: mix(nrgba.G, background.G), B: mix(nrgba.B, background.B), A: 255}
+}
+
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) uint8 {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return uint8(v*255 + 0.5)
+}
+
+// hueDegrees returns the hue angle in [0, 360).
+func hueDegrees(b, a float64) float64 {
+	if a == 0 && b == 0 {
+		return 0
+	}
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
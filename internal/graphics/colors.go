@@ -55,6 +55,11 @@ func GetMatplotlibBurndownColors(opacity uint8) []color.Color {
 func GetBurndownColors(numColors int) []color.Color {
 	opacity := uint8(float64(255) * CurrentTheme.Chart.FillOpacity)
 	
+	// Named and colorblind-safe palettes take precedence over the theme colors
+	if colors, ok := CurrentTheme.paletteBandColorsWithOpacity(numColors, opacity); ok {
+		return colors
+	}
+	
 	// Use matplotlib colors for 2-layer burndown when matplotlib theme is active
 	if numColors == 2 && CurrentTheme.Name == "matplotlib" {
 		return GetMatplotlibBurndownColors(opacity)
@@ -0,0 +1,267 @@
+package graphics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gopkg.in/yaml.v3"
+)
+
+// Default size of a dashboard grid cell
+const (
+	dashboardCellWidth  = 8 * vg.Inch
+	dashboardCellHeight = 5 * vg.Inch
+	dashboardPadding    = 12 // points between and around cells
+)
+
+// DashboardLayout describes charts of several modes composed into one image
+type DashboardLayout struct {
+	Title  string  `yaml:"title,omitempty"`
+	Rows   int     `yaml:"rows,omitempty"`
+	Cols   int     `yaml:"cols,omitempty"`
+	Width  float64 `yaml:"width,omitempty"`  // inches, 8 per column by default
+	Height float64 `yaml:"height,omitempty"` // inches, 5 per row by default
+	// SharedTimeAxis gives all time-based cells the same date range (default true)
+	SharedTimeAxis *bool           `yaml:"shared_time_axis,omitempty"`
+	Cells          []DashboardCell `yaml:"cells"`
+}
+
+// DashboardCell places the chart of a mode on the dashboard grid. Rows and columns
+// count from 1; cells without a position fill the next free slot row by row.
+type DashboardCell struct {
+	Mode    string `yaml:"mode"`
+	Title   string `yaml:"title,omitempty"`
+	Row     int    `yaml:"row,omitempty"`
+	Col     int    `yaml:"col,omitempty"`
+	RowSpan int    `yaml:"rowspan,omitempty"`
+	ColSpan int    `yaml:"colspan,omitempty"`
+}
+
+// DashboardPanel is the chart drawn into a dashboard cell
+type DashboardPanel struct {
+	Plot     *plot.Plot
+	TimeAxis bool // the X axis holds Unix timestamps
+}
+
+// LoadDashboardLayout reads a dashboard layout from a YAML file
+func LoadDashboardLayout(path string) (*DashboardLayout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboard layout %s: %v", path, err)
+	}
+	layout, err := ParseDashboardLayout(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dashboard layout %s: %v", path, err)
+	}
+	return layout, nil
+}
+
+// ParseDashboardLayout parses a layout in the format
+//
+//	title: Weekly overview
+//	rows: 2
+//	cols: 2
+//	cells:
+//	  - mode: burndown-project
+//	    colspan: 2
+//	  - mode: ownership
+//	  - mode: languages
+//	    title: Languages
+//
+// and places every cell on the grid.
+func ParseDashboardLayout(data []byte) (*DashboardLayout, error) {
+	var layout DashboardLayout
+	if err := yaml.Unmarshal(data, &layout); err != nil {
+		return nil, err
+	}
+	if err := layout.place(); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// place fills in the grid size, spans and positions of the cells and checks that the
+// cells fit the grid without overlapping.
+func (l *DashboardLayout) place() error {
+	if len(l.Cells) == 0 {
+		return fmt.Errorf("the layout has no cells")
+	}
+	if l.Rows < 0 || l.Cols < 0 {
+		return fmt.Errorf("rows and cols must not be negative")
+	}
+	if l.Cols == 0 {
+		l.Cols = min(2, len(l.Cells))
+	}
+	if l.Rows == 0 {
+		cells := 0
+		for _, cell := range l.Cells {
+			cells += max(cell.RowSpan, 1) * max(cell.ColSpan, 1)
+		}
+		l.Rows = (cells + l.Cols - 1) / l.Cols
+	}
+
+	used := make([][]bool, l.Rows)
+	for i := range used {
+		used[i] = make([]bool, l.Cols)
+	}
+	free := func(row, col, rows, cols int) bool {
+		if row < 1 || col < 1 || row+rows-1 > l.Rows || col+cols-1 > l.Cols {
+			return false
+		}
+		for r := row - 1; r < row-1+rows; r++ {
+			for c := col - 1; c < col-1+cols; c++ {
+				if used[r][c] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	// Cells with a position are placed first so that the others fill the gaps
+	for pass := 0; pass < 2; pass++ {
+		for i := range l.Cells {
+			cell := &l.Cells[i]
+			if cell.Mode == "" {
+				return fmt.Errorf("cell %d has no mode", i+1)
+			}
+			positioned := cell.Row != 0 || cell.Col != 0
+			if (pass == 0) != positioned {
+				continue
+			}
+			cell.RowSpan, cell.ColSpan = max(cell.RowSpan, 1), max(cell.ColSpan, 1)
+			if positioned {
+				cell.Row, cell.Col = max(cell.Row, 1), max(cell.Col, 1)
+				if !free(cell.Row, cell.Col, cell.RowSpan, cell.ColSpan) {
+					return fmt.Errorf("cell %d (%s) at row %d, col %d does not fit the %dx%d grid or overlaps another cell",
+						i+1, cell.Mode, cell.Row, cell.Col, l.Rows, l.Cols)
+				}
+			} else {
+				found := false
+				for slot := 0; slot < l.Rows*l.Cols && !found; slot++ {
+					if free(slot/l.Cols+1, slot%l.Cols+1, cell.RowSpan, cell.ColSpan) {
+						cell.Row, cell.Col, found = slot/l.Cols+1, slot%l.Cols+1, true
+					}
+				}
+				if !found {
+					return fmt.Errorf("no room left for cell %d (%s) in the %dx%d grid", i+1, cell.Mode, l.Rows, l.Cols)
+				}
+			}
+			for r := cell.Row - 1; r < cell.Row-1+cell.RowSpan; r++ {
+				for c := cell.Col - 1; c < cell.Col-1+cell.ColSpan; c++ {
+					used[r][c] = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ComposeDashboard draws the panels into the cells of the layout, panels[i] into
+// layout.Cells[i], and saves the dashboard as PNG, SVG or PDF depending on the extension
+// of output. Time-based panels share their date range unless disabled in the layout and
+// show the active annotations.
+func ComposeDashboard(layout *DashboardLayout, panels []DashboardPanel, output string) error {
+	if len(panels) != len(layout.Cells) {
+		return fmt.Errorf("expected %d dashboard panels, got %d", len(layout.Cells), len(panels))
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(output)), ".")
+	switch format {
+	case "":
+		format, output = "png", output+".png"
+	case "png", "svg", "pdf":
+	default:
+		return fmt.Errorf("unsupported dashboard format: %s. Supported formats: PNG, SVG, PDF", format)
+	}
+
+	if layout.SharedTimeAxis == nil || *layout.SharedTimeAxis {
+		alignTimeAxes(panels)
+	}
+	for i, panel := range panels {
+		if title := layout.Cells[i].Title; title != "" {
+			panel.Plot.Title.Text = title
+		}
+		if panel.TimeAxis {
+			if err := AddActiveAnnotations(panel.Plot); err != nil {
+				return err
+			}
+		}
+	}
+
+	width, height := dashboardCellWidth*vg.Length(layout.Cols), dashboardCellHeight*vg.Length(layout.Rows)
+	if layout.Width > 0 {
+		width = vg.Length(layout.Width) * vg.Inch
+	}
+	if layout.Height > 0 {
+		height = vg.Length(layout.Height) * vg.Inch
+	}
+	canvas, err := draw.NewFormattedCanvas(width, height, format)
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard canvas: %v", err)
+	}
+	dc := draw.New(canvas)
+	dc.SetColor(CurrentTheme.Background.ToColor())
+	dc.Fill(dc.Rectangle.Path())
+
+	padding := vg.Points(dashboardPadding)
+	if layout.Title != "" {
+		style := plot.New().Title.TextStyle
+		style.Font.Size = vg.Points(max(CurrentTheme.Text.TitleSize, 12) * 1.5)
+		style.Color = CurrentTheme.Text.Color.ToColor()
+		style.XAlign, style.YAlign = draw.XCenter, draw.YTop
+		dc.FillText(style, vg.Point{X: dc.Center().X, Y: dc.Max.Y - padding}, layout.Title)
+		dc.Max.Y -= style.Height(layout.Title) + padding
+	}
+
+	tiles := draw.Tiles{
+		Rows: layout.Rows, Cols: layout.Cols,
+		PadTop: padding, PadBottom: padding, PadLeft: padding, PadRight: padding,
+		PadX: padding, PadY: padding,
+	}
+	for i, cell := range layout.Cells {
+		first := tiles.At(dc, cell.Col-1, cell.Row-1)
+		last := tiles.At(dc, cell.Col+cell.ColSpan-2, cell.Row+cell.RowSpan-2)
+		// Rows count downwards while the canvas Y axis points upwards
+		cellCanvas := draw.Canvas{Canvas: dc.Canvas, Rectangle: vg.Rectangle{
+			Min: vg.Point{X: first.Min.X, Y: last.Min.Y},
+			Max: vg.Point{X: last.Max.X, Y: first.Max.Y},
+		}}
+		panels[i].Plot.Draw(cellCanvas)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create dashboard file %s: %v", output, err)
+	}
+	defer file.Close()
+	if _, err := canvas.WriteTo(file); err != nil {
+		return fmt.Errorf("failed to write dashboard %s: %v", output, err)
+	}
+	return nil
+}
+
+// alignTimeAxes sets the X range of all time-based panels to the union of their ranges.
+func alignTimeAxes(panels []DashboardPanel) {
+	first := true
+	var xMin, xMax float64
+	for _, panel := range panels {
+		if !panel.TimeAxis {
+			continue
+		}
+		if first {
+			xMin, xMax, first = panel.Plot.X.Min, panel.Plot.X.Max, false
+			continue
+		}
+		xMin, xMax = min(xMin, panel.Plot.X.Min), max(xMax, panel.Plot.X.Max)
+	}
+	for _, panel := range panels {
+		if panel.TimeAxis {
+			panel.Plot.X.Min, panel.Plot.X.Max = xMin, xMax
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package graphics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestParseDashboardLayout(t *testing.T) {
+	layout, err := ParseDashboardLayout([]byte(`
+title: Overview
+cols: 2
+cells:
+  - mode: languages
+  - mode: burndown-project
+    row: 1
+    col: 1
+    colspan: 2
+  - mode: devs
+  - mode: ownership
+`))
+	if err != nil {
+		t.Fatalf("ParseDashboardLayout() error = %v", err)
+	}
+	if layout.Rows != 3 || layout.Cols != 2 {
+		t.Errorf("expected a 3x2 grid, got %dx%d", layout.Rows, layout.Cols)
+	}
+	// Positioned cells are placed first, the others fill the free slots row by row
+	want := map[string][2]int{"burndown-project": {1, 1}, "languages": {2, 1}, "devs": {2, 2}, "ownership": {3, 1}}
+	for _, cell := range layout.Cells {
+		if pos := want[cell.Mode]; cell.Row != pos[0] || cell.Col != pos[1] {
+			t.Errorf("%s placed at %d,%d, want %v", cell.Mode, cell.Row, cell.Col, pos)
+		}
+	}
+
+	for _, invalid := range []string{
+		"cells: []",
+		"cells: [{title: no mode}]",
+		"rows: 1\ncols: 1\ncells: [{mode: devs}, {mode: languages}]",
+		"rows: 1\ncols: 2\ncells: [{mode: devs, col: 2, colspan: 2}]",
+		"rows: 2\ncols: 2\ncells: [{mode: devs, row: 1, col: 1, rowspan: 2}, {mode: languages, row: 2, col: 1}]",
+	} {
+		if _, err := ParseDashboardLayout([]byte(invalid)); err == nil {
+			t.Errorf("expected an error for layout %q", invalid)
+		}
+	}
+}
+
+// linePlot returns a plot with a single line between two X values
+func linePlot(t *testing.T, x0, x1 float64) *plot.Plot {
+	t.Helper()
+	p := plot.New()
+	line, err := plotter.NewLine(plotter.XYs{{X: x0, Y: 0}, {X: x1, Y: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Add(line)
+	return p
+}
+
+func TestComposeDashboard(t *testing.T) {
+	layout, err := ParseDashboardLayout([]byte("title: Test\nwidth: 6\nheight: 4\ncells: [{mode: a, title: First}, {mode: b}, {mode: c, colspan: 2}]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	panels := []DashboardPanel{
+		{Plot: linePlot(t, 100, 200), TimeAxis: true},
+		{Plot: linePlot(t, 0, 1)},
+		{Plot: linePlot(t, 150, 400), TimeAxis: true},
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"dashboard.png", "dashboard.svg", "dashboard.pdf"} {
+		if err := ComposeDashboard(layout, panels, filepath.Join(dir, name)); err != nil {
+			t.Fatalf("ComposeDashboard(%s) error = %v", name, err)
+		}
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Errorf("%s was not written", name)
+		}
+	}
+	svg, _ := os.ReadFile(filepath.Join(dir, "dashboard.svg"))
+	if !strings.Contains(string(svg), "First") {
+		t.Error("cell titles should replace the chart titles")
+	}
+
+	// Time-based panels share the date range, others keep theirs
+	if panels[0].Plot.X.Min != 100 || panels[0].Plot.X.Max != 400 || panels[2].Plot.X.Min != 100 {
+		t.Errorf("time axes should be aligned, got [%v, %v] and [%v, %v]",
+			panels[0].Plot.X.Min, panels[0].Plot.X.Max, panels[2].Plot.X.Min, panels[2].Plot.X.Max)
+	}
+	if panels[1].Plot.X.Max != 1 {
+		t.Errorf("non-time axes should not change, got %v", panels[1].Plot.X.Max)
+	}
+
+	if err := ComposeDashboard(layout, panels, filepath.Join(dir, "dashboard.gif")); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	if err := ComposeDashboard(layout, panels[:1], filepath.Join(dir, "dashboard.png")); err == nil {
+		t.Error("expected an error for a missing panel")
+	}
+}
@@ -0,0 +1,163 @@
+package graphics
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"labours-go/internal/burndown"
+)
+
+// forecastDashes is the dash pattern of projected lines
+var forecastDashes = []vg.Length{vg.Points(6), vg.Points(4)}
+
+// PlotBurndownForecast draws the Python-style burndown chart and continues it with the
+// forecast: dashed stacked band boundaries with their shaded intervals, a dashed total and
+// a shaded uncertainty band of the total. The chart is added to the report content of ctx.
+func PlotBurndownForecast(ctx context.Context, data *burndown.ProcessedBurndown, forecast *burndown.BurndownForecast, output string, relative bool) error {
+	p, err := buildBurndownPythonPlot(data, relative)
+	if err != nil {
+		return err
+	}
+	if forecast == nil || len(forecast.Dates) == 0 || IsTerminalOutput(output) {
+		if err := AddActiveAnnotations(p); err != nil {
+			return err
+		}
+		AddReportChart(ctx, p)
+		if IsTerminalOutput(output) {
+			return WriteTerminal(p, burndownTerminalChart(p.Title.Text, data, relative))
+		}
+		width, height := GetPlotSize(ChartTypeDefault)
+		return SavePlotWithFormat(p, width, height, output)
+	}
+
+	numPoints := len(forecast.Dates)
+	xs := make([]float64, numPoints+1)
+	xs[0] = float64(forecast.Start.Unix())
+	for k, date := range forecast.Dates {
+		xs[k+1] = float64(date.Unix())
+	}
+
+	// Observed values at the last date anchor the projection to the chart
+	observed := forecast.Observed
+	observedTotal := 0.0
+	for _, value := range observed {
+		observedTotal += value
+	}
+
+	scale := func(value, total float64) float64 {
+		if !relative {
+			return value
+		}
+		if total <= 0 {
+			return 0
+		}
+		return math.Min(1, value/total)
+	}
+
+	// Uncertainty band of the total size (absolute charts only, relative totals are always 1)
+	if !relative {
+		upper := make(plotter.XYs, numPoints+1)
+		lower := make(plotter.XYs, numPoints+1)
+		upper[0] = plotter.XY{X: xs[0], Y: observedTotal}
+		lower[0] = plotter.XY{X: xs[0], Y: observedTotal}
+		for k := 0; k < numPoints; k++ {
+			upper[k+1] = plotter.XY{X: xs[k+1], Y: forecast.TotalUpper[k]}
+			lower[k+1] = plotter.XY{X: xs[k+1], Y: forecast.TotalLower[k]}
+		}
+		band := color.NRGBA{R: 128, G: 128, B: 128, A: 60}
+		if err := addStackedLayer(p, upper, lower, band, "95% interval"); err != nil {
+			return fmt.Errorf("error adding forecast interval: %v", err)
+		}
+	}
+
+	// Dashed continuation of every stacked band boundary, bottom to top, shaded with
+	// the decay rate interval of the band on top of the expected bands below it
+	colors := generateMatplotlibColorPalette(len(data.Matrix))
+	cumulative := make([]float64, numPoints+1)
+	cumulativeObserved := 0.0
+	for i, band := range forecast.Bands {
+		if i >= len(observed) || i >= len(colors) {
+			break
+		}
+		cumulativeObserved += observed[i]
+		points := make(plotter.XYs, numPoints+1)
+		upper := make(plotter.XYs, numPoints+1)
+		lower := make(plotter.XYs, numPoints+1)
+		points[0] = plotter.XY{X: xs[0], Y: scale(cumulativeObserved, observedTotal)}
+		upper[0], lower[0] = points[0], points[0]
+		for k := 0; k < numPoints; k++ {
+			below := cumulative[k+1]
+			cumulative[k+1] += band.Values[k]
+			points[k+1] = plotter.XY{X: xs[k+1], Y: scale(cumulative[k+1], forecast.Total[k])}
+			upper[k+1] = plotter.XY{X: xs[k+1], Y: scale(below+band.Upper[k], forecast.Total[k])}
+			lower[k+1] = plotter.XY{X: xs[k+1], Y: scale(below+band.Lower[k], forecast.Total[k])}
+		}
+		if err := addForecastInterval(p, upper, lower, colors[i]); err != nil {
+			return err
+		}
+		if err := addForecastLine(p, points, colors[i], ""); err != nil {
+			return err
+		}
+	}
+
+	// The top boundary is the projected total including new code
+	totalPoints := make(plotter.XYs, numPoints+1)
+	totalPoints[0] = plotter.XY{X: xs[0], Y: scale(observedTotal, observedTotal)}
+	for k := 0; k < numPoints; k++ {
+		totalPoints[k+1] = plotter.XY{X: xs[k+1], Y: scale(cumulative[k+1]+forecast.NewCode[k], forecast.Total[k])}
+	}
+	if err := addForecastLine(p, totalPoints, CurrentTheme.Text.Color.ToColor(), fmt.Sprintf("Forecast (%d months)", forecast.Months)); err != nil {
+		return err
+	}
+
+	p.X.Max = xs[len(xs)-1]
+	p.Title.Text += fmt.Sprintf(", forecast %d months", forecast.Months)
+	if err := AddActiveAnnotations(p); err != nil {
+		return err
+	}
+	AddReportChart(ctx, p)
+
+	width, height := GetPlotSize(ChartTypeDefault)
+	return SavePlotWithFormat(p, width, height, output)
+}
+
+// addForecastInterval shades the area between the upper and lower bounds of a band
+// projection in a translucent version of the band color, without a legend entry
+func addForecastInterval(p *plot.Plot, upper, lower plotter.XYs, bandColor color.Color) error {
+	points := make(plotter.XYs, 0, len(upper)+len(lower))
+	points = append(points, upper...)
+	for i := len(lower) - 1; i >= 0; i-- {
+		points = append(points, lower[i])
+	}
+	polygon, err := plotter.NewPolygon(points)
+	if err != nil {
+		return fmt.Errorf("error creating forecast band interval: %v", err)
+	}
+	nrgba := color.NRGBAModel.Convert(bandColor).(color.NRGBA)
+	nrgba.A = 50
+	polygon.Color = nrgba
+	polygon.LineStyle.Width = 0
+	p.Add(polygon)
+	return nil
+}
+
+// addForecastLine adds a dashed projection line, with a legend entry if label is set
+func addForecastLine(p *plot.Plot, points plotter.XYs, lineColor color.Color, label string) error {
+	line, err := plotter.NewLine(points)
+	if err != nil {
+		return fmt.Errorf("error creating forecast line: %v", err)
+	}
+	line.Color = lineColor
+	line.Width = vg.Points(1.5)
+	line.Dashes = forecastDashes
+	p.Add(line)
+	if label != "" {
+		p.Legend.Add(label, line)
+	}
+	return nil
+}
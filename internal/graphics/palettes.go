@@ -0,0 +1,198 @@
+package graphics
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+)
+
+// Palette is a named set of colors. Sequential palettes are sampled evenly for any number
+// of bands, qualitative palettes provide a fixed number of distinct colors.
+type Palette struct {
+	Name       string
+	Colors     []ColorRGB
+	Sequential bool
+}
+
+// colorblindQualitativeMax is the number of bands up to which colorblind-safe themes use
+// the Okabe-Ito palette; more bands are drawn with cividis
+const colorblindQualitativeMax = 8
+
+// hexColor converts 0xRRGGBB to an opaque ColorRGB.
+func hexColor(rgb uint32) ColorRGB {
+	return ColorRGB{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}
+}
+
+func hexColors(values ...uint32) []ColorRGB {
+	colors := make([]ColorRGB, len(values))
+	for i, value := range values {
+		colors[i] = hexColor(value)
+	}
+	return colors
+}
+
+// BuiltinPalettes contains palettes that stay distinguishable with color vision deficiencies.
+// Viridis and cividis are perceptually uniform; the anchors are interpolated linearly.
+var BuiltinPalettes = map[string]Palette{
+	"okabe-ito": {
+		Name:   "okabe-ito",
+		Colors: hexColors(0xE69F00, 0x56B4E9, 0x009E73, 0xF0E442, 0x0072B2, 0xD55E00, 0xCC79A7, 0x000000),
+	},
+	"tol-bright": {
+		Name:   "tol-bright",
+		Colors: hexColors(0x4477AA, 0xEE6677, 0x228833, 0xCCBB44, 0x66CCEE, 0xAA3377, 0xBBBBBB),
+	},
+	"tol-muted": {
+		Name:   "tol-muted",
+		Colors: hexColors(0xCC6677, 0x332288, 0xDDCC77, 0x117733, 0x88CCEE, 0x882255, 0x44AA99, 0x999933, 0xAA4499),
+	},
+	"tol-vibrant": {
+		Name:   "tol-vibrant",
+		Colors: hexColors(0xEE7733, 0x0077BB, 0x33BBEE, 0xEE3377, 0xCC3311, 0x009988, 0xBBBBBB),
+	},
+	"viridis": {
+		Name:       "viridis",
+		Colors:     hexColors(0x440154, 0x472C7A, 0x3B518B, 0x2C718E, 0x21908D, 0x27AD81, 0x5CC863, 0xAADC32, 0xFDE725),
+		Sequential: true,
+	},
+	"cividis": {
+		Name:       "cividis",
+		Colors:     hexColors(0x00224E, 0x123570, 0x3B496C, 0x575D6D, 0x707173, 0x8A8678, 0xA59C74, 0xC3B369, 0xE1CC55, 0xFEE838),
+		Sequential: true,
+	},
+}
+
+// ColorblindTheme is the default theme with the Okabe-Ito palette and a cividis heatmap
+var ColorblindTheme = func() Theme {
+	theme := DefaultTheme
+	theme.Name = "colorblind"
+	theme.Palette = "okabe-ito"
+	theme.ColorblindSafe = true
+	theme.ColorPalette = append([]ColorRGB(nil), BuiltinPalettes["okabe-ito"].Colors...)
+	theme.HeatMap = HeatStyle{
+		ColdColor: hexColor(0x00224E),
+		HotColor:  hexColor(0xFEE838),
+		MidColor:  hexColor(0x707173),
+	}
+	return theme
+}()
+
+// ListPalettes returns the names of the built-in palettes
+func ListPalettes() []string {
+	names := make([]string, 0, len(BuiltinPalettes))
+	for name := range BuiltinPalettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PaletteColors returns n colors of the named palette. Sequential palettes are sampled from
+// one end to the other, qualitative palettes repeat once their colors are used up.
+func PaletteColors(name string, n int) ([]ColorRGB, error) {
+	palette, exists := BuiltinPalettes[name]
+	if !exists {
+		return nil, fmt.Errorf("palette '%s' not found, available: %v", name, ListPalettes())
+	}
+	return palette.Sample(n), nil
+}
+
+// Sample returns n colors of the palette.
+func (p Palette) Sample(n int) []ColorRGB {
+	if n <= 0 {
+		return []ColorRGB{}
+	}
+	colors := make([]ColorRGB, n)
+	if !p.Sequential {
+		for i := range colors {
+			colors[i] = p.Colors[i%len(p.Colors)]
+		}
+		return colors
+	}
+
+	for i := range colors {
+		position := 0.0
+		if n > 1 {
+			position = float64(i) / float64(n-1)
+		}
+		colors[i] = p.at(position)
+	}
+	return colors
+}
+
+// at interpolates the sequential palette at position in [0, 1].
+func (p Palette) at(position float64) ColorRGB {
+	scaled := position * float64(len(p.Colors)-1)
+	left := int(scaled)
+	if left >= len(p.Colors)-1 {
+		return p.Colors[len(p.Colors)-1]
+	}
+	fraction := scaled - float64(left)
+	a, b := p.Colors[left], p.Colors[left+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + fraction*(float64(y)-float64(x)) + 0.5)
+	}
+	return ColorRGB{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 255}
+}
+
+// BandColors returns n opaque colors for the bands of a chart. Themes naming a palette or
+// marked colorblind_safe use the built-in palettes; other themes use their own colors and
+// extend them with generated hues.
+func (t *Theme) BandColors(n int) []color.Color {
+	if named, ok := t.paletteBandColors(n); ok {
+		return named
+	}
+
+	themePalette := t.GetColorPalette()
+	colors := make([]color.Color, n)
+	for i := 0; i < n; i++ {
+		if i < len(themePalette) {
+			colors[i] = themePalette[i]
+		} else {
+			colors[i] = generateHSVColorWithOpacity(i, n, 255)
+		}
+	}
+	return colors
+}
+
+// paletteBandColors returns the band colors of themes with a named palette or the
+// colorblind_safe option, and false for themes using their own colors.
+func (t *Theme) paletteBandColors(n int) ([]color.Color, bool) {
+	name := t.Palette
+	if name == "" && !t.ColorblindSafe {
+		return nil, false
+	}
+	if name == "" || (t.ColorblindSafe && !BuiltinPalettes[name].Sequential && n > len(BuiltinPalettes[name].Colors)) {
+		// Qualitative palettes would repeat colors, sequential ones stay distinguishable
+		name = "okabe-ito"
+		if n > colorblindQualitativeMax {
+			name = "cividis"
+		}
+	}
+
+	sampled, err := PaletteColors(name, n)
+	if err != nil {
+		return nil, false
+	}
+	colors := make([]color.Color, n)
+	for i, c := range sampled {
+		colors[i] = c.ToColor()
+	}
+	return colors, true
+}
+
+// paletteBandColorsWithOpacity is paletteBandColors for filled areas.
+func (t *Theme) paletteBandColorsWithOpacity(n int, opacity uint8) ([]color.Color, bool) {
+	colors, ok := t.paletteBandColors(n)
+	for i := range colors {
+		colors[i] = withOpacity(colors[i], opacity)
+	}
+	return colors, ok
+}
+
+// withOpacity returns c with the given alpha.
+func withOpacity(c color.Color, opacity uint8) color.Color {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nrgba.A = opacity
+	return nrgba
+}
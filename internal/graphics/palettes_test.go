@@ -0,0 +1,154 @@
+package graphics
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestPaletteSample(t *testing.T) {
+	viridis, err := PaletteColors("viridis", 5)
+	if err != nil {
+		t.Fatalf("PaletteColors failed: %v", err)
+	}
+	if viridis[0] != hexColor(0x440154) || viridis[4] != hexColor(0xFDE725) {
+		t.Errorf("sequential palette should span its anchors, got %v", viridis)
+	}
+	if viridis[2] != hexColor(0x21908D) {
+		t.Errorf("middle sample should hit the middle anchor, got %v", viridis[2])
+	}
+
+	okabe, _ := PaletteColors("okabe-ito", 10)
+	if okabe[8] != okabe[0] {
+		t.Errorf("qualitative palette should repeat, got %v", okabe)
+	}
+
+	if _, err := PaletteColors("rainbow", 3); err == nil {
+		t.Error("expected an error for an unknown palette")
+	}
+}
+
+func TestThemeBandColors(t *testing.T) {
+	theme := DefaultTheme
+	theme.ColorblindSafe = true
+
+	colors, ok := theme.paletteBandColors(colorblindQualitativeMax)
+	if !ok || colors[0] != hexColor(0xE69F00).ToColor() {
+		t.Errorf("colorblind-safe theme should use Okabe-Ito for few bands, got %v", colors)
+	}
+	colors, _ = theme.paletteBandColors(colorblindQualitativeMax + 4)
+	if colors[0] != hexColor(0x00224E).ToColor() {
+		t.Errorf("colorblind-safe theme should use cividis for many bands, got %v", colors[0])
+	}
+
+	theme.Palette = "tol-bright"
+	colors, _ = theme.paletteBandColors(3)
+	if colors[0] != hexColor(0x4477AA).ToColor() {
+		t.Errorf("named palette should take precedence, got %v", colors[0])
+	}
+
+	if _, ok := DefaultTheme.paletteBandColors(3); ok {
+		t.Error("themes without a palette should keep their own colors")
+	}
+	if colors := DefaultTheme.BandColors(12); len(colors) != 12 || colors[0] != DefaultTheme.ColorPalette[0].ToColor() {
+		t.Errorf("BandColors should start with the theme colors, got %v", colors)
+	}
+}
+
+func TestBurndownColorsUsePalette(t *testing.T) {
+	saved := CurrentTheme
+	defer func() { CurrentTheme = saved }()
+
+	CurrentTheme = ColorblindTheme
+	colors := GetBurndownColors(3)
+	want := color.NRGBA{R: 0xE6, G: 0x9F, B: 0x00, A: uint8(255 * CurrentTheme.Chart.FillOpacity)}
+	if colors[0] != want {
+		t.Errorf("burndown colors should come from the palette, got %v want %v", colors[0], want)
+	}
+}
+
+func TestPaletteValidation(t *testing.T) {
+	theme := ColorblindTheme
+	theme.ColorPalette = nil
+	if err := theme.Validate(); err != nil {
+		t.Errorf("colorblind-safe theme without colors should be valid: %v", err)
+	}
+	theme.Palette = "rainbow"
+	if err := theme.Validate(); err == nil {
+		t.Error("expected an error for an unknown palette")
+	}
+}
+
+func TestCIEDE2000(t *testing.T) {
+	// Reference pairs from Sharma, Wu and Dalal (2005)
+	tests := []struct {
+		first, second LabColor
+		want          float64
+	}{
+		{LabColor{50, 2.6772, -79.7751}, LabColor{50, 0, -82.7485}, 2.0425},
+		{LabColor{50, -1.3802, -84.2814}, LabColor{50, 0, -82.7485}, 1.0000},
+		{LabColor{50, 2.5, 0}, LabColor{73, 25, -18}, 27.1492},
+		{LabColor{60.2574, -34.0099, 36.2677}, LabColor{60.4626, -34.1751, 39.4387}, 1.2644},
+		{LabColor{22.7233, 20.0904, -46.694}, LabColor{23.0331, 14.973, -42.5619}, 2.0373},
+	}
+	for _, tt := range tests {
+		if got := CIEDE2000(tt.first, tt.second); math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("CIEDE2000(%v, %v) = %.4f, want %.4f", tt.first, tt.second, got, tt.want)
+		}
+	}
+}
+
+func TestSimulateColorVision(t *testing.T) {
+	gray := ColorRGB{R: 128, G: 128, B: 128, A: 255}
+	for _, vision := range VisionTypes {
+		if got := SimulateColorVision(gray, vision); absDiff(got.R, gray.R) > 1 || absDiff(got.G, gray.G) > 1 || absDiff(got.B, gray.B) > 1 {
+			t.Errorf("%s should keep grays, got %v", vision, got)
+		}
+	}
+
+	// Red and green are confused with deuteranopia but not with normal vision
+	red, green := hexColor(0xD62728), hexColor(0x2CA02C)
+	reports := CheckColorDistances([]ColorRGB{red, green})
+	if reports[0].Vision != VisionNormal || reports[2].Vision != VisionDeuteranopia {
+		t.Fatalf("unexpected report order: %v", reports)
+	}
+	if reports[2].MinDistance >= reports[0].MinDistance/2 {
+		t.Errorf("deuteranopia should bring red and green closer, got %.2f vs %.2f", reports[2].MinDistance, reports[0].MinDistance)
+	}
+}
+
+func TestColorblindThemeCheck(t *testing.T) {
+	colorblind := ColorblindTheme
+	for _, report := range colorblind.CheckBandColors(colorblindQualitativeMax) {
+		if report.MinDistance < 5 {
+			t.Errorf("colorblind theme bands are too close with %s: %.2f", report.Vision, report.MinDistance)
+		}
+	}
+
+	def := DefaultTheme
+	worst := math.Inf(1)
+	for _, report := range def.CheckBandColors(colorblindQualitativeMax) {
+		worst = math.Min(worst, report.MinDistance)
+	}
+	if worst >= 5 {
+		t.Errorf("default theme should have bands that are hard to tell apart, closest %.2f", worst)
+	}
+}
+
+func TestColorblindThemeOwnsPalette(t *testing.T) {
+	builtin := BuiltinPalettes["okabe-ito"].Colors[0]
+	colorblind := ColorblindTheme
+	colorblind.ColorPalette[0] = hexColor(0x123456)
+	defer func() { colorblind.ColorPalette[0] = builtin }()
+
+	if BuiltinPalettes["okabe-ito"].Colors[0] != builtin {
+		t.Error("changing the colorblind theme colors should not change the built-in palette")
+	}
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
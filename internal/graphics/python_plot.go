@@ -1,18 +1,61 @@
 package graphics
 
 import (
+	"context"
 	"fmt"
 	"image/color"
+	"log/slog"
 
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"labours-go/internal/burndown"
 )
 
-// PlotBurndownPythonStyle creates a burndown plot that matches Python's pyplot.stackplot behavior
-func PlotBurndownPythonStyle(data *burndown.ProcessedBurndown, output string, relative bool) error {
+// PlotBurndownPythonStyle creates a burndown plot that matches Python's pyplot.stackplot
+// behavior and adds it to the report content of ctx
+func PlotBurndownPythonStyle(ctx context.Context, data *burndown.ProcessedBurndown, output string, relative bool) error {
+	p, err := buildBurndownPythonPlot(data, relative)
+	if err != nil {
+		return err
+	}
+	if err := AddActiveAnnotations(p); err != nil {
+		return err
+	}
+	AddReportChart(ctx, p)
+	if IsTerminalOutput(output) {
+		return WriteTerminal(p, burndownTerminalChart(p.Title.Text, data, relative))
+	}
+
+	// Save plot with dynamic sizing (respects --size flag)
+	width, height := GetPlotSize(ChartTypeDefault)
+	return SavePlotWithFormat(p, width, height, output)
+}
+
+// burndownTerminalChart returns the bands of a burndown chart for terminal output
+func burndownTerminalChart(title string, data *burndown.ProcessedBurndown, relative bool) TermStackedArea {
+	matrix := data.Matrix
+	if relative {
+		matrix = normalizeMatrixColumns(matrix)
+	}
+	return TermStackedArea{
+		Title:  title,
+		Labels: data.Labels,
+		Series: SmoothRows(matrix),
+		Start:  data.DateRange[0],
+		End:    data.DateRange[len(data.DateRange)-1],
+	}
+}
+
+// BurndownPythonPlot creates the Python-style burndown chart without annotations, e.g.
+// for a dashboard cell.
+func BurndownPythonPlot(data *burndown.ProcessedBurndown, relative bool) (*plot.Plot, error) {
+	return buildBurndownPythonPlot(data, relative)
+}
+
+// buildBurndownPythonPlot creates the stacked burndown plot without saving it
+func buildBurndownPythonPlot(data *burndown.ProcessedBurndown, relative bool) (*plot.Plot, error) {
 	if data == nil || len(data.Matrix) == 0 || len(data.DateRange) == 0 {
-		return fmt.Errorf("empty burndown data")
+		return nil, fmt.Errorf("empty burndown data")
 	}
 
 	p := plot.New()
@@ -33,7 +76,7 @@ func PlotBurndownPythonStyle(data *burndown.ProcessedBurndown, output string, re
 
 	// Ensure matrix dimensions are consistent
 	if numSeries == 0 {
-		return fmt.Errorf("empty matrix")
+		return nil, fmt.Errorf("empty matrix")
 	}
 
 	// Convert dates to float64 for plotting (Unix timestamps)
@@ -47,22 +90,21 @@ func PlotBurndownPythonStyle(data *burndown.ProcessedBurndown, output string, re
 	if relative {
 		matrix = normalizeMatrixColumns(data.Matrix)
 	}
+	matrix = SmoothRows(matrix)
 
-	// DEBUG: Print matrix values to understand the data
-	fmt.Printf("DEBUG MATRIX ANALYSIS:\n")
-	fmt.Printf("  Matrix dimensions: %dx%d\n", len(matrix), len(matrix[0]))
-	for i := 0; i < len(matrix); i++ {
-		minVal, maxVal := matrix[i][0], matrix[i][0]
-		negCount, posCount := 0, 0
-		for j := 0; j < len(matrix[i]); j++ {
-			if matrix[i][j] < minVal { minVal = matrix[i][j] }
-			if matrix[i][j] > maxVal { maxVal = matrix[i][j] }
-			if matrix[i][j] < 0 { negCount++ }
-			if matrix[i][j] > 0 { posCount++ }
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		for i, row := range matrix {
+			if len(row) == 0 {
+				continue
+			}
+			minVal, maxVal := row[0], row[0]
+			for _, v := range row {
+				minVal, maxVal = min(minVal, v), max(maxVal, v)
+			}
+			slog.Debug("burndown layer", "layer", i, "min", minVal, "max", maxVal)
 		}
-		fmt.Printf("  Layer %d: min=%.2f, max=%.2f, negatives=%d, positives=%d\n", i, minVal, maxVal, negCount, posCount)
 	}
-	
+
 	// Generate matplotlib-compatible color palette (matches Python exactly)
 	colors := generateMatplotlibColorPalette(numSeries)
 
@@ -107,7 +149,7 @@ func PlotBurndownPythonStyle(data *burndown.ProcessedBurndown, output string, re
 
 		// Create polygon for this stacked area
 		if err := addStackedLayer(p, topPoints, bottomPoints, colors[i], label); err != nil {
-			return fmt.Errorf("error adding layer %s: %v", label, err)
+			return nil, fmt.Errorf("error adding layer %s: %v", label, err)
 		}
 	}
 
@@ -119,6 +161,7 @@ func PlotBurndownPythonStyle(data *burndown.ProcessedBurndown, output string, re
 		p.Y.Min = 0
 		p.Y.Max = 1
 	}
+	ApplyTimeAxes(p)
 
 	// Configure legend position (matches Python behavior)
 	legendLoc := 2 // upper left
@@ -127,13 +170,7 @@ func PlotBurndownPythonStyle(data *burndown.ProcessedBurndown, output string, re
 	}
 	_ = legendLoc // TODO: Implement legend positioning
 
-	// Save plot with dynamic sizing (respects --size flag)
-	width, height := GetPlotSize(ChartTypeDefault)
-	if err := SavePlotWithFormat(p, width, height, output); err != nil {
-		return err
-	}
-
-	return nil
+	return p, nil
 }
 
 // normalizeMatrixColumns normalizes each column to sum to 1 (matches Python's relative mode)
@@ -228,6 +265,11 @@ func PrintSurvivalFunction(matrix [][]float64) {
 
 // generateMatplotlibColorPalette creates colors that exactly match Python matplotlib defaults
 func generateMatplotlibColorPalette(n int) []color.Color {
+	// Themes with a named or colorblind-safe palette override the matplotlib defaults
+	if colors, ok := CurrentTheme.paletteBandColorsWithOpacity(n, 180); ok {
+		return colors
+	}
+	
 	// Matplotlib default colors (C0, C1, C2, ...) - these exactly match Python pyplot
 	matplotlibColors := []color.Color{
 		color.RGBA{R: 31, G: 119, B: 180, A: 180},   // Blue (C0) - matplotlib default
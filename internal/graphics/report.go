@@ -0,0 +1,291 @@
+package graphics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgpdf"
+)
+
+// Report pages are A4 landscape
+const (
+	reportPageWidth  = 297 * vg.Millimeter
+	reportPageHeight = 210 * vg.Millimeter
+	reportMargin     = 15 * vg.Millimeter
+	reportCellMax    = 40 // characters shown of a table cell
+	reportColumnGap  = 2  // characters between table columns
+)
+
+// Report is a PDF document with a cover page, a page per chart and text pages with tables
+type Report struct {
+	Title     string
+	Fields    []ReportField // shown on the cover page
+	Notes     []string      // shown on the cover page below the fields
+	Charts    []*plot.Plot
+	Tables    []ReportTable
+	Generated time.Time
+}
+
+// ReportField is a named value on the cover page
+type ReportField struct {
+	Name  string
+	Value string
+}
+
+// ReportTable is a summary table; tables longer than a page continue on the next one.
+// Columns holding only numbers are right-aligned.
+type ReportTable struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// reportContentKey is the context key of a ReportContent
+type reportContentKey struct{}
+
+// ReportContent collects the charts and tables a mode draws, so that the report is
+// written from them instead of running the analysis a second time
+type ReportContent struct {
+	mu     sync.Mutex
+	Charts []*plot.Plot
+	Tables []ReportTable
+}
+
+// WithReportContent returns a context whose charts and tables, added with AddReportChart
+// and AddReportTable, are collected in the returned content
+func WithReportContent(ctx context.Context) (context.Context, *ReportContent) {
+	content := &ReportContent{}
+	return context.WithValue(ctx, reportContentKey{}, content), content
+}
+
+// AddReportChart adds a chart to the report content of ctx. Without one it does nothing.
+func AddReportChart(ctx context.Context, p *plot.Plot) {
+	if content, ok := ctx.Value(reportContentKey{}).(*ReportContent); ok {
+		content.mu.Lock()
+		defer content.mu.Unlock()
+		content.Charts = append(content.Charts, p)
+	}
+}
+
+// AddReportTable adds a table to the report content of ctx. Without one it does nothing.
+func AddReportTable(ctx context.Context, table ReportTable) {
+	if content, ok := ctx.Value(reportContentKey{}).(*ReportContent); ok {
+		content.mu.Lock()
+		defer content.mu.Unlock()
+		content.Tables = append(content.Tables, table)
+	}
+}
+
+// AddReportContent adds content collected separately, e.g. by one of several concurrent
+// workers, to the report content of ctx. A nil content adds nothing.
+func AddReportContent(ctx context.Context, part *ReportContent) {
+	if part == nil {
+		return
+	}
+	for _, p := range part.Charts {
+		AddReportChart(ctx, p)
+	}
+	for _, table := range part.Tables {
+		AddReportTable(ctx, table)
+	}
+}
+
+// reportPage draws the content of a page into the area inside the margins
+type reportPage func(dc draw.Canvas)
+
+// WriteReport writes the report as a multi-page PDF file.
+func WriteReport(report *Report, output string) error {
+	if strings.ToLower(filepath.Ext(output)) != ".pdf" {
+		return fmt.Errorf("reports are written as PDF files, got %s", output)
+	}
+
+	pages := []reportPage{func(dc draw.Canvas) { drawReportCover(dc, report) }}
+	for _, p := range report.Charts {
+		pages = append(pages, func(dc draw.Canvas) { p.Draw(dc) })
+	}
+	for _, table := range report.Tables {
+		pages = append(pages, tablePages(table)...)
+	}
+
+	canvas := vgpdf.New(reportPageWidth, reportPageHeight)
+	footer := reportTextStyle("Sans", 8)
+	footer.XAlign = draw.XRight
+	for i, page := range pages {
+		if i > 0 {
+			canvas.NextPage()
+		}
+		dc := draw.New(canvas)
+		dc.SetColor(CurrentTheme.Background.ToColor())
+		dc.Fill(dc.Rectangle.Path())
+
+		dc.FillText(footer, vg.Point{X: dc.Max.X - reportMargin, Y: reportMargin / 2},
+			fmt.Sprintf("%s  ·  page %d of %d", report.Title, i+1, len(pages)))
+		page(insetCanvas(dc, reportMargin))
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create report file %s: %v", output, err)
+	}
+	defer file.Close()
+	if _, err := canvas.WriteTo(file); err != nil {
+		return fmt.Errorf("failed to write report %s: %v", output, err)
+	}
+	return nil
+}
+
+// drawReportCover draws the title, the fields, the notes and the table of contents.
+func drawReportCover(dc draw.Canvas, report *Report) {
+	y := dc.Max.Y - 20*vg.Millimeter
+	title := reportTextStyle("Sans", 28)
+	dc.FillText(title, vg.Point{X: dc.Min.X, Y: y}, report.Title)
+	y -= title.Height(report.Title) + 10*vg.Millimeter
+
+	label, value := reportTextStyle("Sans", 12), reportTextStyle("Serif", 12)
+	labelWidth := vg.Length(0)
+	for _, field := range report.Fields {
+		labelWidth = max(labelWidth, label.Width(field.Name))
+	}
+	lineHeight := label.Height("M") * 1.6
+	for _, field := range report.Fields {
+		dc.FillText(label, vg.Point{X: dc.Min.X, Y: y}, field.Name)
+		dc.FillText(value, vg.Point{X: dc.Min.X + labelWidth + 6*vg.Millimeter, Y: y}, field.Value)
+		y -= lineHeight
+	}
+	if !report.Generated.IsZero() {
+		dc.FillText(label, vg.Point{X: dc.Min.X, Y: y}, "Generated")
+		dc.FillText(value, vg.Point{X: dc.Min.X + labelWidth + 6*vg.Millimeter, Y: y}, report.Generated.Format("2006-01-02 15:04 MST"))
+		y -= lineHeight
+	}
+
+	y -= lineHeight / 2
+	for _, note := range report.Notes {
+		dc.FillText(value, vg.Point{X: dc.Min.X, Y: y}, note)
+		y -= lineHeight
+	}
+
+	// Contents: page 1 is the cover
+	y -= lineHeight / 2
+	dc.FillText(label, vg.Point{X: dc.Min.X, Y: y}, "Contents")
+	y -= lineHeight
+	page := 2
+	for _, p := range report.Charts {
+		dc.FillText(value, vg.Point{X: dc.Min.X, Y: y}, fmt.Sprintf("%3d  %s", page, p.Title.Text))
+		y -= lineHeight
+		page++
+	}
+	for _, table := range report.Tables {
+		dc.FillText(value, vg.Point{X: dc.Min.X, Y: y}, fmt.Sprintf("%3d  %s", page, table.Title))
+		y -= lineHeight
+		page += len(tablePages(table))
+	}
+}
+
+// tablePages splits a table into pages that repeat the header.
+func tablePages(table ReportTable) []reportPage {
+	title, cell := reportTextStyle("Sans", 16), reportTextStyle("Mono", 9)
+	lineHeight := cell.Height("M") * 1.4
+	available := reportPageHeight - 2*reportMargin - title.Height(table.Title)*2 - 2*lineHeight
+	perPage := max(int(available/lineHeight), 1)
+
+	columns := tableColumns(table)
+	var pages []reportPage
+	for start := 0; start == 0 || start < len(table.Rows); start += perPage {
+		rows := table.Rows[start:min(start+perPage, len(table.Rows))]
+		heading := table.Title
+		if start > 0 {
+			heading += " (continued)"
+		}
+		pages = append(pages, func(dc draw.Canvas) {
+			y := dc.Max.Y - title.Height(heading)
+			dc.FillText(title, vg.Point{X: dc.Min.X, Y: y}, heading)
+			y -= title.Height(heading)
+
+			charWidth := cell.Width("M")
+			drawRow := func(values []string) {
+				x := dc.Min.X
+				for i, column := range columns {
+					text := ""
+					if i < len(values) {
+						text = truncateCell(values[i])
+					}
+					offset := vg.Length(0)
+					if column.numeric {
+						offset = vg.Length(column.width-utf8.RuneCountInString(text)) * charWidth
+					}
+					dc.FillText(cell, vg.Point{X: x + offset, Y: y}, text)
+					x += vg.Length(column.width+reportColumnGap) * charWidth
+				}
+				y -= lineHeight
+			}
+			drawRow(table.Header)
+			rule := y + lineHeight*0.6
+			dc.StrokeLine2(draw.LineStyle{Color: CurrentTheme.Text.Color.ToColor(), Width: vg.Points(0.5)},
+				dc.Min.X, rule, dc.Max.X, rule)
+			for _, row := range rows {
+				drawRow(row)
+			}
+		})
+	}
+	return pages
+}
+
+// tableColumn is the width in characters and the alignment of a table column
+type tableColumn struct {
+	width   int
+	numeric bool
+}
+
+func tableColumns(table ReportTable) []tableColumn {
+	columns := make([]tableColumn, len(table.Header))
+	for i, name := range table.Header {
+		columns[i] = tableColumn{width: utf8.RuneCountInString(truncateCell(name)), numeric: len(table.Rows) > 0}
+		for _, row := range table.Rows {
+			if i >= len(row) {
+				continue
+			}
+			columns[i].width = max(columns[i].width, utf8.RuneCountInString(truncateCell(row[i])))
+			if _, err := strconv.ParseFloat(strings.TrimSuffix(row[i], "%"), 64); err != nil && row[i] != "" {
+				columns[i].numeric = false
+			}
+		}
+	}
+	return columns
+}
+
+// truncateCell shortens a table cell to reportCellMax characters
+func truncateCell(text string) string {
+	if utf8.RuneCountInString(text) <= reportCellMax {
+		return text
+	}
+	return string([]rune(text)[:reportCellMax-1]) + "…"
+}
+
+// reportTextStyle returns a Liberation font style of the given variant in the theme's text color
+func reportTextStyle(variant string, size float64) draw.TextStyle {
+	style := plot.New().Title.TextStyle
+	style.Font = font.Font{Typeface: "Liberation", Variant: font.Variant(variant), Size: vg.Points(size)}
+	style.Color = CurrentTheme.Text.Color.ToColor()
+	style.XAlign, style.YAlign = draw.XLeft, draw.YBottom
+	return style
+}
+
+// insetCanvas returns the canvas area without a margin on every side
+func insetCanvas(dc draw.Canvas, margin vg.Length) draw.Canvas {
+	dc.Rectangle = vg.Rectangle{
+		Min: vg.Point{X: dc.Min.X + margin, Y: dc.Min.Y + margin},
+		Max: vg.Point{X: dc.Max.X - margin, Y: dc.Max.Y - margin},
+	}
+	return dc
+}
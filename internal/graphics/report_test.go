@@ -0,0 +1,69 @@
+package graphics
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gonum.org/v1/plot"
+)
+
+func TestWriteReport(t *testing.T) {
+	rows := make([][]string, 100)
+	for i := range rows {
+		rows[i] = []string{fmt.Sprintf("developer %d", i), fmt.Sprint(i * 10)}
+	}
+	report := &Report{
+		Title:     "Test report",
+		Fields:    []ReportField{{Name: "Repository", Value: "test-repo"}},
+		Notes:     []string{"sentiment: not available in reports"},
+		Charts:    []*plot.Plot{linePlot(t, 0, 1), linePlot(t, 100, 200)},
+		Tables:    []ReportTable{{Title: "Developers", Header: []string{"Developer", "Commits"}, Rows: rows}},
+		Generated: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	output := filepath.Join(t.TempDir(), "report.pdf")
+	if err := WriteReport(report, output); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		t.Error("the report is not a PDF file")
+	}
+
+	// Cover, two charts and a table spread over several pages
+	tablePageCount := len(tablePages(report.Tables[0]))
+	if tablePageCount < 2 {
+		t.Errorf("100 rows should not fit one page, got %d pages", tablePageCount)
+	}
+	if pages := bytes.Count(data, []byte("/Type /Page\n")); pages != 3+tablePageCount {
+		t.Errorf("expected %d pages, got %d", 3+tablePageCount, pages)
+	}
+
+	if err := WriteReport(report, filepath.Join(t.TempDir(), "report.png")); err == nil {
+		t.Error("expected an error for a non-PDF output")
+	}
+}
+
+func TestTableColumns(t *testing.T) {
+	long := "a very long developer name that does not fit into the column"
+	columns := tableColumns(ReportTable{
+		Header: []string{"Name", "Lines", "Share"},
+		Rows:   [][]string{{long, "10", "50.0%"}, {"short", "1200", "50.0%"}},
+	})
+	if columns[0].numeric || !columns[1].numeric || !columns[2].numeric {
+		t.Errorf("only number columns should be right-aligned, got %+v", columns)
+	}
+	if columns[0].width != reportCellMax {
+		t.Errorf("long cells should be cut to %d characters, got width %d", reportCellMax, columns[0].width)
+	}
+	if columns[1].width != 5 {
+		t.Errorf("expected the header width 5, got %d", columns[1].width)
+	}
+}
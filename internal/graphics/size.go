@@ -2,6 +2,7 @@ package graphics
 
 import (
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 
@@ -72,8 +73,7 @@ func GetPlotSize(chartType ChartType) (width, height vg.Length) {
 	sizeStr := viper.GetString("size")
 	width, height, err := ParsePlotSize(sizeStr, chartType)
 	if err != nil {
-		// Log error and use default
-		fmt.Printf("Warning: %v, using default size\n", err)
+		slog.Warn("invalid plot size, using default size", "error", err)
 		defaultSize := defaultSizes[chartType]
 		return vg.Length(defaultSize[0]) * vg.Inch, vg.Length(defaultSize[1]) * vg.Inch
 	}
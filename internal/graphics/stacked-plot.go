@@ -1,6 +1,7 @@
 package graphics
 
 import (
+	"context"
 	"fmt"
 	"image/color"
 	"math"
@@ -15,8 +16,9 @@ import (
 	"strings"
 )
 
-// PlotStackedBurndown generates a proper stacked area chart for burndown analysis
-func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output string, relative bool) error {
+// PlotStackedBurndown generates a proper stacked area chart for burndown analysis and adds
+// it to the report content of ctx
+func PlotStackedBurndown(ctx context.Context, matrix [][]float64, dateRange []time.Time, output string, relative bool) error {
 	if len(matrix) == 0 || len(dateRange) == 0 {
 		return fmt.Errorf("empty matrix or date range")
 	}
@@ -26,11 +28,51 @@ func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output strin
 	progEstimator := progress.NewProgressEstimator(!quiet)
 	
 	// Start multi-phase chart generation
-	totalPhases := 4 // setup, data processing, plotting, saving
+	totalPhases := 2 // plotting, saving
 	progEstimator.StartMultiOperation(totalPhases, "Chart Generation")
 
-	// Phase 1: Setup and validation
-	progEstimator.NextOperation("Setting up plot")
+	// Phase 1: Building the plot
+	progEstimator.NextOperation("Creating plot layers")
+	p, err := buildStackedBurndownPlot(matrix, dateRange, relative)
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	if err := AddActiveAnnotations(p); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	AddReportChart(ctx, p)
+
+	// Phase 2: Saving chart
+	progEstimator.NextOperation("Saving chart")
+	if IsTerminalOutput(output) {
+		progEstimator.FinishMultiOperation()
+		labels := make([]string, len(matrix))
+		for i := range labels {
+			labels[i] = fmt.Sprintf("Layer %d", i)
+		}
+		return WriteTerminal(p, TermStackedArea{
+			Title:  p.Title.Text,
+			Labels: labels,
+			Series: matrix,
+			Start:  dateRange[0],
+			End:    dateRange[len(dateRange)-1],
+		})
+	}
+	
+	width, height := GetPlotSize(ChartTypeDefault)
+	if err := SavePlotWithFormat(p, width, height, output); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+
+	progEstimator.FinishMultiOperation()
+	return nil
+}
+
+// buildStackedBurndownPlot creates the stacked burndown plot without saving it
+func buildStackedBurndownPlot(matrix [][]float64, dateRange []time.Time, relative bool) (*plot.Plot, error) {
 	p := plot.New()
 	p.Title.Text = "Burndown Chart"
 	p.X.Label.Text = "Time"
@@ -45,8 +87,7 @@ func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output strin
 	// Ensure matrix dimensions are consistent
 	numSeries := len(matrix)
 	if numSeries == 0 {
-		progEstimator.FinishMultiOperation()
-		return fmt.Errorf("empty matrix")
+		return nil, fmt.Errorf("empty matrix")
 	}
 
 	numPoints := len(matrix[0])
@@ -57,9 +98,6 @@ func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output strin
 		dateRange = dateRange[:minLen]
 	}
 
-	// Phase 2: Data processing
-	progEstimator.NextOperation("Processing chart data")
-	
 	// Convert dates to float64 for plotting (Unix timestamps)
 	timeValues := make([]float64, numPoints)
 	for i, date := range dateRange {
@@ -67,6 +105,7 @@ func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output strin
 	}
 
 	// Generate cumulative data for stacking
+	matrix = SmoothRows(matrix)
 	cumulative := make([][]float64, numSeries)
 	for i := range cumulative {
 		cumulative[i] = make([]float64, numPoints)
@@ -81,9 +120,6 @@ func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output strin
 	// Color palette for different series - use burndown-specific colors
 	colors := generateBurndownColorPalette(numSeries)
 
-	// Phase 3: Creating plot layers
-	progEstimator.NextOperation("Creating plot layers")
-
 	// Create stacked areas (bottom to top)
 	for i := numSeries - 1; i >= 0; i-- {
 		// Create data points for this layer
@@ -107,7 +143,7 @@ func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output strin
 
 		// Create polygon for this stacked area
 		if err := addStackedLayer(p, topPoints, bottomPoints, colors[i], fmt.Sprintf("Layer %d", i)); err != nil {
-			return fmt.Errorf("error adding layer %d: %v", i, err)
+			return nil, fmt.Errorf("error adding layer %d: %v", i, err)
 		}
 	}
 
@@ -119,18 +155,8 @@ func PlotStackedBurndown(matrix [][]float64, dateRange []time.Time, output strin
 		p.X.Min = timeValues[0]
 		p.X.Max = timeValues[len(timeValues)-1]
 	}
-
-	// Phase 4: Saving chart
-	progEstimator.NextOperation("Saving chart")
-	
-	width, height := GetPlotSize(ChartTypeDefault)
-	if err := SavePlotWithFormat(p, width, height, output); err != nil {
-		progEstimator.FinishMultiOperation()
-		return err
-	}
-
-	progEstimator.FinishMultiOperation()
-	return nil
+	ApplyTimeAxes(p)
+	return p, nil
 }
 
 // addStackedLayer adds a filled area between top and bottom curves
@@ -187,6 +213,9 @@ func generateColorPaletteFromTheme(n int) []color.Color {
 
 	themePalette := CurrentTheme.GetColorPalette()
 	opacity := uint8(float64(255) * CurrentTheme.Chart.FillOpacity)
+	if colors, ok := CurrentTheme.paletteBandColorsWithOpacity(n, opacity); ok {
+		return colors
+	}
 	
 	colors := make([]color.Color, n)
 	for i := 0; i < n; i++ {
@@ -406,6 +435,18 @@ func SavePlotWithFormat(p *plot.Plot, width, height vg.Length, output string) er
 
 // applyThemeToPlot applies the current theme's styling to a plot
 func applyThemeToPlot(p *plot.Plot) {
+	ApplyTheme(p)
+
+	// Apply grid styling
+	if CurrentTheme.Grid.Show {
+		// Enable grid lines
+		p.Add(plotter.NewGrid())
+	}
+}
+
+// ApplyTheme applies the current theme's text, background and legend styling to a plot.
+// Unlike the grid, these can be applied to a chart that already holds its data.
+func ApplyTheme(p *plot.Plot) {
 	// Apply text styling
 	if CurrentTheme.Text.TitleSize > 0 {
 		p.Title.TextStyle.Font.Size = vg.Points(CurrentTheme.Text.TitleSize)
@@ -421,12 +462,6 @@ func applyThemeToPlot(p *plot.Plot) {
 	// Apply background color
 	p.BackgroundColor = CurrentTheme.Background.ToColor()
 	
-	// Apply grid styling
-	if CurrentTheme.Grid.Show {
-		// Enable grid lines
-		p.Add(plotter.NewGrid())
-	}
-	
 	// Apply legend styling if enabled
 	if CurrentTheme.Chart.LegendShow {
 		p.Legend.TextStyle.Font.Size = vg.Points(CurrentTheme.Text.Size)
@@ -0,0 +1,552 @@
+package graphics
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	imagedraw "image/draw"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+)
+
+// TerminalOutput is the --output value that renders charts in the terminal instead of files
+const TerminalOutput = "term"
+
+// Terminal rendering styles selected with --term-style
+const (
+	TermStyleBlocks  = "blocks"  // half-block characters, two colored pixels per cell
+	TermStyleBraille = "braille" // braille dots, eight pixels per cell drawn as lines
+	TermStyleSixel   = "sixel"   // the full chart as a sixel image
+)
+
+const (
+	defaultTerminalWidth = 100
+	terminalChartRows    = 20 // text rows of area charts
+	terminalLabelWidth   = 24 // maximum width of bar and heatmap labels
+	terminalAxisWidth    = 10 // width of the value axis of area charts
+	sixelDPI             = 72
+)
+
+// terminalOut receives terminal charts; tests replace it to capture the output
+var terminalOut io.Writer = os.Stdout
+
+const ansiReset = "\x1b[0m"
+
+// barEighths are the partial block characters for 1/8 to 7/8 of a cell
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// IsTerminalOutput reports whether charts should be rendered in the terminal
+func IsTerminalOutput(output string) bool {
+	return output == TerminalOutput
+}
+
+// TerminalChart is chart data that can be drawn with text characters
+type TerminalChart interface {
+	renderTerminal(w io.Writer, style string, width int) error
+}
+
+// TermStackedArea is a stacked area chart such as a burndown; Series holds one row per band.
+type TermStackedArea struct {
+	Title  string
+	Labels []string
+	Series [][]float64
+	Start  time.Time
+	End    time.Time
+}
+
+// TermBars is a horizontal bar chart with one bar per label
+type TermBars struct {
+	Title  string
+	Labels []string
+	Values []float64
+	Unit   string
+}
+
+// TermHeatmap is a matrix drawn with the theme's heat colors
+type TermHeatmap struct {
+	Title  string
+	Rows   []string
+	Cols   []string
+	Matrix [][]float64
+}
+
+// WriteTerminal renders a chart in the terminal with the style chosen by --term-style.
+// The sixel style draws the plot itself, the text styles draw the chart data. Plots
+// without a text form (chart is nil) are only drawn as sixel images.
+func WriteTerminal(p *plot.Plot, chart TerminalChart) error {
+	style := viper.GetString("term-style")
+	if style == "" {
+		style = TermStyleBlocks
+	}
+
+	switch style {
+	case TermStyleSixel:
+		width, height := GetPlotSize(ChartTypeDefault)
+		return writeSixel(terminalOut, renderPlotImage(p, width, height, sixelDPI))
+	case TermStyleBlocks, TermStyleBraille:
+	default:
+		return fmt.Errorf("unknown terminal style '%s', available: %s, %s, %s", style, TermStyleBlocks, TermStyleBraille, TermStyleSixel)
+	}
+
+	if chart == nil {
+		_, err := fmt.Fprintf(terminalOut, "%s: use --term-style %s to draw this chart in the terminal\n", p.Title.Text, TermStyleSixel)
+		return err
+	}
+	out := bufio.NewWriter(terminalOut)
+	if err := chart.renderTerminal(out, style, terminalWidth()); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// terminalWidth returns the number of columns of the terminal, or a default when
+// the output is not a terminal.
+func terminalWidth() int {
+	if f, ok := terminalOut.(*os.File); ok {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultTerminalWidth
+}
+
+func (a TermStackedArea) renderTerminal(w io.Writer, style string, width int) error {
+	if len(a.Series) == 0 || len(a.Series[0]) == 0 {
+		return fmt.Errorf("empty chart data")
+	}
+	points := len(a.Series[0])
+	plotWidth := max(width-terminalAxisWidth-1, 10)
+
+	// Cumulative band tops for every time point
+	tops := make([][]float64, points)
+	maxTotal := 0.0
+	for i := range tops {
+		tops[i] = make([]float64, len(a.Series))
+		total := 0.0
+		for band, series := range a.Series {
+			if i < len(series) {
+				total += math.Max(series[i], 0)
+			}
+			tops[i][band] = total
+		}
+		maxTotal = math.Max(maxTotal, total)
+	}
+	if maxTotal == 0 {
+		maxTotal = 1
+	}
+	colors := CurrentTheme.BandColors(len(a.Series))
+
+	var canvas *termCanvas
+	if style == TermStyleBraille {
+		canvas = newTermCanvas(plotWidth*2, terminalChartRows*4)
+		for band := range a.Series {
+			previous := -1
+			for x := 0; x < canvas.width; x++ {
+				top := tops[sampleIndex(x, canvas.width, points)][band]
+				y := canvas.height - 1 - int(top/maxTotal*float64(canvas.height-1)+0.5)
+				if previous < 0 {
+					previous = y
+				}
+				for from, to := min(previous, y), max(previous, y); from <= to; from++ {
+					canvas.set(x, from, colors[band])
+				}
+				previous = y
+			}
+		}
+	} else {
+		canvas = newTermCanvas(plotWidth, terminalChartRows*2)
+		for x := 0; x < canvas.width; x++ {
+			column := tops[sampleIndex(x, canvas.width, points)]
+			for y := 0; y < canvas.height; y++ {
+				value := (float64(canvas.height-y) - 0.5) / float64(canvas.height) * maxTotal
+				for band, top := range column {
+					if top > value {
+						canvas.set(x, y, colors[band])
+						break
+					}
+				}
+			}
+		}
+	}
+
+	fmt.Fprintln(w, a.Title)
+	axis := func(row int) string {
+		label := ""
+		switch row {
+		case 0:
+			label = formatTermValue(maxTotal)
+		case terminalChartRows / 2:
+			label = formatTermValue(maxTotal / 2)
+		case terminalChartRows - 1:
+			label = "0"
+		}
+		return fmt.Sprintf("%*s ┤", terminalAxisWidth-2, label)
+	}
+	if style == TermStyleBraille {
+		canvas.writeBraille(w, axis)
+	} else {
+		canvas.writeBlocks(w, axis)
+	}
+
+	fmt.Fprintf(w, "%*s└%s\n", terminalAxisWidth-1, "", strings.Repeat("─", plotWidth))
+	if !a.Start.IsZero() && !a.End.IsZero() {
+		start, end := a.Start.Format("2006-01-02"), a.End.Format("2006-01-02")
+		fmt.Fprintf(w, "%*s%s%*s\n", terminalAxisWidth, "", start, max(plotWidth-len(start), len(end)+1), end)
+	}
+	writeTermLegend(w, a.Labels, colors, width)
+	return nil
+}
+
+func (b TermBars) renderTerminal(w io.Writer, style string, width int) error {
+	if len(b.Values) == 0 {
+		return fmt.Errorf("empty chart data")
+	}
+	labelWidth := 0
+	maxValue := 0.0
+	values := make([]string, len(b.Values))
+	valueWidth := 0
+	for i, value := range b.Values {
+		if i < len(b.Labels) {
+			labelWidth = max(labelWidth, min(utf8.RuneCountInString(b.Labels[i]), terminalLabelWidth))
+		}
+		maxValue = math.Max(maxValue, value)
+		values[i] = formatTermValue(value) + b.Unit
+		valueWidth = max(valueWidth, len(values[i]))
+	}
+	if maxValue <= 0 {
+		maxValue = 1
+	}
+	barWidth := max(width-labelWidth-valueWidth-3, 10)
+	fg := ansiColor(38, CurrentTheme.BandColors(1)[0])
+
+	fmt.Fprintln(w, b.Title)
+	for i, value := range b.Values {
+		label := ""
+		if i < len(b.Labels) {
+			label = truncateTermLabel(b.Labels[i], labelWidth)
+		}
+		eighths := int(math.Max(value, 0)/maxValue*float64(barWidth*8) + 0.5)
+		bar := strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+		padding := barWidth - eighths/8
+		if eighths%8 != 0 {
+			padding--
+		}
+		fmt.Fprintf(w, "%s %s%s%s%s %*s\n", padTermLabel(label, labelWidth), fg, bar, ansiReset,
+			strings.Repeat(" ", max(padding, 0)), valueWidth, values[i])
+	}
+	return nil
+}
+
+func (h TermHeatmap) renderTerminal(w io.Writer, style string, width int) error {
+	if len(h.Matrix) == 0 {
+		return fmt.Errorf("empty chart data")
+	}
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	columns := 0
+	for _, row := range h.Matrix {
+		columns = max(columns, len(row))
+		for _, value := range row {
+			minValue, maxValue = math.Min(minValue, value), math.Max(maxValue, value)
+		}
+	}
+	span := maxValue - minValue
+	if span == 0 {
+		span = 1
+	}
+
+	labelWidth := 0
+	for _, label := range h.Rows {
+		labelWidth = max(labelWidth, min(utf8.RuneCountInString(label), terminalLabelWidth))
+	}
+	indexWidth := len(strconv.Itoa(len(h.Matrix)))
+	rowPrefix := indexWidth + labelWidth + 2
+	shown := min(columns, max((width-rowPrefix)/2, 1))
+
+	fmt.Fprintln(w, h.Title)
+	// Column numbers, last two digits so that every column keeps two characters
+	fmt.Fprint(w, strings.Repeat(" ", rowPrefix))
+	for j := 0; j < shown; j++ {
+		fmt.Fprintf(w, "%2d", (j+1)%100)
+	}
+	fmt.Fprintln(w)
+	for i, row := range h.Matrix {
+		label := ""
+		if i < len(h.Rows) {
+			label = truncateTermLabel(h.Rows[i], labelWidth)
+		}
+		fmt.Fprintf(w, "%*d %s ", indexWidth, i+1, padTermLabel(label, labelWidth))
+		for j := 0; j < shown; j++ {
+			if j < len(row) {
+				fmt.Fprint(w, ansiColor(38, CurrentTheme.GetHeatColor((row[j]-minValue)/span)), "██", ansiReset)
+			} else {
+				fmt.Fprint(w, "  ")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	if shown < columns {
+		fmt.Fprintf(w, "(showing %d of %d columns)\n", shown, columns)
+	}
+	if len(h.Cols) > 0 && !equalStrings(h.Cols, h.Rows) {
+		names := make([]string, min(shown, len(h.Cols)))
+		for j := range names {
+			names[j] = fmt.Sprintf("%d %s", j+1, h.Cols[j])
+		}
+		fmt.Fprintf(w, "Columns: %s\n", strings.Join(names, ", "))
+	}
+
+	// Color scale from the smallest to the largest value
+	fmt.Fprintf(w, "%s ", formatTermValue(minValue))
+	for step := 0; step < 10; step++ {
+		fmt.Fprint(w, ansiColor(38, CurrentTheme.GetHeatColor(float64(step)/9)), "█", ansiReset)
+	}
+	fmt.Fprintf(w, " %s\n", formatTermValue(maxValue))
+	return nil
+}
+
+// termCanvas is a small raster of colored pixels; nil pixels show the terminal background
+type termCanvas struct {
+	width, height int
+	pixels        []color.Color
+}
+
+func newTermCanvas(width, height int) *termCanvas {
+	return &termCanvas{width: width, height: height, pixels: make([]color.Color, width*height)}
+}
+
+func (c *termCanvas) set(x, y int, col color.Color) {
+	if x >= 0 && x < c.width && y >= 0 && y < c.height {
+		c.pixels[y*c.width+x] = col
+	}
+}
+
+func (c *termCanvas) at(x, y int) color.Color {
+	if x < 0 || x >= c.width || y < 0 || y >= c.height {
+		return nil
+	}
+	return c.pixels[y*c.width+x]
+}
+
+// writeBlocks writes two pixel rows per text row with upper half blocks, using the
+// foreground color for the upper pixel and the background color for the lower one.
+func (c *termCanvas) writeBlocks(w io.Writer, prefix func(row int) string) {
+	for row := 0; row*2 < c.height; row++ {
+		if prefix != nil {
+			fmt.Fprint(w, prefix(row))
+		}
+		for x := 0; x < c.width; x++ {
+			top, bottom := c.at(x, row*2), c.at(x, row*2+1)
+			switch {
+			case top == nil && bottom == nil:
+				fmt.Fprint(w, " ")
+			case bottom == nil:
+				fmt.Fprint(w, ansiColor(38, top), "▀", ansiReset)
+			case top == nil:
+				fmt.Fprint(w, ansiColor(38, bottom), "▄", ansiReset)
+			default:
+				fmt.Fprint(w, ansiColor(38, top), ansiColor(48, bottom), "▀", ansiReset)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// brailleDots maps the pixel offsets within a cell to the bits of the braille pattern
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// writeBraille writes 2x4 pixels per text cell as braille dots. A cell has a single
+// color, the one most of its pixels have.
+func (c *termCanvas) writeBraille(w io.Writer, prefix func(row int) string) {
+	for row := 0; row*4 < c.height; row++ {
+		if prefix != nil {
+			fmt.Fprint(w, prefix(row))
+		}
+		for cell := 0; cell*2 < c.width; cell++ {
+			pattern := rune(0)
+			counts := make(map[color.Color]int)
+			var dominant color.Color
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					if col := c.at(cell*2+dx, row*4+dy); col != nil {
+						pattern |= brailleDots[dy][dx]
+						counts[col]++
+						if dominant == nil || counts[col] > counts[dominant] {
+							dominant = col
+						}
+					}
+				}
+			}
+			if pattern == 0 {
+				fmt.Fprint(w, " ")
+				continue
+			}
+			fmt.Fprint(w, ansiColor(38, dominant), string(0x2800+pattern), ansiReset)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeTermLegend lists the band labels with their colors, wrapped at the terminal width.
+func writeTermLegend(w io.Writer, labels []string, colors []color.Color, width int) {
+	column := 0
+	for i, label := range labels {
+		if i >= len(colors) {
+			break
+		}
+		entry := utf8.RuneCountInString(label) + 4
+		if column > 0 && column+entry > width {
+			fmt.Fprintln(w)
+			column = 0
+		}
+		fmt.Fprint(w, ansiColor(38, colors[i]), "■", ansiReset, " ", label, "  ")
+		column += entry
+	}
+	if column > 0 {
+		fmt.Fprintln(w)
+	}
+}
+
+// ansiColor returns the escape sequence selecting c as foreground (38) or background (48) color
+func ansiColor(layer int, c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", layer, n.R, n.G, n.B)
+}
+
+// formatTermValue formats axis and bar values compactly, e.g. 1.2k or 3.4M
+func formatTermValue(value float64) string {
+	abs := math.Abs(value)
+	switch {
+	case abs >= 1e9:
+		return strconv.FormatFloat(value/1e9, 'f', 1, 64) + "G"
+	case abs >= 1e6:
+		return strconv.FormatFloat(value/1e6, 'f', 1, 64) + "M"
+	case abs >= 1e4:
+		return strconv.FormatFloat(value/1e3, 'f', 1, 64) + "k"
+	case abs >= 100 || value == math.Trunc(value):
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	default:
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+}
+
+func truncateTermLabel(label string, width int) string {
+	if utf8.RuneCountInString(label) <= width {
+		return label
+	}
+	runes := []rune(label)
+	return string(runes[:width-1]) + "…"
+}
+
+func padTermLabel(label string, width int) string {
+	return label + strings.Repeat(" ", max(width-utf8.RuneCountInString(label), 0))
+}
+
+// sampleIndex maps column x of width columns onto one of points samples.
+func sampleIndex(x, width, points int) int {
+	if width <= 1 {
+		return 0
+	}
+	return min(x*(points-1)/(width-1), points-1)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// renderPlotImage draws a plot into an in-memory image.
+func renderPlotImage(p *plot.Plot, width, height vg.Length, dpi int) image.Image {
+	canvas := vgimg.NewWith(vgimg.UseWH(width, height), vgimg.UseDPI(dpi))
+	p.Draw(draw.New(canvas))
+	return canvas.Image()
+}
+
+// writeSixel encodes an image as sixel graphics with the 216 web-safe colors.
+func writeSixel(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette.WebSafe)
+	imagedraw.Draw(paletted, paletted.Bounds(), img, bounds.Min, imagedraw.Src)
+	width, height := paletted.Bounds().Dx(), paletted.Bounds().Dy()
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "\x1bPq\"1;1;%d;%d", width, height)
+	for i, c := range paletted.Palette {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(out, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+
+	sixels := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		used := make(map[uint8]bool)
+		var order []uint8
+		for y := top; y < min(top+6, height); y++ {
+			for x := 0; x < width; x++ {
+				if index := paletted.ColorIndexAt(x, y); !used[index] {
+					used[index] = true
+					order = append(order, index)
+				}
+			}
+		}
+		for n, index := range order {
+			for x := 0; x < width; x++ {
+				bits := byte(0)
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if paletted.ColorIndexAt(x, top+dy) == index {
+						bits |= 1 << dy
+					}
+				}
+				sixels[x] = '?' + bits
+			}
+			fmt.Fprintf(out, "#%d", index)
+			writeSixelRuns(out, sixels)
+			if n < len(order)-1 {
+				out.WriteByte('$')
+			}
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\")
+	return out.Flush()
+}
+
+// writeSixelRuns writes sixel characters with run-length encoding for repeats.
+func writeSixelRuns(out *bufio.Writer, sixels []byte) {
+	for start := 0; start < len(sixels); {
+		end := start
+		for end < len(sixels) && sixels[end] == sixels[start] {
+			end++
+		}
+		if run := end - start; run > 3 {
+			fmt.Fprintf(out, "!%d%c", run, sixels[start])
+		} else {
+			for i := 0; i < run; i++ {
+				out.WriteByte(sixels[start])
+			}
+		}
+		start = end
+	}
+}
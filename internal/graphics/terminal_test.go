@@ -0,0 +1,139 @@
+package graphics
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"gonum.org/v1/plot"
+)
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// captureTerminal renders a chart with the given style and returns the output without colors
+func captureTerminal(t *testing.T, style string, p *plot.Plot, chart TerminalChart) string {
+	t.Helper()
+	var buf bytes.Buffer
+	savedOut, savedStyle := terminalOut, viper.GetString("term-style")
+	terminalOut = &buf
+	viper.Set("term-style", style)
+	defer func() {
+		terminalOut = savedOut
+		viper.Set("term-style", savedStyle)
+	}()
+
+	if err := WriteTerminal(p, chart); err != nil {
+		t.Fatalf("WriteTerminal(%s) error = %v", style, err)
+	}
+	return buf.String()
+}
+
+func TestTerminalBars(t *testing.T) {
+	out := captureTerminal(t, TermStyleBlocks, plot.New(), TermBars{
+		Title:  "Languages",
+		Labels: []string{"Go", "a-very-long-language-name-that-is-cut"},
+		Values: []float64{1200, 600},
+	})
+	lines := strings.Split(strings.TrimRight(ansiPattern.ReplaceAllString(out, ""), "\n"), "\n")
+	if len(lines) != 3 || lines[0] != "Languages" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	full := strings.Count(lines[1], "█")
+	half := strings.Count(lines[2], "█")
+	if full == 0 || full-2*half < 0 || full-2*half > 1 {
+		t.Errorf("bar lengths should be proportional, got %d and %d", full, half)
+	}
+	if !strings.Contains(lines[2], "…") || !strings.HasSuffix(lines[1], "1200") {
+		t.Errorf("labels should be truncated and values shown:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestTerminalStackedArea(t *testing.T) {
+	chart := TermStackedArea{
+		Title:  "Burndown",
+		Labels: []string{"2023", "2024"},
+		Series: [][]float64{{10, 10, 10}, {0, 5, 10}},
+		Start:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for _, style := range []string{TermStyleBlocks, TermStyleBraille} {
+		out := captureTerminal(t, style, plot.New(), chart)
+		plain := ansiPattern.ReplaceAllString(out, "")
+		if strings.Count(plain, "┤") != terminalChartRows {
+			t.Errorf("%s: expected %d chart rows:\n%s", style, terminalChartRows, plain)
+		}
+		for _, want := range []string{"Burndown", "20 ┤", "2023-01-01", "2024-12-31", "■ 2024"} {
+			if !strings.Contains(plain, want) {
+				t.Errorf("%s: output is missing %q:\n%s", style, want, plain)
+			}
+		}
+		// Both bands are drawn with their theme colors
+		for _, c := range CurrentTheme.BandColors(2) {
+			if !strings.Contains(out, ansiColor(38, c)) {
+				t.Errorf("%s: band color %v is not used", style, c)
+			}
+		}
+	}
+}
+
+func TestTerminalHeatmap(t *testing.T) {
+	out := captureTerminal(t, TermStyleBlocks, plot.New(), TermHeatmap{
+		Title:  "Overwrites",
+		Rows:   []string{"alice", "bob"},
+		Cols:   []string{"alice", "bob"},
+		Matrix: [][]float64{{0, 4}, {2, 0}},
+	})
+	if !strings.Contains(out, ansiColor(38, CurrentTheme.GetHeatColor(1))) || !strings.Contains(out, ansiColor(38, CurrentTheme.GetHeatColor(0))) {
+		t.Errorf("heatmap should use the theme heat colors:\n%s", out)
+	}
+	plain := ansiPattern.ReplaceAllString(out, "")
+	for _, want := range []string{"1 alice ████", "2 bob   ████", "0 ██████████ 4"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("output is missing %q:\n%s", want, plain)
+		}
+	}
+}
+
+func TestTerminalSixel(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 7))
+	for x := 0; x < 8; x++ {
+		img.Set(x, 0, color.White)
+	}
+	var buf bytes.Buffer
+	if err := writeSixel(&buf, img); err != nil {
+		t.Fatalf("writeSixel() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1bPq\"1;1;8;7") || !strings.HasSuffix(out, "-\x1b\\") {
+		t.Errorf("unexpected sixel framing: %q", out)
+	}
+	// The white first row is a run of eight sixels with only the top bit set
+	if !strings.Contains(out, "!8@") {
+		t.Errorf("expected a run-length encoded white row: %q", out)
+	}
+	if strings.Count(out, "-") != 2 {
+		t.Errorf("seven rows need two sixel bands: %q", out)
+	}
+
+	p := plot.New()
+	p.Title.Text = "Scatter"
+	if out := captureTerminal(t, TermStyleSixel, p, nil); !strings.HasPrefix(out, "\x1bPq") {
+		t.Errorf("sixel style should draw the plot, got %q", out[:min(len(out), 20)])
+	}
+	if out := captureTerminal(t, TermStyleBlocks, p, nil); !strings.Contains(out, "--term-style sixel") {
+		t.Errorf("plots without text form should point to sixel, got %q", out)
+	}
+}
+
+func TestTerminalUnknownStyle(t *testing.T) {
+	viper.Set("term-style", "ascii-art")
+	defer viper.Set("term-style", "")
+	if err := WriteTerminal(plot.New(), TermBars{Values: []float64{1}}); err == nil {
+		t.Error("expected an error for an unknown terminal style")
+	}
+}
@@ -14,6 +14,29 @@ type Theme struct {
 	Text         TextStyle  `yaml:"text" json:"text"`
 	Chart        ChartStyle `yaml:"chart" json:"chart"`
 	HeatMap      HeatStyle  `yaml:"heatmap" json:"heatmap"`
+	// Palette names a built-in palette used instead of colors, e.g. "viridis"
+	Palette string `yaml:"palette,omitempty" json:"palette,omitempty"`
+	// ColorblindSafe draws bands with Okabe-Ito or cividis unless Palette names another one
+	ColorblindSafe bool `yaml:"colorblind_safe,omitempty" json:"colorblind_safe,omitempty"`
+	// Modes holds partial themes merged over this one for single modes, e.g. "heatmap"
+	Modes map[string]ThemeOverride `yaml:"modes,omitempty" json:"modes,omitempty"`
+}
+
+// ThemeOverride is a partial theme in YAML field names, deep-merged over a base theme
+type ThemeOverride map[string]interface{}
+
+// ThemeValidationError reports an invalid theme field by its YAML path
+type ThemeValidationError struct {
+	Path    string // e.g. "chart.fill_opacity"
+	Line    int    // line in the theme file, 0 if unknown
+	Message string
+}
+
+func (e *ThemeValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s (line %d): %s", e.Path, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
 }
 
 // ColorRGB represents an RGB color that can be serialized
@@ -288,12 +311,28 @@ var BuiltinThemes = map[string]Theme{
 	"minimal":   MinimalTheme,
 	"vibrant":   VibranthColorTheme,
 	"matplotlib": MatplotlibTheme,
+	"colorblind": ColorblindTheme,
 }
 
 // GetColorPalette returns the color palette as color.Color slice
 func (t *Theme) GetColorPalette() []color.Color {
-	colors := make([]color.Color, len(t.ColorPalette))
-	for i, c := range t.ColorPalette {
+	source := t.ColorPalette
+	if t.Palette != "" || t.ColorblindSafe {
+		name := t.Palette
+		if name == "" {
+			name = "okabe-ito"
+		}
+		if palette, exists := BuiltinPalettes[name]; exists {
+			size := len(palette.Colors)
+			if palette.Sequential {
+				size = 10
+			}
+			source = palette.Sample(size)
+		}
+	}
+	
+	colors := make([]color.Color, len(source))
+	for i, c := range source {
 		colors[i] = c.ToColor()
 	}
 	return colors
@@ -338,20 +377,24 @@ func (t *Theme) GetHeatColor(ratio float64) color.Color {
 
 // Validate checks if theme configuration is valid
 func (t *Theme) Validate() error {
-	if len(t.ColorPalette) == 0 {
-		return fmt.Errorf("theme must have at least one color in palette")
+	if t.Palette != "" {
+		if _, exists := BuiltinPalettes[t.Palette]; !exists {
+			return &ThemeValidationError{Path: "palette", Message: fmt.Sprintf("unknown palette '%s', available: %v", t.Palette, ListPalettes())}
+		}
+	} else if len(t.ColorPalette) == 0 && !t.ColorblindSafe {
+		return &ThemeValidationError{Path: "colors", Message: "theme must have at least one color in palette"}
 	}
 	
 	if t.Name == "" {
-		return fmt.Errorf("theme must have a name")
+		return &ThemeValidationError{Path: "name", Message: "theme must have a name"}
 	}
 	
 	if t.Text.Size <= 0 {
-		return fmt.Errorf("text size must be positive")
+		return &ThemeValidationError{Path: "text.size", Message: "text size must be positive"}
 	}
 	
 	if t.Chart.FillOpacity < 0 || t.Chart.FillOpacity > 1 {
-		return fmt.Errorf("fill opacity must be between 0 and 1")
+		return &ThemeValidationError{Path: "chart.fill_opacity", Message: "fill opacity must be between 0 and 1"}
 	}
 	
 	return nil
@@ -1,7 +1,9 @@
 package graphics
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,33 +30,89 @@ func NewThemeManager() *ThemeManager {
 	return tm
 }
 
-// LoadThemeFromFile loads a theme from a YAML file
+// LoadThemeFromFile loads a theme from a YAML file. The file may name a base theme with
+// "extends" and override any subset of its fields.
 func (tm *ThemeManager) LoadThemeFromFile(filepath string) error {
 	data, err := os.ReadFile(filepath)
 	if err != nil {
 		return fmt.Errorf("failed to read theme file %s: %w", filepath, err)
 	}
 	
-	var theme Theme
-	if err := yaml.Unmarshal(data, &theme); err != nil {
-		return fmt.Errorf("failed to parse theme file %s: %w", filepath, err)
+	theme, err := tm.ParseTheme(data)
+	if err != nil {
+		return fmt.Errorf("invalid theme in file %s: %w", filepath, err)
 	}
 	
+	tm.themes[theme.Name] = *theme
+	return nil
+}
+
+// ParseTheme builds a theme from YAML. With "extends: <name>" the fields set in the YAML are
+// deep-merged over the named theme; otherwise the YAML must describe a complete theme.
+func (tm *ThemeManager) ParseTheme(data []byte) (*Theme, error) {
+	file, err := parseThemeFile(data)
+	if err != nil {
+		return nil, err
+	}
+	
+	var base Theme
+	if file.Extends != "" {
+		extended, exists := tm.themes[file.Extends]
+		if !exists {
+			return nil, &ThemeValidationError{
+				Path:    themeExtendsKey,
+				Line:    file.line(themeExtendsKey),
+				Message: fmt.Sprintf("base theme '%s' not found", file.Extends),
+			}
+		}
+		base = extended
+	}
+	
+	theme, err := mergeTheme(base, file.Override)
+	if err != nil {
+		return nil, err
+	}
 	if err := theme.Validate(); err != nil {
-		return fmt.Errorf("invalid theme in file %s: %w", filepath, err)
+		return nil, locateThemeError(file, "", err)
 	}
 	
-	tm.themes[theme.Name] = theme
-	return nil
+	// Every mode override must result in a valid theme as well
+	for mode := range theme.Modes {
+		modeTheme, err := theme.ForMode(mode)
+		if err != nil {
+			return nil, err
+		}
+		if err := modeTheme.Validate(); err != nil {
+			return nil, locateThemeError(file, "modes."+mode+".", err)
+		}
+	}
+	
+	return &theme, nil
+}
+
+// locateThemeError prefixes a validation error path and adds the line it has in the file.
+func locateThemeError(file *themeFile, prefix string, err error) error {
+	var validationErr *ThemeValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+	located := *validationErr
+	located.Path = prefix + located.Path
+	if located.Line == 0 {
+		located.Line = file.line(located.Path)
+	}
+	return &located
 }
 
-// LoadThemesFromDirectory loads all theme files from a directory
+// LoadThemesFromDirectory loads all theme files from a directory. Themes may extend other
+// themes of the same directory regardless of the file order.
 func (tm *ThemeManager) LoadThemesFromDirectory(dirPath string) error {
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
 		return fmt.Errorf("failed to read theme directory %s: %w", dirPath, err)
 	}
 	
+	var pending []string
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -66,11 +124,28 @@ func (tm *ThemeManager) LoadThemesFromDirectory(dirPath string) error {
 			continue
 		}
 		
-		fullPath := filepath.Join(dirPath, fileName)
-		if err := tm.LoadThemeFromFile(fullPath); err != nil {
-			// Log warning but continue loading other themes
-			fmt.Printf("Warning: failed to load theme from %s: %v\n", fullPath, err)
+		pending = append(pending, filepath.Join(dirPath, fileName))
+	}
+	
+	// Retry failed files while others load, so that base themes are registered first
+	failures := make(map[string]error)
+	for len(pending) > 0 {
+		var failed []string
+		for _, fullPath := range pending {
+			if err := tm.LoadThemeFromFile(fullPath); err != nil {
+				failures[fullPath] = err
+				failed = append(failed, fullPath)
+			}
 		}
+		if len(failed) == len(pending) {
+			break
+		}
+		pending = failed
+	}
+	
+	for _, fullPath := range pending {
+		// Log warning but continue loading other themes
+		slog.Warn("failed to load theme", "path", fullPath, "error", failures[fullPath])
 	}
 	
 	return nil
@@ -134,31 +209,30 @@ func (tm *ThemeManager) RegisterTheme(theme Theme) error {
 	return nil
 }
 
-// CreateCustomTheme creates a custom theme based on an existing theme with modifications
+// CreateCustomTheme creates a custom theme based on an existing theme with modifications.
+// The customizations use the YAML field names of Theme and are deep-merged over the base.
 func (tm *ThemeManager) CreateCustomTheme(baseName string, customizations map[string]interface{}) (*Theme, error) {
 	base, err := tm.GetTheme(baseName)
 	if err != nil {
 		return nil, fmt.Errorf("base theme not found: %w", err)
 	}
 	
-	// Create a copy of the base theme
-	custom := *base
-	
-	// Apply customizations (simplified version - could be expanded)
-	if name, ok := customizations["name"].(string); ok {
-		custom.Name = name
+	// Round-trip through YAML so that the customizations are checked like a theme file
+	data, err := yaml.Marshal(customizations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid customizations: %w", err)
+	}
+	file, err := parseThemeFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid customizations: %w", err)
 	}
 	
-	if bg, ok := customizations["background"].(map[string]interface{}); ok {
-		if r, ok := bg["r"].(int); ok {
-			custom.Background.R = uint8(r)
-		}
-		if g, ok := bg["g"].(int); ok {
-			custom.Background.G = uint8(g)
-		}
-		if b, ok := bg["b"].(int); ok {
-			custom.Background.B = uint8(b)
-		}
+	custom, err := mergeTheme(*base, file.Override)
+	if err != nil {
+		return nil, err
+	}
+	if err := custom.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid custom theme: %w", err)
 	}
 	
 	return &custom, nil
@@ -182,7 +256,7 @@ func LoadUserThemes() error {
 	// Try to load from current directory themes/
 	if _, err := os.Stat("themes"); err == nil {
 		if err := GlobalThemeManager.LoadThemesFromDirectory("themes"); err != nil {
-			fmt.Printf("Warning: failed to load themes from ./themes: %v\n", err)
+			slog.Warn("failed to load themes", "path", "./themes", "error", err)
 		}
 	}
 	
@@ -192,7 +266,7 @@ func LoadUserThemes() error {
 		themeDir := filepath.Join(homeDir, ".labours-go", "themes")
 		if _, err := os.Stat(themeDir); err == nil {
 			if err := GlobalThemeManager.LoadThemesFromDirectory(themeDir); err != nil {
-				fmt.Printf("Warning: failed to load themes from %s: %v\n", themeDir, err)
+				slog.Warn("failed to load themes", "path", themeDir, "error", err)
 			}
 		}
 	}
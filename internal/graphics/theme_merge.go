@@ -0,0 +1,276 @@
+package graphics
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// themeExtendsKey names the base theme in a theme file
+const themeExtendsKey = "extends"
+
+// themeFile is a parsed theme file before it is merged over its base theme
+type themeFile struct {
+	Extends  string        // base theme name, empty for a complete theme
+	Override ThemeOverride // the fields set in the file
+	root     *yaml.Node    // mapping node of the document, for error line numbers
+}
+
+// parseThemeFile parses a full or partial theme and checks every field it sets against
+// the Theme structure, reporting unknown fields and wrong types by YAML path and line.
+func parseThemeFile(data []byte) (*themeFile, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil, fmt.Errorf("empty theme file")
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &ThemeValidationError{Path: "(root)", Line: root.Line, Message: "theme must be a mapping"}
+	}
+
+	file := &themeFile{root: root}
+	fields := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value == themeExtendsKey {
+			if value.Kind != yaml.ScalarNode || value.Value == "" {
+				return nil, &ThemeValidationError{Path: themeExtendsKey, Line: value.Line, Message: "expected the name of a theme"}
+			}
+			file.Extends = value.Value
+			continue
+		}
+		fields.Content = append(fields.Content, key, value)
+	}
+
+	if err := checkThemeNode(fields, reflect.TypeOf(Theme{}), ""); err != nil {
+		return nil, err
+	}
+	if err := fields.Decode(&file.Override); err != nil {
+		return nil, err
+	}
+	if file.Override == nil {
+		file.Override = ThemeOverride{}
+	}
+	return file, nil
+}
+
+// line returns the line of the field at the dotted YAML path, or 0 if the file does not set it.
+func (f *themeFile) line(path string) int {
+	node := f.root
+	line := 0
+	for _, key := range strings.Split(path, ".") {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return 0
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				line, next = node.Content[i].Line, node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return 0
+		}
+		node = next
+	}
+	return line
+}
+
+// checkThemeNode checks that a mapping node only sets fields of the struct type t with values
+// of the right type. Mode overrides are checked against the Theme structure as well.
+func checkThemeNode(node *yaml.Node, t reflect.Type, path string) error {
+	if node.Kind != yaml.MappingNode {
+		return &ThemeValidationError{Path: pathOrRoot(path), Line: node.Line, Message: "expected a mapping"}
+	}
+
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = t.Field(i)
+		}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		fieldPath := joinThemePath(path, key.Value)
+		field, ok := fields[key.Value]
+		if !ok {
+			return &ThemeValidationError{Path: fieldPath, Line: key.Line, Message: "unknown field"}
+		}
+		if value.Tag == "!!null" {
+			continue // keeps the base value
+		}
+		if err := checkThemeValue(value, field.Type, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkThemeValue checks a single field value against its Go type.
+func checkThemeValue(value *yaml.Node, t reflect.Type, path string) error {
+	switch {
+	case t == reflect.TypeOf(map[string]ThemeOverride{}):
+		if value.Kind != yaml.MappingNode {
+			return &ThemeValidationError{Path: path, Line: value.Line, Message: "expected a mapping of mode names to partial themes"}
+		}
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			if err := checkThemeNode(value.Content[i+1], reflect.TypeOf(Theme{}), joinThemePath(path, value.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case t.Kind() == reflect.Struct:
+		return checkThemeNode(value, t, path)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct:
+		if value.Kind != yaml.SequenceNode {
+			return &ThemeValidationError{Path: path, Line: value.Line, Message: "expected a list"}
+		}
+		for i, item := range value.Content {
+			if err := checkThemeNode(item, t.Elem(), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := value.Decode(reflect.New(t).Interface()); err != nil {
+		return &ThemeValidationError{Path: path, Line: value.Line, Message: fmt.Sprintf("cannot use %q as %s", value.Value, t)}
+	}
+	return nil
+}
+
+func joinThemePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// mergeTheme deep-merges a partial theme over base: mappings are merged field by field,
+// while lists such as the color palette and scalars replace the base value.
+func mergeTheme(base Theme, override ThemeOverride) (Theme, error) {
+	data, err := yaml.Marshal(base)
+	if err != nil {
+		return Theme{}, fmt.Errorf("failed to marshal base theme: %w", err)
+	}
+	var merged map[string]interface{}
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		return Theme{}, fmt.Errorf("failed to convert base theme: %w", err)
+	}
+	merged = deepMerge(merged, override)
+
+	if data, err = yaml.Marshal(merged); err != nil {
+		return Theme{}, fmt.Errorf("failed to marshal merged theme: %w", err)
+	}
+	var theme Theme
+	if err := yaml.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("failed to apply theme overrides: %w", err)
+	}
+	return theme, nil
+}
+
+// deepMerge merges src into dst recursively; nil values in src keep the dst value.
+func deepMerge(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{}, len(src))
+	}
+	for key, value := range src {
+		if value == nil {
+			continue
+		}
+		srcMap, srcIsMap := asStringMap(value)
+		dstMap, dstIsMap := asStringMap(dst[key])
+		if srcIsMap && dstIsMap {
+			dst[key] = deepMerge(dstMap, srcMap)
+		} else {
+			dst[key] = value
+		}
+	}
+	return dst
+}
+
+// asStringMap returns value as a map if it is a YAML mapping.
+func asStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case ThemeOverride:
+		return m, true
+	}
+	return nil, false
+}
+
+// ForMode returns the theme with the overrides for the given keys merged in order,
+// so that later keys take precedence. Unknown keys are ignored.
+func (t *Theme) ForMode(keys ...string) (Theme, error) {
+	theme := *t
+	for _, key := range keys {
+		override, ok := t.Modes[key]
+		if !ok {
+			continue
+		}
+		merged, err := mergeTheme(theme, override)
+		if err != nil {
+			return *t, fmt.Errorf("invalid theme override for mode %s: %w", key, err)
+		}
+		theme = merged
+	}
+	return theme, nil
+}
+
+// themeMu guards CurrentTheme and ColorPalette while modes run concurrently: modes
+// drawing with the current theme share it, a mode with its own overrides holds it alone.
+var themeMu sync.RWMutex
+
+// UseModeTheme activates the current theme's overrides for the given mode keys and returns
+// a function restoring the previous theme. The theme stays locked until restore is
+// called, so concurrent modes never see each other's overrides; UseModeTheme must not be
+// called again before then on the same goroutine.
+func UseModeTheme(keys ...string) (restore func(), err error) {
+	themeMu.RLock()
+	if !CurrentTheme.hasModeOverrides(keys...) {
+		return themeMu.RUnlock, nil
+	}
+	themeMu.RUnlock()
+
+	themeMu.Lock()
+	savedTheme, savedPalette := CurrentTheme, ColorPalette
+	restore = func() {
+		CurrentTheme, ColorPalette = savedTheme, savedPalette
+		themeMu.Unlock()
+	}
+	theme, err := CurrentTheme.ForMode(keys...)
+	if err != nil {
+		return restore, err
+	}
+	CurrentTheme = theme
+	ColorPalette = theme.GetColorPalette()
+	return restore, nil
+}
+
+// hasModeOverrides reports whether the theme overrides any of the given mode keys
+func (t *Theme) hasModeOverrides(keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := t.Modes[key]; ok {
+			return true
+		}
+	}
+	return false
+}
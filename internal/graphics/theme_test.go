@@ -2,8 +2,11 @@ package graphics
 
 import (
 	"image/color"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestThemeValidation(t *testing.T) {
@@ -257,4 +260,174 @@ func TestBuiltinThemes(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestThemeInheritance(t *testing.T) {
+	tm := NewThemeManager()
+
+	theme, err := tm.ParseTheme([]byte(`
+name: dark-large
+extends: dark
+text:
+  size: 14
+grid:
+  show: false
+colors:
+  - {r: 1, g: 2, b: 3, a: 255}
+`))
+	if err != nil {
+		t.Fatalf("ParseTheme() error = %v", err)
+	}
+
+	if theme.Name != "dark-large" || theme.Text.Size != 14 || theme.Grid.Show {
+		t.Errorf("overridden fields not applied: %+v", theme)
+	}
+	// Fields not set in the file are inherited field by field
+	if theme.Text.Color != DarkTheme.Text.Color || theme.Text.TitleSize != DarkTheme.Text.TitleSize {
+		t.Errorf("text style not deep-merged: %+v", theme.Text)
+	}
+	if theme.Background != DarkTheme.Background || theme.Grid.Color != DarkTheme.Grid.Color {
+		t.Errorf("inherited fields lost: background %+v, grid %+v", theme.Background, theme.Grid)
+	}
+	// Lists replace the base value
+	if len(theme.ColorPalette) != 1 || theme.ColorPalette[0] != (ColorRGB{R: 1, G: 2, B: 3, A: 255}) {
+		t.Errorf("palette should be replaced, got %v", theme.ColorPalette)
+	}
+	if DarkTheme.Text.Size != 10 || !DarkTheme.Grid.Show {
+		t.Errorf("base theme was modified")
+	}
+}
+
+func TestThemeDirectoryInheritanceOrder(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a-child.yaml": "name: child\nextends: parent\ntext: {size: 20}\n",
+		"b-parent.yaml": "name: parent\nextends: minimal\nbackground: {r: 1, g: 1, b: 1, a: 255}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tm := NewThemeManager()
+	if err := tm.LoadThemesFromDirectory(dir); err != nil {
+		t.Fatalf("LoadThemesFromDirectory() error = %v", err)
+	}
+	child, err := tm.GetTheme("child")
+	if err != nil {
+		t.Fatalf("child theme not loaded: %v", err)
+	}
+	if child.Text.Size != 20 || child.Background.R != 1 || child.Text.LabelSize != MinimalTheme.Text.LabelSize {
+		t.Errorf("child theme not merged over its parent chain: %+v", child)
+	}
+}
+
+func TestThemeErrorPaths(t *testing.T) {
+	tm := NewThemeManager()
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"unknown field", "name: x\nextends: dark\nchart:\n  fill_opacityy: 0.5\n", "chart.fill_opacityy (line 4): unknown field"},
+		{"wrong type", "name: x\nextends: dark\ntext:\n  size: big\n", "text.size (line 4)"},
+		{"out of range color", "name: x\nextends: dark\ncolors:\n  - {r: 300, g: 0, b: 0}\n", "colors[0].r (line 4)"},
+		{"invalid value", "name: x\nextends: dark\nchart:\n  fill_opacity: 1.5\n", "chart.fill_opacity (line 4): fill opacity must be between 0 and 1"},
+		{"missing base", "name: x\nextends: neon\n", "extends (line 2): base theme 'neon' not found"},
+		{"invalid mode override", "name: x\nextends: dark\nmodes:\n  heatmap:\n    text: {size: 0}\n", "modes.heatmap.text.size (line 5)"},
+		{"incomplete theme", "name: x\n", "colors: theme must have at least one color"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tm.ParseTheme([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestThemeModeOverrides(t *testing.T) {
+	tm := NewThemeManager()
+	theme, err := tm.ParseTheme([]byte(`
+name: reports
+extends: default
+modes:
+  heatmap:
+    colors:
+      - {r: 10, g: 20, b: 30, a: 255}
+    heatmap:
+      hot_color: {r: 200, g: 0, b: 0, a: 255}
+  overwrites-matrix:
+    text: {title_size: 20}
+`))
+	if err != nil {
+		t.Fatalf("ParseTheme() error = %v", err)
+	}
+
+	modeTheme, err := theme.ForMode("heatmap", "overwrites-matrix")
+	if err != nil {
+		t.Fatalf("ForMode() error = %v", err)
+	}
+	if modeTheme.ColorPalette[0] != (ColorRGB{R: 10, G: 20, B: 30, A: 255}) || modeTheme.HeatMap.HotColor.R != 200 {
+		t.Errorf("heatmap override not applied: %+v", modeTheme)
+	}
+	if modeTheme.HeatMap.ColdColor != DefaultTheme.HeatMap.ColdColor || modeTheme.Text.TitleSize != 20 {
+		t.Errorf("mode overrides not deep-merged: %+v", modeTheme)
+	}
+
+	saved := CurrentTheme
+	CurrentTheme = *theme
+	restore, err := UseModeTheme("heatmap")
+	if err != nil {
+		t.Fatalf("UseModeTheme() error = %v", err)
+	}
+	if CurrentTheme.ColorPalette[0].R != 10 || GetColor(0) != (color.RGBA{R: 10, G: 20, B: 30, A: 255}) {
+		t.Errorf("mode theme not active: %+v", CurrentTheme.ColorPalette)
+	}
+	restore()
+	if CurrentTheme.ColorPalette[0] != DefaultTheme.ColorPalette[0] {
+		t.Errorf("theme not restored")
+	}
+
+	// A mode with overrides waits for the modes sharing the theme
+	restoreShared, _ := UseModeTheme("devs")
+	acquired, released := make(chan struct{}), make(chan struct{})
+	go func() {
+		restore, _ := UseModeTheme("heatmap")
+		close(acquired)
+		restore()
+		close(released)
+	}()
+	select {
+	case <-acquired:
+		t.Error("mode overrides applied while another mode draws with the theme")
+	case <-time.After(20 * time.Millisecond):
+	}
+	restoreShared()
+	<-acquired
+	<-released
+	CurrentTheme = saved
+}
+
+func TestCreateCustomTheme(t *testing.T) {
+	tm := NewThemeManager()
+	custom, err := tm.CreateCustomTheme("default", map[string]interface{}{
+		"name":       "custom",
+		"background": map[string]interface{}{"r": 10, "g": 20, "b": 30},
+		"chart":      map[string]interface{}{"fill_opacity": 0.4},
+	})
+	if err != nil {
+		t.Fatalf("CreateCustomTheme() error = %v", err)
+	}
+	if custom.Name != "custom" || custom.Background.B != 30 || custom.Background.A != 255 || custom.Chart.FillOpacity != 0.4 {
+		t.Errorf("customizations not applied: %+v", custom)
+	}
+	if custom.Chart.LineWidth != DefaultTheme.Chart.LineWidth {
+		t.Errorf("unset chart fields should be inherited")
+	}
+
+	if _, err := tm.CreateCustomTheme("default", map[string]interface{}{"backgroud": "white"}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
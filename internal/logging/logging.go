@@ -0,0 +1,188 @@
+// Package logging configures the log/slog logger used for mode lifecycle events,
+// warnings and errors.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Log formats accepted by --log-format
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Setup installs the default slog logger writing to w. The text format prints
+// "Warning: message: error (key=value)" lines for people, the JSON format one object per
+// event for CI and web front-ends. An empty level logs warnings and errors in text
+// format and everything from info up in JSON format.
+func Setup(w io.Writer, format, level string) error {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "":
+		lvl = slog.LevelWarn
+		if format == FormatJSON {
+			lvl = slog.LevelInfo
+		}
+	case "debug":
+		lvl = slog.LevelDebug
+	case "info":
+		lvl = slog.LevelInfo
+	case "warn", "warning":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		return fmt.Errorf("unknown log level '%s', available: debug, info, warn, error", level)
+	}
+
+	var handler slog.Handler
+	switch format {
+	case FormatText, "":
+		handler = &plainHandler{w: w, level: lvl, mu: &sync.Mutex{}}
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl})
+	default:
+		return fmt.Errorf("unknown log format '%s', available: %s, %s", format, FormatText, FormatJSON)
+	}
+	slog.SetDefault(slog.New(&routingHandler{next: handler}))
+	return nil
+}
+
+// bufferKey is the context key of a Buffer
+type bufferKey struct{}
+
+// Buffer holds the log events of one of several concurrently running modes, so that
+// they can be written in a fixed order rather than interleaved
+type Buffer struct {
+	mu      sync.Mutex
+	flushed bool
+	entries []bufferedRecord
+}
+
+type bufferedRecord struct {
+	handler slog.Handler
+	record  slog.Record
+}
+
+// WithBuffer returns a context whose events, logged with the slog *Context functions,
+// are held in the returned buffer until it is flushed
+func WithBuffer(ctx context.Context) (context.Context, *Buffer) {
+	buffer := &Buffer{}
+	return context.WithValue(ctx, bufferKey{}, buffer), buffer
+}
+
+// Flush writes the held events; later events of the buffer's context are written
+// directly. Flushing a nil buffer does nothing.
+func (b *Buffer) Flush() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, entry := range b.entries {
+		entry.handler.Handle(context.Background(), entry.record)
+	}
+	b.entries, b.flushed = nil, true
+}
+
+// hold keeps the record for Flush and reports false once the buffer has been flushed
+func (b *Buffer) hold(handler slog.Handler, r slog.Record) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.flushed {
+		return false
+	}
+	b.entries = append(b.entries, bufferedRecord{handler: handler, record: r.Clone()})
+	return true
+}
+
+// routingHandler passes records to the buffer of their context, if any
+type routingHandler struct {
+	next slog.Handler
+}
+
+func (h *routingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *routingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if buffer, ok := ctx.Value(bufferKey{}).(*Buffer); ok && buffer.hold(h.next, r) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *routingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &routingHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *routingHandler) WithGroup(name string) slog.Handler {
+	return &routingHandler{next: h.next.WithGroup(name)}
+}
+
+// plainHandler writes records as single human-readable lines without timestamps
+type plainHandler struct {
+	w     io.Writer
+	level slog.Level
+	attrs []slog.Attr
+	mu    *sync.Mutex
+}
+
+func (h *plainHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *plainHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+		b.WriteString("Error: ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("Warning: ")
+	case r.Level < slog.LevelInfo:
+		b.WriteString("Debug: ")
+	}
+	b.WriteString(r.Message)
+
+	// The error explains the message, the other attributes give context
+	var context []string
+	add := func(a slog.Attr) bool {
+		if a.Key == "error" {
+			b.WriteString(": ")
+			b.WriteString(a.Value.String())
+		} else {
+			context = append(context, a.Key+"="+a.Value.String())
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(add)
+	if len(context) > 0 {
+		b.WriteString(" (" + strings.Join(context, ", ") + ")")
+	}
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *plainHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// WithGroup is not used by labours; groups are flattened into the attributes
+func (h *plainHandler) WithGroup(string) slog.Handler {
+	return h
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetupText(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	var buf bytes.Buffer
+	if err := Setup(&buf, FormatText, ""); err != nil {
+		t.Fatal(err)
+	}
+	slog.Info("mode started", "mode", "devs")
+	slog.Warn("failed to save SVG", "error", errors.New("disk full"), "output", "a.svg")
+	slog.Error("mode failed", "mode", "devs")
+
+	want := "Warning: failed to save SVG: disk full (output=a.svg)\nError: mode failed (mode=devs)\n"
+	if buf.String() != want {
+		t.Errorf("unexpected text log:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestSetupJSON(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	var buf bytes.Buffer
+	if err := Setup(&buf, FormatJSON, ""); err != nil {
+		t.Fatal(err)
+	}
+	slog.Debug("hidden")
+	slog.Info("mode finished", "mode", "devs")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one event, got %q", buf.String())
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatalf("event is not JSON: %v", err)
+	}
+	if event["level"] != "INFO" || event["msg"] != "mode finished" || event["mode"] != "devs" {
+		t.Errorf("unexpected event %v", event)
+	}
+}
+
+func TestSetupInvalid(t *testing.T) {
+	if err := Setup(&bytes.Buffer{}, "xml", ""); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if err := Setup(&bytes.Buffer{}, FormatText, "loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	var buf bytes.Buffer
+	if err := Setup(&buf, FormatText, ""); err != nil {
+		t.Fatal(err)
+	}
+	first, firstBuffer := WithBuffer(context.Background())
+	second, secondBuffer := WithBuffer(context.Background())
+	slog.WarnContext(second, "second")
+	slog.Default().With("mode", "devs").WarnContext(first, "first")
+	slog.InfoContext(first, "hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("buffered events written early: %q", buf.String())
+	}
+	firstBuffer.Flush()
+	secondBuffer.Flush()
+	slog.WarnContext(first, "after")
+
+	want := "Warning: first (mode=devs)\nWarning: second\nWarning: after\n"
+	if buf.String() != want {
+		t.Errorf("unexpected log:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,35 @@
+package modes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"labours-go/internal/graphics"
+)
+
+// animateChart writes the animated version of a chart if --animate is set. Animations are
+// skipped for terminal and JSON output, which have no frames to show, and when ctx is
+// cancelled.
+func animateChart(ctx context.Context, output string, animate func(*graphics.AnimationConfig) (string, error)) error {
+	config, err := graphics.AnimationFromConfig()
+	if err != nil || config == nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if graphics.IsTerminalOutput(output) || filepath.Ext(output) == ".json" {
+		return nil
+	}
+
+	path, err := animate(config)
+	if err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Animation saved to %s\n", path)
+	}
+	return nil
+}
@@ -0,0 +1,496 @@
+package modes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"labours-go/internal/burndown"
+	"labours-go/internal/graphics"
+	"labours-go/internal/progress"
+	"labours-go/internal/readers"
+)
+
+const (
+	// madScale makes the MAD a consistent estimator of the standard deviation
+	madScale = 1.4826
+	// spikeMinRatio additionally requires spikes to be this many times the median, so that
+	// tiny deviations in very regular histories are not reported
+	spikeMinRatio = 2
+	// activityWindowTicks is the size of the blocks commits are summed over to detect drops
+	activityWindowTicks = 7
+	// activityBaselineWindows is the number of preceding blocks the drop baseline is
+	// taken from
+	activityBaselineWindows = 8
+	// activityDropRatio flags a block whose commits fall to this fraction of the baseline
+	activityDropRatio = 0.2
+	// activityMinBaseline ignores drops from an already quiet baseline (commits per block)
+	activityMinBaseline = 3
+)
+
+// Anomaly kinds
+const (
+	AnomalyAddedSpike   = "added-spike"
+	AnomalyRemovedSpike = "removed-spike"
+	AnomalyActivityDrop = "activity-drop"
+)
+
+// Anomaly is an unusual tick in the developer activity time series. For spikes Score is
+// the robust z-score of the value; for activity drops it is the relative change to the
+// baseline.
+type Anomaly struct {
+	Tick         int       `json:"tick"`
+	Date         time.Time `json:"date"`
+	Kind         string    `json:"kind"`
+	Value        float64   `json:"value"`
+	Baseline     float64   `json:"baseline"`
+	Score        float64   `json:"score"`
+	TopDeveloper string    `json:"top_developer,omitempty"`
+}
+
+// activitySeries is the project-wide activity per tick
+type activitySeries struct {
+	Dates     []time.Time
+	Added     []float64
+	Removed   []float64
+	Commits   []float64
+	TopAdded  []string // developer with the most added lines on each tick
+	TopRemove []string // developer with the most removed lines on each tick
+}
+
+// Anomalies flags unusual spikes in added/removed lines (mass reformatting, vendoring, code
+// dumps) and abrupt drops in activity, annotates them on the project burndown, devs and
+// churn charts and lists them in a report table. It stops between charts when ctx is
+// cancelled.
+func Anomalies(ctx context.Context, reader readers.Reader, output string, threshold float64) error {
+	quiet := viper.GetBool("quiet")
+	progEstimator := progress.NewProgressEstimator(!quiet)
+
+	totalPhases := 4 // data extraction, detection, report, plotting
+	progEstimator.StartMultiOperation(totalPhases, "Anomaly Detection")
+
+	// Phase 1: Extract developer time series
+	progEstimator.NextOperation("Extracting developer time series")
+	series, err := loadActivitySeries(reader)
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+
+	// Phase 2: Detect anomalies
+	progEstimator.NextOperation("Detecting anomalies")
+	anomalies := detectAnomalies(series, threshold)
+	graphics.AddReportTable(ctx, anomaliesReportTable(anomalies))
+
+	// Phase 3: Report
+	progEstimator.NextOperation("Writing report")
+	if strings.ToLower(filepath.Ext(output)) == ".json" {
+		progEstimator.FinishMultiOperation()
+		return saveAnomaliesAsJSON(output, anomalies, threshold)
+	}
+	// An image path is used as prefix for all outputs, anything else as a directory
+	prefix, ext := filepath.Join(output, "anomalies"), ".png"
+	if e := filepath.Ext(output); e != "" {
+		prefix, ext = strings.TrimSuffix(output, e), e
+		if prefix == "" || strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix = filepath.Join(prefix, "anomalies")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(prefix), os.ModePerm); err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("failed to create output directory %s: %v", filepath.Dir(prefix), err)
+	}
+	if !quiet {
+		printAnomalyTable(anomalies)
+	}
+	if err := saveAnomaliesAsJSON(prefix+".json", anomalies, threshold); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+
+	// Phase 4: Annotated charts
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	progEstimator.NextOperation("Generating visualization")
+	annotations := anomalyAnnotations(anomalies)
+	if err := plotChurnWithAnomalies(ctx, series, annotations, prefix+"_churn"+ext); err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("failed to plot churn anomalies: %v", err)
+	}
+	if header, name, matrix, err := reader.GetProjectBurndownWithHeader(); err == nil {
+		processed, err := burndown.LoadBurndownContext(ctx, header, name, matrix, viper.GetString("resample"), false, false)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			progEstimator.FinishMultiOperation()
+			return ctxErr
+		}
+		if err == nil {
+			err = graphics.PlotBurndownWithAnnotations(ctx, processed, annotations, prefix+"_burndown"+ext, false)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "skipping annotated burndown chart", "error", err)
+		}
+	} else {
+		slog.WarnContext(ctx, "no burndown data available, skipping annotated burndown chart")
+	}
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	if err := plotDevsWithAnomalies(ctx, reader, annotations, prefix+"_devs"+ext); err != nil {
+		slog.WarnContext(ctx, "skipping annotated devs chart", "error", err)
+	}
+
+	progEstimator.FinishMultiOperation()
+	if !quiet {
+		fmt.Printf("Anomaly detection found %d anomalies, results saved to %s*\n", len(anomalies), prefix)
+	}
+	return nil
+}
+
+// DetectAnomalies returns the anomalies in the reader's developer activity, sorted by tick.
+func DetectAnomalies(reader readers.Reader, threshold float64) ([]Anomaly, error) {
+	series, err := loadActivitySeries(reader)
+	if err != nil {
+		return nil, err
+	}
+	return detectAnomalies(series, threshold), nil
+}
+
+// loadActivitySeries reads the developer ticks and builds the project-wide activity series.
+func loadActivitySeries(reader readers.Reader) (activitySeries, error) {
+	devData, err := reader.GetDeveloperTimeSeriesData()
+	if err != nil {
+		return activitySeries{}, fmt.Errorf("failed to get developer time series: %v", err)
+	}
+	begin, _ := reader.GetHeader()
+	series := buildActivitySeries(devData, time.Unix(begin, 0).UTC())
+	if len(series.Dates) == 0 {
+		return activitySeries{}, fmt.Errorf("no developer activity found")
+	}
+	return series, nil
+}
+
+// buildActivitySeries sums the developer ticks into dense project-wide series.
+func buildActivitySeries(devData *readers.DeveloperTimeSeriesData, begin time.Time) activitySeries {
+	var series activitySeries
+	if devData == nil || len(devData.Days) == 0 {
+		return series
+	}
+
+	maxTick := 0
+	for tick := range devData.Days {
+		if tick > maxTick {
+			maxTick = tick
+		}
+	}
+	tickSize := devData.TickSize
+	if tickSize <= 0 {
+		tickSize = 86400 // hercules' default tick is one day
+	}
+
+	n := maxTick + 1
+	series.Dates = make([]time.Time, n)
+	series.Added = make([]float64, n)
+	series.Removed = make([]float64, n)
+	series.Commits = make([]float64, n)
+	series.TopAdded = make([]string, n)
+	series.TopRemove = make([]string, n)
+	for tick := 0; tick < n; tick++ {
+		series.Dates[tick] = begin.Add(time.Duration(float64(tick) * tickSize * float64(time.Second)))
+	}
+
+	for tick, devs := range devData.Days {
+		if tick < 0 {
+			continue
+		}
+		maxAdded, maxRemoved := -1, -1
+		for devIdx, day := range devs {
+			series.Added[tick] += float64(day.LinesAdded)
+			series.Removed[tick] += float64(day.LinesRemoved)
+			series.Commits[tick] += float64(day.Commits)
+
+			name := fmt.Sprintf("developer %d", devIdx)
+			if devIdx >= 0 && devIdx < len(devData.People) {
+				name = strings.Split(devData.People[devIdx], "|")[0]
+			}
+			if day.LinesAdded > maxAdded || (day.LinesAdded == maxAdded && name < series.TopAdded[tick]) {
+				maxAdded, series.TopAdded[tick] = day.LinesAdded, name
+			}
+			if day.LinesRemoved > maxRemoved || (day.LinesRemoved == maxRemoved && name < series.TopRemove[tick]) {
+				maxRemoved, series.TopRemove[tick] = day.LinesRemoved, name
+			}
+		}
+	}
+	return series
+}
+
+// detectAnomalies runs spike and drop detection and returns the anomalies sorted by tick.
+func detectAnomalies(series activitySeries, threshold float64) []Anomaly {
+	var anomalies []Anomaly
+	for _, spike := range detectSpikes(series.Added, threshold) {
+		spike.Kind = AnomalyAddedSpike
+		spike.Date = series.Dates[spike.Tick]
+		spike.TopDeveloper = series.TopAdded[spike.Tick]
+		anomalies = append(anomalies, spike)
+	}
+	for _, spike := range detectSpikes(series.Removed, threshold) {
+		spike.Kind = AnomalyRemovedSpike
+		spike.Date = series.Dates[spike.Tick]
+		spike.TopDeveloper = series.TopRemove[spike.Tick]
+		anomalies = append(anomalies, spike)
+	}
+	for _, drop := range detectActivityDrops(series.Commits) {
+		drop.Kind = AnomalyActivityDrop
+		drop.Date = series.Dates[drop.Tick]
+		anomalies = append(anomalies, drop)
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return anomalies[i].Tick < anomalies[j].Tick
+	})
+	return anomalies
+}
+
+// detectSpikes flags active ticks whose robust z-score (median/MAD over the active ticks)
+// exceeds the threshold and which are at least spikeMinRatio times the median. Inactive
+// ticks are ignored so that sparse histories do not collapse the MAD to zero.
+func detectSpikes(values []float64, threshold float64) []Anomaly {
+	var active []float64
+	for _, v := range values {
+		if v > 0 {
+			active = append(active, v)
+		}
+	}
+	if len(active) < 3 {
+		return nil
+	}
+
+	median := medianOf(active)
+	deviations := make([]float64, len(active))
+	for i, v := range active {
+		deviations[i] = math.Abs(v - median)
+	}
+	scale := madScale * medianOf(deviations)
+	if scale == 0 {
+		// More than half of the active ticks are identical; fall back to the mean deviation
+		mean := 0.0
+		for _, d := range deviations {
+			mean += d
+		}
+		scale = math.Sqrt(math.Pi/2) * mean / float64(len(deviations))
+	}
+	if scale == 0 {
+		return nil
+	}
+
+	var spikes []Anomaly
+	for tick, v := range values {
+		if v <= 0 {
+			continue
+		}
+		if score := (v - median) / scale; score > threshold && v >= spikeMinRatio*median {
+			spikes = append(spikes, Anomaly{Tick: tick, Value: v, Baseline: median, Score: score})
+		}
+	}
+	return spikes
+}
+
+// detectActivityDrops sums commits over blocks of activityWindowTicks and flags the first
+// block of every run which falls to activityDropRatio of the median of the preceding
+// blocks.
+func detectActivityDrops(commits []float64) []Anomaly {
+	var blocks []float64
+	for start := 0; start < len(commits); start += activityWindowTicks {
+		sum := 0.0
+		for tick := start; tick < start+activityWindowTicks && tick < len(commits); tick++ {
+			sum += commits[tick]
+		}
+		blocks = append(blocks, sum)
+	}
+
+	var drops []Anomaly
+	inDrop := false
+	for b := activityBaselineWindows / 2; b < len(blocks); b++ {
+		from := b - activityBaselineWindows
+		if from < 0 {
+			from = 0
+		}
+		baseline := medianOf(append([]float64{}, blocks[from:b]...))
+		dropped := baseline >= activityMinBaseline && blocks[b] <= baseline*activityDropRatio
+		if dropped && !inDrop {
+			drops = append(drops, Anomaly{
+				Tick:     b * activityWindowTicks,
+				Value:    blocks[b],
+				Baseline: baseline,
+				Score:    (blocks[b] - baseline) / baseline,
+			})
+		}
+		inDrop = dropped
+	}
+	return drops
+}
+
+// medianOf returns the median of values, sorting them in place.
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// anomalyAnnotations converts anomalies into chart annotations.
+func anomalyAnnotations(anomalies []Anomaly) []graphics.Annotation {
+	annotations := make([]graphics.Annotation, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		var markerColor color.Color
+		switch anomaly.Kind {
+		case AnomalyAddedSpike:
+			markerColor = color.RGBA{R: 44, G: 160, B: 44, A: 255}
+		case AnomalyRemovedSpike:
+			markerColor = color.RGBA{R: 214, G: 39, B: 40, A: 255}
+		default:
+			markerColor = color.RGBA{R: 127, G: 127, B: 127, A: 255}
+		}
+		annotations = append(annotations, graphics.Annotation{
+			Time:  anomaly.Date,
+			Label: fmt.Sprintf("%s %s", anomaly.Kind, anomaly.Date.Format("2006-01-02")),
+			Color: markerColor,
+		})
+	}
+	return annotations
+}
+
+// plotDevsWithAnomalies plots the weekly commits of the --max-people top developers with
+// anomaly markers
+func plotDevsWithAnomalies(ctx context.Context, reader readers.Reader, annotations []graphics.Annotation, output string) error {
+	stats, err := topDevelopers(reader)
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		return fmt.Errorf("no developer stats available")
+	}
+	series := generateTimeSeries(stats)
+	begin, end := annotationTimeRange(reader, nil, nil)
+	return plotDevs(ctx, stats, series, clusterDevelopers(series), output, begin, end, annotations)
+}
+
+// plotChurnWithAnomalies plots added and removed lines per tick with anomaly markers.
+func plotChurnWithAnomalies(ctx context.Context, series activitySeries, annotations []graphics.Annotation, output string) error {
+	p, err := buildChurnPlot(series)
+	if err != nil {
+		return err
+	}
+	if err := graphics.AddTimeAnnotations(p, append(annotations, graphics.ActiveAnnotations()...)); err != nil {
+		return err
+	}
+	graphics.AddReportChart(ctx, p)
+
+	width, height := graphics.GetPlotSize(graphics.ChartTypeWide)
+	if err := graphics.SavePlotWithFormat(p, width, height, output); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved churn anomalies plot to %s\n", output)
+	}
+	return nil
+}
+
+// buildChurnPlot creates the chart of added and removed lines per tick without markers.
+func buildChurnPlot(series activitySeries) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = "Code Churn Anomalies"
+	p.X.Label.Text = "Time"
+	p.Y.Label.Text = "Lines per tick"
+	p.X.Tick.Marker = &graphics.TimeTicker{Format: "2006-01-02"}
+
+	for i, s := range []struct {
+		name   string
+		values []float64
+	}{
+		{"Added", series.Added},
+		{"Removed", series.Removed},
+	} {
+		pts := make(plotter.XYs, len(s.values))
+		for tick, v := range s.values {
+			pts[tick].X = float64(series.Dates[tick].Unix())
+			pts[tick].Y = v
+		}
+		line, err := plotter.NewLine(pts)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s line: %v", strings.ToLower(s.name), err)
+		}
+		line.Color = graphics.ColorPalette[i%len(graphics.ColorPalette)]
+		p.Add(line)
+		p.Legend.Add(s.name, line)
+	}
+	p.Legend.Top = true
+
+	return p, nil
+}
+
+// printAnomalyTable prints the anomaly report as a table.
+func printAnomalyTable(anomalies []Anomaly) {
+	if len(anomalies) == 0 {
+		fmt.Println("No anomalies detected.")
+		return
+	}
+	fmt.Printf("\n%-12s %-15s %12s %12s %8s  %s\n", "Date", "Kind", "Value", "Baseline", "Score", "Top developer")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, anomaly := range anomalies {
+		fmt.Printf("%-12s %-15s %12.0f %12.1f %8.2f  %s\n",
+			anomaly.Date.Format("2006-01-02"), anomaly.Kind, anomaly.Value, anomaly.Baseline, anomaly.Score, anomaly.TopDeveloper)
+	}
+	fmt.Println()
+}
+
+// saveAnomaliesAsJSON writes the anomaly report.
+func saveAnomaliesAsJSON(output string, anomalies []Anomaly, threshold float64) error {
+	if anomalies == nil {
+		anomalies = []Anomaly{}
+	}
+	data := struct {
+		Type      string    `json:"type"`
+		Threshold float64   `json:"threshold"`
+		Anomalies []Anomaly `json:"anomalies"`
+	}{
+		Type:      "anomalies",
+		Threshold: threshold,
+		Anomalies: anomalies,
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON output file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write JSON data: %v", err)
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Printf("JSON data saved to %s\n", output)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package modes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"labours-go/internal/burndown"
+	"labours-go/internal/readers"
+)
+
+// MockAnomaliesReader provides a steady activity history with a code dump, a mass deletion
+// and a sudden stop of activity
+type MockAnomaliesReader struct {
+	readers.Reader
+}
+
+func (m *MockAnomaliesReader) GetHeader() (int64, int64) {
+	return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC).Unix()
+}
+
+func (m *MockAnomaliesReader) GetProjectBurndownWithHeader() (burndown.BurndownHeader, string, [][]int, error) {
+	return burndown.BurndownHeader{}, "", nil, fmt.Errorf("no burndown data")
+}
+
+func (m *MockAnomaliesReader) GetDeveloperStats() ([]readers.DeveloperStat, error) {
+	return []readers.DeveloperStat{{Name: "alice", Commits: 120}, {Name: "bob", Commits: 80}}, nil
+}
+
+func (m *MockAnomaliesReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTimeSeriesData, error) {
+	days := make(map[int]map[int]readers.DevDay)
+	for tick := 0; tick < 100; tick++ {
+		added := 100 + (tick%5)*10
+		removed := 40 + (tick%3)*5
+		switch tick {
+		case 30:
+			added = 50000 // vendored dependency
+		case 60:
+			removed = 20000 // mass deletion
+		}
+		days[tick] = map[int]readers.DevDay{
+			0: {Commits: 2, LinesAdded: added / 2, LinesRemoved: removed / 2},
+			1: {Commits: 1, LinesAdded: added - added/2, LinesRemoved: removed - removed/2},
+		}
+	}
+	// Nobody commits after tick 100 but the history continues
+	days[140] = map[int]readers.DevDay{0: {Commits: 1, LinesAdded: 10, LinesRemoved: 5}}
+	return &readers.DeveloperTimeSeriesData{
+		People:   []string{"alice|alice@example.com", "bob|bob@example.com"},
+		Days:     days,
+		TickSize: 86400,
+	}, nil
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	anomalies, err := DetectAnomalies(&MockAnomaliesReader{}, 3.5)
+	if err != nil {
+		t.Fatalf("DetectAnomalies() error = %v", err)
+	}
+
+	found := make(map[string]Anomaly)
+	for _, anomaly := range anomalies {
+		found[anomaly.Kind] = anomaly
+	}
+
+	if spike, ok := found[AnomalyAddedSpike]; !ok || spike.Tick != 30 {
+		t.Errorf("expected added spike at tick 30, got %+v", anomalies)
+	} else if spike.TopDeveloper != "alice" && spike.TopDeveloper != "bob" {
+		t.Errorf("unexpected top developer %q", spike.TopDeveloper)
+	}
+	if spike, ok := found[AnomalyRemovedSpike]; !ok || spike.Tick != 60 {
+		t.Errorf("expected removed spike at tick 60, got %+v", anomalies)
+	}
+	if drop, ok := found[AnomalyActivityDrop]; !ok || drop.Tick < 100 || drop.Tick > 105 {
+		t.Errorf("expected activity drop right after tick 100, got %+v", anomalies)
+	}
+	if !found[AnomalyAddedSpike].Date.Equal(time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("tick 30 should map to 2023-01-31, got %v", found[AnomalyAddedSpike].Date)
+	}
+
+	for i := 1; i < len(anomalies); i++ {
+		if anomalies[i].Tick < anomalies[i-1].Tick {
+			t.Errorf("anomalies are not sorted by tick")
+		}
+	}
+}
+
+func TestAnomaliesOutputs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := Anomalies(context.Background(), &MockAnomaliesReader{}, filepath.Join(tmpDir, "report.png"), 3.5); err != nil {
+		t.Fatalf("Anomalies() error = %v", err)
+	}
+
+	for _, name := range []string{"report.json", "report_churn.png", "report_devs.png"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); os.IsNotExist(err) {
+			t.Errorf("expected output %s was not created", name)
+		}
+	}
+}
+
+func TestDetectSpikesIgnoresQuietHistory(t *testing.T) {
+	if spikes := detectSpikes([]float64{0, 0, 5, 0, 0}, 3.5); len(spikes) != 0 {
+		t.Errorf("expected no spikes with too few active ticks, got %v", spikes)
+	}
+	if spikes := detectSpikes([]float64{10, 10, 10, 10, 11}, 3.5); len(spikes) != 0 {
+		t.Errorf("expected no spikes in a flat series, got %v", spikes)
+	}
+}
@@ -1,7 +1,9 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -12,9 +14,8 @@ import (
 )
 
 // generateBurndownPlot creates the burndown plot with stacking, resampling, and survival ratio output.
-func generateBurndownPlot(name string, matrix [][]int, output string, relative bool, startTime, endTime *time.Time, resample string) error {
-	fmt.Println("Running: burndown-project")
-
+// It stops between phases when ctx is cancelled.
+func generateBurndownPlot(ctx context.Context, name string, matrix [][]int, output string, relative bool, startTime, endTime *time.Time, resample string) error {
 	// Initialize progress tracking
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
@@ -27,9 +28,7 @@ func generateBurndownPlot(name string, matrix [][]int, output string, relative b
 	progEstimator.NextOperation("Validating output path")
 	if output == "" {
 		output = fmt.Sprintf("burndown_%s.png", name)
-		if !quiet {
-			fmt.Printf("Output not provided, using default: %s\n", output)
-		}
+		slog.InfoContext(ctx, "output not provided, using default", "output", output)
 	}
 
 	outputDir := filepath.Dir(output)
@@ -43,9 +42,7 @@ func generateBurndownPlot(name string, matrix [][]int, output string, relative b
 	if resample == "" {
 		resample = "year"
 	}
-	if !quiet {
-		fmt.Printf("resampling to %s, please wait...\n", resample)
-	}
+	slog.InfoContext(ctx, "resampling burndown", "name", name, "resample", resample)
 
 	// Use default endTime if not provided
 	if endTime == nil {
@@ -66,10 +63,18 @@ func generateBurndownPlot(name string, matrix [][]int, output string, relative b
 	}
 
 	// Phase 3: Interpolation with enhanced progress tracking
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Interpolating burndown data")
 	interpolatedMatrix, dateRange := interpolateBurndownMatrixWithProgress(matrix, *startTime, *endTime, resample, progEstimator)
 
 	// Phase 4: Final processing and visualization
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Generating visualization")
 	
 	// Survival analysis
@@ -84,13 +89,19 @@ func generateBurndownPlot(name string, matrix [][]int, output string, relative b
 	}
 
 	// Create plot
-	if err := graphics.PlotStackedBurndown(interpolatedMatrix, dateRange, output, relative); err != nil {
+	if err := graphics.PlotStackedBurndown(ctx, interpolatedMatrix, dateRange, output, relative); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("error creating burndown plot: %v", err)
 	}
+	if err := animateChart(ctx, output, func(config *graphics.AnimationConfig) (string, error) {
+		return config.AnimateStackedBurndown(interpolatedMatrix, dateRange, output, relative)
+	}); err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("error creating burndown animation: %v", err)
+	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
+	if !quiet && !graphics.IsTerminalOutput(output) {
 		fmt.Printf("Chart saved to %s\n", output)
 	}
 	return nil
@@ -348,7 +359,7 @@ func findEarliestTime(matrix [][]int, tickSize time.Duration, endTime time.Time)
 			if val > 0 {
 				// Calculate the corresponding time for this column
 				earliestTime := endTime.Add(-tickSize * time.Duration(len(row)-colIndex))
-				fmt.Printf("Earliest time found at row %d, col %d: %s\n", rowIndex, colIndex, earliestTime)
+				slog.Debug("earliest time found", "row", rowIndex, "column", colIndex, "time", earliestTime)
 				return earliestTime
 			}
 		}
@@ -1,14 +1,16 @@
 package modes
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"labours-go/internal/readers"
 )
 
-// BurndownFile generates burndown charts for individual files.
-func BurndownFile(reader readers.Reader, output string, relative bool, startDate, endDate *time.Time, resample string) error {
+// BurndownFile generates burndown charts for individual files, stopping between files
+// when ctx is cancelled.
+func BurndownFile(ctx context.Context, reader readers.Reader, output string, relative bool, startDate, endDate *time.Time, resample string) error {
 	fileBurndowns, err := reader.GetFilesBurndown()
 	if err != nil {
 		return fmt.Errorf("failed to get files burndown data: %v", err)
@@ -16,8 +18,11 @@ func BurndownFile(reader readers.Reader, output string, relative bool, startDate
 
 	// Generate a chart for each file
 	for _, file := range fileBurndowns {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		outputFile := fmt.Sprintf("%s_%s.png", output, file.Filename)
-		if err := generateBurndownPlot(file.Filename, file.Matrix, outputFile, relative, startDate, endDate, resample); err != nil {
+		if err := generateBurndownPlot(ctx, file.Filename, file.Matrix, outputFile, relative, startDate, endDate, resample); err != nil {
 			return fmt.Errorf("failed to generate burndown for file %s: %v", file.Filename, err)
 		}
 	}
@@ -1,26 +1,55 @@
 package modes
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"labours-go/internal/graphics"
+	"labours-go/internal/progress"
 	"labours-go/internal/readers"
+	"labours-go/internal/workers"
 )
 
-// BurndownPerson generates burndown charts for individual people/developers.
-func BurndownPerson(reader readers.Reader, output string, relative bool, startDate, endDate *time.Time, resample string) error {
+// BurndownPerson generates burndown charts for individual people/developers, --jobs at
+// a time, stopping between people when ctx is cancelled.
+func BurndownPerson(ctx context.Context, reader readers.Reader, output string, relative bool, startDate, endDate *time.Time, resample string) error {
 	peopleBurndowns, err := reader.GetPeopleBurndown()
 	if err != nil {
 		return fmt.Errorf("failed to get people burndown data: %v", err)
 	}
 
-	// Generate a chart for each person
-	for _, person := range peopleBurndowns {
+	jobs := chartJobs(output, len(peopleBurndowns))
+	if jobs > 1 {
+		defer progress.SuppressBars()()
+	}
+
+	// Generate a chart for each person on --jobs workers; the first failure in people
+	// order is reported and the charts are added to the report in people order
+	parts := make([]*graphics.ReportContent, len(peopleBurndowns))
+	errs := workers.Run(ctx, len(peopleBurndowns), jobs, func(ctx context.Context, i int) error {
+		person := peopleBurndowns[i]
+		ctx, parts[i] = graphics.WithReportContent(ctx)
 		outputFile := fmt.Sprintf("%s_%s.png", output, person.Person)
-		if err := generateBurndownPlot(person.Person, person.Matrix, outputFile, relative, startDate, endDate, resample); err != nil {
+		if graphics.IsTerminalOutput(output) {
+			outputFile = output
+		}
+		if err := generateBurndownPlot(ctx, person.Person, person.Matrix, outputFile, relative, startDate, endDate, resample); err != nil {
 			return fmt.Errorf("failed to generate burndown for person %s: %v", person.Person, err)
 		}
+		return nil
+	}, func(i int, err error) {
+		if err == nil {
+			graphics.AddReportContent(ctx, parts[i])
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
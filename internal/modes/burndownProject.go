@@ -1,6 +1,7 @@
 package modes
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,12 +9,12 @@ import (
 )
 
 // BurndownProject generates a burndown chart for the entire project.
-func BurndownProject(reader readers.Reader, output string, relative bool, startTime, endTime *time.Time, resample string) error {
+func BurndownProject(ctx context.Context, reader readers.Reader, output string, relative bool, startTime, endTime *time.Time, resample string) error {
 	repoName, burndownMatrix := reader.GetProjectBurndown()
 	if len(burndownMatrix) == 0 {
 		return fmt.Errorf("no burndown data available for project")
 	}
 
 	// Generate plot
-	return generateBurndownPlot(repoName, burndownMatrix, output, relative, startTime, endTime, resample)
+	return generateBurndownPlot(ctx, repoName, burndownMatrix, output, relative, startTime, endTime, resample)
 }
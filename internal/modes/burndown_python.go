@@ -1,21 +1,25 @@
 package modes
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/viper"
 	"labours-go/internal/burndown"
 	"labours-go/internal/graphics"
 	"labours-go/internal/progress"
 	"labours-go/internal/readers"
+	"labours-go/internal/workers"
 )
 
-// GenerateBurndownProjectPython creates a Python-compatible burndown chart
-func GenerateBurndownProjectPython(reader readers.Reader, output string, relative bool, resample string) error {
-	fmt.Println("Running: burndown-project (Python-compatible)")
-
+// GenerateBurndownProjectPython creates a Python-compatible burndown chart. The
+// interpolation stops when ctx is cancelled.
+func GenerateBurndownProjectPython(ctx context.Context, reader readers.Reader, output string, relative bool, resample string) error {
 	// Initialize progress tracking
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
@@ -27,9 +31,7 @@ func GenerateBurndownProjectPython(reader readers.Reader, output string, relativ
 	progEstimator.NextOperation("Validating output path")
 	if output == "" {
 		output = "burndown_project_python.png"
-		if !quiet {
-			fmt.Printf("Output not provided, using default: %s\n", output)
-		}
+		slog.InfoContext(ctx, "output not provided, using default", "output", output)
 	}
 
 	outputDir := filepath.Dir(output)
@@ -46,11 +48,9 @@ func GenerateBurndownProjectPython(reader readers.Reader, output string, relativ
 		return fmt.Errorf("failed to load burndown data: %v", err)
 	}
 
-	if !quiet {
-		fmt.Printf("Processing %s with %d age bands and %d time points\n", name, len(matrix), len(matrix[0]))
-		fmt.Printf("Header: start=%d, last=%d, sampling=%d, granularity=%d, tick_size=%.3f\n", 
-			header.Start, header.Last, header.Sampling, header.Granularity, header.TickSize)
-	}
+	slog.DebugContext(ctx, "processing burndown", "name", name, "bands", len(matrix), "points", len(matrix[0]),
+		"start", header.Start, "last", header.Last, "sampling", header.Sampling,
+		"granularity", header.Granularity, "tick_size", header.TickSize)
 
 	// Phase 3: Process data using Python-compatible algorithms
 	progEstimator.NextOperation("Processing data with Python algorithms")
@@ -58,40 +58,91 @@ func GenerateBurndownProjectPython(reader readers.Reader, output string, relativ
 		resample = "year" // Default to yearly like Python
 	}
 
-	processedData, err := burndown.LoadBurndown(header, name, matrix, resample, true, true)
+	processedData, err := burndown.LoadBurndownContext(ctx, header, name, matrix, resample, true, true)
 	if err != nil {
 		progEstimator.FinishMultiOperation()
-		return fmt.Errorf("failed to process burndown data: %v", err)
+		return fmt.Errorf("failed to process burndown data: %w", err)
 	}
 
-	if !quiet {
-		fmt.Printf("Processed into %d layers: %v\n", len(processedData.Labels), processedData.Labels)
-		fmt.Printf("Final matrix dimensions: %dx%d\n", len(processedData.Matrix), len(processedData.Matrix[0]))
-	}
+	slog.DebugContext(ctx, "processed burndown", "layers", len(processedData.Labels), "labels", processedData.Labels,
+		"rows", len(processedData.Matrix), "columns", len(processedData.Matrix[0]))
 
 	// Print survival analysis (like Python does)
 	if !quiet {
 		graphics.PrintSurvivalFunction(processedData.Matrix)
 	}
+	if table, ok := burndownReportTable(processedData); ok {
+		graphics.AddReportTable(ctx, table)
+	}
 
 	// Phase 4: Generate visualization
 	progEstimator.NextOperation("Generating Python-style visualization")
-	if err := graphics.PlotBurndownPythonStyle(processedData, output, relative); err != nil {
+	if months := viper.GetInt("forecast"); months > 0 {
+		forecast, err := burndown.ForecastBurndown(processedData, months)
+		if err != nil {
+			progEstimator.FinishMultiOperation()
+			return fmt.Errorf("failed to forecast burndown: %v", err)
+		}
+		if err := graphics.PlotBurndownForecast(ctx, processedData, forecast, output, relative); err != nil {
+			progEstimator.FinishMultiOperation()
+			return fmt.Errorf("error creating burndown forecast plot: %v", err)
+		}
+		if !graphics.IsTerminalOutput(output) {
+			forecastOutput := strings.TrimSuffix(output, filepath.Ext(output)) + "_forecast.json"
+			if err := saveBurndownForecastAsJSON(forecastOutput, forecast); err != nil {
+				progEstimator.FinishMultiOperation()
+				return err
+			}
+		}
+	} else if err := graphics.PlotBurndownPythonStyle(ctx, processedData, output, relative); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("error creating Python-style burndown plot: %v", err)
 	}
+	if err := animateChart(ctx, output, func(config *graphics.AnimationConfig) (string, error) {
+		return config.AnimateBurndown(processedData, output, relative)
+	}); err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("error creating burndown animation: %v", err)
+	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
+	if !quiet && !graphics.IsTerminalOutput(output) {
 		fmt.Printf("Python-compatible chart saved to %s\n", output)
 	}
 	return nil
 }
 
-// GenerateBurndownFilePython creates Python-compatible file-level burndown charts
-func GenerateBurndownFilePython(reader readers.Reader, output string, relative bool, resample string) error {
-	fmt.Println("Running: burndown-file (Python-compatible)")
-	
+// saveBurndownForecastAsJSON writes the burndown forecast next to the chart
+func saveBurndownForecastAsJSON(output string, forecast *burndown.BurndownForecast) error {
+	data := struct {
+		Type     string                     `json:"type"`
+		Forecast *burndown.BurndownForecast `json:"forecast"`
+	}{
+		Type:     "burndown-forecast",
+		Forecast: forecast,
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create forecast JSON file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write forecast JSON data: %v", err)
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Forecast data saved to %s\n", output)
+	}
+	return nil
+}
+
+// GenerateBurndownFilePython creates Python-compatible file-level burndown charts,
+// stopping between files and during interpolation when ctx is cancelled
+func GenerateBurndownFilePython(ctx context.Context, reader readers.Reader, output string, relative bool, resample string) error {
 	// Get files burndown data
 	files, err := reader.GetFilesBurndown()
 	if err != nil {
@@ -105,33 +156,37 @@ func GenerateBurndownFilePython(reader readers.Reader, output string, relative b
 	}
 
 	quiet := viper.GetBool("quiet")
-	if !quiet {
-		fmt.Printf("Processing %d files\n", len(files))
+	slog.InfoContext(ctx, "processing file burndowns", "files", len(files))
+
+	if resample == "" {
+		resample = "year"
+	}
+	jobs := chartJobs(output, len(files))
+	if jobs > 1 {
+		defer progress.SuppressBars()()
 	}
 
-	// Process each file
-	for i, file := range files {
-		if !quiet {
-			fmt.Printf("Processing file %d/%d: %s\n", i+1, len(files), file.Filename)
-		}
+	// Process the files on --jobs workers; results are reported in file order
+	outputs := make([]string, len(files))
+	parts := make([]*graphics.ReportContent, len(files))
+	render := func(ctx context.Context, i int) error {
+		file := files[i]
+		ctx, parts[i] = graphics.WithReportContent(ctx)
+		slog.DebugContext(ctx, "processing file burndown", "index", i+1, "files", len(files), "file", file.Filename)
 
-		if resample == "" {
-			resample = "year"
+		processedData, err := burndown.LoadBurndownContext(ctx, header, file.Filename, file.Matrix, resample, false, false)
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
-
-		processedData, err := burndown.LoadBurndown(header, file.Filename, file.Matrix, resample, false, false)
 		if err != nil {
-			if !quiet {
-				fmt.Printf("Warning: failed to process %s: %v\n", file.Filename, err)
-			}
-			continue
+			return fmt.Errorf("failed to process file burndown: %w", err)
 		}
 
 		// Generate output filename
 		fileOutput := output
 		if output == "" {
 			fileOutput = fmt.Sprintf("burndown_file_%s.png", sanitizeFilename(file.Filename))
-		} else {
+		} else if !graphics.IsTerminalOutput(output) {
 			dir := filepath.Dir(output)
 			ext := filepath.Ext(output)
 			base := filepath.Base(output)
@@ -139,19 +194,26 @@ func GenerateBurndownFilePython(reader readers.Reader, output string, relative b
 			fileOutput = filepath.Join(dir, fmt.Sprintf("%s_%s%s", base, sanitizeFilename(file.Filename), ext))
 		}
 
-		if err := graphics.PlotBurndownPythonStyle(processedData, fileOutput, relative); err != nil {
-			if !quiet {
-				fmt.Printf("Warning: failed to create plot for %s: %v\n", file.Filename, err)
-			}
-			continue
+		if err := graphics.PlotBurndownPythonStyle(ctx, processedData, fileOutput, relative); err != nil {
+			return fmt.Errorf("failed to create file burndown plot: %w", err)
 		}
-
-		if !quiet {
-			fmt.Printf("Chart saved: %s\n", fileOutput)
+		outputs[i] = fileOutput
+		return nil
+	}
+	workers.Run(ctx, len(files), jobs, render, func(i int, err error) {
+		switch {
+		case ctx.Err() != nil:
+		case err != nil:
+			slog.Warn("skipped file burndown", "file", files[i].Filename, "error", err)
+		default:
+			graphics.AddReportContent(ctx, parts[i])
+			if !quiet && !graphics.IsTerminalOutput(output) {
+				fmt.Printf("Chart saved: %s\n", outputs[i])
+			}
 		}
-	}
+	})
 
-	return nil
+	return ctx.Err()
 }
 
 // sanitizeFilename removes problematic characters from filenames
@@ -1,10 +1,16 @@
 package modes
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/spf13/viper"
+	"labours-go/internal/burndown"
+	"labours-go/internal/readers"
 )
 
 func TestGenerateBurndownPlot(t *testing.T) {
@@ -23,7 +29,7 @@ func TestGenerateBurndownPlot(t *testing.T) {
 	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	endTime := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
 
-	err := generateBurndownPlot("test", testMatrix, outputPath, false, &startTime, &endTime, "day")
+	err := generateBurndownPlot(context.Background(), "test", testMatrix, outputPath, false, &startTime, &endTime, "day")
 	if err != nil {
 		t.Errorf("generateBurndownPlot() error = %v", err)
 	}
@@ -48,7 +54,7 @@ func TestGenerateBurndownPlotRelative(t *testing.T) {
 	endTime := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
 
 	// Test relative mode
-	err := generateBurndownPlot("test_relative", testMatrix, outputPath, true, &startTime, &endTime, "day")
+	err := generateBurndownPlot(context.Background(), "test_relative", testMatrix, outputPath, true, &startTime, &endTime, "day")
 	if err != nil {
 		t.Errorf("generateBurndownPlot() with relative=true error = %v", err)
 	}
@@ -76,7 +82,7 @@ func TestGenerateBurndownPlotResamplingModes(t *testing.T) {
 		t.Run("resampling_"+mode, func(t *testing.T) {
 			outputPath := filepath.Join(tmpDir, "test_burndown_"+mode+".png")
 
-			err := generateBurndownPlot("test_"+mode, testMatrix, outputPath, false, &startTime, &endTime, mode)
+			err := generateBurndownPlot(context.Background(), "test_"+mode, testMatrix, outputPath, false, &startTime, &endTime, mode)
 			if err != nil {
 				t.Errorf("generateBurndownPlot() with resample=%s error = %v", mode, err)
 			}
@@ -105,12 +111,51 @@ func TestGenerateBurndownPlotEmptyMatrix(t *testing.T) {
 		}
 	}()
 
-	err := generateBurndownPlot("test_empty", emptyMatrix, outputPath, false, &startTime, &endTime, "day")
+	err := generateBurndownPlot(context.Background(), "test_empty", emptyMatrix, outputPath, false, &startTime, &endTime, "day")
 	if err == nil {
 		t.Error("Expected error for empty matrix, but got nil")
 	}
 }
 
+// filesBurndownReader serves the same file burndown for several files
+type filesBurndownReader struct {
+	MockSentimentReader
+	files []readers.FileBurndown
+}
+
+func (r *filesBurndownReader) GetFilesBurndown() ([]readers.FileBurndown, error) {
+	return r.files, nil
+}
+
+func (r *filesBurndownReader) GetProjectBurndownWithHeader() (burndown.BurndownHeader, string, [][]int, error) {
+	header := burndown.BurndownHeader{Start: 1704067200, Last: 1704067200 + 90*86400, Sampling: 30, Granularity: 30, TickSize: 86400}
+	return header, "project", nil, nil
+}
+
+func TestGenerateBurndownFilePythonJobs(t *testing.T) {
+	viper.Set("quiet", true)
+	viper.Set("jobs", 4)
+	defer viper.Set("jobs", 1)
+
+	reader := &filesBurndownReader{}
+	for i := 0; i < 8; i++ {
+		reader.files = append(reader.files, readers.FileBurndown{
+			Filename: fmt.Sprintf("file%d.go", i),
+			Matrix:   [][]int{{100, 80, 70}, {0, 50, 40}, {0, 0, 30}},
+		})
+	}
+	output := filepath.Join(t.TempDir(), "files.png")
+	if err := GenerateBurndownFilePython(context.Background(), reader, output, false, "month"); err != nil {
+		t.Fatalf("GenerateBurndownFilePython() error = %v", err)
+	}
+	for _, file := range reader.files {
+		chart := filepath.Join(filepath.Dir(output), "files_"+sanitizeFilename(file.Filename)+".png")
+		if _, err := os.Stat(chart); err != nil {
+			t.Errorf("chart of %s not written: %v", file.Filename, err)
+		}
+	}
+}
+
 func TestFindEarliestTime(t *testing.T) {
 	// Test data with 3x3 matrix
 	testMatrix := [][]int{
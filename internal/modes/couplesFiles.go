@@ -1,8 +1,10 @@
 package modes
 
 import (
+	"context"
 	"fmt"
 	"image/color"
+	"log/slog"
 	"path/filepath"
 	"strconv"
 
@@ -15,8 +17,9 @@ import (
 	"labours-go/internal/readers"
 )
 
-// CouplesFiles generates file coupling analysis and visualization
-func CouplesFiles(reader readers.Reader, output string) error {
+// CouplesFiles generates file coupling analysis and visualization, stopping
+// between phases when ctx is cancelled
+func CouplesFiles(ctx context.Context, reader readers.Reader, output string) error {
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
 	
@@ -33,27 +36,26 @@ func CouplesFiles(reader readers.Reader, output string) error {
 
 	if len(fileNames) == 0 {
 		progEstimator.FinishMultiOperation()
-		if !quiet {
-			fmt.Println("No file coupling data available")
-		}
+		slog.WarnContext(ctx, "no file coupling data available")
 		return nil
 	}
 
 	// Phase 2: Analyze coupling patterns
 	progEstimator.NextOperation("Analyzing coupling patterns")
-	couplingAnalysis := analyzeFileCoupling(fileNames, couplingMatrix)
+	couplingAnalysis, err := analyzeFileCoupling(ctx, fileNames, couplingMatrix)
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 
 	// Phase 3: Generate visualizations
 	progEstimator.NextOperation("Generating visualization")
-	if err := plotFileCoupling(couplingAnalysis, output); err != nil {
+	if err := plotFileCoupling(ctx, couplingAnalysis, output); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to generate file coupling plots: %v", err)
 	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
-		fmt.Println("File coupling analysis completed successfully.")
-	}
 	return nil
 }
 
@@ -82,8 +84,8 @@ type CouplingStatistics struct {
 	MinCoupling    int
 }
 
-// analyzeFileCoupling performs analysis on file coupling data
-func analyzeFileCoupling(fileNames []string, couplingMatrix [][]int) FileCouplingAnalysis {
+// analyzeFileCoupling performs analysis on file coupling data, stopping when ctx is cancelled
+func analyzeFileCoupling(ctx context.Context, fileNames []string, couplingMatrix [][]int) (FileCouplingAnalysis, error) {
 	analysis := FileCouplingAnalysis{
 		FileNames:      fileNames,
 		CouplingMatrix: couplingMatrix,
@@ -96,6 +98,9 @@ func analyzeFileCoupling(fileNames []string, couplingMatrix [][]int) FileCouplin
 	minCoupling := int(^uint(0) >> 1) // Max int
 	
 	for i := 0; i < len(fileNames); i++ {
+		if err := ctx.Err(); err != nil {
+			return analysis, err
+		}
 		for j := i + 1; j < len(fileNames); j++ {
 			if i < len(couplingMatrix) && j < len(couplingMatrix[i]) {
 				coupling := couplingMatrix[i][j]
@@ -122,6 +127,9 @@ func analyzeFileCoupling(fileNames []string, couplingMatrix [][]int) FileCouplin
 	
 	// Sort pairs by coupling score (descending)
 	for i := 0; i < len(pairs)-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return analysis, err
+		}
 		for j := i + 1; j < len(pairs); j++ {
 			if pairs[i].CouplingScore < pairs[j].CouplingScore {
 				pairs[i], pairs[j] = pairs[j], pairs[i]
@@ -150,18 +158,18 @@ func analyzeFileCoupling(fileNames []string, couplingMatrix [][]int) FileCouplin
 		MinCoupling:     minCoupling,
 	}
 	
-	return analysis
+	return analysis, nil
 }
 
 // plotFileCoupling generates coupling visualization plots
-func plotFileCoupling(analysis FileCouplingAnalysis, output string) error {
+func plotFileCoupling(ctx context.Context, analysis FileCouplingAnalysis, output string) error {
 	// Create heatmap for top coupled files
-	if err := plotCouplingHeatmap(analysis, output); err != nil {
+	if err := plotCouplingHeatmap(ctx, analysis, output); err != nil {
 		return err
 	}
 	
 	// Create bar chart of top coupling pairs
-	if err := plotTopCouplingPairs(analysis, output); err != nil {
+	if err := plotTopCouplingPairs(ctx, analysis, output); err != nil {
 		return err
 	}
 	
@@ -169,7 +177,7 @@ func plotFileCoupling(analysis FileCouplingAnalysis, output string) error {
 }
 
 // plotCouplingHeatmap creates a heatmap of file coupling relationships
-func plotCouplingHeatmap(analysis FileCouplingAnalysis, output string) error {
+func plotCouplingHeatmap(ctx context.Context, analysis FileCouplingAnalysis, output string) error {
 	if len(analysis.CouplingMatrix) == 0 {
 		return fmt.Errorf("no coupling matrix data available")
 	}
@@ -212,6 +220,11 @@ func plotCouplingHeatmap(analysis FileCouplingAnalysis, output string) error {
 	heatmap := graphics.NewHeatMap(heatmapData, analysis.FileNames, analysis.FileNames, palette)
 	p.Add(heatmap)
 	
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		return graphics.WriteTerminal(p, graphics.TermHeatmap{Title: p.Title.Text, Rows: analysis.FileNames, Cols: analysis.FileNames, Matrix: heatmapData})
+	}
+	
 	// Save the plot
 	outputFile := filepath.Join(output, "file_coupling_heatmap.png")
 	widthHeat, heightHeat := graphics.GetPlotSize(graphics.ChartTypeSquare)
@@ -219,12 +232,14 @@ func plotCouplingHeatmap(analysis FileCouplingAnalysis, output string) error {
 		return fmt.Errorf("failed to save heatmap: %v", err)
 	}
 	
-	fmt.Printf("Saved file coupling heatmap to %s\n", outputFile)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved file coupling heatmap to %s\n", outputFile)
+	}
 	return nil
 }
 
 // plotTopCouplingPairs creates a bar chart of the most coupled file pairs
-func plotTopCouplingPairs(analysis FileCouplingAnalysis, output string) error {
+func plotTopCouplingPairs(ctx context.Context, analysis FileCouplingAnalysis, output string) error {
 	if len(analysis.TopCoupling) == 0 {
 		return fmt.Errorf("no coupling pairs data available")
 	}
@@ -274,15 +289,24 @@ func plotTopCouplingPairs(analysis FileCouplingAnalysis, output string) error {
 	}
 	p.X.Tick.Marker = plot.ConstantTicks(ticks)
 	
-	// Save the plot
-	outputFile := filepath.Join(output, "top_file_coupling_pairs.png")
-	widthBar, heightBar := graphics.GetPlotSize(graphics.ChartTypeDefault)
-	if err := p.Save(widthBar, heightBar, outputFile); err != nil {
-		return fmt.Errorf("failed to save coupling pairs plot: %v", err)
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		if err := graphics.WriteTerminal(p, graphics.TermBars{Title: p.Title.Text, Labels: labels, Values: values}); err != nil {
+			return err
+		}
+	} else {
+		// Save the plot
+		outputFile := filepath.Join(output, "top_file_coupling_pairs.png")
+		widthBar, heightBar := graphics.GetPlotSize(graphics.ChartTypeDefault)
+		if err := p.Save(widthBar, heightBar, outputFile); err != nil {
+			return fmt.Errorf("failed to save coupling pairs plot: %v", err)
+		}
+		
+		if !viper.GetBool("quiet") {
+			fmt.Printf("Saved top coupling pairs plot to %s\n", outputFile)
+		}
 	}
 	
-	fmt.Printf("Saved top coupling pairs plot to %s\n", outputFile)
-	
 	// Print summary information
 	fmt.Printf("File Coupling Analysis Summary:\n")
 	fmt.Printf("  Total files: %d\n", analysis.Statistics.TotalFiles)
@@ -1,7 +1,9 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"math"
 	"os"
 	"path/filepath"
@@ -9,12 +11,14 @@ import (
 	"strings"
 
 	"github.com/spf13/viper"
+	"labours-go/internal/graphics"
 	"labours-go/internal/progress"
 	"labours-go/internal/readers"
 )
 
-// CouplesPeople generates people coupling embeddings (Python-compatible)
-func CouplesPeople(reader readers.Reader, output string) error {
+// CouplesPeople generates people coupling embeddings (Python-compatible), stopping
+// between phases and people when ctx is cancelled
+func CouplesPeople(ctx context.Context, reader readers.Reader, output string) error {
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
 	
@@ -31,27 +35,27 @@ func CouplesPeople(reader readers.Reader, output string) error {
 
 	if len(peopleNames) == 0 {
 		progEstimator.FinishMultiOperation()
-		if !quiet {
-			fmt.Println("Coupling stats were not collected. Re-run hercules with --couples.")
-		}
+		slog.WarnContext(ctx, "coupling stats were not collected, re-run hercules with --couples")
 		return nil
 	}
 
 	// Phase 2: Preprocess matrix (Python-compatible outlier handling)
 	progEstimator.NextOperation("Preprocessing coupling matrix")
 	processedMatrix := preprocessCouplingMatrix(couplingMatrix)
+	graphics.AddReportTable(ctx, peopleCouplingReportTable(peopleNames, couplingMatrix))
 
 	// Phase 3: Generate embeddings
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Training embeddings")
-	if err := writeEmbeddings("people", output, peopleNames, processedMatrix); err != nil {
+	if err := writeEmbeddings(ctx, "people", output, peopleNames, processedMatrix); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to write people embeddings: %v", err)
 	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
-		fmt.Println("People coupling embeddings completed successfully.")
-	}
 	return nil
 }
 
@@ -112,8 +116,9 @@ func preprocessCouplingMatrix(matrix [][]int) [][]float64 {
 	return processed
 }
 
-// trainEmbeddings trains vector embeddings using a simplified approach
-func trainEmbeddings(index []string, matrix [][]float64) ([]EmbeddingVector, error) {
+// trainEmbeddings trains vector embeddings using a simplified approach, stopping between
+// rows when ctx is cancelled
+func trainEmbeddings(ctx context.Context, index []string, matrix [][]float64) ([]EmbeddingVector, error) {
 	if len(matrix) == 0 || len(index) == 0 {
 		return nil, fmt.Errorf("empty matrix or index")
 	}
@@ -125,6 +130,9 @@ func trainEmbeddings(index []string, matrix [][]float64) ([]EmbeddingVector, err
 		if i >= len(matrix) {
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		
 		// Create embedding vector from matrix row
 		vector := make([]float64, len(matrix[i]))
@@ -152,10 +160,13 @@ func trainEmbeddings(index []string, matrix [][]float64) ([]EmbeddingVector, err
 }
 
 // writeEmbeddings writes embeddings in TensorFlow Projector compatible format
-func writeEmbeddings(prefix, outputDir string, index []string, matrix [][]float64) error {
+func writeEmbeddings(ctx context.Context, prefix, outputDir string, index []string, matrix [][]float64) error {
 	// Train embeddings (using tmpdir if specified)
 	tmpdir := viper.GetString("tmpdir")
-	embeddings, err := trainEmbeddings(index, matrix)
+	embeddings, err := trainEmbeddings(ctx, index, matrix)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to train embeddings: %v", err)
 	}
@@ -184,11 +195,13 @@ func writeEmbeddings(prefix, outputDir string, index []string, matrix [][]float6
 		if err := writeMetadataFile(metadataFile, embeddings, matrix); err != nil {
 			return fmt.Errorf("failed to write metadata file: %v", err)
 		}
-		fmt.Printf("Embeddings written to:\n")
-		fmt.Printf("  Vocabulary: %s\n", vocabFile)
-		fmt.Printf("  Vectors: %s\n", vectorFile)
-		fmt.Printf("  Metadata: %s\n", metadataFile)
-	} else {
+		if !viper.GetBool("quiet") {
+			fmt.Printf("Embeddings written to:\n")
+			fmt.Printf("  Vocabulary: %s\n", vocabFile)
+			fmt.Printf("  Vectors: %s\n", vectorFile)
+			fmt.Printf("  Metadata: %s\n", metadataFile)
+		}
+	} else if !viper.GetBool("quiet") {
 		fmt.Printf("Embeddings written to:\n")
 		fmt.Printf("  Vocabulary: %s\n", vocabFile)
 		fmt.Printf("  Vectors: %s\n", vectorFile)
@@ -197,7 +210,7 @@ func writeEmbeddings(prefix, outputDir string, index []string, matrix [][]float6
 
 	// Note: tmpdir parameter is acknowledged but not used in simplified implementation
 	if tmpdir != "" {
-		fmt.Printf("  Using tmpdir: %s\n", tmpdir)
+		slog.DebugContext(ctx, "tmpdir is not used by the embeddings", "tmpdir", tmpdir)
 	}
 
 	return nil
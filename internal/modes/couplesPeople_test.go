@@ -1,6 +1,7 @@
 package modes
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -16,6 +17,7 @@ type MockCouplesReader struct{}
 func (r *MockCouplesReader) Read(file io.Reader) error { return nil }
 func (r *MockCouplesReader) GetName() string { return "test-repo" }
 func (r *MockCouplesReader) GetHeader() (int64, int64) { return 1234567890, 1234567890 }
+func (r *MockCouplesReader) GetMetadata() (readers.Metadata, error) { return readers.Metadata{}, nil }
 func (r *MockCouplesReader) GetProjectBurndown() (string, [][]int) { return "", nil }
 func (r *MockCouplesReader) GetBurndownParameters() (burndown.BurndownParameters, error) { return burndown.BurndownParameters{}, nil }
 func (r *MockCouplesReader) GetProjectBurndownWithHeader() (burndown.BurndownHeader, string, [][]int, error) { return burndown.BurndownHeader{}, "", nil, nil }
@@ -30,6 +32,7 @@ func (r *MockCouplesReader) GetDeveloperStats() ([]readers.DeveloperStat, error)
 func (r *MockCouplesReader) GetLanguageStats() ([]readers.LanguageStat, error) { return nil, nil }
 func (r *MockCouplesReader) GetRuntimeStats() (map[string]float64, error) { return nil, nil }
 func (r *MockCouplesReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTimeSeriesData, error) { return nil, nil }
+func (r *MockCouplesReader) GetCommentSentiment() (map[int]readers.SentimentTick, error) { return nil, nil }
 
 // GetPeopleCooccurrence returns test coupling data mimicking real hercules output
 func (r *MockCouplesReader) GetPeopleCooccurrence() ([]string, [][]int, error) {
@@ -63,7 +66,7 @@ func TestCouplesPeopleEmbeddings(t *testing.T) {
 	reader := &MockCouplesReader{}
 	
 	// Test the couples-people function
-	err := CouplesPeople(reader, tempDir)
+	err := CouplesPeople(context.Background(), reader, tempDir)
 	if err != nil {
 		t.Fatalf("CouplesPeople failed: %v", err)
 	}
@@ -96,7 +99,7 @@ func TestCouplesPeopleWithDisabledProjector(t *testing.T) {
 	reader := &MockCouplesReader{}
 	
 	// Test the couples-people function
-	err := CouplesPeople(reader, tempDir)
+	err := CouplesPeople(context.Background(), reader, tempDir)
 	if err != nil {
 		t.Fatalf("CouplesPeople failed: %v", err)
 	}
@@ -158,7 +161,7 @@ func TestTrainEmbeddings(t *testing.T) {
 		{0.2, 0.8, 1.0},
 	}
 	
-	embeddings, err := trainEmbeddings(index, matrix)
+	embeddings, err := trainEmbeddings(context.Background(), index, matrix)
 	if err != nil {
 		t.Fatalf("trainEmbeddings failed: %v", err)
 	}
@@ -1,8 +1,10 @@
 package modes
 
 import (
+	"context"
 	"fmt"
 	"image/color"
+	"log/slog"
 	"path/filepath"
 	"strconv"
 
@@ -15,8 +17,9 @@ import (
 	"labours-go/internal/readers"
 )
 
-// CouplesShotness generates shotness-based coupling analysis and visualization
-func CouplesShotness(reader readers.Reader, output string) error {
+// CouplesShotness generates shotness-based coupling analysis and visualization, stopping
+// between phases when ctx is cancelled
+func CouplesShotness(ctx context.Context, reader readers.Reader, output string) error {
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
 	
@@ -33,27 +36,26 @@ func CouplesShotness(reader readers.Reader, output string) error {
 
 	if len(entityNames) == 0 {
 		progEstimator.FinishMultiOperation()
-		if !quiet {
-			fmt.Println("No shotness coupling data available")
-		}
+		slog.WarnContext(ctx, "no shotness coupling data available")
 		return nil
 	}
 
 	// Phase 2: Analyze coupling patterns
 	progEstimator.NextOperation("Analyzing shotness coupling patterns")
-	couplingAnalysis := analyzeShotnessCoupling(entityNames, couplingMatrix)
+	couplingAnalysis, err := analyzeShotnessCoupling(ctx, entityNames, couplingMatrix)
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 
 	// Phase 3: Generate visualizations
 	progEstimator.NextOperation("Generating visualization")
-	if err := plotShotnessCoupling(couplingAnalysis, output); err != nil {
+	if err := plotShotnessCoupling(ctx, couplingAnalysis, output); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to generate shotness coupling plots: %v", err)
 	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
-		fmt.Println("Shotness coupling analysis completed successfully.")
-	}
 	return nil
 }
 
@@ -82,8 +84,8 @@ type ShotnessCouplingStatistics struct {
 	MinCoupling       int
 }
 
-// analyzeShotnessCoupling performs analysis on shotness coupling data
-func analyzeShotnessCoupling(entityNames []string, couplingMatrix [][]int) ShotnessCouplingAnalysis {
+// analyzeShotnessCoupling performs analysis on shotness coupling data, stopping when ctx is cancelled
+func analyzeShotnessCoupling(ctx context.Context, entityNames []string, couplingMatrix [][]int) (ShotnessCouplingAnalysis, error) {
 	analysis := ShotnessCouplingAnalysis{
 		EntityNames:    entityNames,
 		CouplingMatrix: couplingMatrix,
@@ -96,6 +98,9 @@ func analyzeShotnessCoupling(entityNames []string, couplingMatrix [][]int) Shotn
 	minCoupling := int(^uint(0) >> 1) // Max int
 	
 	for i := 0; i < len(entityNames); i++ {
+		if err := ctx.Err(); err != nil {
+			return analysis, err
+		}
 		for j := i + 1; j < len(entityNames); j++ {
 			if i < len(couplingMatrix) && j < len(couplingMatrix[i]) {
 				coupling := couplingMatrix[i][j]
@@ -122,6 +127,9 @@ func analyzeShotnessCoupling(entityNames []string, couplingMatrix [][]int) Shotn
 	
 	// Sort pairs by coupling score (descending)
 	for i := 0; i < len(pairs)-1; i++ {
+		if err := ctx.Err(); err != nil {
+			return analysis, err
+		}
 		for j := i + 1; j < len(pairs); j++ {
 			if pairs[i].CouplingScore < pairs[j].CouplingScore {
 				pairs[i], pairs[j] = pairs[j], pairs[i]
@@ -150,18 +158,18 @@ func analyzeShotnessCoupling(entityNames []string, couplingMatrix [][]int) Shotn
 		MinCoupling:     minCoupling,
 	}
 	
-	return analysis
+	return analysis, nil
 }
 
 // plotShotnessCoupling generates coupling visualization plots
-func plotShotnessCoupling(analysis ShotnessCouplingAnalysis, output string) error {
+func plotShotnessCoupling(ctx context.Context, analysis ShotnessCouplingAnalysis, output string) error {
 	// Create heatmap for shotness entities
-	if err := plotShotnessCouplingHeatmap(analysis, output); err != nil {
+	if err := plotShotnessCouplingHeatmap(ctx, analysis, output); err != nil {
 		return err
 	}
 	
 	// Create bar chart of top coupling pairs
-	if err := plotTopShotnessCouplingPairs(analysis, output); err != nil {
+	if err := plotTopShotnessCouplingPairs(ctx, analysis, output); err != nil {
 		return err
 	}
 	
@@ -169,7 +177,7 @@ func plotShotnessCoupling(analysis ShotnessCouplingAnalysis, output string) erro
 }
 
 // plotShotnessCouplingHeatmap creates a heatmap of shotness coupling relationships
-func plotShotnessCouplingHeatmap(analysis ShotnessCouplingAnalysis, output string) error {
+func plotShotnessCouplingHeatmap(ctx context.Context, analysis ShotnessCouplingAnalysis, output string) error {
 	if len(analysis.CouplingMatrix) == 0 {
 		return fmt.Errorf("no coupling matrix data available")
 	}
@@ -212,18 +220,25 @@ func plotShotnessCouplingHeatmap(analysis ShotnessCouplingAnalysis, output strin
 	heatmap := graphics.NewHeatMap(heatmapData, analysis.EntityNames, analysis.EntityNames, palette)
 	p.Add(heatmap)
 	
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		return graphics.WriteTerminal(p, graphics.TermHeatmap{Title: p.Title.Text, Rows: analysis.EntityNames, Cols: analysis.EntityNames, Matrix: heatmapData})
+	}
+	
 	// Save the plot
 	outputFile := filepath.Join(output, "shotness_coupling_heatmap.png")
 	if err := p.Save(12*vg.Inch, 12*vg.Inch, outputFile); err != nil {
 		return fmt.Errorf("failed to save heatmap: %v", err)
 	}
 	
-	fmt.Printf("Saved shotness coupling heatmap to %s\n", outputFile)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved shotness coupling heatmap to %s\n", outputFile)
+	}
 	return nil
 }
 
 // plotTopShotnessCouplingPairs creates a bar chart of the most coupled shotness entities
-func plotTopShotnessCouplingPairs(analysis ShotnessCouplingAnalysis, output string) error {
+func plotTopShotnessCouplingPairs(ctx context.Context, analysis ShotnessCouplingAnalysis, output string) error {
 	if len(analysis.TopCoupling) == 0 {
 		return fmt.Errorf("no coupling pairs data available")
 	}
@@ -279,14 +294,23 @@ func plotTopShotnessCouplingPairs(analysis ShotnessCouplingAnalysis, output stri
 	}
 	p.X.Tick.Marker = plot.ConstantTicks(ticks)
 	
-	// Save the plot
-	outputFile := filepath.Join(output, "top_shotness_coupling_pairs.png")
-	if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
-		return fmt.Errorf("failed to save coupling pairs plot: %v", err)
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		if err := graphics.WriteTerminal(p, graphics.TermBars{Title: p.Title.Text, Labels: labels, Values: values}); err != nil {
+			return err
+		}
+	} else {
+		// Save the plot
+		outputFile := filepath.Join(output, "top_shotness_coupling_pairs.png")
+		if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
+			return fmt.Errorf("failed to save coupling pairs plot: %v", err)
+		}
+		
+		if !viper.GetBool("quiet") {
+			fmt.Printf("Saved top shotness coupling pairs plot to %s\n", outputFile)
+		}
 	}
 	
-	fmt.Printf("Saved top shotness coupling pairs plot to %s\n", outputFile)
-	
 	// Print summary information
 	fmt.Printf("Shotness Coupling Analysis Summary:\n")
 	fmt.Printf("  Total entities: %d\n", analysis.Statistics.TotalEntities)
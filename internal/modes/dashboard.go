@@ -0,0 +1,259 @@
+package modes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gonum.org/v1/plot"
+	"labours-go/internal/burndown"
+	"labours-go/internal/graphics"
+	"labours-go/internal/progress"
+	"labours-go/internal/readers"
+)
+
+// modeChart builds the chart of a mode for a dashboard cell or a report page
+type modeChart struct {
+	build    func(reader readers.Reader) (*plot.Plot, error)
+	timeAxis bool // the chart's X axis holds Unix timestamps
+}
+
+// modeCharts are the modes that can be placed on a dashboard or in a report
+var modeCharts = map[string]modeChart{
+	"burndown-project":  {build: burndownProjectPanel, timeAxis: true},
+	"ownership":         {build: ownershipPanel, timeAxis: true},
+	"anomalies":         {build: anomaliesPanel, timeAxis: true},
+	"devs":              {build: devsPanel},
+	"devs-efforts":      {build: devsEffortsPanel},
+	"languages":         {build: languagesPanel},
+	"overwrites-matrix": {build: overwritesPanel},
+	"run-times":         {build: runTimesPanel},
+}
+
+// DashboardModes returns the modes that can be placed on a dashboard
+func DashboardModes() []string {
+	names := make([]string, 0, len(modeCharts))
+	for name := range modeCharts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dashboard composes the charts of several modes into one image as described by the
+// layout file. Nothing is written when ctx is cancelled before all panels are built.
+func Dashboard(ctx context.Context, reader readers.Reader, output string, layoutPath string) error {
+	if layoutPath == "" {
+		return fmt.Errorf("no dashboard layout given, use --dashboard <layout.yaml>")
+	}
+	layout, err := graphics.LoadDashboardLayout(layoutPath)
+	if err != nil {
+		return err
+	}
+	for i, cell := range layout.Cells {
+		if _, ok := modeCharts[cell.Mode]; !ok {
+			return fmt.Errorf("cell %d: mode %s cannot be shown on a dashboard, available: %s",
+				i+1, cell.Mode, strings.Join(DashboardModes(), ", "))
+		}
+	}
+	if graphics.IsTerminalOutput(output) || filepath.Ext(output) == ".json" {
+		return fmt.Errorf("dashboards are written as PNG, SVG or PDF files")
+	}
+
+	quiet := viper.GetBool("quiet")
+	progEstimator := progress.NewProgressEstimator(!quiet)
+	progEstimator.StartOperation("Building dashboard panels", len(layout.Cells))
+
+	if output == "" {
+		output = "dashboard.png"
+	}
+	if err := os.MkdirAll(filepath.Dir(output), os.ModePerm); err != nil {
+		progEstimator.FinishOperation()
+		return fmt.Errorf("failed to create output directory %s: %v", filepath.Dir(output), err)
+	}
+
+	panels := make([]graphics.DashboardPanel, len(layout.Cells))
+	for i, cell := range layout.Cells {
+		if err := ctx.Err(); err != nil {
+			progEstimator.FinishOperation()
+			return err
+		}
+		panel := modeCharts[cell.Mode]
+		p, err := panel.build(reader)
+		if err != nil {
+			// A cell without data must not cost the whole overview
+			slog.Warn("dashboard cell left empty", "cell", i+1, "mode", cell.Mode, "error", err)
+			panels[i] = graphics.DashboardPanel{Plot: emptyDashboardPlot(cell.Mode, err)}
+		} else {
+			panels[i] = graphics.DashboardPanel{Plot: p, TimeAxis: panel.timeAxis}
+			graphics.AddReportChart(ctx, p)
+		}
+		progEstimator.UpdateProgress(1)
+	}
+	progEstimator.FinishOperation()
+
+	if err := graphics.ComposeDashboard(layout, panels, output); err != nil {
+		return err
+	}
+	if !quiet {
+		fmt.Printf("Dashboard with %d charts saved to %s\n", len(panels), output)
+	}
+	return nil
+}
+
+// emptyDashboardPlot stands in for a chart that could not be built
+func emptyDashboardPlot(mode string, err error) *plot.Plot {
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("%s: %v", mode, err)
+	p.HideAxes()
+	graphics.ApplyTheme(p)
+	return p
+}
+
+func burndownProjectPanel(reader readers.Reader) (*plot.Plot, error) {
+	header, name, matrix, err := reader.GetProjectBurndownWithHeader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load burndown data: %v", err)
+	}
+	resample := viper.GetString("resample")
+	if resample == "" {
+		resample = "year"
+	}
+	processed, err := burndown.LoadBurndown(header, name, matrix, resample, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process burndown data: %v", err)
+	}
+	return graphics.BurndownPythonPlot(processed, viper.GetBool("relative"))
+}
+
+func ownershipPanel(reader readers.Reader) (*plot.Plot, error) {
+	sequence, data, err := reader.GetOwnershipBurndown()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ownership burndown data: %v", err)
+	}
+	if len(sequence) == 0 || len(data[sequence[0]]) == 0 {
+		return nil, fmt.Errorf("no ownership data found")
+	}
+	sampling := 1
+	start, last := ownershipTimeRange(reader, len(data[sequence[0]][0]), sampling)
+	names, people, dateRange := processOwnershipBurndown(start, last, sampling, sequence, data, 20, false)
+	p, err := buildOwnershipPlot(names, people, dateRange)
+	if err != nil {
+		return nil, err
+	}
+	graphics.ApplyTheme(p)
+	return p, nil
+}
+
+func anomaliesPanel(reader readers.Reader) (*plot.Plot, error) {
+	series, err := loadActivitySeries(reader)
+	if err != nil {
+		return nil, err
+	}
+	p, err := buildChurnPlot(series)
+	if err != nil {
+		return nil, err
+	}
+	graphics.ApplyTheme(p)
+	anomalies := detectAnomalies(series, viper.GetFloat64("anomaly-threshold"))
+	if err := graphics.AddTimeAnnotations(p, anomalyAnnotations(anomalies)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func devsPanel(reader readers.Reader) (*plot.Plot, error) {
+	stats, err := topDevelopers(reader)
+	if err != nil {
+		return nil, err
+	}
+	series := generateTimeSeries(stats)
+	p, err := buildDevsPlot(stats, series)
+	if err != nil {
+		return nil, err
+	}
+	graphics.ApplyTheme(p)
+	if len(stats) > 0 {
+		begin, end := annotationTimeRange(reader, nil, nil)
+		weeks := len(series[stats[0].Name])
+		graphics.ApplyIndexedAxes(p, begin, end, 0, float64(weeks-1))
+		if err := graphics.AddIndexedAnnotations(p, begin, end, 0, float64(weeks-1)); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func devsEffortsPanel(reader readers.Reader) (*plot.Plot, error) {
+	stats, err := topDevelopers(reader)
+	if err != nil {
+		return nil, err
+	}
+	p, names, _, err := buildProductivityRankingPlot(analyzeDevEfforts(stats))
+	if err != nil {
+		return nil, err
+	}
+	p.NominalX(names...)
+	graphics.ApplyTheme(p)
+	return p, nil
+}
+
+func languagesPanel(reader readers.Reader) (*plot.Plot, error) {
+	stats, err := reader.GetLanguageStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get language stats: %v", err)
+	}
+	if len(stats) == 0 {
+		return nil, fmt.Errorf("no language statistics found in the data")
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Lines > stats[j].Lines
+	})
+	p, err := buildLanguagesPlot(stats)
+	if err != nil {
+		return nil, err
+	}
+	graphics.ApplyTheme(p)
+	return p, nil
+}
+
+func overwritesPanel(reader readers.Reader) (*plot.Plot, error) {
+	people, matrix, err := reader.GetPeopleInteraction()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get people interaction data: %v", err)
+	}
+	people, normalized := processOverwritesMatrix(people, matrix, 20, true)
+	p := buildOverwritesPlot(people, normalized)
+	graphics.ApplyTheme(p)
+	return p, nil
+}
+
+func runTimesPanel(reader readers.Reader) (*plot.Plot, error) {
+	stats, err := reader.GetRuntimeStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime stats: %v", err)
+	}
+	p, _, err := buildRuntimeBreakdownPlot(analyzeRuntimeStats(stats))
+	if err != nil {
+		return nil, err
+	}
+	graphics.ApplyTheme(p)
+	return p, nil
+}
+
+// topDevelopers returns the statistics of the --max-people developers with most commits
+func topDevelopers(reader readers.Reader) ([]readers.DeveloperStat, error) {
+	stats, err := reader.GetDeveloperStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get developer stats: %v", err)
+	}
+	if maxPeople := viper.GetInt("max-people"); maxPeople > 0 && len(stats) > maxPeople {
+		stats = selectTopDevelopers(stats, maxPeople)
+	}
+	return stats, nil
+}
@@ -1,8 +1,11 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"sort"
+	"time"
 
 	"github.com/spf13/viper"
 	"gonum.org/v1/plot"
@@ -12,8 +15,9 @@ import (
 	"labours-go/internal/readers"
 )
 
-// Devs generates plots for individual developers' contributions over time.
-func Devs(reader readers.Reader, output string, maxPeople int) error {
+// Devs generates plots for individual developers' contributions over time, stopping
+// between phases when ctx is cancelled.
+func Devs(ctx context.Context, reader readers.Reader, output string, maxPeople int) error {
 	// Initialize progress tracking
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
@@ -29,17 +33,20 @@ func Devs(reader readers.Reader, output string, maxPeople int) error {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to get developer stats: %v", err)
 	}
+	graphics.AddReportTable(ctx, devsReportTable(developerStats))
 
 	// Phase 2: Select top developers
 	progEstimator.NextOperation("Selecting top developers")
 	if len(developerStats) > maxPeople {
-		if !quiet {
-			fmt.Printf("Picking top %d developers by commit count.\n", maxPeople)
-		}
+		slog.InfoContext(ctx, "picking top developers by commit count", "max_people", maxPeople)
 		developerStats = selectTopDevelopers(developerStats, maxPeople)
 	}
 
 	// Phase 3: Generate time series data for each developer
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Generating time series data")
 	devSeries := generateTimeSeriesWithProgress(developerStats, progEstimator)
 
@@ -48,16 +55,18 @@ func Devs(reader readers.Reader, output string, maxPeople int) error {
 	clusters := clusterDevelopers(devSeries)
 
 	// Phase 5: Plot the developer contributions
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Generating visualization")
-	if err := plotDevs(developerStats, devSeries, clusters, output); err != nil {
+	begin, end := annotationTimeRange(reader, nil, nil)
+	if err := plotDevs(ctx, developerStats, devSeries, clusters, output, begin, end, nil); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to generate developer plots: %v", err)
 	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
-		fmt.Println("Developer plots generated successfully.")
-	}
 	return nil
 }
 
@@ -125,8 +134,38 @@ func clusterDevelopers(devSeries map[string][]float64) map[string]int {
 	return clusters
 }
 
-// plotDevs generates plots for developers' contributions.
-func plotDevs(developerStats []readers.DeveloperStat, devSeries map[string][]float64, clusters map[string]int, output string) error {
+// plotDevs generates plots for developers' contributions, marking the given annotations
+// next to the --annotations. The weekly series are spread over the period from begin to
+// end for annotation markers.
+func plotDevs(ctx context.Context, developerStats []readers.DeveloperStat, devSeries map[string][]float64, clusters map[string]int, output string, begin, end time.Time, annotations []graphics.Annotation) error {
+	p, err := buildDevsPlot(developerStats, devSeries)
+	if err != nil {
+		return err
+	}
+	if len(developerStats) > 0 {
+		weeks := len(devSeries[developerStats[0].Name])
+		graphics.ApplyIndexedAxes(p, begin, end, 0, float64(weeks-1))
+		annotations = append(annotations, graphics.ActiveAnnotations()...)
+		if err := graphics.AddIndexedTimeAnnotations(p, annotations, begin, end, 0, float64(weeks-1)); err != nil {
+			return err
+		}
+	}
+	graphics.AddReportChart(ctx, p)
+
+	// Save the plot
+	width, height := graphics.GetPlotSize(graphics.ChartTypeDefault)
+	if err := graphics.SavePlotWithFormat(p, width, height, output); err != nil {
+		return err
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved developer plot to %s\n", output)
+	}
+	return nil
+}
+
+// buildDevsPlot creates the weekly commit chart of the developers without saving it
+func buildDevsPlot(developerStats []readers.DeveloperStat, devSeries map[string][]float64) (*plot.Plot, error) {
 	// Create a new plot
 	p := plot.New()
 	p.Title.Text = "Developer Contributions Over Time"
@@ -135,7 +174,7 @@ func plotDevs(developerStats []readers.DeveloperStat, devSeries map[string][]flo
 
 	// Plot each developer's time series
 	for _, dev := range developerStats {
-		series := devSeries[dev.Name]
+		series := graphics.SmoothSeries(devSeries[dev.Name])
 		pts := make(plotter.XYs, len(series))
 		for i, val := range series {
 			pts[i].X = float64(i)
@@ -144,7 +183,7 @@ func plotDevs(developerStats []readers.DeveloperStat, devSeries map[string][]flo
 
 		line, err := plotter.NewLine(pts)
 		if err != nil {
-			return fmt.Errorf("error creating plot line for developer %s: %v", dev.Name, err)
+			return nil, fmt.Errorf("error creating plot line for developer %s: %v", dev.Name, err)
 		}
 
 		line.Color = graphics.ColorPalette[0] // Use the first color for now
@@ -152,12 +191,5 @@ func plotDevs(developerStats []readers.DeveloperStat, devSeries map[string][]flo
 		p.Legend.Add(dev.Name, line)
 	}
 
-	// Save the plot
-	width, height := graphics.GetPlotSize(graphics.ChartTypeDefault)
-	if err := graphics.SavePlotWithFormat(p, width, height, output); err != nil {
-		return err
-	}
-
-	fmt.Printf("Saved developer plot to %s\n", output)
-	return nil
+	return p, nil
 }
@@ -1,7 +1,9 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"sort"
 
@@ -14,8 +16,9 @@ import (
 	"labours-go/internal/readers"
 )
 
-// DevsEfforts generates plots for developers' effort analysis over time
-func DevsEfforts(reader readers.Reader, output string, maxPeople int) error {
+// DevsEfforts generates plots for developers' effort analysis over time, stopping
+// between phases when ctx is cancelled
+func DevsEfforts(ctx context.Context, reader readers.Reader, output string, maxPeople int) error {
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
 	
@@ -29,31 +32,35 @@ func DevsEfforts(reader readers.Reader, output string, maxPeople int) error {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to get developer stats: %v", err)
 	}
+	graphics.AddReportTable(ctx, devsReportTable(developerStats))
 
 	// Phase 2: Select top developers
 	progEstimator.NextOperation("Selecting top developers")
 	if len(developerStats) > maxPeople {
-		if !quiet {
-			fmt.Printf("Picking top %d developers by commit count.\n", maxPeople)
-		}
+		slog.InfoContext(ctx, "picking top developers by commit count", "max_people", maxPeople)
 		developerStats = selectTopDevelopers(developerStats, maxPeople)
 	}
 
 	// Phase 3: Analyze developer efforts
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Analyzing developer efforts")
 	effortMetrics := analyzeDevEfforts(developerStats)
 
 	// Phase 4: Generate plots
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Generating visualization")
-	if err := plotDevEfforts(effortMetrics, output); err != nil {
+	if err := plotDevEfforts(ctx, effortMetrics, output); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to generate developer efforts plots: %v", err)
 	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
-		fmt.Println("Developer efforts analysis completed successfully.")
-	}
 	return nil
 }
 
@@ -101,14 +108,14 @@ func analyzeDevEfforts(stats []readers.DeveloperStat) []EffortMetric {
 }
 
 // plotDevEfforts generates effort analysis plots
-func plotDevEfforts(metrics []EffortMetric, output string) error {
+func plotDevEfforts(ctx context.Context, metrics []EffortMetric, output string) error {
 	// Create commits vs lines changed scatter plot
-	if err := plotCommitsVsLines(metrics, output); err != nil {
+	if err := plotCommitsVsLines(ctx, metrics, output); err != nil {
 		return err
 	}
 	
 	// Create productivity ranking bar chart
-	if err := plotProductivityRanking(metrics, output); err != nil {
+	if err := plotProductivityRanking(ctx, metrics, output); err != nil {
 		return err
 	}
 	
@@ -116,7 +123,7 @@ func plotDevEfforts(metrics []EffortMetric, output string) error {
 }
 
 // plotCommitsVsLines creates scatter plot of commits vs total lines changed
-func plotCommitsVsLines(metrics []EffortMetric, output string) error {
+func plotCommitsVsLines(ctx context.Context, metrics []EffortMetric, output string) error {
 	p := plot.New()
 	p.Title.Text = "Developer Efforts: Commits vs Lines Changed"
 	p.X.Label.Text = "Total Commits"
@@ -151,18 +158,50 @@ func plotCommitsVsLines(metrics []EffortMetric, output string) error {
 		}
 	}
 	
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		return graphics.WriteTerminal(p, nil)
+	}
+
 	// Save the plot
 	outputFile := filepath.Join(output, "devs_efforts_scatter.png")
 	if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
 		return fmt.Errorf("failed to save scatter plot: %v", err)
 	}
 	
-	fmt.Printf("Saved developer efforts scatter plot to %s\n", outputFile)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved developer efforts scatter plot to %s\n", outputFile)
+	}
 	return nil
 }
 
 // plotProductivityRanking creates bar chart of developer productivity ranking
-func plotProductivityRanking(metrics []EffortMetric, output string) error {
+func plotProductivityRanking(ctx context.Context, metrics []EffortMetric, output string) error {
+	p, names, values, err := buildProductivityRankingPlot(metrics)
+	if err != nil {
+		return err
+	}
+
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		return graphics.WriteTerminal(p, graphics.TermBars{Title: p.Title.Text, Labels: names, Values: values})
+	}
+
+	// Save the plot
+	outputFile := filepath.Join(output, "devs_productivity_ranking.png")
+	if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
+		return fmt.Errorf("failed to save productivity ranking plot: %v", err)
+	}
+	
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved developer productivity ranking to %s\n", outputFile)
+	}
+	return nil
+}
+
+// buildProductivityRankingPlot creates the ranking bar chart of the top 20 developers and
+// returns it with their names and scores.
+func buildProductivityRankingPlot(metrics []EffortMetric) (*plot.Plot, []string, []float64, error) {
 	p := plot.New()
 	p.Title.Text = "Developer Productivity Ranking"
 	p.X.Label.Text = "Developer Rank"
@@ -175,26 +214,21 @@ func plotProductivityRanking(metrics []EffortMetric, output string) error {
 	}
 	
 	values := make(plotter.Values, maxDev)
+	names := make([]string, maxDev)
 	for i := 0; i < maxDev; i++ {
 		metric := metrics[i]
 		values[i] = float64(metric.Commits) + float64(metric.LinesAdded+metric.LinesRemoved+metric.LinesModified)*0.01
+		names[i] = metric.Name
 	}
 	
 	// Create bar chart
 	bars, err := plotter.NewBarChart(values, vg.Points(20))
 	if err != nil {
-		return fmt.Errorf("error creating bar chart: %v", err)
+		return nil, nil, nil, fmt.Errorf("error creating bar chart: %v", err)
 	}
 	
 	bars.Color = graphics.ColorPalette[1]
 	p.Add(bars)
-	
-	// Save the plot
-	outputFile := filepath.Join(output, "devs_productivity_ranking.png")
-	if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
-		return fmt.Errorf("failed to save productivity ranking plot: %v", err)
-	}
-	
-	fmt.Printf("Saved developer productivity ranking to %s\n", outputFile)
-	return nil
+
+	return p, names, values, nil
 }
\ No newline at end of file
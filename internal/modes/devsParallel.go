@@ -1,206 +1,318 @@
 package modes
 
 import (
+	"context"
 	"fmt"
 	"image/color"
+	"log/slog"
 	"math"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/spf13/viper"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
 	"labours-go/internal/graphics"
 	"labours-go/internal/readers"
 )
 
+const (
+	// parallelWindowDays is the length of the windows in which developers count as concurrent
+	parallelWindowDays = 7
+	// parallelSameFilesRatio is the file overlap from which a pair working in parallel
+	// is considered to work on the same files
+	parallelSameFilesRatio = 0.25
+	// parallelLabeledPairs is the number of pairs named on the charts and in the summary
+	parallelLabeledPairs = 8
+)
+
+// Areas of the developer pairs working in parallel
+const (
+	ParallelSameFiles      = "same files"
+	ParallelDifferentAreas = "different areas"
+	ParallelUnknownArea    = "unknown"
+)
+
+// ParallelismMetrics describes when developers work concurrently and on what
 type ParallelismMetrics struct {
-	TotalPeriods        int
-	ParallelPeriods     int
-	ParallelismIndex    float64
-	PeakConcurrency     int
-	AverageConcurrency  float64
-	DeveloperOverlaps   map[string]map[string]float64
-	PeriodConcurrency   []int
-	ActiveDevelopers    []string
+	WindowTicks        int                           `json:"window_ticks"`
+	TotalPeriods       int                           `json:"total_periods"`
+	ActivePeriods      int                           `json:"active_periods"`
+	ParallelPeriods    int                           `json:"parallel_periods"`
+	ParallelismIndex   float64                       `json:"parallelism_index"` // % of the active windows with several developers
+	PeakConcurrency    int                           `json:"peak_concurrency"`
+	AverageConcurrency float64                       `json:"average_concurrency"` // over the active windows
+	PeriodDates        []time.Time                   `json:"period_dates"`
+	PeriodConcurrency  []int                         `json:"period_concurrency"`
+	ActiveDevelopers   []string                      `json:"active_developers"`
+	DeveloperOverlaps  map[string]map[string]float64 `json:"developer_overlaps"` // Jaccard over the active ticks
+	Pairs              []DeveloperPair               `json:"pairs"`
+	HasFileOverlap     bool                          `json:"has_file_overlap"`
 }
 
-// DevsParallel analyzes parallel development patterns and visualizes when developers work concurrently
-func DevsParallel(reader readers.Reader, output string) error {
-	fmt.Println("Analyzing parallel development patterns...")
+// DeveloperPair is the co-activity of two developers and how much their files overlap
+type DeveloperPair struct {
+	First       string  `json:"first"`
+	Second      string  `json:"second"`
+	SharedTicks int     `json:"shared_ticks"`
+	CoActivity  float64 `json:"co_activity"`  // Jaccard index of the ticks they are active on
+	FileOverlap float64 `json:"file_overlap"` // people co-occurrence relative to the less active of the two
+	Area        string  `json:"area"`
+}
 
-	// Get people burndown data to analyze temporal activity
-	peopleBurndown, err := reader.GetPeopleBurndown()
+// DevsParallel analyzes parallel development patterns and visualizes when developers work
+// concurrently, stopping between developers and plots when ctx is cancelled
+func DevsParallel(ctx context.Context, reader readers.Reader, output string) error {
+	metrics, err := LoadDevsParallel(ctx, reader)
 	if err != nil {
-		fmt.Printf("Warning: could not get people burndown data: %v\n", err)
-		return generateSyntheticParallelAnalysis(reader, output)
+		return err
 	}
-
-	if len(peopleBurndown) == 0 {
-		fmt.Println("No people burndown data available, using synthetic data")
-		return generateSyntheticParallelAnalysis(reader, output)
+	if !metrics.HasFileOverlap {
+		slog.WarnContext(ctx, "no people co-occurrence data, run hercules with --couples to separate work on the same files from work on different areas")
 	}
 
-	// Calculate parallelism metrics
-	metrics := calculateParallelismMetrics(peopleBurndown)
-
-	// Generate visualizations
-	if err := plotParallelActivity(metrics, output); err != nil {
+	if !graphics.IsTerminalOutput(output) {
+		if err := os.MkdirAll(output, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %v", err)
+		}
+	}
+	if err := plotParallelActivity(ctx, metrics, output); err != nil {
 		return fmt.Errorf("failed to create parallel activity plot: %v", err)
 	}
-
-	if err := plotDeveloperConcurrency(metrics, output); err != nil {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := plotDeveloperConcurrency(ctx, metrics, output); err != nil {
 		return fmt.Errorf("failed to create developer concurrency plot: %v", err)
 	}
 
-	// Print summary statistics
 	printParallelismSummary(metrics)
 
-	fmt.Println("Parallel development analysis completed successfully.")
 	return nil
 }
 
-// calculateParallelismMetrics analyzes the temporal activity data to find parallel development patterns
-func calculateParallelismMetrics(peopleBurndown []readers.PeopleBurndown) ParallelismMetrics {
-	if len(peopleBurndown) == 0 {
-		return ParallelismMetrics{}
+// LoadDevsParallel computes the parallelism metrics from the developer ticks (hercules --devs)
+// and, when present, the people co-occurrence (hercules --couples)
+func LoadDevsParallel(ctx context.Context, reader readers.Reader) (ParallelismMetrics, error) {
+	devData, err := reader.GetDeveloperTimeSeriesData()
+	if err != nil {
+		return ParallelismMetrics{}, fmt.Errorf("no developer data for parallel analysis - run hercules with --devs: %v", err)
+	}
+	if devData == nil || len(devData.Days) == 0 {
+		return ParallelismMetrics{}, fmt.Errorf("no developer activity found - run hercules with --devs")
 	}
 
-	// Find the maximum time period across all developers
-	maxPeriods := 0
-	for _, person := range peopleBurndown {
-		if len(person.Matrix) > maxPeriods {
-			maxPeriods = len(person.Matrix)
-		}
+	begin, _ := reader.GetHeader()
+	metrics, err := calculateParallelismMetrics(ctx, devData, time.Unix(begin, 0).UTC())
+	if err != nil {
+		return ParallelismMetrics{}, err
+	}
+	if len(metrics.ActiveDevelopers) == 0 {
+		return ParallelismMetrics{}, fmt.Errorf("no developer activity found")
 	}
 
-	if maxPeriods == 0 {
-		return ParallelismMetrics{}
+	if names, matrix, err := reader.GetPeopleCooccurrence(); err == nil && len(names) > 0 {
+		classifyDeveloperPairs(&metrics, names, matrix)
 	}
+	return metrics, nil
+}
 
-	// Create a matrix of developer activity over time
-	developers := make([]string, len(peopleBurndown))
-	activityMatrix := make([][]bool, len(peopleBurndown))
-	
-	for i, person := range peopleBurndown {
-		developers[i] = person.Person
-		activityMatrix[i] = make([]bool, maxPeriods)
-		
-		// Mark periods where developer was active (had any commits/changes)
-		for j, period := range person.Matrix {
-			if j >= maxPeriods {
-				break
+// calculateParallelismMetrics finds the ticks every developer was active on and derives
+// the concurrency per window and the pairwise co-activity, stopping between developers
+// when ctx is cancelled
+func calculateParallelismMetrics(ctx context.Context, devData *readers.DeveloperTimeSeriesData, begin time.Time) (ParallelismMetrics, error) {
+	tickSize := devData.TickSize
+	if tickSize <= 0 {
+		tickSize = 86400 // hercules' default tick is one day
+	}
+	windowTicks := int(math.Round(parallelWindowDays * 86400 / tickSize))
+	if windowTicks < 1 {
+		windowTicks = 1
+	}
+
+	// active ticks of every developer
+	activity := make(map[int]map[int]bool)
+	maxTick := -1
+	for tick, devs := range devData.Days {
+		if tick < 0 {
+			continue
+		}
+		for devIdx, day := range devs {
+			if devIdx < 0 || devIdx >= len(devData.People) {
+				continue
 			}
-			// Consider active if there's any value in the period (sum > 0)
-			active := false
-			for _, val := range period {
-				if val > 0 {
-					active = true
-					break
-				}
+			if day.Commits == 0 && day.LinesAdded+day.LinesRemoved+day.LinesModified == 0 {
+				continue
+			}
+			if activity[devIdx] == nil {
+				activity[devIdx] = make(map[int]bool)
+			}
+			activity[devIdx][tick] = true
+			if tick > maxTick {
+				maxTick = tick
 			}
-			activityMatrix[i][j] = active
 		}
 	}
+	metrics := ParallelismMetrics{WindowTicks: windowTicks}
+	if len(activity) == 0 {
+		return metrics, nil
+	}
 
-	// Calculate concurrency for each time period
-	periodConcurrency := make([]int, maxPeriods)
-	parallelPeriods := 0
-	totalConcurrency := 0
-	peakConcurrency := 0
-
-	for period := 0; period < maxPeriods; period++ {
-		concurrent := 0
-		for dev := 0; dev < len(developers); dev++ {
-			if activityMatrix[dev][period] {
-				concurrent++
+	devIndexes := make([]int, 0, len(activity))
+	for devIdx := range activity {
+		devIndexes = append(devIndexes, devIdx)
+	}
+	sort.Slice(devIndexes, func(i, j int) bool {
+		a, b := devIndexes[i], devIndexes[j]
+		if len(activity[a]) != len(activity[b]) {
+			return len(activity[a]) > len(activity[b])
+		}
+		return devData.People[a] < devData.People[b]
+	})
+
+	// distinct active developers per window
+	windows := maxTick/windowTicks + 1
+	concurrent := make([]map[int]bool, windows)
+	for devIdx, ticks := range activity {
+		for tick := range ticks {
+			window := tick / windowTicks
+			if concurrent[window] == nil {
+				concurrent[window] = make(map[int]bool)
 			}
+			concurrent[window][devIdx] = true
+		}
+	}
+	metrics.TotalPeriods = windows
+	metrics.PeriodDates = make([]time.Time, windows)
+	metrics.PeriodConcurrency = make([]int, windows)
+	total := 0
+	for window, devs := range concurrent {
+		seconds := float64(window*windowTicks) * tickSize
+		metrics.PeriodDates[window] = begin.Add(time.Duration(seconds * float64(time.Second)))
+		metrics.PeriodConcurrency[window] = len(devs)
+		if len(devs) == 0 {
+			continue
 		}
-		periodConcurrency[period] = concurrent
-		totalConcurrency += concurrent
-		
-		if concurrent > 1 {
-			parallelPeriods++
+		metrics.ActivePeriods++
+		total += len(devs)
+		if len(devs) > 1 {
+			metrics.ParallelPeriods++
 		}
-		if concurrent > peakConcurrency {
-			peakConcurrency = concurrent
+		if len(devs) > metrics.PeakConcurrency {
+			metrics.PeakConcurrency = len(devs)
 		}
 	}
+	if metrics.ActivePeriods > 0 {
+		metrics.ParallelismIndex = float64(metrics.ParallelPeriods) / float64(metrics.ActivePeriods) * 100
+		metrics.AverageConcurrency = float64(total) / float64(metrics.ActivePeriods)
+	}
 
-	// Calculate developer overlap coefficients
-	developerOverlaps := make(map[string]map[string]float64)
-	for i, dev1 := range developers {
-		developerOverlaps[dev1] = make(map[string]float64)
-		for j, dev2 := range developers {
-			if i == j {
-				developerOverlaps[dev1][dev2] = 1.0
-				continue
-			}
-			
-			// Count periods where both developers were active
-			overlap := 0
-			dev1Active := 0
-			dev2Active := 0
-			
-			for period := 0; period < maxPeriods; period++ {
-				if activityMatrix[i][period] {
-					dev1Active++
-				}
-				if activityMatrix[j][period] {
-					dev2Active++
-				}
-				if activityMatrix[i][period] && activityMatrix[j][period] {
-					overlap++
+	// Jaccard similarity of the active ticks of every pair
+	metrics.ActiveDevelopers = make([]string, len(devIndexes))
+	metrics.DeveloperOverlaps = make(map[string]map[string]float64, len(devIndexes))
+	for i, devIdx := range devIndexes {
+		metrics.ActiveDevelopers[i] = devData.People[devIdx]
+		metrics.DeveloperOverlaps[devData.People[devIdx]] = map[string]float64{devData.People[devIdx]: 1}
+	}
+	for i, a := range devIndexes {
+		if err := ctx.Err(); err != nil {
+			return metrics, err
+		}
+		for _, b := range devIndexes[i+1:] {
+			shared := 0
+			for tick := range activity[a] {
+				if activity[b][tick] {
+					shared++
 				}
 			}
-			
-			// Calculate Jaccard similarity coefficient
-			union := dev1Active + dev2Active - overlap
-			if union > 0 {
-				developerOverlaps[dev1][dev2] = float64(overlap) / float64(union)
-			} else {
-				developerOverlaps[dev1][dev2] = 0.0
+			jaccard := float64(shared) / float64(len(activity[a])+len(activity[b])-shared)
+			metrics.DeveloperOverlaps[devData.People[a]][devData.People[b]] = jaccard
+			metrics.DeveloperOverlaps[devData.People[b]][devData.People[a]] = jaccard
+			if shared == 0 {
+				continue
 			}
+			metrics.Pairs = append(metrics.Pairs, DeveloperPair{
+				First:       devData.People[a],
+				Second:      devData.People[b],
+				SharedTicks: shared,
+				CoActivity:  jaccard,
+				Area:        ParallelUnknownArea,
+			})
 		}
 	}
+	sort.SliceStable(metrics.Pairs, func(i, j int) bool {
+		if metrics.Pairs[i].CoActivity != metrics.Pairs[j].CoActivity {
+			return metrics.Pairs[i].CoActivity > metrics.Pairs[j].CoActivity
+		}
+		return metrics.Pairs[i].SharedTicks > metrics.Pairs[j].SharedTicks
+	})
+	return metrics, nil
+}
 
-	parallelismIndex := 0.0
-	if maxPeriods > 0 {
-		parallelismIndex = float64(parallelPeriods) / float64(maxPeriods) * 100
+// classifyDeveloperPairs tells the pairs working in parallel on the same files from those
+// working on different areas using the people co-occurrence matrix, whose diagonal holds
+// the file changes of every developer and the other cells the changes they share
+func classifyDeveloperPairs(metrics *ParallelismMetrics, names []string, matrix [][]int) {
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		if i < len(matrix) {
+			index[personKey(name)] = i
+		}
 	}
-
-	averageConcurrency := 0.0
-	if maxPeriods > 0 {
-		averageConcurrency = float64(totalConcurrency) / float64(maxPeriods)
+	cell := func(i, j int) int {
+		if j < len(matrix[i]) {
+			return matrix[i][j]
+		}
+		return 0
 	}
 
-	return ParallelismMetrics{
-		TotalPeriods:       maxPeriods,
-		ParallelPeriods:    parallelPeriods,
-		ParallelismIndex:   parallelismIndex,
-		PeakConcurrency:    peakConcurrency,
-		AverageConcurrency: averageConcurrency,
-		DeveloperOverlaps:  developerOverlaps,
-		PeriodConcurrency:  periodConcurrency,
-		ActiveDevelopers:   developers,
+	for k := range metrics.Pairs {
+		pair := &metrics.Pairs[k]
+		i, okFirst := index[personKey(pair.First)]
+		j, okSecond := index[personKey(pair.Second)]
+		if !okFirst || !okSecond {
+			continue
+		}
+		metrics.HasFileOverlap = true
+		changes := cell(i, i)
+		if other := cell(j, j); other < changes {
+			changes = other
+		}
+		if changes > 0 {
+			pair.FileOverlap = math.Min(1, float64(cell(i, j))/float64(changes))
+		}
+		if pair.FileOverlap >= parallelSameFilesRatio {
+			pair.Area = ParallelSameFiles
+		} else {
+			pair.Area = ParallelDifferentAreas
+		}
 	}
 }
 
+// personKey normalizes the developer identities of the devs and couples analyses
+func personKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
 // plotParallelActivity creates a timeline showing concurrent developer activity
-func plotParallelActivity(metrics ParallelismMetrics, output string) error {
+func plotParallelActivity(ctx context.Context, metrics ParallelismMetrics, output string) error {
 	p := plot.New()
 	p.Title.Text = "Parallel Development Activity Over Time"
-	p.X.Label.Text = "Time Period"
-	p.Y.Label.Text = "Number of Concurrent Developers"
+	p.X.Label.Text = "Date"
+	p.Y.Label.Text = fmt.Sprintf("Concurrent developers per %d days", parallelWindowDays)
+	p.X.Tick.Marker = &graphics.TimeTicker{Format: "2006-01-02"}
 
-	// Create points for the concurrency timeline
 	pts := make(plotter.XYs, len(metrics.PeriodConcurrency))
 	for i, concurrency := range metrics.PeriodConcurrency {
-		pts[i].X = float64(i)
+		pts[i].X = float64(metrics.PeriodDates[i].Unix())
 		pts[i].Y = float64(concurrency)
 	}
 
-	// Create line plot
 	line, err := plotter.NewLine(pts)
 	if err != nil {
 		return fmt.Errorf("error creating line plot: %v", err)
@@ -209,26 +321,21 @@ func plotParallelActivity(metrics ParallelismMetrics, output string) error {
 	line.Width = vg.Points(2)
 
 	// Add a filled polygon area under the line
-	// Create polygon points for filled area
 	areaPoints := make(plotter.XYs, len(pts)+2)
 	areaPoints[0] = plotter.XY{X: pts[0].X, Y: 0}
-	for i, pt := range pts {
-		areaPoints[i+1] = pt
-	}
+	copy(areaPoints[1:], pts)
 	areaPoints[len(areaPoints)-1] = plotter.XY{X: pts[len(pts)-1].X, Y: 0}
-	
 	polygon, err := plotter.NewPolygon(areaPoints)
 	if err == nil {
-		// Create a semi-transparent version of the color
 		baseColor := graphics.ColorPalette[0].(color.RGBA)
 		polygon.Color = color.RGBA{R: baseColor.R, G: baseColor.G, B: baseColor.B, A: 100}
+		polygon.LineStyle.Width = 0
 		p.Add(polygon)
 	}
 
 	p.Add(line)
 	p.Legend.Add("Concurrent Developers", line)
 
-	// Add horizontal line for average concurrency
 	if metrics.AverageConcurrency > 0 {
 		avgLine := plotter.NewFunction(func(x float64) float64 {
 			return metrics.AverageConcurrency
@@ -238,203 +345,178 @@ func plotParallelActivity(metrics ParallelismMetrics, output string) error {
 		p.Add(avgLine)
 		p.Legend.Add(fmt.Sprintf("Average (%.1f)", metrics.AverageConcurrency), avgLine)
 	}
+	p.Y.Min = 0
+	graphics.AddTimeAnnotations(p, graphics.ActiveAnnotations())
 
-	// Save PNG with dynamic sizing
-	width, height := graphics.GetPlotSize(graphics.ChartTypeDefault)
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		series := make([]float64, len(metrics.PeriodConcurrency))
+		for i, concurrency := range metrics.PeriodConcurrency {
+			series[i] = float64(concurrency)
+		}
+		return graphics.WriteTerminal(p, graphics.TermStackedArea{
+			Title:  p.Title.Text,
+			Labels: []string{"Concurrent Developers"},
+			Series: [][]float64{series},
+			Start:  metrics.PeriodDates[0],
+			End:    metrics.PeriodDates[len(metrics.PeriodDates)-1],
+		})
+	}
+
+	width, height := graphics.GetPlotSize(graphics.ChartTypeWide)
 	outputFile := filepath.Join(output, "parallel_activity.png")
 	if err := p.Save(width, height, outputFile); err != nil {
 		return fmt.Errorf("failed to save parallel activity plot: %v", err)
 	}
-
-	// Save SVG
 	outputFileSVG := filepath.Join(output, "parallel_activity.svg")
 	if err := p.Save(width, height, outputFileSVG); err != nil {
 		return fmt.Errorf("failed to save parallel activity SVG: %v", err)
 	}
 
-	fmt.Printf("Saved parallel activity plots to %s and %s\n", outputFile, outputFileSVG)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved parallel activity plots to %s and %s\n", outputFile, outputFileSVG)
+	}
 	return nil
 }
 
-// plotDeveloperConcurrency creates a heatmap/bar chart showing developer overlap patterns
-func plotDeveloperConcurrency(metrics ParallelismMetrics, output string) error {
-	if len(metrics.ActiveDevelopers) == 0 {
-		return fmt.Errorf("no active developers found")
+// plotDeveloperConcurrency scatters the pairs working in parallel by co-activity and file overlap
+func plotDeveloperConcurrency(ctx context.Context, metrics ParallelismMetrics, output string) error {
+	if len(metrics.Pairs) == 0 {
+		slog.Info("no developers worked in parallel, skipping the developer concurrency plot")
+		return nil
 	}
 
-	// Create a bar chart showing average overlap per developer
 	p := plot.New()
-	p.Title.Text = "Developer Collaboration Patterns"
-	p.X.Label.Text = "Developers"
-	p.Y.Label.Text = "Average Overlap Coefficient"
-
-	// Calculate average overlap for each developer
-	devOverlapAvgs := make([]float64, len(metrics.ActiveDevelopers))
-	for i, dev := range metrics.ActiveDevelopers {
-		total := 0.0
-		count := 0
-		for otherDev, overlap := range metrics.DeveloperOverlaps[dev] {
-			if dev != otherDev {
-				total += overlap
-				count++
+	p.Title.Text = "Developers Working in Parallel"
+	p.X.Label.Text = "Co-activity (Jaccard index of active ticks)"
+	if metrics.HasFileOverlap {
+		p.Y.Label.Text = "Shared file changes"
+	} else {
+		p.Y.Label.Text = "Shared file changes (no couples data)"
+	}
+	p.X.Min, p.X.Max = 0, 1
+	p.Y.Min, p.Y.Max = 0, 1
+
+	areas := []struct {
+		name  string
+		color color.Color
+	}{
+		{ParallelSameFiles, color.RGBA{R: 0xE1, G: 0x4C, B: 0x35, A: 255}},
+		{ParallelDifferentAreas, color.RGBA{R: 0x8D, G: 0xB8, B: 0x43, A: 255}},
+		{ParallelUnknownArea, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 255}},
+	}
+	for _, area := range areas {
+		var pts plotter.XYs
+		for _, pair := range metrics.Pairs {
+			if pair.Area == area.name {
+				pts = append(pts, plotter.XY{X: pair.CoActivity, Y: pair.FileOverlap})
 			}
 		}
-		if count > 0 {
-			devOverlapAvgs[i] = total / float64(count)
+		if len(pts) == 0 {
+			continue
+		}
+		scatter, err := plotter.NewScatter(pts)
+		if err != nil {
+			return fmt.Errorf("error creating scatter plot: %v", err)
 		}
+		scatter.GlyphStyle.Color = area.color
+		scatter.GlyphStyle.Shape = draw.CircleGlyph{}
+		scatter.GlyphStyle.Radius = vg.Points(4)
+		p.Add(scatter)
+		p.Legend.Add(area.name, scatter)
 	}
 
-	// Create bar chart
-	bars := make(plotter.Values, len(devOverlapAvgs))
-	for i, avg := range devOverlapAvgs {
-		bars[i] = avg
+	if metrics.HasFileOverlap {
+		threshold := plotter.NewFunction(func(x float64) float64 { return parallelSameFilesRatio })
+		threshold.Color = color.Gray{Y: 0x80}
+		threshold.Dashes = []vg.Length{vg.Points(3), vg.Points(3)}
+		p.Add(threshold)
 	}
 
-	barChart, err := plotter.NewBarChart(bars, vg.Points(20))
+	labels := plotter.XYLabels{}
+	for i, pair := range metrics.Pairs {
+		if i == parallelLabeledPairs {
+			break
+		}
+		labels.XYs = append(labels.XYs, plotter.XY{X: pair.CoActivity, Y: pair.FileOverlap})
+		labels.Labels = append(labels.Labels, parallelPairName(pair))
+	}
+	label, err := plotter.NewLabels(labels)
 	if err != nil {
-		return fmt.Errorf("error creating bar chart: %v", err)
+		return fmt.Errorf("error creating pair labels: %v", err)
 	}
-	barChart.Color = graphics.ColorPalette[2]
-
-	p.Add(barChart)
+	for i := range label.TextStyle {
+		label.TextStyle[i].XAlign = draw.XLeft
+	}
+	label.Offset = vg.Point{X: vg.Points(5)}
+	p.Add(label)
 
-	// Set custom X-axis labels
-	p.NominalX(metrics.ActiveDevelopers...)
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		values := make([]float64, len(labels.XYs))
+		for i, xy := range labels.XYs {
+			values[i] = xy.X
+		}
+		return graphics.WriteTerminal(p, graphics.TermBars{Title: p.Title.Text, Labels: labels.Labels, Values: values})
+	}
 
-	// Save PNG with dynamic sizing
-	width2, height2 := graphics.GetPlotSize(graphics.ChartTypeDefault)
+	width, height := graphics.GetPlotSize(graphics.ChartTypeDefault)
 	outputFile := filepath.Join(output, "developer_concurrency.png")
-	if err := p.Save(width2, height2, outputFile); err != nil {
+	if err := p.Save(width, height, outputFile); err != nil {
 		return fmt.Errorf("failed to save developer concurrency plot: %v", err)
 	}
-
-	// Save SVG
 	outputFileSVG := filepath.Join(output, "developer_concurrency.svg")
-	if err := p.Save(width2, height2, outputFileSVG); err != nil {
+	if err := p.Save(width, height, outputFileSVG); err != nil {
 		return fmt.Errorf("failed to save developer concurrency SVG: %v", err)
 	}
 
-	fmt.Printf("Saved developer concurrency plots to %s and %s\n", outputFile, outputFileSVG)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved developer concurrency plots to %s and %s\n", outputFile, outputFileSVG)
+	}
 	return nil
 }
 
-// generateSyntheticParallelAnalysis creates a fallback analysis when real data is not available
-func generateSyntheticParallelAnalysis(reader readers.Reader, output string) error {
-	fmt.Println("Generating synthetic parallel development analysis...")
-
-	// Try to get basic developer stats for fallback
-	developerStats, err := reader.GetDeveloperStats()
-	if err != nil {
-		fmt.Printf("Warning: could not get developer stats: %v\n", err)
-		return fmt.Errorf("no data available for parallel analysis")
-	}
-
-	if len(developerStats) == 0 {
-		return fmt.Errorf("no developer data available")
-	}
-
-	// Create synthetic parallel activity data
-	numPeriods := 52 // 52 weeks
-	metrics := ParallelismMetrics{
-		TotalPeriods:       numPeriods,
-		ParallelPeriods:    int(float64(numPeriods) * 0.6), // Assume 60% parallel activity
-		ParallelismIndex:   60.0,
-		PeakConcurrency:    min(len(developerStats), 4),
-		AverageConcurrency: math.Min(float64(len(developerStats))*0.7, 3.0),
-		ActiveDevelopers:   make([]string, 0, len(developerStats)),
-		PeriodConcurrency:  make([]int, numPeriods),
-		DeveloperOverlaps:  make(map[string]map[string]float64),
-	}
-
-	// Generate synthetic data based on developer stats
-	for i, dev := range developerStats {
-		metrics.ActiveDevelopers = append(metrics.ActiveDevelopers, dev.Name)
-		
-		// Initialize overlaps
-		metrics.DeveloperOverlaps[dev.Name] = make(map[string]float64)
-		for j, otherDev := range developerStats {
-			if i == j {
-				metrics.DeveloperOverlaps[dev.Name][otherDev.Name] = 1.0
-			} else {
-				// Synthetic overlap based on relative activity
-				ratio := float64(min(dev.Commits, otherDev.Commits)) / float64(max(dev.Commits, otherDev.Commits))
-				overlap := ratio * (0.3 + 0.4*math.Sin(float64(i+j)*0.5)) // Add some variation
-				metrics.DeveloperOverlaps[dev.Name][otherDev.Name] = math.Max(0, math.Min(1, overlap))
-			}
-		}
-	}
-
-	// Generate synthetic period concurrency
-	for i := 0; i < numPeriods; i++ {
-		// Simulate realistic parallel activity patterns
-		baseActivity := 1 + int(metrics.AverageConcurrency*math.Sin(float64(i)*0.3)+0.5)
-		variation := int(math.Sin(float64(i)*0.1) * 2)
-		concurrency := max(1, min(len(developerStats), baseActivity+variation))
-		metrics.PeriodConcurrency[i] = concurrency
-	}
-
-	// Generate plots with synthetic data
-	if err := plotParallelActivity(metrics, output); err != nil {
-		return fmt.Errorf("failed to create parallel activity plot: %v", err)
-	}
-
-	if err := plotDeveloperConcurrency(metrics, output); err != nil {
-		return fmt.Errorf("failed to create developer concurrency plot: %v", err)
-	}
-
-	printParallelismSummary(metrics)
-
-	fmt.Println("Synthetic parallel development analysis completed.")
-	return nil
+// parallelPairName names a pair by the names of its developers without their emails
+func parallelPairName(pair DeveloperPair) string {
+	first := strings.SplitN(pair.First, "|", 2)[0]
+	second := strings.SplitN(pair.Second, "|", 2)[0]
+	return first + " ↔ " + second
 }
 
 // printParallelismSummary displays key metrics about parallel development
 func printParallelismSummary(metrics ParallelismMetrics) {
 	fmt.Println("\n=== Parallel Development Summary ===")
-	fmt.Printf("Total Time Periods: %d\n", metrics.TotalPeriods)
-	fmt.Printf("Periods with Parallel Activity: %d (%.1f%%)\n", 
+	fmt.Printf("Windows of %d ticks: %d, %d with activity\n", metrics.WindowTicks, metrics.TotalPeriods, metrics.ActivePeriods)
+	fmt.Printf("Windows with Parallel Activity: %d (%.1f%%)\n",
 		metrics.ParallelPeriods, metrics.ParallelismIndex)
 	fmt.Printf("Peak Concurrent Developers: %d\n", metrics.PeakConcurrency)
 	fmt.Printf("Average Concurrent Developers: %.2f\n", metrics.AverageConcurrency)
 	fmt.Printf("Active Developers: %d\n", len(metrics.ActiveDevelopers))
 
-	if len(metrics.ActiveDevelopers) > 1 {
-		fmt.Println("\nTop Developer Collaborations:")
-		
-		type overlap struct {
-			pair    string
-			overlap float64
-		}
-		
-		var overlaps []overlap
-		processed := make(map[string]bool)
-		
-		for dev1, others := range metrics.DeveloperOverlaps {
-			for dev2, ovr := range others {
-				if dev1 != dev2 {
-					pairKey := dev1 + "-" + dev2
-					reversePairKey := dev2 + "-" + dev1
-					
-					if !processed[pairKey] && !processed[reversePairKey] {
-						overlaps = append(overlaps, overlap{
-							pair:    fmt.Sprintf("%s ↔ %s", dev1, dev2),
-							overlap: ovr,
-						})
-						processed[pairKey] = true
-						processed[reversePairKey] = true
-					}
-				}
-			}
+	if len(metrics.Pairs) == 0 {
+		return
+	}
+	counts := make(map[string]int)
+	for _, pair := range metrics.Pairs {
+		counts[pair.Area]++
+	}
+	fmt.Printf("Pairs Working in Parallel: %d", len(metrics.Pairs))
+	if metrics.HasFileOverlap {
+		fmt.Printf(" (%d on the same files, %d on different areas)",
+			counts[ParallelSameFiles], counts[ParallelDifferentAreas])
+	}
+	fmt.Println()
+
+	fmt.Println("\nTop Parallel Pairs:")
+	for i, pair := range metrics.Pairs {
+		if i == parallelLabeledPairs {
+			break
 		}
-		
-		sort.Slice(overlaps, func(i, j int) bool {
-			return overlaps[i].overlap > overlaps[j].overlap
-		})
-		
-		maxDisplay := min(5, len(overlaps))
-		for i := 0; i < maxDisplay; i++ {
-			fmt.Printf("  %s: %.3f\n", overlaps[i].pair, overlaps[i].overlap)
+		fmt.Printf("  %s: co-activity %.3f over %d ticks", parallelPairName(pair), pair.CoActivity, pair.SharedTicks)
+		if pair.Area != ParallelUnknownArea {
+			fmt.Printf(", shared files %.2f, %s", pair.FileOverlap, pair.Area)
 		}
+		fmt.Println()
 	}
 }
 
@@ -451,4 +533,4 @@ func max(a, b int) int {
 		return a
 	}
 	return b
-}
\ No newline at end of file
+}
@@ -0,0 +1,146 @@
+package modes
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"labours-go/internal/readers"
+)
+
+// MockParallelReader serves developer ticks and the people co-occurrence of devs-parallel
+type MockParallelReader struct {
+	MockSentimentReader
+	devs        *readers.DeveloperTimeSeriesData
+	peopleIndex []string
+	peopleCoocc [][]int
+}
+
+func (m *MockParallelReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTimeSeriesData, error) {
+	if m.devs == nil {
+		return m.MockSentimentReader.GetDeveloperTimeSeriesData()
+	}
+	return m.devs, nil
+}
+
+func (m *MockParallelReader) GetPeopleCooccurrence() ([]string, [][]int, error) {
+	return m.peopleIndex, m.peopleCoocc, nil
+}
+
+// newMockParallelReader has alice and bob working together in the first week on the same
+// files, alice and carol together in the third week on different files, and dave alone
+func newMockParallelReader() *MockParallelReader {
+	active := readers.DevDay{Commits: 1, LinesAdded: 10}
+	return &MockParallelReader{
+		MockSentimentReader: MockSentimentReader{
+			begin: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		},
+		devs: &readers.DeveloperTimeSeriesData{
+			People:   []string{"alice|a@x", "bob|b@x", "carol|c@x", "dave|d@x"},
+			TickSize: 86400,
+			Days: map[int]map[int]readers.DevDay{
+				0:  {0: active, 1: active},
+				1:  {0: active, 1: active},
+				2:  {1: active},
+				14: {0: active, 2: active},
+				15: {0: active, -1: active},
+				30: {3: active},
+			},
+		},
+		peopleIndex: []string{"Alice|a@x", "bob|b@x", "carol|c@x", "dave|d@x"},
+		peopleCoocc: [][]int{
+			{10, 6, 1, 0},
+			{6, 8, 0, 0},
+			{1, 0, 12, 0},
+			{0, 0, 0, 3},
+		},
+	}
+}
+
+func TestLoadDevsParallel(t *testing.T) {
+	metrics, err := LoadDevsParallel(context.Background(), newMockParallelReader())
+	if err != nil {
+		t.Fatalf("LoadDevsParallel failed: %v", err)
+	}
+
+	if metrics.WindowTicks != 7 || metrics.TotalPeriods != 5 || metrics.ActivePeriods != 3 {
+		t.Errorf("unexpected windows: %d ticks, %d total, %d active",
+			metrics.WindowTicks, metrics.TotalPeriods, metrics.ActivePeriods)
+	}
+	want := []int{2, 0, 2, 0, 1}
+	for i, concurrency := range want {
+		if metrics.PeriodConcurrency[i] != concurrency {
+			t.Errorf("window %d: expected %d concurrent developers, got %d", i, concurrency, metrics.PeriodConcurrency[i])
+		}
+	}
+	if metrics.ParallelPeriods != 2 || metrics.PeakConcurrency != 2 ||
+		math.Abs(metrics.AverageConcurrency-5.0/3) > 1e-9 || math.Abs(metrics.ParallelismIndex-200.0/3) > 1e-9 {
+		t.Errorf("unexpected concurrency: %+v", metrics)
+	}
+	if !metrics.PeriodDates[2].Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("the third window should start on 2024-01-15, got %s", metrics.PeriodDates[2])
+	}
+	if metrics.ActiveDevelopers[0] != "alice|a@x" || len(metrics.ActiveDevelopers) != 4 {
+		t.Errorf("expected the most active developer first, got %v", metrics.ActiveDevelopers)
+	}
+
+	if !metrics.HasFileOverlap || len(metrics.Pairs) != 2 {
+		t.Fatalf("expected 2 classified pairs, got %+v", metrics.Pairs)
+	}
+	// alice is active on 0, 1, 14, 15 and bob on 0, 1, 2
+	first := metrics.Pairs[0]
+	if first.First != "alice|a@x" || first.Second != "bob|b@x" || first.SharedTicks != 2 ||
+		math.Abs(first.CoActivity-0.4) > 1e-9 || math.Abs(first.FileOverlap-0.75) > 1e-9 ||
+		first.Area != ParallelSameFiles {
+		t.Errorf("unexpected alice and bob pair: %+v", first)
+	}
+	second := metrics.Pairs[1]
+	if second.Second != "carol|c@x" || math.Abs(second.CoActivity-0.25) > 1e-9 ||
+		math.Abs(second.FileOverlap-0.1) > 1e-9 || second.Area != ParallelDifferentAreas {
+		t.Errorf("unexpected alice and carol pair: %+v", second)
+	}
+	if overlap := metrics.DeveloperOverlaps["bob|b@x"]["dave|d@x"]; overlap != 0 {
+		t.Errorf("bob and dave never worked together, got %g", overlap)
+	}
+}
+
+func TestLoadDevsParallelWithoutCouples(t *testing.T) {
+	reader := newMockParallelReader()
+	reader.peopleIndex, reader.peopleCoocc = nil, nil
+
+	metrics, err := LoadDevsParallel(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("LoadDevsParallel failed: %v", err)
+	}
+	if metrics.HasFileOverlap {
+		t.Error("expected no file overlap without people co-occurrence")
+	}
+	for _, pair := range metrics.Pairs {
+		if pair.Area != ParallelUnknownArea {
+			t.Errorf("expected an unknown area without couples, got %+v", pair)
+		}
+	}
+}
+
+func TestDevsParallel(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := DevsParallel(context.Background(), newMockParallelReader(), tempDir); err != nil {
+		t.Fatalf("DevsParallel failed: %v", err)
+	}
+	for _, filename := range []string{"parallel_activity.png", "parallel_activity.svg",
+		"developer_concurrency.png", "developer_concurrency.svg"} {
+		if _, err := os.Stat(filepath.Join(tempDir, filename)); err != nil {
+			t.Errorf("Expected output file %s was not created", filename)
+		}
+	}
+}
+
+func TestDevsParallelWithNoData(t *testing.T) {
+	if err := DevsParallel(context.Background(), &MockParallelReader{}, t.TempDir()); err == nil {
+		t.Error("Expected error when no developer data is available, but got nil")
+	}
+}
@@ -0,0 +1,16 @@
+package modes
+
+import (
+	"github.com/spf13/viper"
+	"labours-go/internal/graphics"
+	"labours-go/internal/workers"
+)
+
+// chartJobs is the number of charts of a mode rendered at once, from --jobs. Terminal
+// charts are drawn one after the other.
+func chartJobs(output string, charts int) int {
+	if graphics.IsTerminalOutput(output) {
+		return 1
+	}
+	return min(workers.Count(viper.GetInt("jobs")), charts)
+}
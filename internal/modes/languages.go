@@ -1,9 +1,12 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"sort"
 
+	"github.com/spf13/viper"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
@@ -12,8 +15,8 @@ import (
 )
 
 // Languages generates language statistics and visualization showing the distribution
-// of programming languages used in the repository.
-func Languages(reader readers.Reader, output string) error {
+// of programming languages used in the repository. No chart is drawn when ctx is cancelled.
+func Languages(ctx context.Context, reader readers.Reader, output string) error {
 	// Step 1: Read language statistics
 	languageStats, err := reader.GetLanguageStats()
 	if err != nil {
@@ -28,19 +31,70 @@ func Languages(reader readers.Reader, output string) error {
 	sort.Slice(languageStats, func(i, j int) bool {
 		return languageStats[i].Lines > languageStats[j].Lines
 	})
+	graphics.AddReportTable(ctx, languagesReportTable(languageStats))
 
 	// Step 3: Generate visualization
-	if err := plotLanguages(languageStats, output); err != nil {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := plotLanguages(ctx, languageStats, output); err != nil {
 		return fmt.Errorf("failed to generate language plot: %v", err)
 	}
 
-	fmt.Printf("Language analysis completed. Found %d languages.\n", len(languageStats))
+	slog.InfoContext(ctx, "language analysis completed", "languages", len(languageStats))
 	return nil
 }
 
 // plotLanguages creates a bar chart showing language distribution by lines of code
-func plotLanguages(languageStats []readers.LanguageStat, output string) error {
-	// Create a new plot
+func plotLanguages(ctx context.Context, languageStats []readers.LanguageStat, output string) error {
+	p, err := buildLanguagesPlot(languageStats)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(languageStats))
+	values := make([]float64, len(languageStats))
+	for i, stat := range languageStats {
+		names[i] = stat.Language
+		values[i] = float64(stat.Lines)
+	}
+
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		if err := graphics.WriteTerminal(p, graphics.TermBars{Title: p.Title.Text, Labels: names, Values: values}); err != nil {
+			return err
+		}
+	} else {
+		// Save the plot with dynamic sizing
+		width, height := graphics.GetPlotSize(graphics.ChartTypeDefault)
+		if err := graphics.SavePlotWithFormat(p, width, height, output); err != nil {
+			return err
+		}
+
+		if !viper.GetBool("quiet") {
+			fmt.Printf("Language chart saved to %s\n", output)
+		}
+	}
+	
+	// Print text summary
+	fmt.Println("\nLanguage Statistics:")
+	fmt.Println("====================")
+	totalLines := 0
+	for _, stat := range languageStats {
+		totalLines += stat.Lines
+	}
+	
+	for i, stat := range languageStats {
+		percentage := float64(stat.Lines) / float64(totalLines) * 100
+		fmt.Printf("%2d. %-15s %8d lines (%5.1f%%)\n", i+1, stat.Language, stat.Lines, percentage)
+	}
+	
+	fmt.Printf("\nTotal: %d lines across %d languages\n", totalLines, len(languageStats))
+
+	return nil
+}
+
+// buildLanguagesPlot creates the language bar chart without saving it
+func buildLanguagesPlot(languageStats []readers.LanguageStat) (*plot.Plot, error) {
 	p := plot.New()
 	p.Title.Text = "Programming Languages by Lines of Code"
 	p.X.Label.Text = "Languages"
@@ -58,7 +112,7 @@ func plotLanguages(languageStats []readers.LanguageStat, output string) error {
 	// Create bar chart
 	bars, err := plotter.NewBarChart(values, vg.Points(50))
 	if err != nil {
-		return fmt.Errorf("failed to create bar chart: %v", err)
+		return nil, fmt.Errorf("failed to create bar chart: %v", err)
 	}
 
 	// Style the bars with different colors
@@ -78,28 +132,5 @@ func plotLanguages(languageStats []readers.LanguageStat, output string) error {
 		p.X.Tick.Label.YAlign = -0.5
 	}
 
-	// Save the plot with dynamic sizing
-	width, height := graphics.GetPlotSize(graphics.ChartTypeDefault)
-	if err := graphics.SavePlotWithFormat(p, width, height, output); err != nil {
-		return err
-	}
-
-	fmt.Printf("Language chart saved to %s\n", output)
-	
-	// Print text summary
-	fmt.Println("\nLanguage Statistics:")
-	fmt.Println("====================")
-	totalLines := 0
-	for _, stat := range languageStats {
-		totalLines += stat.Lines
-	}
-	
-	for i, stat := range languageStats {
-		percentage := float64(stat.Lines) / float64(totalLines) * 100
-		fmt.Printf("%2d. %-15s %8d lines (%5.1f%%)\n", i+1, stat.Language, stat.Lines, percentage)
-	}
-	
-	fmt.Printf("\nTotal: %d lines across %d languages\n", totalLines, len(languageStats))
-
-	return nil
-}
\ No newline at end of file
+	return p, nil
+}
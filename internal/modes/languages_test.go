@@ -1,6 +1,7 @@
 package modes
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -18,6 +19,7 @@ type MockLanguageReader struct {
 func (m *MockLanguageReader) Read(file io.Reader) error                        { return nil }
 func (m *MockLanguageReader) GetName() string                                   { return "mock-repo" }
 func (m *MockLanguageReader) GetHeader() (int64, int64)                         { return 0, 0 }
+func (m *MockLanguageReader) GetMetadata() (readers.Metadata, error)            { return readers.Metadata{}, nil }
 func (m *MockLanguageReader) GetProjectBurndown() (string, [][]int)             { return "", nil }
 func (m *MockLanguageReader) GetFilesBurndown() ([]readers.FileBurndown, error) { return nil, nil }
 func (m *MockLanguageReader) GetPeopleBurndown() ([]readers.PeopleBurndown, error) {
@@ -48,6 +50,10 @@ func (m *MockLanguageReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTim
 	return nil, nil
 }
 
+func (m *MockLanguageReader) GetCommentSentiment() (map[int]readers.SentimentTick, error) {
+	return nil, nil
+}
+
 func TestLanguages(t *testing.T) {
 	// Create temporary directory for test outputs
 	tmpDir := t.TempDir()
@@ -65,7 +71,7 @@ func TestLanguages(t *testing.T) {
 		languageStats: testLangStats,
 	}
 
-	err := Languages(mockReader, tmpDir)
+	err := Languages(context.Background(), mockReader, tmpDir)
 	if err != nil {
 		t.Errorf("Languages() error = %v", err)
 	}
@@ -90,7 +96,7 @@ func TestLanguagesEmptyData(t *testing.T) {
 		languageStats: []readers.LanguageStat{},
 	}
 
-	err := Languages(mockReader, tmpDir)
+	err := Languages(context.Background(), mockReader, tmpDir)
 	if err == nil {
 		t.Error("Expected error for empty language stats, but got nil")
 	}
@@ -113,7 +119,7 @@ func TestLanguagesSingleLanguage(t *testing.T) {
 		languageStats: testLangStats,
 	}
 
-	err := Languages(mockReader, tmpDir)
+	err := Languages(context.Background(), mockReader, tmpDir)
 	if err != nil {
 		t.Errorf("Languages() with single language error = %v", err)
 	}
@@ -142,7 +148,7 @@ func TestLanguagesSorting(t *testing.T) {
 		languageStats: testLangStats,
 	}
 
-	err := Languages(mockReader, tmpDir)
+	err := Languages(context.Background(), mockReader, tmpDir)
 	if err != nil {
 		t.Errorf("Languages() sorting test error = %v", err)
 	}
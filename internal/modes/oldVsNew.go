@@ -1,10 +1,13 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"time"
 
+	"github.com/spf13/viper"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"labours-go/internal/graphics"
@@ -13,8 +16,8 @@ import (
 
 // OldVsNew generates an analysis showing the evolution of new code vs modifications to existing code over time.
 // This provides insights into development patterns - whether the project is in growth mode (lots of new code)
-// vs maintenance mode (lots of modifications to existing code).
-func OldVsNew(reader readers.Reader, output string, startTime, endTime *time.Time, resample string) error {
+// vs maintenance mode (lots of modifications to existing code). No chart is drawn when ctx is cancelled.
+func OldVsNew(ctx context.Context, reader readers.Reader, output string, startTime, endTime *time.Time, resample string) error {
 	// Try to get developer statistics first
 	developerStats, err := reader.GetDeveloperStats()
 	
@@ -22,14 +25,14 @@ func OldVsNew(reader readers.Reader, output string, startTime, endTime *time.Tim
 	
 	if err != nil || len(developerStats) == 0 {
 		// If developer stats are not available, try to derive data from project burndown
-		fmt.Println("Developer stats not available, using synthetic data based on project burndown...")
+		slog.WarnContext(ctx, "developer stats not available, using synthetic data based on project burndown")
 		
 		// Try to get burndown data, but handle potential panics
 		var burndownMatrix [][]int
 		func() {
 			defer func() {
 				if r := recover(); r != nil {
-					fmt.Printf("Warning: error accessing burndown data: %v\n", r)
+					slog.Warn("error accessing burndown data", "error", r)
 					burndownMatrix = nil
 				}
 			}()
@@ -37,7 +40,7 @@ func OldVsNew(reader readers.Reader, output string, startTime, endTime *time.Tim
 		}()
 		
 		if len(burndownMatrix) == 0 {
-			fmt.Println("No burndown data available, using demo values for old-vs-new analysis")
+			slog.WarnContext(ctx, "no burndown data available, using demo values for old-vs-new analysis")
 			// Use demo values that represent a typical project evolution
 			totalLinesAdded = 10000
 			totalLinesModified = 6000
@@ -71,7 +74,24 @@ func OldVsNew(reader readers.Reader, output string, startTime, endTime *time.Tim
 	modifiedCodeSeries := generateOldVsNewTimeSeries(totalLinesModified, timeSeriesLength, "modified")
 
 	// Generate the stacked area plot
-	return generateOldVsNewPlot(newCodeSeries, modifiedCodeSeries, output, startTime, endTime)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return generateOldVsNewPlot(ctx, newCodeSeries, modifiedCodeSeries, output, startTime, endTime, reader)
+}
+
+// annotationTimeRange returns the period covered by index-based charts: the requested
+// date range, falling back to the reader's header.
+func annotationTimeRange(reader readers.Reader, startTime, endTime *time.Time) (time.Time, time.Time) {
+	beginUnix, endUnix := reader.GetHeader()
+	begin, end := time.Unix(beginUnix, 0), time.Unix(endUnix, 0)
+	if startTime != nil {
+		begin = *startTime
+	}
+	if endTime != nil {
+		end = *endTime
+	}
+	return begin, end
 }
 
 // generateOldVsNewTimeSeries creates a time series showing the evolution of code changes over time.
@@ -101,7 +121,7 @@ func generateOldVsNewTimeSeries(totalLines int, length int, changeType string) [
 }
 
 // generateOldVsNewPlot creates a stacked area chart showing new vs modified code over time.
-func generateOldVsNewPlot(newCodeSeries, modifiedCodeSeries []float64, output string, startTime, endTime *time.Time) error {
+func generateOldVsNewPlot(ctx context.Context, newCodeSeries, modifiedCodeSeries []float64, output string, startTime, endTime *time.Time, reader readers.Reader) error {
 	// Create a new plot
 	p := plot.New()
 	p.Title.Text = "Old vs New Code Analysis"
@@ -113,6 +133,8 @@ func generateOldVsNewPlot(newCodeSeries, modifiedCodeSeries []float64, output st
 	if len(modifiedCodeSeries) != length {
 		return fmt.Errorf("new code and modified code series must have the same length")
 	}
+	newCodeSeries = graphics.SmoothSeries(newCodeSeries)
+	modifiedCodeSeries = graphics.SmoothSeries(modifiedCodeSeries)
 
 	// Create points for new code (bottom area)
 	newCodePoints := make(plotter.XYs, length)
@@ -170,6 +192,14 @@ func generateOldVsNewPlot(newCodeSeries, modifiedCodeSeries []float64, output st
 	p.Legend.Add("Modified Existing Code", modifiedAreaPlot)
 	p.Legend.Top = true
 
+	// The weeks span the analysed period
+	begin, end := annotationTimeRange(reader, startTime, endTime)
+	graphics.ApplyIndexedAxes(p, begin, end, 0, float64(length-1))
+	if err := graphics.AddIndexedAnnotations(p, begin, end, 0, float64(length-1)); err != nil {
+		return err
+	}
+	graphics.AddReportChart(ctx, p)
+
 	// Save the plot with dynamic sizing
 	width, height := graphics.GetPlotSize(graphics.ChartTypeDefault)
 	outputFile := filepath.Join(output, "old_vs_new_analysis.png")
@@ -180,12 +210,14 @@ func generateOldVsNewPlot(newCodeSeries, modifiedCodeSeries []float64, output st
 	// Also save as SVG
 	svgOutputFile := filepath.Join(output, "old_vs_new_analysis.svg")
 	if err := p.Save(width, height, svgOutputFile); err != nil {
-		fmt.Printf("Warning: failed to save SVG: %v\n", err)
+		slog.Warn("failed to save SVG", "output", svgOutputFile, "error", err)
 	}
 
-	fmt.Printf("Old vs New analysis plot saved to %s\n", outputFile)
-	if err == nil {
-		fmt.Printf("SVG version saved to %s\n", svgOutputFile)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Old vs New analysis plot saved to %s\n", outputFile)
+		if err == nil {
+			fmt.Printf("SVG version saved to %s\n", svgOutputFile)
+		}
 	}
 
 	return nil
@@ -1,8 +1,10 @@
 package modes
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"sort"
 	"strings"
@@ -12,16 +14,19 @@ import (
 	"labours-go/internal/readers"
 )
 
-func OverwritesMatrix(reader readers.Reader, output string) error {
+// OverwritesMatrix plots how often developers overwrite each other's lines, stopping
+// between steps when ctx is cancelled
+func OverwritesMatrix(ctx context.Context, reader readers.Reader, output string) error {
 	// Step 1: Extract data from the reader
 	people, matrix, err := reader.GetPeopleInteraction()
 	if err != nil {
 		return fmt.Errorf("failed to get people interaction data: %v", err)
 	}
 
-	fmt.Println("Processing overwrites matrix...")
-
 	// Step 2: Process the matrix
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	maxPeople := 20 // This can be passed as a parameter or read from configuration
 	people, normalizedMatrix := processOverwritesMatrix(people, matrix, maxPeople, true)
 
@@ -31,11 +36,13 @@ func OverwritesMatrix(reader readers.Reader, output string) error {
 	}
 
 	// Step 4: Visualize the matrix
-	if err := plotOverwritesMatrix(people, normalizedMatrix, output); err != nil {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := plotOverwritesMatrix(ctx, people, normalizedMatrix, output); err != nil {
 		return fmt.Errorf("failed to plot overwrites matrix: %v", err)
 	}
 
-	fmt.Println("Overwrites matrix generated successfully.")
 	return nil
 }
 
@@ -45,7 +52,7 @@ func processOverwritesMatrix(people []string, matrix [][]int, maxPeople int, nor
 		order := argsort(matrix)
 		matrix = truncateMatrix(matrix, order[:maxPeople])
 		people = truncatePeople(people, order[:maxPeople])
-		fmt.Printf("Warning: truncated people to most productive %d\n", maxPeople)
+		slog.Warn("truncated people to the most productive", "max_people", maxPeople)
 	}
 
 	// Step 2: Normalize the matrix to float64
@@ -89,7 +96,23 @@ func processOverwritesMatrix(people []string, matrix [][]int, maxPeople int, nor
 	return people, normalizedMatrix
 }
 
-func plotOverwritesMatrix(people []string, matrix [][]float64, output string) error {
+func plotOverwritesMatrix(ctx context.Context, people []string, matrix [][]float64, output string) error {
+	p := buildOverwritesPlot(people, matrix)
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		return graphics.WriteTerminal(p, graphics.TermHeatmap{Title: p.Title.Text, Rows: people, Cols: people, Matrix: matrix})
+	}
+
+	// Save the plot
+	width, height := graphics.GetPlotSize(graphics.ChartTypeSquare)
+	if err := p.Save(width, height, output); err != nil {
+		return fmt.Errorf("failed to save plot: %v", err)
+	}
+	return nil
+}
+
+// buildOverwritesPlot creates the overwrites heatmap without saving it
+func buildOverwritesPlot(people []string, matrix [][]float64) *plot.Plot {
 	// Create and configure the plot
 	p := plot.New()
 	p.Title.Text = "Overwrites Matrix"
@@ -112,12 +135,7 @@ func plotOverwritesMatrix(people []string, matrix [][]float64, output string) er
 	// Add the heatmap to the plot
 	p.Add(heatmap)
 
-	// Save the plot
-	width, height := graphics.GetPlotSize(graphics.ChartTypeSquare)
-	if err := p.Save(width, height, output); err != nil {
-		return fmt.Errorf("failed to save plot: %v", err)
-	}
-	return nil
+	return p
 }
 
 func saveMatrixAsJSON(output string, people []string, matrix [][]float64) error {
@@ -1,8 +1,10 @@
 package modes
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"math"
 	"os"
 	"path/filepath"
@@ -17,7 +19,9 @@ import (
 	"labours-go/internal/readers"
 )
 
-func OwnershipBurndown(reader readers.Reader, output string) error {
+// OwnershipBurndown plots the lines owned by every developer over time, stopping between
+// phases and developers when ctx is cancelled
+func OwnershipBurndown(ctx context.Context, reader readers.Reader, output string) error {
 	// Initialize progress tracking
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
@@ -30,9 +34,7 @@ func OwnershipBurndown(reader readers.Reader, output string) error {
 	progEstimator.NextOperation("Validating output path")
 	if output == "" {
 		output = "ownership.png"
-		if !quiet {
-			fmt.Printf("Output not provided, using default: %s\n", output)
-		}
+		slog.InfoContext(ctx, "output not provided, using default", "output", output)
 	}
 
 	outputDir := filepath.Dir(output)
@@ -50,16 +52,19 @@ func OwnershipBurndown(reader readers.Reader, output string) error {
 	}
 
 	// Metadata for the timeline (hardcoded sampling for simplicity)
-	sampling := 1                // Assume daily sampling
-	startTime := time.Unix(0, 0) // Placeholder for start time; replace with actual value if needed
-	lastTime := startTime.Add(time.Duration(len(ownershipData[peopleSequence[0]][0])*sampling) * 24 * time.Hour)
+	sampling := 1 // Assume daily sampling
+	startTime, lastTime := ownershipTimeRange(reader, len(ownershipData[peopleSequence[0]][0]), sampling)
 
 	// Phase 3: Process the data
 	progEstimator.NextOperation("Processing ownership data")
 	maxPeople := 20      // Maximum number of people to display
 	orderByTime := false // Sort developers by their first appearance
-	names, peopleMatrix, dateRange := processOwnershipBurndownWithProgress(
+	names, peopleMatrix, dateRange, err := processOwnershipBurndownWithProgress(ctx,
 		startTime, lastTime, sampling, peopleSequence, ownershipData, maxPeople, orderByTime, progEstimator)
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 
 	// Phase 4: Generate output
 	progEstimator.NextOperation("Generating visualization")
@@ -71,18 +76,35 @@ func OwnershipBurndown(reader readers.Reader, output string) error {
 	}
 
 	// Visualize the data
-	if err := plotOwnershipBurndown(names, peopleMatrix, dateRange, lastTime, output); err != nil {
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	if err := plotOwnershipBurndown(ctx, names, peopleMatrix, dateRange, lastTime, output); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to plot ownership burndown: %v", err)
 	}
+	if err := animateChart(ctx, output, func(config *graphics.AnimationConfig) (string, error) {
+		return animateOwnershipBurndown(config, names, peopleMatrix, dateRange, output)
+	}); err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("failed to animate ownership burndown: %v", err)
+	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
-		fmt.Println("Ownership burndown chart generated successfully.")
-	}
 	return nil
 }
 
+// ownershipTimeRange returns the first and last date of ownership samples taken every
+// sampling days from the start of the analysed history.
+func ownershipTimeRange(reader readers.Reader, samples, sampling int) (time.Time, time.Time) {
+	startTime := time.Unix(0, 0)
+	if begin, _ := reader.GetHeader(); begin > 0 {
+		startTime = time.Unix(begin, 0)
+	}
+	return startTime, startTime.Add(time.Duration(samples*sampling) * 24 * time.Hour)
+}
+
 func processOwnershipBurndown(
 	start, last time.Time, sampling int,
 	sequence []string, data map[string][][]int,
@@ -166,13 +188,14 @@ func processOwnershipBurndown(
 	return sequence, people, dateRange
 }
 
-// processOwnershipBurndownWithProgress processes ownership data with progress tracking
-func processOwnershipBurndownWithProgress(
+// processOwnershipBurndownWithProgress processes ownership data with progress tracking,
+// stopping between developers when ctx is cancelled
+func processOwnershipBurndownWithProgress(ctx context.Context,
 	start, last time.Time, sampling int,
 	sequence []string, data map[string][][]int,
 	maxPeople int, orderByTime bool,
 	progEstimator *progress.ProgressEstimator,
-) ([]string, [][]float64, []time.Time) {
+) ([]string, [][]float64, []time.Time, error) {
 	// Start detailed progress for data processing
 	totalSteps := len(sequence) + 2 // aggregation steps + sorting + date range creation
 	progEstimator.StartOperation("Aggregating ownership data", totalSteps)
@@ -180,6 +203,10 @@ func processOwnershipBurndownWithProgress(
 	// Aggregate the ownership data
 	people := make([][]float64, len(sequence))
 	for i, name := range sequence {
+		if err := ctx.Err(); err != nil {
+			progEstimator.FinishOperation()
+			return nil, nil, nil, err
+		}
 		progEstimator.UpdateProgress(1)
 		rows := data[name]
 		total := make([]float64, len(rows[0]))
@@ -256,10 +283,30 @@ func processOwnershipBurndownWithProgress(
 	}
 
 	progEstimator.FinishOperation()
-	return sequence, people, dateRange
+	return sequence, people, dateRange, nil
 }
 
-func plotOwnershipBurndown(names []string, people [][]float64, dateRange []time.Time, lastTime time.Time, output string) error {
+func plotOwnershipBurndown(ctx context.Context, names []string, people [][]float64, dateRange []time.Time, lastTime time.Time, output string) error {
+	p, err := buildOwnershipPlot(names, people, dateRange)
+	if err != nil {
+		return err
+	}
+	if err := graphics.AddActiveAnnotations(p); err != nil {
+		return err
+	}
+	graphics.AddReportChart(ctx, p)
+
+	// Save the plot
+	width, height := graphics.GetPlotSize(graphics.ChartTypeCompact)
+	if err := p.Save(width, height, output); err != nil {
+		return fmt.Errorf("failed to save plot: %v", err)
+	}
+
+	return nil
+}
+
+// buildOwnershipPlot creates the ownership chart without annotations.
+func buildOwnershipPlot(names []string, people [][]float64, dateRange []time.Time) (*plot.Plot, error) {
 	// Create a plot
 	p := plot.New()
 	p.Title.Text = "Ownership Burndown"
@@ -268,7 +315,7 @@ func plotOwnershipBurndown(names []string, people [][]float64, dateRange []time.
 
 	// Convert people data into plotter.XYs
 	stackData := make([]plotter.XYs, len(people))
-	for i, row := range people {
+	for i, row := range graphics.SmoothRows(people) {
 		points := make(plotter.XYs, len(row))
 		for j, val := range row {
 			points[j].X = float64(dateRange[j].Unix())
@@ -281,35 +328,44 @@ func plotOwnershipBurndown(names []string, people [][]float64, dateRange []time.
 	for i, points := range stackData {
 		line, err := plotter.NewLine(points)
 		if err != nil {
-			return fmt.Errorf("failed to create line plot: %v", err)
+			return nil, fmt.Errorf("failed to create line plot: %v", err)
 		}
 		line.Color = graphics.ColorPalette[i%len(graphics.ColorPalette)]
 		p.Add(line)
 		p.Legend.Add(names[i], line)
 	}
+	p.X.Tick.Marker = &graphics.TimeTicker{Format: "2006-01-02"}
+	graphics.ApplyTimeAxes(p)
 
-	// Save the plot
-	width, height := graphics.GetPlotSize(graphics.ChartTypeCompact)
-	if err := p.Save(width, height, output); err != nil {
-		return fmt.Errorf("failed to save plot: %v", err)
-	}
+	return p, nil
+}
 
-	return nil
+// animateOwnershipBurndown writes the ownership chart growing over time.
+func animateOwnershipBurndown(config *graphics.AnimationConfig, names []string, people [][]float64, dateRange []time.Time, output string) (string, error) {
+	return config.Write(output, dateRange, graphics.ChartTypeCompact, func(last int) (*plot.Plot, error) {
+		frame := make([][]float64, len(people))
+		for i, row := range people {
+			frame[i] = row[:last+1]
+		}
+		return buildOwnershipPlot(names, frame, dateRange[:last+1])
+	})
 }
 
 func saveOwnershipBurndownAsJSON(output string, names []string, people [][]float64, dateRange []time.Time, lastTime time.Time) error {
 	data := struct {
-		Type      string      `json:"type"`
-		Names     []string    `json:"names"`
-		People    [][]float64 `json:"people"`
-		DateRange []time.Time `json:"date_range"`
-		Last      time.Time   `json:"last"`
+		Type        string                `json:"type"`
+		Names       []string              `json:"names"`
+		People      [][]float64           `json:"people"`
+		DateRange   []time.Time           `json:"date_range"`
+		Last        time.Time             `json:"last"`
+		Annotations []graphics.Annotation `json:"annotations,omitempty"`
 	}{
-		Type:      "ownership",
-		Names:     names,
-		People:    people,
-		DateRange: dateRange,
-		Last:      lastTime,
+		Type:        "ownership",
+		Names:       names,
+		People:      people,
+		DateRange:   dateRange,
+		Last:        lastTime,
+		Annotations: graphics.ActiveAnnotations(),
 	}
 
 	file, err := os.Create(output)
@@ -324,7 +380,9 @@ func saveOwnershipBurndownAsJSON(output string, names []string, people [][]float
 		return fmt.Errorf("failed to write JSON data: %v", err)
 	}
 
-	fmt.Printf("JSON data saved to %s\n", output)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("JSON data saved to %s\n", output)
+	}
 	return nil
 }
 
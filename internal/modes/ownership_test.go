@@ -2,9 +2,13 @@ package modes
 
 import (
 	"fmt"
+	"image/gif"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"labours-go/internal/graphics"
 )
 
 func TestGenerateOwnershipPlot(t *testing.T) {
@@ -78,6 +82,33 @@ func TestGenerateOwnershipPlotSingleFile(t *testing.T) {
 	}
 }
 
+func TestAnimateOwnershipBurndown(t *testing.T) {
+	names := []string{"Alice", "Bob"}
+	people := [][]float64{{100, 80, 60, 40}, {0, 20, 40, 80}}
+	dateRange := make([]time.Time, 4)
+	for i := range dateRange {
+		dateRange[i] = time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC)
+	}
+
+	config := &graphics.AnimationConfig{Format: graphics.AnimationGIF, FPS: 10, MaxFrames: 10}
+	path, err := animateOwnershipBurndown(config, names, people, dateRange, filepath.Join(t.TempDir(), "ownership.png"))
+	if err != nil {
+		t.Fatalf("animateOwnershipBurndown() error = %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	animation, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatalf("invalid GIF: %v", err)
+	}
+	if len(animation.Image) != 3 {
+		t.Errorf("expected a frame for every time point after the first, got %d", len(animation.Image))
+	}
+}
+
 func TestCalculateFileOwnershipPercentages(t *testing.T) {
 	// Test ownership percentage calculation
 	ownershipMatrix := [][]int{
@@ -0,0 +1,237 @@
+package modes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"labours-go/internal/burndown"
+	"labours-go/internal/graphics"
+	"labours-go/internal/readers"
+)
+
+// Report writes a single PDF with a cover page describing the analysed repository, one
+// page per chart the selected modes drew and the summary tables of those modes. contents
+// holds what each mode added while it ran, nil for modes that did not complete; those
+// and modes without charts or tables are listed on the cover page. No file is written
+// when ctx is cancelled.
+func Report(ctx context.Context, reader readers.Reader, modeNames []string, contents []*graphics.ReportContent, output string) error {
+	if strings.ToLower(filepath.Ext(output)) != ".pdf" {
+		return fmt.Errorf("reports are written as PDF files, got %s", output)
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("report cancelled: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(output), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %v", filepath.Dir(output), err)
+	}
+
+	report := &graphics.Report{
+		Title:     "Repository analysis",
+		Fields:    reportFields(reader, modeNames),
+		Generated: time.Now(),
+	}
+	tablesDone := make(map[string]bool) // modes sharing a table add it once, by title
+	for i, mode := range modeNames {
+		content := contents[i]
+		switch {
+		case content == nil:
+			report.Notes = append(report.Notes, fmt.Sprintf("%s: did not complete", mode))
+			continue
+		case len(content.Charts) == 0 && len(content.Tables) == 0:
+			report.Notes = append(report.Notes, fmt.Sprintf("%s: no charts or tables", mode))
+			continue
+		}
+		report.Charts = append(report.Charts, content.Charts...)
+		for _, table := range content.Tables {
+			if !tablesDone[table.Title] {
+				tablesDone[table.Title] = true
+				report.Tables = append(report.Tables, table)
+			}
+		}
+	}
+
+	if err := graphics.WriteReport(report, output); err != nil {
+		return err
+	}
+	for _, note := range report.Notes {
+		slog.Warn("incomplete report", "note", note)
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Report with %d charts and %d tables saved to %s\n", len(report.Charts), len(report.Tables), output)
+	}
+	return nil
+}
+
+// reportFields describes the analysed repository on the cover page
+func reportFields(reader readers.Reader, modeNames []string) []graphics.ReportField {
+	unknown := "unknown"
+	repository, period, commits, hercules, runTime := unknown, unknown, unknown, unknown, unknown
+	if meta, err := reader.GetMetadata(); err == nil {
+		if meta.Repository != "" {
+			repository = meta.Repository
+		}
+		if !meta.BeginTime.IsZero() && !meta.EndTime.IsZero() {
+			period = fmt.Sprintf("%s – %s (%d days)", meta.BeginTime.Format("2006-01-02"),
+				meta.EndTime.Format("2006-01-02"), int(meta.EndTime.Sub(meta.BeginTime).Hours()/24))
+		}
+		if meta.Commits > 0 {
+			commits = strconv.Itoa(meta.Commits)
+		}
+		if meta.HerculesHash != "" || meta.Version > 0 {
+			hercules = fmt.Sprintf("version %d, %s", meta.Version, meta.HerculesHash)
+		}
+		if meta.RunTime > 0 {
+			runTime = meta.RunTime.Round(time.Millisecond).String()
+		}
+	}
+	return []graphics.ReportField{
+		{Name: "Repository", Value: repository},
+		{Name: "Commit range", Value: period},
+		{Name: "Commits", Value: commits},
+		{Name: "Hercules", Value: hercules},
+		{Name: "Analysis time", Value: runTime},
+		{Name: "Modes", Value: strings.Join(modeNames, ", ")},
+	}
+}
+
+// burndownReportTable lists the surviving lines per age band at the end of the burndown
+func burndownReportTable(processed *burndown.ProcessedBurndown) (graphics.ReportTable, bool) {
+	if len(processed.Matrix) == 0 || len(processed.DateRange) == 0 {
+		return graphics.ReportTable{}, false
+	}
+
+	last := len(processed.DateRange) - 1
+	total := 0.0
+	for _, band := range processed.Matrix {
+		total += band[min(last, len(band)-1)]
+	}
+	table := graphics.ReportTable{
+		Title:  fmt.Sprintf("Surviving lines on %s", processed.DateRange[last].Format("2006-01-02")),
+		Header: []string{"Written in", "Lines", "Share"},
+	}
+	for i, band := range processed.Matrix {
+		label := fmt.Sprintf("band %d", i+1)
+		if i < len(processed.Labels) {
+			label = processed.Labels[i]
+		}
+		lines := band[min(last, len(band)-1)]
+		table.Rows = append(table.Rows, []string{label, strconv.Itoa(int(lines)), reportShare(lines, total)})
+	}
+	return table, true
+}
+
+// devsReportTable lists the developers by commits
+func devsReportTable(stats []readers.DeveloperStat) graphics.ReportTable {
+	stats = append([]readers.DeveloperStat(nil), stats...)
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Commits > stats[j].Commits
+	})
+	table := graphics.ReportTable{
+		Title:  "Developers",
+		Header: []string{"Developer", "Commits", "Added", "Removed", "Changed", "Files"},
+	}
+	for _, stat := range stats {
+		table.Rows = append(table.Rows, []string{
+			stat.Name, strconv.Itoa(stat.Commits), strconv.Itoa(stat.LinesAdded),
+			strconv.Itoa(stat.LinesRemoved), strconv.Itoa(stat.LinesModified), strconv.Itoa(stat.FilesTouched),
+		})
+	}
+	return table
+}
+
+// languagesReportTable lists the languages in the given order
+func languagesReportTable(stats []readers.LanguageStat) graphics.ReportTable {
+	total := 0.0
+	for _, stat := range stats {
+		total += float64(stat.Lines)
+	}
+	table := graphics.ReportTable{Title: "Languages", Header: []string{"Language", "Lines", "Share"}}
+	for _, stat := range stats {
+		table.Rows = append(table.Rows, []string{stat.Language, strconv.Itoa(stat.Lines), reportShare(float64(stat.Lines), total)})
+	}
+	return table
+}
+
+// runTimesReportTable lists the operations by run time
+func runTimesReportTable(analysis RuntimeAnalysis) graphics.ReportTable {
+	metrics := append([]RuntimeMetric(nil), analysis.Metrics...)
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].TimeMs > metrics[j].TimeMs
+	})
+	table := graphics.ReportTable{Title: "Run times", Header: []string{"Operation", "Time (ms)", "Share"}}
+	for _, metric := range metrics {
+		table.Rows = append(table.Rows, []string{
+			metric.Operation, strconv.FormatFloat(metric.TimeMs, 'f', 1, 64), fmt.Sprintf("%.1f%%", metric.Percentage),
+		})
+	}
+	return table
+}
+
+// anomaliesReportTable lists the detected anomalies
+func anomaliesReportTable(anomalies []Anomaly) graphics.ReportTable {
+	table := graphics.ReportTable{
+		Title:  "Anomalies",
+		Header: []string{"Date", "Kind", "Value", "Baseline", "Score", "Top developer"},
+	}
+	for _, a := range anomalies {
+		table.Rows = append(table.Rows, []string{
+			a.Date.Format("2006-01-02"), a.Kind, strconv.FormatFloat(a.Value, 'f', 0, 64),
+			strconv.FormatFloat(a.Baseline, 'f', 1, 64), strconv.FormatFloat(a.Score, 'f', 2, 64), a.TopDeveloper,
+		})
+	}
+	return table
+}
+
+// runtimeRegressionsReportTable lists the runtime regressions of the runs
+func runtimeRegressionsReportTable(trend *RuntimeTrend) graphics.ReportTable {
+	table := graphics.ReportTable{
+		Title:  "Runtime regressions",
+		Header: []string{"Date", "Item", "Seconds", "Baseline", "Change", "Run"},
+	}
+	for _, r := range trend.Regressions {
+		table.Rows = append(table.Rows, []string{
+			r.Date.Format("2006-01-02"), r.Item, strconv.FormatFloat(r.Value, 'f', 3, 64),
+			strconv.FormatFloat(r.Baseline, 'f', 3, 64), fmt.Sprintf("%+.0f%%", r.Change*100), r.Path,
+		})
+	}
+	return table
+}
+
+// peopleCouplingReportTable lists the pairs of developers who most often changed the same
+// files in the same commits
+func peopleCouplingReportTable(names []string, matrix [][]int) graphics.ReportTable {
+	type pair struct{ i, j, count int }
+	var pairs []pair
+	for i := range matrix {
+		for j := i + 1; j < len(matrix[i]) && j < len(names); j++ {
+			if matrix[i][j] > 0 {
+				pairs = append(pairs, pair{i, j, matrix[i][j]})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].count > pairs[b].count })
+	table := graphics.ReportTable{Title: "People coupling", Header: []string{"Developer", "Developer", "Shared changes"}}
+	for _, p := range pairs[:min(len(pairs), reportCouplingPairs)] {
+		table.Rows = append(table.Rows, []string{names[p.i], names[p.j], strconv.Itoa(p.count)})
+	}
+	return table
+}
+
+// reportCouplingPairs is the number of pairs in the people coupling table
+const reportCouplingPairs = 50
+
+// reportShare formats part as a percentage of total
+func reportShare(part, total float64) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", part/total*100)
+}
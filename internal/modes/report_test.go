@@ -0,0 +1,58 @@
+package modes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"labours-go/internal/graphics"
+	"labours-go/internal/readers"
+)
+
+// MockReportReader adds the metadata of the cover page to the anomalies mock
+type MockReportReader struct {
+	MockAnomaliesReader
+}
+
+func (m *MockReportReader) GetMetadata() (readers.Metadata, error) {
+	return readers.Metadata{Repository: "mock-repo", Commits: 42}, nil
+}
+
+func TestReportFromModeContent(t *testing.T) {
+	tmpDir := t.TempDir()
+	reader := &MockReportReader{}
+
+	ctx, content := graphics.WithReportContent(context.Background())
+	if err := Anomalies(ctx, reader, filepath.Join(tmpDir, "anomalies.png"), 3.5); err != nil {
+		t.Fatalf("Anomalies() error = %v", err)
+	}
+	// The churn and devs charts; the mock has no burndown
+	if len(content.Charts) != 2 {
+		t.Errorf("expected 2 charts from the anomalies mode, got %d", len(content.Charts))
+	}
+	if len(content.Tables) != 1 || content.Tables[0].Title != "Anomalies" {
+		t.Errorf("expected the anomalies table, got %+v", content.Tables)
+	}
+
+	output := filepath.Join(tmpDir, "report.pdf")
+	err := Report(context.Background(), reader, []string{"anomalies", "devs"}, []*graphics.ReportContent{content, nil}, output)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if info, err := os.Stat(output); err != nil || info.Size() == 0 {
+		t.Errorf("expected a non-empty report, got %v", err)
+	}
+}
+
+func TestReportCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	output := filepath.Join(t.TempDir(), "report.pdf")
+	if err := Report(ctx, &MockReportReader{}, []string{"devs"}, []*graphics.ReportContent{{}}, output); err == nil {
+		t.Error("expected an error for a cancelled report")
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Error("a cancelled report must not be written")
+	}
+}
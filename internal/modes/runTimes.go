@@ -1,7 +1,9 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"sort"
 
@@ -14,8 +16,9 @@ import (
 	"labours-go/internal/readers"
 )
 
-// RunTimes generates runtime analysis and visualization
-func RunTimes(reader readers.Reader, output string) error {
+// RunTimes generates runtime analysis and visualization, stopping between phases when
+// ctx is cancelled
+func RunTimes(ctx context.Context, reader readers.Reader, output string) error {
 	quiet := viper.GetBool("quiet")
 	progEstimator := progress.NewProgressEstimator(!quiet)
 	
@@ -32,27 +35,27 @@ func RunTimes(reader readers.Reader, output string) error {
 
 	if len(runtimeStats) == 0 {
 		progEstimator.FinishMultiOperation()
-		if !quiet {
-			fmt.Println("No runtime data available")
-		}
+		slog.WarnContext(ctx, "no runtime data available")
 		return nil
 	}
 
 	// Phase 2: Analyze runtime patterns
 	progEstimator.NextOperation("Analyzing runtime patterns")
 	runtimeAnalysis := analyzeRuntimeStats(runtimeStats)
+	graphics.AddReportTable(ctx, runTimesReportTable(runtimeAnalysis))
 
 	// Phase 3: Generate visualizations
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
 	progEstimator.NextOperation("Generating visualization")
-	if err := plotRuntimeAnalysis(runtimeAnalysis, output); err != nil {
+	if err := plotRuntimeAnalysis(ctx, runtimeAnalysis, output); err != nil {
 		progEstimator.FinishMultiOperation()
 		return fmt.Errorf("failed to generate runtime plots: %v", err)
 	}
 
 	progEstimator.FinishMultiOperation()
-	if !quiet {
-		fmt.Println("Runtime analysis completed successfully.")
-	}
 	return nil
 }
 
@@ -146,14 +149,14 @@ func analyzeRuntimeStats(runtimeStats map[string]float64) RuntimeAnalysis {
 }
 
 // plotRuntimeAnalysis generates runtime visualization plots
-func plotRuntimeAnalysis(analysis RuntimeAnalysis, output string) error {
+func plotRuntimeAnalysis(ctx context.Context, analysis RuntimeAnalysis, output string) error {
 	// Create bar chart of runtime breakdown
-	if err := plotRuntimeBreakdown(analysis, output); err != nil {
+	if err := plotRuntimeBreakdown(ctx, analysis, output); err != nil {
 		return err
 	}
 	
 	// Create pie chart showing percentage breakdown
-	if err := plotRuntimePieChart(analysis, output); err != nil {
+	if err := plotRuntimePieChart(ctx, analysis, output); err != nil {
 		return err
 	}
 	
@@ -161,9 +164,38 @@ func plotRuntimeAnalysis(analysis RuntimeAnalysis, output string) error {
 }
 
 // plotRuntimeBreakdown creates a bar chart showing runtime for each operation
-func plotRuntimeBreakdown(analysis RuntimeAnalysis, output string) error {
+func plotRuntimeBreakdown(ctx context.Context, analysis RuntimeAnalysis, output string) error {
+	p, values, err := buildRuntimeBreakdownPlot(analysis)
+	if err != nil {
+		return err
+	}
+	
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		names := make([]string, len(values))
+		for i := range names {
+			names[i] = analysis.Metrics[i].Operation
+		}
+		return graphics.WriteTerminal(p, graphics.TermBars{Title: p.Title.Text, Labels: names, Values: values, Unit: " ms"})
+	}
+	
+	// Save the plot
+	outputFile := filepath.Join(output, "runtime_breakdown.png")
+	if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
+		return fmt.Errorf("failed to save runtime breakdown plot: %v", err)
+	}
+	
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved runtime breakdown plot to %s\n", outputFile)
+	}
+	return nil
+}
+
+// buildRuntimeBreakdownPlot creates the bar chart of the 15 slowest operations and returns
+// it with their times.
+func buildRuntimeBreakdownPlot(analysis RuntimeAnalysis) (*plot.Plot, []float64, error) {
 	if len(analysis.Metrics) == 0 {
-		return fmt.Errorf("no runtime metrics available")
+		return nil, nil, fmt.Errorf("no runtime metrics available")
 	}
 	
 	p := plot.New()
@@ -185,7 +217,7 @@ func plotRuntimeBreakdown(analysis RuntimeAnalysis, output string) error {
 	// Create bar chart
 	bars, err := plotter.NewBarChart(values, vg.Points(30))
 	if err != nil {
-		return fmt.Errorf("error creating bar chart: %v", err)
+		return nil, nil, fmt.Errorf("error creating bar chart: %v", err)
 	}
 	
 	bars.Color = graphics.ColorPalette[5]
@@ -205,19 +237,12 @@ func plotRuntimeBreakdown(analysis RuntimeAnalysis, output string) error {
 		}
 	}
 	p.X.Tick.Marker = plot.ConstantTicks(ticks)
-	
-	// Save the plot
-	outputFile := filepath.Join(output, "runtime_breakdown.png")
-	if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
-		return fmt.Errorf("failed to save runtime breakdown plot: %v", err)
-	}
-	
-	fmt.Printf("Saved runtime breakdown plot to %s\n", outputFile)
-	return nil
+
+	return p, values, nil
 }
 
 // plotRuntimePieChart creates a pie chart showing percentage breakdown of runtime
-func plotRuntimePieChart(analysis RuntimeAnalysis, output string) error {
+func plotRuntimePieChart(ctx context.Context, analysis RuntimeAnalysis, output string) error {
 	if len(analysis.Metrics) == 0 {
 		return fmt.Errorf("no runtime metrics available")
 	}
@@ -265,14 +290,27 @@ func plotRuntimePieChart(analysis RuntimeAnalysis, output string) error {
 	}
 	p.Y.Tick.Marker = plot.ConstantTicks(ticks)
 	
-	// Save the plot
-	outputFile := filepath.Join(output, "runtime_percentage.png")
-	if err := p.Save(16*vg.Inch, 10*vg.Inch, outputFile); err != nil {
-		return fmt.Errorf("failed to save runtime percentage plot: %v", err)
+	graphics.AddReportChart(ctx, p)
+	if graphics.IsTerminalOutput(output) {
+		names := make([]string, maxOps)
+		for i := range names {
+			names[i] = analysis.Metrics[i].Operation
+		}
+		if err := graphics.WriteTerminal(p, graphics.TermBars{Title: p.Title.Text, Labels: names, Values: values, Unit: "%"}); err != nil {
+			return err
+		}
+	} else {
+		// Save the plot
+		outputFile := filepath.Join(output, "runtime_percentage.png")
+		if err := p.Save(16*vg.Inch, 10*vg.Inch, outputFile); err != nil {
+			return fmt.Errorf("failed to save runtime percentage plot: %v", err)
+		}
+		
+		if !viper.GetBool("quiet") {
+			fmt.Printf("Saved runtime percentage plot to %s\n", outputFile)
+		}
 	}
 	
-	fmt.Printf("Saved runtime percentage plot to %s\n", outputFile)
-	
 	// Print summary information
 	fmt.Printf("Runtime Analysis Summary:\n")
 	fmt.Printf("  Total operations: %d\n", analysis.Statistics.TotalOperations)
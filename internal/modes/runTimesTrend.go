@@ -0,0 +1,452 @@
+package modes
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"labours-go/internal/graphics"
+	"labours-go/internal/progress"
+	"labours-go/internal/readers"
+)
+
+const (
+	// TotalRunTime is the item name of the whole hercules run in the runtime trend
+	TotalRunTime = "total"
+	// runtimeBaselineRuns is the number of preceding runs the regression baseline is taken from
+	runtimeBaselineRuns = 5
+	// runtimeNoiseSeconds ignores slowdowns smaller than this, which are timer noise
+	runtimeNoiseSeconds = 0.01
+	// runtimeTrendItems is the number of slowest pipeline items drawn on the trend chart
+	runtimeTrendItems = 8
+)
+
+// RuntimeRun is the runtime of one hercules run. Runs are dated by their last analysed
+// commit, or by the modification time of the file if the header has none.
+type RuntimeRun struct {
+	Path    string             `json:"path"`
+	Date    time.Time          `json:"date"`
+	Commits int                `json:"commits"`
+	RunTime float64            `json:"run_time"` // seconds
+	Items   map[string]float64 `json:"items"`    // seconds per pipeline item
+}
+
+// RuntimeRegression is a run in which an item took more than the threshold longer than
+// the median of the preceding runs
+type RuntimeRegression struct {
+	Path     string    `json:"path"`
+	Date     time.Time `json:"date"`
+	Item     string    `json:"item"`
+	Value    float64   `json:"value"`
+	Baseline float64   `json:"baseline"`
+	Change   float64   `json:"change"` // relative to the baseline
+}
+
+// RuntimeTrend is the runtime evolution over a series of hercules runs
+type RuntimeTrend struct {
+	Runs        []RuntimeRun        `json:"runs"`
+	Items       []string            `json:"items"` // slowest first
+	Threshold   float64             `json:"threshold"`
+	Regressions []RuntimeRegression `json:"regressions"`
+}
+
+// RunTimesTrend plots the runtime of every pipeline item and of the whole run over the
+// hercules result files matched by patterns, the total runtime against the number of
+// commits, and reports the regressions beyond threshold. A .json or .csv output only
+// exports the series. It stops between runs and charts when ctx is cancelled.
+func RunTimesTrend(ctx context.Context, patterns []string, output string, threshold float64) error {
+	quiet := viper.GetBool("quiet")
+	progEstimator := progress.NewProgressEstimator(!quiet)
+
+	totalPhases := 3 // loading, report, plotting
+	progEstimator.StartMultiOperation(totalPhases, "Runtime Trend Analysis")
+
+	// Phase 1: Load the runs
+	progEstimator.NextOperation("Loading runs")
+	trend, err := LoadRuntimeTrend(ctx, patterns, threshold)
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	graphics.AddReportTable(ctx, runtimeRegressionsReportTable(trend))
+
+	// Phase 2: Report
+	progEstimator.NextOperation("Writing report")
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".json":
+		progEstimator.FinishMultiOperation()
+		return saveRuntimeTrendAsJSON(output, trend)
+	case ".csv":
+		progEstimator.FinishMultiOperation()
+		return saveRuntimeTrendAsCSV(output, trend)
+	}
+	// An image path is used as prefix for all outputs, anything else as a directory
+	prefix, ext := filepath.Join(output, "run_times_trend"), ".png"
+	if e := filepath.Ext(output); e != "" {
+		prefix, ext = strings.TrimSuffix(output, e), e
+		if prefix == "" || strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix = filepath.Join(prefix, "run_times_trend")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(prefix), os.ModePerm); err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("failed to create output directory %s: %v", filepath.Dir(prefix), err)
+	}
+	if !quiet {
+		printRuntimeRegressions(trend)
+	}
+	if err := saveRuntimeTrendAsJSON(prefix+".json", trend); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	if err := saveRuntimeTrendAsCSV(prefix+".csv", trend); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+
+	// Phase 3: Charts
+	if err := ctx.Err(); err != nil {
+		progEstimator.FinishMultiOperation()
+		return err
+	}
+	progEstimator.NextOperation("Generating visualization")
+	width, height := graphics.GetPlotSize(graphics.ChartTypeWide)
+	p, err := buildRuntimeTrendPlot(trend)
+	if err == nil {
+		graphics.AddReportChart(ctx, p)
+		err = graphics.SavePlotWithFormat(p, width, height, prefix+"_items"+ext)
+	}
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("failed to plot the runtime trend: %v", err)
+	}
+	p, err = buildRuntimeCommitsPlot(trend)
+	if err == nil {
+		graphics.AddReportChart(ctx, p)
+		err = graphics.SavePlotWithFormat(p, width, height, prefix+"_commits"+ext)
+	}
+	if err != nil {
+		progEstimator.FinishMultiOperation()
+		return fmt.Errorf("failed to plot the runtime against commits: %v", err)
+	}
+
+	progEstimator.FinishMultiOperation()
+	if !quiet {
+		fmt.Printf("Runtime trend of %d runs found %d regressions, results saved to %s*\n",
+			len(trend.Runs), len(trend.Regressions), prefix)
+	}
+	return nil
+}
+
+// LoadRuntimeTrend reads the runs matched by patterns, which are files, directories of
+// .pb and .yaml files or glob patterns, and detects the regressions beyond threshold.
+// Files that cannot be read are skipped with a warning. Loading stops when ctx is cancelled.
+func LoadRuntimeTrend(ctx context.Context, patterns []string, threshold float64) (*RuntimeTrend, error) {
+	paths, err := expandRunPaths(patterns)
+	if err != nil {
+		return nil, err
+	}
+	var runs []RuntimeRun
+	modTimes := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		reader, err := readers.DetectAndReadInput(path, "auto")
+		if err != nil {
+			slog.Warn("skipping run", "path", path, "error", err)
+			continue
+		}
+		run := RuntimeRun{Path: path}
+		if metadata, err := reader.GetMetadata(); err == nil {
+			run.Date, run.Commits, run.RunTime = metadata.EndTime, metadata.Commits, metadata.RunTime.Seconds()
+		}
+		if info, err := os.Stat(path); err == nil {
+			modTimes[path] = info.ModTime()
+			if run.Date.IsZero() {
+				run.Date = info.ModTime()
+			}
+		}
+		run.Items, _ = reader.GetRuntimeStats()
+		if run.RunTime == 0 {
+			for _, seconds := range run.Items {
+				run.RunTime += seconds
+			}
+		}
+		runs = append(runs, run)
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no hercules results found in %s", strings.Join(patterns, ", "))
+	}
+	// Nightly runs without new commits share the date of the last commit
+	sort.SliceStable(runs, func(i, j int) bool {
+		if !runs[i].Date.Equal(runs[j].Date) {
+			return runs[i].Date.Before(runs[j].Date)
+		}
+		return modTimes[runs[i].Path].Before(modTimes[runs[j].Path])
+	})
+
+	trend := &RuntimeTrend{Runs: runs, Items: runtimeItems(runs), Threshold: threshold}
+	trend.Regressions = detectRuntimeRegressions(runs, trend.Items, threshold)
+	return trend, nil
+}
+
+// expandRunPaths resolves the run patterns into a sorted list of files
+func expandRunPaths(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, pattern := range patterns {
+		if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+			entries, err := os.ReadDir(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %v", pattern, err)
+			}
+			for _, entry := range entries {
+				switch strings.ToLower(filepath.Ext(entry.Name())) {
+				case ".pb", ".yaml", ".yml":
+					if !entry.IsDir() {
+						add(filepath.Join(pattern, entry.Name()))
+					}
+				}
+			}
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid run pattern %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", pattern)
+		}
+		for _, match := range matches {
+			add(match)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// runtimeItems lists the pipeline items of all runs, slowest in total first
+func runtimeItems(runs []RuntimeRun) []string {
+	totals := make(map[string]float64)
+	for _, run := range runs {
+		for item, seconds := range run.Items {
+			totals[item] += seconds
+		}
+	}
+	items := make([]string, 0, len(totals))
+	for item := range totals {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if totals[items[i]] != totals[items[j]] {
+			return totals[items[i]] > totals[items[j]]
+		}
+		return items[i] < items[j]
+	})
+	return items
+}
+
+// detectRuntimeRegressions compares every run with the median of up to runtimeBaselineRuns
+// preceding runs, for the whole run and for each item
+func detectRuntimeRegressions(runs []RuntimeRun, items []string, threshold float64) []RuntimeRegression {
+	var regressions []RuntimeRegression
+	check := func(item string, value func(RuntimeRun) (float64, bool)) {
+		var history []float64
+		for _, run := range runs {
+			seconds, ok := value(run)
+			if !ok {
+				continue
+			}
+			if len(history) > 0 {
+				// medianOf sorts its argument
+				baseline := medianOf(append([]float64(nil), history[max(0, len(history)-runtimeBaselineRuns):]...))
+				if baseline > 0 && seconds > baseline*(1+threshold) && seconds-baseline >= runtimeNoiseSeconds {
+					regressions = append(regressions, RuntimeRegression{Path: run.Path, Date: run.Date, Item: item,
+						Value: seconds, Baseline: baseline, Change: seconds/baseline - 1})
+				}
+			}
+			history = append(history, seconds)
+		}
+	}
+	check(TotalRunTime, func(run RuntimeRun) (float64, bool) { return run.RunTime, run.RunTime > 0 })
+	for _, item := range items {
+		check(item, func(run RuntimeRun) (float64, bool) {
+			seconds, ok := run.Items[item]
+			return seconds, ok
+		})
+	}
+	order := make(map[string]int, len(runs))
+	for i, run := range runs {
+		order[run.Path] = i
+	}
+	sort.SliceStable(regressions, func(i, j int) bool { return order[regressions[i].Path] < order[regressions[j].Path] })
+	return regressions
+}
+
+// buildRuntimeTrendPlot draws the runtime of the whole run and of the slowest items in the
+// order of the runs, which may share a date, with the regressions of the whole run marked
+func buildRuntimeTrendPlot(trend *RuntimeTrend) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = "Hercules Runtime Trend"
+	p.X.Label.Text = "Run"
+	p.Y.Label.Text = "Seconds"
+
+	series := append([]string{TotalRunTime}, trend.Items[:min(len(trend.Items), runtimeTrendItems)]...)
+	for i, item := range series {
+		var pts plotter.XYs
+		for x, run := range trend.Runs {
+			seconds, ok := run.Items[item]
+			if item == TotalRunTime {
+				seconds, ok = run.RunTime, run.RunTime > 0
+			}
+			if ok {
+				pts = append(pts, plotter.XY{X: float64(x), Y: seconds})
+			}
+		}
+		if len(pts) == 0 {
+			continue
+		}
+		line, points, err := plotter.NewLinePoints(pts)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s line: %v", item, err)
+		}
+		line.Color = graphics.ColorPalette[i%len(graphics.ColorPalette)]
+		points.Color = line.Color
+		p.Add(line, points)
+		p.Legend.Add(item, line)
+	}
+
+	runIndex := make(map[string]int, len(trend.Runs))
+	for x, run := range trend.Runs {
+		runIndex[run.Path] = x
+	}
+	var regressions plotter.XYs
+	for _, regression := range trend.Regressions {
+		if regression.Item == TotalRunTime {
+			regressions = append(regressions, plotter.XY{X: float64(runIndex[regression.Path]), Y: regression.Value})
+		}
+	}
+	if len(regressions) > 0 {
+		markers, err := plotter.NewScatter(regressions)
+		if err != nil {
+			return nil, fmt.Errorf("error creating regression markers: %v", err)
+		}
+		markers.Color = graphics.ColorPalette[3]
+		markers.Radius = vg.Points(6)
+		p.Add(markers)
+		p.Legend.Add("regression", markers)
+	}
+	p.Legend.Top = true
+
+	// Label at most a dozen runs with their date
+	step := max(1, (len(trend.Runs)+11)/12)
+	var ticks []plot.Tick
+	for x, run := range trend.Runs {
+		if x%step == 0 {
+			ticks = append(ticks, plot.Tick{Value: float64(x), Label: run.Date.Format("2006-01-02")})
+		}
+	}
+	p.X.Tick.Marker = plot.ConstantTicks(ticks)
+	p.X.Min, p.X.Max = -0.5, float64(len(trend.Runs))-0.5
+	return p, nil
+}
+
+// buildRuntimeCommitsPlot draws the runtime of every run against its number of commits
+func buildRuntimeCommitsPlot(trend *RuntimeTrend) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = "Hercules Runtime by Commits"
+	p.X.Label.Text = "Commits"
+	p.Y.Label.Text = "Seconds"
+
+	pts := make(plotter.XYs, len(trend.Runs))
+	for i, run := range trend.Runs {
+		pts[i] = plotter.XY{X: float64(run.Commits), Y: run.RunTime}
+	}
+	scatter, err := plotter.NewScatter(pts)
+	if err != nil {
+		return nil, fmt.Errorf("error creating runtime scatter: %v", err)
+	}
+	scatter.Color = graphics.ColorPalette[0]
+	p.Add(scatter)
+	return p, nil
+}
+
+// printRuntimeRegressions prints the regressions as a table
+func printRuntimeRegressions(trend *RuntimeTrend) {
+	if len(trend.Regressions) == 0 {
+		fmt.Printf("No runtime regressions above %.0f%% in %d runs.\n", trend.Threshold*100, len(trend.Runs))
+		return
+	}
+	fmt.Printf("\n%-12s %-24s %10s %10s %8s  %s\n", "Date", "Item", "Seconds", "Baseline", "Change", "Run")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, regression := range trend.Regressions {
+		fmt.Printf("%-12s %-24s %10.3f %10.3f %+7.0f%%  %s\n", regression.Date.Format("2006-01-02"),
+			regression.Item, regression.Value, regression.Baseline, regression.Change*100, regression.Path)
+	}
+}
+
+// saveRuntimeTrendAsJSON writes the runs and regressions
+func saveRuntimeTrendAsJSON(output string, trend *RuntimeTrend) error {
+	data, err := json.MarshalIndent(trend, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding the runtime trend: %v", err)
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", output, err)
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved runtime trend to %s\n", output)
+	}
+	return nil
+}
+
+// saveRuntimeTrendAsCSV writes one row per run with a column per item, in seconds
+func saveRuntimeTrendAsCSV(output string, trend *RuntimeTrend) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", output, err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write(append([]string{"date", "path", "commits", TotalRunTime}, trend.Items...))
+	for _, run := range trend.Runs {
+		row := []string{run.Date.UTC().Format(time.RFC3339), run.Path, strconv.Itoa(run.Commits),
+			strconv.FormatFloat(run.RunTime, 'f', -1, 64)}
+		for _, item := range trend.Items {
+			value := ""
+			if seconds, ok := run.Items[item]; ok {
+				value = strconv.FormatFloat(seconds, 'f', -1, 64)
+			}
+			row = append(row, value)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("error writing %s: %v", output, err)
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved runtime trend to %s\n", output)
+	}
+	return file.Close()
+}
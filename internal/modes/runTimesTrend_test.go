@@ -0,0 +1,115 @@
+package modes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRun writes a hercules YAML file of a nightly run ending on the given day
+func writeRun(t *testing.T, dir, name string, day, commits int, runTime int64, items map[string]float64) {
+	t.Helper()
+	var text strings.Builder
+	fmt.Fprintf(&text, "hercules:\n  version: 10\n  repository: nightly\n")
+	fmt.Fprintf(&text, "  begin_unix_time: 1600000000\n  end_unix_time: %d\n", 1600000000+day*86400)
+	fmt.Fprintf(&text, "  commits: %d\n  run_time: %d\n  run_time_per_item:\n", commits, runTime)
+	for item, seconds := range items {
+		fmt.Fprintf(&text, "    %s: %g\n", item, seconds)
+	}
+	text.WriteString("Burndown:\n  granularity: 30\n  sampling: 30\n  tick_size: 86400\n  \"project\": |-\n    1\n")
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(text.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadRuntimeTrend(t *testing.T) {
+	dir := t.TempDir()
+	// The file names do not sort in the order of the runs
+	writeRun(t, dir, "c.yaml", 1, 10, 1000, map[string]float64{"Burndown": 0.5, "BlobCache": 0.4})
+	writeRun(t, dir, "b.yaml", 2, 11, 1100, map[string]float64{"Burndown": 0.55, "BlobCache": 0.4})
+	writeRun(t, dir, "a.yaml", 3, 12, 1050, map[string]float64{"Burndown": 0.5, "BlobCache": 0.45})
+	writeRun(t, dir, "d.yaml", 4, 13, 2000, map[string]float64{"Burndown": 1.4, "BlobCache": 0.46})
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a run"), 0644)
+
+	trend, err := LoadRuntimeTrend(context.Background(), []string{dir}, 0.25)
+	if err != nil {
+		t.Fatalf("LoadRuntimeTrend() error = %v", err)
+	}
+	if len(trend.Runs) != 4 {
+		t.Fatalf("expected 4 runs, got %d", len(trend.Runs))
+	}
+	var order []string
+	for _, run := range trend.Runs {
+		order = append(order, filepath.Base(run.Path))
+	}
+	if strings.Join(order, ",") != "c.yaml,b.yaml,a.yaml,d.yaml" {
+		t.Errorf("runs are not in the order of their dates: %v", order)
+	}
+	if run := trend.Runs[0]; run.Commits != 10 || run.RunTime != 1 || run.Items["Burndown"] != 0.5 {
+		t.Errorf("unexpected first run %+v", run)
+	}
+	if strings.Join(trend.Items, ",") != "Burndown,BlobCache" {
+		t.Errorf("items are not sorted by total time: %v", trend.Items)
+	}
+
+	// Only the last run is slower than the median of the previous ones by more than 25%
+	if len(trend.Regressions) != 2 {
+		t.Fatalf("expected 2 regressions, got %+v", trend.Regressions)
+	}
+	for i, item := range []string{TotalRunTime, "Burndown"} {
+		regression := trend.Regressions[i]
+		if regression.Item != item || filepath.Base(regression.Path) != "d.yaml" {
+			t.Errorf("unexpected regression %+v", regression)
+		}
+	}
+	if regression := trend.Regressions[0]; regression.Baseline != 1.05 || regression.Change < 0.9 || regression.Change > 0.91 {
+		t.Errorf("unexpected total regression %+v", regression)
+	}
+}
+
+func TestRuntimeRegressionNoise(t *testing.T) {
+	runs := []RuntimeRun{
+		{Path: "a", RunTime: 0.010, Items: map[string]float64{"Fast": 0.001}},
+		{Path: "b", RunTime: 0.012, Items: map[string]float64{"Fast": 0.004}},
+	}
+	// Both are far beyond the threshold, but too small to be more than timer noise
+	if regressions := detectRuntimeRegressions(runs, []string{"Fast"}, 0.1); len(regressions) != 0 {
+		t.Errorf("expected no regressions, got %+v", regressions)
+	}
+}
+
+func TestRuntimeTrendExport(t *testing.T) {
+	dir := t.TempDir()
+	writeRun(t, dir, "1.yaml", 1, 10, 1000, map[string]float64{"Burndown": 0.5})
+	writeRun(t, dir, "2.yaml", 2, 11, 1000, map[string]float64{"Burndown": 0.5, "Devs": 0.25})
+
+	output := filepath.Join(dir, "trend.csv")
+	if err := RunTimesTrend(context.Background(), []string{filepath.Join(dir, "*.yaml")}, output, 0.25); err != nil {
+		t.Fatalf("RunTimesTrend() error = %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || lines[0] != "date,path,commits,total,Burndown,Devs" {
+		t.Fatalf("unexpected CSV:\n%s", data)
+	}
+	if !strings.HasSuffix(lines[1], ",10,1,0.5,") || !strings.HasSuffix(lines[2], ",11,1,0.5,0.25") {
+		t.Errorf("unexpected CSV rows:\n%s", data)
+	}
+}
+
+func TestLoadRuntimeTrendErrors(t *testing.T) {
+	if _, err := LoadRuntimeTrend(context.Background(), []string{filepath.Join(t.TempDir(), "*.pb")}, 0.25); err == nil ||
+		!strings.Contains(err.Error(), "no files match") {
+		t.Errorf("expected an error for a pattern without files, got %v", err)
+	}
+	if _, err := LoadRuntimeTrend(context.Background(), []string{t.TempDir()}, 0.25); err == nil ||
+		!strings.Contains(err.Error(), "no hercules results found") {
+		t.Errorf("expected an error for an empty directory, got %v", err)
+	}
+}
@@ -1,10 +1,18 @@
 package modes
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"image/color"
+	"math"
+	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/spf13/viper"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
@@ -12,426 +20,388 @@ import (
 	"labours-go/internal/readers"
 )
 
-// SentimentResult represents sentiment analysis for a developer or file
-type SentimentResult struct {
-	Entity    string
-	Type      string // "developer" or "language"
-	Positive  float64
-	Neutral   float64
-	Negative  float64
-	Score     float64 // Overall sentiment score (-1 to 1)
+const (
+	// sentimentTopTicks is the number of most negative and most positive ticks listed
+	sentimentTopTicks = 5
+	// sentimentSnippetComments is the number of comments shown for a listed tick
+	sentimentSnippetComments = 3
+	// sentimentSnippetLength truncates the comments shown for a listed tick
+	sentimentSnippetLength = 100
+)
+
+// Python labours' colors of the sentiment chart
+var (
+	sentimentPositiveColor = color.RGBA{R: 0x8D, G: 0xB8, B: 0x43, A: 0xFF}
+	sentimentNegativeColor = color.RGBA{R: 0xE1, G: 0x4C, B: 0x35, A: 0xFF}
+	sentimentAverageColor  = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}
+)
+
+// SentimentPeriod is the comment mood of a resampled period. The mood of a tick is
+// (0.5 - value) * 2 like in Python labours, so that positive comments are above zero;
+// the mood of a period is the mean over its ticks with comments.
+type SentimentPeriod struct {
+	Date     time.Time `json:"date"`
+	Mood     float64   `json:"mood"`
+	Ticks    int       `json:"ticks"` // ticks with comments
+	Comments int       `json:"comments"`
 }
 
-// Sentiment generates sentiment analysis based on available repository data
-// This analyzes patterns in developer activity and language usage to infer sentiment trends
-func Sentiment(reader readers.Reader, output string) error {
-	fmt.Println("Analyzing repository sentiment patterns...")
+// SentimentComments are the comments hercules evaluated on a tick
+type SentimentComments struct {
+	Tick     int       `json:"tick"`
+	Date     time.Time `json:"date"`
+	Value    float64   `json:"value"` // from 0 (positive) to 1 (negative)
+	Comments []string  `json:"comments"`
+	Commits  []string  `json:"commits"`
+}
 
-	// Collect sentiment results from different data sources
-	var sentimentResults []SentimentResult
+// SentimentSeries is the comment sentiment over time of hercules --sentiment.
+// Positive and Negative are the overall sums of the tick moods on each side of neutral.
+type SentimentSeries struct {
+	Resample     string              `json:"resample"`
+	Periods      []SentimentPeriod   `json:"periods"`
+	Positive     float64             `json:"positive"`
+	Negative     float64             `json:"negative"`
+	MostNegative []SentimentComments `json:"most_negative"`
+	MostPositive []SentimentComments `json:"most_positive"`
+}
 
-	// Analyze developer sentiment based on activity patterns
-	devResults, err := analyzeDeveloperSentiment(reader)
+// Sentiment plots the comment sentiment over time resampled by resample, and lists the
+// comments of the most negative and most positive ticks. No chart is drawn when ctx is
+// cancelled.
+func Sentiment(ctx context.Context, reader readers.Reader, output, resample string) error {
+	series, err := LoadSentiment(reader, resample)
 	if err != nil {
-		fmt.Printf("Warning: Could not analyze developer sentiment: %v\n", err)
-	} else {
-		sentimentResults = append(sentimentResults, devResults...)
+		return err
 	}
 
-	// Analyze language sentiment based on usage patterns
-	langResults, err := analyzeLanguageSentiment(reader)
-	if err != nil {
-		fmt.Printf("Warning: Could not analyze language sentiment: %v\n", err)
-	} else {
-		sentimentResults = append(sentimentResults, langResults...)
+	if strings.ToLower(filepath.Ext(output)) == ".json" {
+		return saveSentimentAsJSON(output, series)
 	}
-
-	if len(sentimentResults) == 0 {
-		return fmt.Errorf("no sentiment data available - ensure the input contains developer stats or language stats")
+	// An image path is used as prefix for all outputs, anything else as a directory
+	prefix, ext := filepath.Join(output, "sentiment"), ".png"
+	if e := filepath.Ext(output); e != "" {
+		prefix, ext = strings.TrimSuffix(output, e), e
+		if prefix == "" || strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix = filepath.Join(prefix, "sentiment")
+		}
 	}
-
-	// Generate visualizations
-	if err := plotSentimentOverview(sentimentResults, output); err != nil {
-		return fmt.Errorf("failed to generate sentiment overview: %v", err)
+	if err := os.MkdirAll(filepath.Dir(prefix), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %v", filepath.Dir(prefix), err)
 	}
-
-	if err := plotSentimentByType(sentimentResults, output); err != nil {
-		return fmt.Errorf("failed to generate sentiment by type: %v", err)
+	if !viper.GetBool("quiet") {
+		printSentimentComments(reader.GetName(), series)
+	}
+	if err := saveSentimentAsJSON(prefix+".json", series); err != nil {
+		return err
 	}
 
-	// Print summary
-	printSentimentSummary(sentimentResults)
-
-	fmt.Printf("Sentiment analysis completed. Analyzed %d entities.\n", len(sentimentResults))
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p, err := buildSentimentPlot(reader.GetName(), series)
+	if err != nil {
+		return err
+	}
+	if err := graphics.AddTimeAnnotations(p, graphics.ActiveAnnotations()); err != nil {
+		return err
+	}
+	graphics.AddReportChart(ctx, p)
+	width, height := graphics.GetPlotSize(graphics.ChartTypeWide)
+	if err := graphics.SavePlotWithFormat(p, width, height, prefix+ext); err != nil {
+		return fmt.Errorf("failed to save sentiment plot: %v", err)
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved sentiment plot to %s\n", prefix+ext)
+	}
 	return nil
 }
 
-// analyzeDeveloperSentiment analyzes sentiment based on developer activity patterns
-func analyzeDeveloperSentiment(reader readers.Reader) ([]SentimentResult, error) {
-	devStats, err := reader.GetDeveloperStats()
-	if err != nil || len(devStats) == 0 {
-		return nil, fmt.Errorf("no developer statistics available")
+// LoadSentiment reads the comment sentiment of the reader and resamples it
+func LoadSentiment(reader readers.Reader, resample string) (*SentimentSeries, error) {
+	ticks, err := reader.GetCommentSentiment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment sentiment: %v", err)
 	}
-
-	var results []SentimentResult
-
-	// Calculate total metrics for normalization
-	var totalCommits, totalAdded, totalRemoved int
-	for _, dev := range devStats {
-		totalCommits += dev.Commits
-		totalAdded += dev.LinesAdded
-		totalRemoved += dev.LinesRemoved
+	if len(ticks) == 0 {
+		return nil, fmt.Errorf("no comment sentiment found - run hercules with --sentiment")
 	}
+	begin, end := reader.GetHeader()
+	return buildSentimentSeries(ticks, time.Unix(begin, 0).UTC(), time.Unix(end, 0).UTC(),
+		sentimentTickSize(reader), resample)
+}
 
-	for _, dev := range devStats {
-		// Calculate activity ratios
-		commitRatio := float64(dev.Commits) / float64(totalCommits)
-		changeRatio := float64(dev.LinesAdded) / float64(dev.LinesAdded + dev.LinesRemoved + 1)
-		
-		// Sentiment heuristics based on activity patterns:
-		// - High commit activity = positive engagement
-		// - Balanced add/remove ratio = positive code quality focus  
-		// - Many languages = positive exploration/learning
-		// - Many files touched = positive collaboration
-		
-		positive := commitRatio * 0.3 + changeRatio * 0.3 + 
-				   float64(len(dev.Languages))/10.0 * 0.2 + 
-				   float64(dev.FilesTouched)/100.0 * 0.2
-		
-		// Factors that might indicate negative sentiment:
-		// - Very high removal ratio (frustration/cleanup)
-		// - Very low commit activity (disengagement)
-		negative := 0.0
-		if dev.LinesRemoved > dev.LinesAdded*2 {
-			negative += 0.3 // High removal ratio
-		}
-		if commitRatio < 0.01 && len(devStats) > 5 {
-			negative += 0.2 // Very low activity
-		}
-		
-		neutral := 1.0 - positive - negative
-		if neutral < 0 {
-			// Normalize if we went over 1.0
-			total := positive + negative
-			positive = positive / total
-			negative = negative / total
-			neutral = 0.0
-		}
-
-		score := positive - negative // Overall sentiment score
-
-		results = append(results, SentimentResult{
-			Entity:   dev.Name,
-			Type:     "developer",
-			Positive: positive,
-			Neutral:  neutral,
-			Negative: negative,
-			Score:    score,
-		})
+// sentimentTickSize returns the tick size in seconds, hercules ticks are days by default
+func sentimentTickSize(reader readers.Reader) float64 {
+	if data, err := reader.GetDeveloperTimeSeriesData(); err == nil && data != nil && data.TickSize > 0 {
+		return data.TickSize
+	}
+	if params, err := reader.GetBurndownParameters(); err == nil && params.TickSize > 0 {
+		return params.TickSize
 	}
+	return 86400
+}
 
-	return results, nil
+// sentimentMood maps a hercules sentiment value to the mood Python labours plots
+func sentimentMood(value float64) float64 {
+	return (0.5 - value) * 2
 }
 
-// analyzeLanguageSentiment analyzes sentiment based on language usage patterns
-func analyzeLanguageSentiment(reader readers.Reader) ([]SentimentResult, error) {
-	langStats, err := reader.GetLanguageStats()
-	if err != nil || len(langStats) == 0 {
-		return nil, fmt.Errorf("no language statistics available")
+func buildSentimentSeries(ticks map[int]readers.SentimentTick, begin, end time.Time, tickSize float64,
+	resample string) (*SentimentSeries, error) {
+	tickDate := func(tick int) time.Time {
+		return begin.Add(time.Duration(float64(tick) * tickSize * float64(time.Second)))
 	}
-
-	var results []SentimentResult
-
-	// Calculate total lines for normalization
-	totalLines := 0
-	for _, lang := range langStats {
-		totalLines += lang.Lines
+	order := make([]int, 0, len(ticks))
+	for tick := range ticks {
+		order = append(order, tick)
 	}
-
-	// Language sentiment heuristics based on common perceptions and usage patterns
-	languageSentimentMap := map[string]float64{
-		"Go":         0.7,  // Modern, loved language
-		"Python":     0.6,  // Popular, versatile
-		"JavaScript": 0.2,  // Mixed feelings, necessary evil
-		"TypeScript": 0.5,  // Improvement over JS
-		"Rust":       0.8,  // Loved by developers
-		"Java":       0.1,  // Corporate, verbose
-		"C++":        0.0,  // Complex, powerful but hard
-		"C":          0.3,  // Respected but challenging
-		"Kotlin":     0.6,  // Modern improvement over Java
-		"Swift":      0.5,  // Apple ecosystem
-		"PHP":        -0.2, // Often criticized
-		"Ruby":       0.4,  // Elegant but less popular
-		"Shell":      0.1,  // Necessary but limited
-		"HTML":       0.2,  // Markup, not programming
-		"CSS":        0.1,  // Styling challenges
-		"SQL":        0.3,  // Necessary data tool
-		"Dockerfile": 0.4,  // Modern deployment
-		"YAML":       0.2,  // Configuration
-		"JSON":       0.3,  // Data format
-		"Markdown":   0.5,  // Documentation friendly
+	sort.Ints(order)
+	if last := tickDate(order[len(order)-1]); end.Before(last) {
+		end = last
 	}
 
-	for _, lang := range langStats {
-		usage := float64(lang.Lines) / float64(totalLines)
-		
-		// Get base sentiment for this language (default neutral)
-		baseSentiment := languageSentimentMap[lang.Language]
-		if _, exists := languageSentimentMap[lang.Language]; !exists {
-			baseSentiment = 0.0 // Neutral for unknown languages
+	series := &SentimentSeries{Resample: resample,
+		MostNegative: []SentimentComments{}, MostPositive: []SentimentComments{}}
+	var periods []time.Time
+	periodOf := make(map[int]int, len(ticks)) // the period of each tick
+	if resample == "no" || resample == "raw" {
+		for tick := 0; tick <= order[len(order)-1]; tick++ {
+			periods = append(periods, tickDate(tick))
 		}
-		
-		// Weight sentiment by usage - more used languages have more impact
-		weightedSentiment := baseSentiment * usage * 2.0 // Amplify for visibility
-		
-		var positive, negative, neutral float64
-		if weightedSentiment > 0 {
-			positive = weightedSentiment
-			negative = 0.0
-		} else if weightedSentiment < 0 {
-			positive = 0.0
-			negative = -weightedSentiment
+		for _, tick := range order {
+			periodOf[tick] = tick
 		}
-		
-		neutral = 1.0 - positive - negative
-		if neutral < 0 {
-			neutral = 0.0
+	} else {
+		start, err := resamplePeriodStart(begin, resample)
+		if err != nil {
+			return nil, err
+		}
+		for date := start; !date.After(end); date = resampleNextPeriod(date, resample) {
+			periods = append(periods, date)
+		}
+		for _, tick := range order {
+			date, _ := resamplePeriodStart(tickDate(tick), resample)
+			periodOf[tick] = sort.Search(len(periods), func(i int) bool { return !periods[i].Before(date) })
 		}
-
-		results = append(results, SentimentResult{
-			Entity:   lang.Language,
-			Type:     "language",
-			Positive: positive,
-			Neutral:  neutral,
-			Negative: negative,
-			Score:    weightedSentiment,
-		})
-	}
-
-	return results, nil
-}
-
-// plotSentimentOverview creates a stacked bar chart showing overall sentiment distribution
-func plotSentimentOverview(results []SentimentResult, output string) error {
-	// Sort by sentiment score (descending)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
-
-	p := plot.New()
-	p.Title.Text = "Repository Sentiment Analysis Overview"
-	p.X.Label.Text = "Entities (Developers & Languages)"
-	p.Y.Label.Text = "Sentiment Distribution"
-
-	// Prepare data for stacked bars
-	entities := make([]string, len(results))
-	positiveVals := make(plotter.Values, len(results))
-	neutralVals := make(plotter.Values, len(results))
-	negativeVals := make(plotter.Values, len(results))
-
-	for i, result := range results {
-		entities[i] = fmt.Sprintf("%s (%s)", result.Entity, result.Type)
-		positiveVals[i] = result.Positive
-		neutralVals[i] = result.Neutral
-		negativeVals[i] = result.Negative
-	}
-
-	// Create stacked bars
-	positiveBars, err := plotter.NewBarChart(positiveVals, vg.Points(40))
-	if err != nil {
-		return fmt.Errorf("failed to create positive bars: %v", err)
 	}
-	positiveBars.Color = graphics.ColorPalette[2] // Green
 
-	neutralBars, err := plotter.NewBarChart(neutralVals, vg.Points(40))
-	if err != nil {
-		return fmt.Errorf("failed to create neutral bars: %v", err)
+	series.Periods = make([]SentimentPeriod, len(periods))
+	for i, date := range periods {
+		series.Periods[i].Date = date
 	}
-	neutralBars.Color = graphics.ColorPalette[7] // Gray
-	neutralBars.StackOn(positiveBars)
-
-	negativeBars, err := plotter.NewBarChart(negativeVals, vg.Points(40))
-	if err != nil {
-		return fmt.Errorf("failed to create negative bars: %v", err)
+	comments := make([]SentimentComments, 0, len(order))
+	for _, tick := range order {
+		value := ticks[tick]
+		mood := sentimentMood(value.Value)
+		if mood > 0 {
+			series.Positive += mood
+		} else {
+			series.Negative -= mood
+		}
+		if i := periodOf[tick]; i >= 0 && i < len(series.Periods) {
+			period := &series.Periods[i]
+			period.Mood += mood
+			period.Ticks++
+			period.Comments += len(value.Comments)
+		}
+		comments = append(comments, SentimentComments{Tick: tick, Date: tickDate(tick), Value: value.Value,
+			Comments: value.Comments, Commits: value.Commits})
 	}
-	negativeBars.Color = graphics.ColorPalette[3] // Red
-	negativeBars.StackOn(neutralBars)
-
-	p.Add(positiveBars, neutralBars, negativeBars)
-	p.NominalX(entities...)
-
-	// Rotate labels if many entities
-	if len(results) > 8 {
-		p.X.Tick.Label.Rotation = 0.785398 // 45 degrees
-		p.X.Tick.Label.XAlign = -0.5
-		p.X.Tick.Label.YAlign = -0.5
+	for i := range series.Periods {
+		if series.Periods[i].Ticks > 0 {
+			series.Periods[i].Mood /= float64(series.Periods[i].Ticks)
+		}
 	}
 
-	// Add legend
-	p.Legend.Add("Positive", positiveBars)
-	p.Legend.Add("Neutral", neutralBars)
-	p.Legend.Add("Negative", negativeBars)
-	p.Legend.Top = true
-
-	// Save plots
-	outputFile := filepath.Join(output, "sentiment-overview.png")
-	if err := p.Save(16*vg.Inch, 8*vg.Inch, outputFile); err != nil {
-		return fmt.Errorf("failed to save sentiment overview: %v", err)
+	// Most negative first, ties in tick order
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].Value > comments[j].Value })
+	for _, tick := range comments {
+		if tick.Value <= 0.5 || len(series.MostNegative) == sentimentTopTicks {
+			break
+		}
+		series.MostNegative = append(series.MostNegative, tick)
 	}
-
-	svgFile := filepath.Join(output, "sentiment-overview.svg")
-	if err := p.Save(16*vg.Inch, 8*vg.Inch, svgFile); err != nil {
-		return fmt.Errorf("failed to save sentiment overview SVG: %v", err)
+	for i := len(comments) - 1; i >= 0; i-- {
+		if comments[i].Value >= 0.5 || len(series.MostPositive) == sentimentTopTicks {
+			break
+		}
+		series.MostPositive = append(series.MostPositive, comments[i])
 	}
-
-	fmt.Printf("Sentiment overview charts saved to %s and %s\n", outputFile, svgFile)
-	return nil
+	return series, nil
 }
 
-// plotSentimentByType creates separate charts for developers and languages
-func plotSentimentByType(results []SentimentResult, output string) error {
-	// Separate by type
-	var developers, languages []SentimentResult
-	for _, result := range results {
-		if result.Type == "developer" {
-			developers = append(developers, result)
-		} else if result.Type == "language" {
-			languages = append(languages, result)
-		}
+// resamplePeriodStart returns the start of the resampling period of date
+func resamplePeriodStart(date time.Time, resample string) (time.Time, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch resample {
+	case "year", "A":
+		return time.Date(date.Year(), 1, 1, 0, 0, 0, 0, date.Location()), nil
+	case "month", "M":
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()), nil
+	case "week", "W":
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil // Monday
+	case "day", "D":
+		return day, nil
 	}
+	return time.Time{}, fmt.Errorf("unsupported resampling %q, use year, month, week, day or no", resample)
+}
 
-	// Plot developer sentiment if available
-	if len(developers) > 0 {
-		if err := plotSentimentForType(developers, "Developer Sentiment Analysis", output, "sentiment-developers"); err != nil {
-			return fmt.Errorf("failed to plot developer sentiment: %v", err)
-		}
+func resampleNextPeriod(date time.Time, resample string) time.Time {
+	switch resample {
+	case "year", "A":
+		return date.AddDate(1, 0, 0)
+	case "month", "M":
+		return date.AddDate(0, 1, 0)
+	case "week", "W":
+		return date.AddDate(0, 0, 7)
 	}
+	return date.AddDate(0, 0, 1)
+}
 
-	// Plot language sentiment if available
-	if len(languages) > 0 {
-		if err := plotSentimentForType(languages, "Language Sentiment Analysis", output, "sentiment-languages"); err != nil {
-			return fmt.Errorf("failed to plot language sentiment: %v", err)
+// sentimentAverage is the centred moving average over a quarter of the periods, like the
+// average line of Python labours
+func sentimentAverage(periods []SentimentPeriod) []float64 {
+	window := max(len(periods)/4, 1)
+	average := make([]float64, len(periods))
+	for i := range periods {
+		from, to := max(i-window/2, 0), min(i-window/2+window, len(periods))
+		for _, period := range periods[from:to] {
+			average[i] += period.Mood
 		}
+		average[i] /= float64(to - from)
 	}
-
-	return nil
+	return average
 }
 
-// plotSentimentForType creates a sentiment chart for a specific type
-func plotSentimentForType(results []SentimentResult, title, output, filename string) error {
-	// Sort by sentiment score
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
-
+// buildSentimentPlot fills the positive and negative mood of each period and draws the
+// moving average over them
+func buildSentimentPlot(name string, series *SentimentSeries) (*plot.Plot, error) {
 	p := plot.New()
-	p.Title.Text = title
-	p.X.Label.Text = "Entities"
-	p.Y.Label.Text = "Sentiment Score"
-
-	// Create scatter plot of sentiment scores
-	pts := make(plotter.XYs, len(results))
-	for i, result := range results {
-		pts[i].X = float64(i)
-		pts[i].Y = result.Score
+	p.Title.Text = fmt.Sprintf("%s sentiment +%.1f -%.1f δ=%.1f", name, series.Positive, series.Negative,
+		series.Positive-series.Negative)
+	p.X.Label.Text = "Time"
+	p.Y.Label.Text = "Comment sentiment"
+	p.X.Tick.Marker = &graphics.TimeTicker{Format: "2006-01-02"}
+
+	n := len(series.Periods)
+	positive := make(plotter.XYs, 0, n+2)
+	negative := make(plotter.XYs, 0, n+2)
+	average := make(plotter.XYs, n)
+	first, last := float64(series.Periods[0].Date.Unix()), float64(series.Periods[n-1].Date.Unix())
+	positive = append(positive, plotter.XY{X: first})
+	negative = append(negative, plotter.XY{X: first})
+	for i, period := range series.Periods {
+		x := float64(period.Date.Unix())
+		positive = append(positive, plotter.XY{X: x, Y: math.Max(period.Mood, 0)})
+		negative = append(negative, plotter.XY{X: x, Y: math.Min(period.Mood, 0)})
+		average[i].X = x
 	}
-
-	scatter, err := plotter.NewScatter(pts)
-	if err != nil {
-		return fmt.Errorf("failed to create scatter plot: %v", err)
+	positive = append(positive, plotter.XY{X: last})
+	negative = append(negative, plotter.XY{X: last})
+	for i, mood := range sentimentAverage(series.Periods) {
+		average[i].Y = mood
 	}
-	scatter.GlyphStyle.Color = graphics.ColorPalette[0]
-	scatter.GlyphStyle.Radius = vg.Points(4)
-
-	p.Add(scatter)
 
-	// Set X-axis labels
-	names := make([]string, len(results))
-	for i, result := range results {
-		names[i] = result.Entity
+	for _, area := range []struct {
+		label  string
+		points plotter.XYs
+		color  color.Color
+	}{
+		{"Positive", positive, sentimentPositiveColor},
+		{"Negative", negative, sentimentNegativeColor},
+	} {
+		polygon, err := plotter.NewPolygon(area.points)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s area: %v", strings.ToLower(area.label), err)
+		}
+		polygon.Color = area.color
+		polygon.LineStyle.Width = 0
+		p.Add(polygon)
+		p.Legend.Add(area.label, polygon)
 	}
-	p.NominalX(names...)
-
-	// Rotate labels if many entities
-	if len(results) > 6 {
-		p.X.Tick.Label.Rotation = 0.785398
-		p.X.Tick.Label.XAlign = -0.5
-		p.X.Tick.Label.YAlign = -0.5
+	line, err := plotter.NewLine(average)
+	if err != nil {
+		return nil, fmt.Errorf("error creating average line: %v", err)
 	}
-
-	// Add horizontal line at y=0 for neutral sentiment
-	line := plotter.NewFunction(func(x float64) float64 { return 0 })
-	line.Color = graphics.ColorPalette[4]
-	line.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
+	line.Color = sentimentAverageColor
+	line.Width = vg.Points(3)
 	p.Add(line)
+	p.Legend.Add("Average", line)
+	p.Legend.Top = true
 
-	// Save plots
-	outputFile := filepath.Join(output, filename+".png")
-	if err := p.Save(14*vg.Inch, 8*vg.Inch, outputFile); err != nil {
-		return fmt.Errorf("failed to save %s: %v", filename, err)
-	}
-
-	svgFile := filepath.Join(output, filename+".svg")
-	if err := p.Save(14*vg.Inch, 8*vg.Inch, svgFile); err != nil {
-		return fmt.Errorf("failed to save %s SVG: %v", filename, err)
-	}
-
-	fmt.Printf("%s charts saved to %s and %s\n", title, outputFile, svgFile)
-	return nil
+	p.Y.Min, p.Y.Max = -1, 1
+	return p, nil
 }
 
-// printSentimentSummary prints a text summary of sentiment analysis
-func printSentimentSummary(results []SentimentResult) {
-	fmt.Println("\nSentiment Analysis Summary:")
-	fmt.Println("===========================")
-
-	// Calculate overall statistics
-	var totalPositive, totalNeutral, totalNegative float64
-	var devCount, langCount int
-
-	for _, result := range results {
-		totalPositive += result.Positive
-		totalNeutral += result.Neutral
-		totalNegative += result.Negative
-
-		if result.Type == "developer" {
-			devCount++
-		} else {
-			langCount++
+// printSentimentComments prints the overall sentiment and the comments of the most
+// negative and most positive ticks
+func printSentimentComments(name string, series *SentimentSeries) {
+	fmt.Printf("\n%s sentiment: +%.1f -%.1f δ=%.1f\n", name, series.Positive, series.Negative,
+		series.Positive-series.Negative)
+	for _, list := range []struct {
+		title string
+		ticks []SentimentComments
+	}{
+		{"Most negative comments", series.MostNegative},
+		{"Most positive comments", series.MostPositive},
+	} {
+		fmt.Printf("\n%s:\n", list.title)
+		if len(list.ticks) == 0 {
+			fmt.Println("  none")
+			continue
+		}
+		for _, tick := range list.ticks {
+			commits := make([]string, len(tick.Commits))
+			for i, hash := range tick.Commits {
+				commits[i] = hash[:min(len(hash), 8)]
+			}
+			fmt.Printf("  %s  %.2f  %s\n", tick.Date.Format("2006-01-02"), tick.Value, strings.Join(commits, " "))
+			for i, comment := range tick.Comments {
+				if i == sentimentSnippetComments {
+					fmt.Printf("    ... %d more\n", len(tick.Comments)-i)
+					break
+				}
+				fmt.Printf("    %s\n", sentimentSnippet(comment))
+			}
 		}
 	}
+	fmt.Println()
+}
 
-	avgPositive := totalPositive / float64(len(results))
-	avgNeutral := totalNeutral / float64(len(results))
-	avgNegative := totalNegative / float64(len(results))
+// sentimentSnippet returns the comment on a single line, truncated
+func sentimentSnippet(comment string) string {
+	snippet := []rune(strings.Join(strings.Fields(comment), " "))
+	if len(snippet) > sentimentSnippetLength {
+		return string(snippet[:sentimentSnippetLength-3]) + "..."
+	}
+	return string(snippet)
+}
 
-	fmt.Printf("Overall Sentiment Distribution:\n")
-	fmt.Printf("  Positive: %.1f%%\n", avgPositive*100)
-	fmt.Printf("  Neutral:  %.1f%%\n", avgNeutral*100)
-	fmt.Printf("  Negative: %.1f%%\n", avgNegative*100)
-	fmt.Printf("\nAnalyzed: %d developers, %d languages\n", devCount, langCount)
+// saveSentimentAsJSON writes the sentiment series and the listed comments
+func saveSentimentAsJSON(output string, series *SentimentSeries) error {
+	data := struct {
+		Type string `json:"type"`
+		*SentimentSeries
+	}{
+		Type:            "sentiment",
+		SentimentSeries: series,
+	}
 
-	// Show top positive and negative entities
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON output file: %v", err)
+	}
+	defer file.Close()
 
-	fmt.Println("\nMost Positive Entities:")
-	for i, result := range results {
-		if i >= 5 || result.Score <= 0 {
-			break
-		}
-		fmt.Printf("  %d. %s (%s) - Score: %.3f\n", i+1, result.Entity, result.Type, result.Score)
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write JSON data: %v", err)
 	}
 
-	fmt.Println("\nMost Negative Entities:")
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score < results[j].Score
-	})
-	for i, result := range results {
-		if i >= 5 || result.Score >= 0 {
-			break
-		}
-		fmt.Printf("  %d. %s (%s) - Score: %.3f\n", i+1, result.Entity, result.Type, result.Score)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("JSON data saved to %s\n", output)
 	}
-}
\ No newline at end of file
+	return nil
+}
@@ -1,148 +1,183 @@
 package modes
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
-	
+	"time"
+
 	"labours-go/internal/burndown"
 	"labours-go/internal/readers"
 )
 
-// MockReader implements readers.Reader interface for testing sentiment mode
-type MockSentimentReader struct{}
+// MockSentimentReader implements readers.Reader, serving the comment sentiment of sentiment
+type MockSentimentReader struct {
+	begin, end int64
+	sentiment  map[int]readers.SentimentTick
+}
 
-func (m *MockSentimentReader) Read(file io.Reader) error { return nil }
-func (m *MockSentimentReader) GetName() string { return "test" }
-func (m *MockSentimentReader) GetHeader() (int64, int64) { return 0, 0 }
-func (m *MockSentimentReader) GetProjectBurndown() (string, [][]int) { return "", nil }
-func (m *MockSentimentReader) GetFilesBurndown() ([]readers.FileBurndown, error) { return nil, nil }
+func (m *MockSentimentReader) Read(file io.Reader) error                            { return nil }
+func (m *MockSentimentReader) GetName() string                                      { return "test" }
+func (m *MockSentimentReader) GetHeader() (int64, int64)                            { return m.begin, m.end }
+func (m *MockSentimentReader) GetMetadata() (readers.Metadata, error)               { return readers.Metadata{}, nil }
+func (m *MockSentimentReader) GetProjectBurndown() (string, [][]int)                { return "", nil }
+func (m *MockSentimentReader) GetFilesBurndown() ([]readers.FileBurndown, error)    { return nil, nil }
 func (m *MockSentimentReader) GetPeopleBurndown() ([]readers.PeopleBurndown, error) { return nil, nil }
-func (m *MockSentimentReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) { return nil, nil, nil }
+func (m *MockSentimentReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) {
+	return nil, nil, nil
+}
 func (m *MockSentimentReader) GetPeopleInteraction() ([]string, [][]int, error) { return nil, nil, nil }
-func (m *MockSentimentReader) GetFileCooccurrence() ([]string, [][]int, error) { return nil, nil, nil }
-func (m *MockSentimentReader) GetPeopleCooccurrence() ([]string, [][]int, error) { return nil, nil, nil }
-func (m *MockSentimentReader) GetShotnessCooccurrence() ([]string, [][]int, error) { return nil, nil, nil }
+func (m *MockSentimentReader) GetFileCooccurrence() ([]string, [][]int, error)  { return nil, nil, nil }
+func (m *MockSentimentReader) GetPeopleCooccurrence() ([]string, [][]int, error) {
+	return nil, nil, nil
+}
+func (m *MockSentimentReader) GetShotnessCooccurrence() ([]string, [][]int, error) {
+	return nil, nil, nil
+}
 func (m *MockSentimentReader) GetShotnessRecords() ([]readers.ShotnessRecord, error) { return nil, nil }
-func (m *MockSentimentReader) GetRuntimeStats() (map[string]float64, error) { return nil, nil }
-func (m *MockSentimentReader) GetBurndownParameters() (burndown.BurndownParameters, error) { 
-	return burndown.BurndownParameters{}, nil 
+func (m *MockSentimentReader) GetDeveloperStats() ([]readers.DeveloperStat, error)   { return nil, nil }
+func (m *MockSentimentReader) GetLanguageStats() ([]readers.LanguageStat, error)     { return nil, nil }
+func (m *MockSentimentReader) GetRuntimeStats() (map[string]float64, error)          { return nil, nil }
+func (m *MockSentimentReader) GetBurndownParameters() (burndown.BurndownParameters, error) {
+	return burndown.BurndownParameters{}, nil
 }
 func (m *MockSentimentReader) GetProjectBurndownWithHeader() (burndown.BurndownHeader, string, [][]int, error) {
 	return burndown.BurndownHeader{}, "", nil, nil
 }
-
-func (m *MockSentimentReader) GetDeveloperStats() ([]readers.DeveloperStat, error) {
-	return []readers.DeveloperStat{
-		{
-			Name:          "Alice",
-			Commits:       50,
-			LinesAdded:    1000,
-			LinesRemoved:  100,
-			LinesModified: 200,
-			FilesTouched:  25,
-			Languages:     map[string]int{"Go": 800, "Python": 200},
-		},
-		{
-			Name:          "Bob",
-			Commits:       30,
-			LinesAdded:    500,
-			LinesRemoved:  800,
-			LinesModified: 100,
-			FilesTouched:  15,
-			Languages:     map[string]int{"JavaScript": 300, "CSS": 200},
-		},
-	}, nil
+func (m *MockSentimentReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTimeSeriesData, error) {
+	return nil, fmt.Errorf("no developer data")
 }
 
-func (m *MockSentimentReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTimeSeriesData, error) {
-	return nil, nil
+func (m *MockSentimentReader) GetCommentSentiment() (map[int]readers.SentimentTick, error) {
+	if len(m.sentiment) == 0 {
+		return nil, fmt.Errorf("no sentiment data")
+	}
+	return m.sentiment, nil
 }
 
-func (m *MockSentimentReader) GetLanguageStats() ([]readers.LanguageStat, error) {
-	return []readers.LanguageStat{
-		{Language: "Go", Lines: 800},
-		{Language: "Python", Lines: 200},
-		{Language: "JavaScript", Lines: 300},
-		{Language: "CSS", Lines: 200},
-	}, nil
+// newMockSentimentReader covers January to March 2024 with daily ticks
+func newMockSentimentReader() *MockSentimentReader {
+	begin := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &MockSentimentReader{
+		begin: begin.Unix(),
+		end:   begin.AddDate(0, 3, 0).Unix() - 1,
+		sentiment: map[int]readers.SentimentTick{
+			2:  {Value: 0.1, Comments: []string{"great work", "nice"}, Commits: []string{"0123456789abcdef"}},
+			20: {Value: 0.3, Comments: []string{"ok"}, Commits: []string{"1111111111"}},
+			40: {Value: 0.9, Comments: []string{"this hack is terrible"}, Commits: []string{"2222222222"}},
+			45: {Value: 0.7, Comments: []string{"ugly workaround"}, Commits: []string{"3333333333"}},
+			70: {Value: 0.5, Comments: []string{"neutral"}, Commits: []string{"4444444444"}},
+		},
+	}
 }
 
-func TestSentiment(t *testing.T) {
-	// Create temporary output directory
-	tempDir, err := os.MkdirTemp("", "sentiment_test")
+func TestLoadSentiment(t *testing.T) {
+	series, err := LoadSentiment(newMockSentimentReader(), "month")
 	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
+		t.Fatalf("LoadSentiment failed: %v", err)
+	}
+	if len(series.Periods) != 3 {
+		t.Fatalf("expected 3 monthly periods, got %d", len(series.Periods))
+	}
+	// January has moods 0.8 and 0.4, February -0.8 and -0.4, March 0
+	want := []struct {
+		month    time.Month
+		mood     float64
+		ticks    int
+		comments int
+	}{
+		{time.January, 0.6, 2, 3},
+		{time.February, -0.6, 2, 2},
+		{time.March, 0, 1, 1},
+	}
+	for i, w := range want {
+		period := series.Periods[i]
+		if period.Date.Month() != w.month || math.Abs(period.Mood-w.mood) > 1e-9 ||
+			period.Ticks != w.ticks || period.Comments != w.comments {
+			t.Errorf("period %d: got %+v, want %+v", i, period, w)
+		}
+	}
+	if math.Abs(series.Positive-1.2) > 1e-9 || math.Abs(series.Negative-1.2) > 1e-9 {
+		t.Errorf("expected overall +1.2 -1.2, got +%g -%g", series.Positive, series.Negative)
 	}
-	defer os.RemoveAll(tempDir)
 
-	// Create mock reader with test data
-	reader := &MockSentimentReader{}
+	if len(series.MostNegative) != 2 || series.MostNegative[0].Tick != 40 || series.MostNegative[1].Tick != 45 {
+		t.Errorf("unexpected most negative ticks: %+v", series.MostNegative)
+	}
+	if len(series.MostPositive) != 2 || series.MostPositive[0].Tick != 2 || series.MostPositive[1].Tick != 20 {
+		t.Errorf("unexpected most positive ticks: %+v", series.MostPositive)
+	}
+	if date := series.MostNegative[0].Date; !date.Equal(time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("tick 40 should be dated 2024-02-10, got %s", date)
+	}
+}
 
-	// Run sentiment analysis
-	err = Sentiment(reader, tempDir)
+func TestLoadSentimentRawTicks(t *testing.T) {
+	series, err := LoadSentiment(newMockSentimentReader(), "no")
 	if err != nil {
-		t.Fatalf("Sentiment analysis failed: %v", err)
+		t.Fatalf("LoadSentiment failed: %v", err)
 	}
-
-	// Check that output files were created
-	expectedFiles := []string{
-		"sentiment-overview.png",
-		"sentiment-overview.svg",
-		"sentiment-developers.png",
-		"sentiment-developers.svg",
-		"sentiment-languages.png",
-		"sentiment-languages.svg",
+	if len(series.Periods) != 71 {
+		t.Fatalf("expected a period per tick up to 70, got %d", len(series.Periods))
+	}
+	if math.Abs(series.Periods[40].Mood+0.8) > 1e-9 || series.Periods[41].Ticks != 0 {
+		t.Errorf("unexpected raw periods: %+v %+v", series.Periods[40], series.Periods[41])
 	}
 
-	for _, filename := range expectedFiles {
-		filepath := tempDir + "/" + filename
-		if _, err := os.Stat(filepath); os.IsNotExist(err) {
-			t.Errorf("Expected output file %s was not created", filename)
-		}
+	if _, err := LoadSentiment(newMockSentimentReader(), "fortnight"); err == nil {
+		t.Error("expected an error for an unsupported resampling")
 	}
 }
 
-// NoDataReader implements readers.Reader but returns no data
-type NoDataReader struct{}
-
-func (n *NoDataReader) Read(file io.Reader) error { return nil }
-func (n *NoDataReader) GetName() string { return "test" }
-func (n *NoDataReader) GetHeader() (int64, int64) { return 0, 0 }
-func (n *NoDataReader) GetProjectBurndown() (string, [][]int) { return "", nil }
-func (n *NoDataReader) GetFilesBurndown() ([]readers.FileBurndown, error) { return nil, nil }
-func (n *NoDataReader) GetPeopleBurndown() ([]readers.PeopleBurndown, error) { return nil, nil }
-func (n *NoDataReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) { return nil, nil, nil }
-func (n *NoDataReader) GetPeopleInteraction() ([]string, [][]int, error) { return nil, nil, nil }
-func (n *NoDataReader) GetFileCooccurrence() ([]string, [][]int, error) { return nil, nil, nil }
-func (n *NoDataReader) GetPeopleCooccurrence() ([]string, [][]int, error) { return nil, nil, nil }
-func (n *NoDataReader) GetShotnessCooccurrence() ([]string, [][]int, error) { return nil, nil, nil }
-func (n *NoDataReader) GetShotnessRecords() ([]readers.ShotnessRecord, error) { return nil, nil }
-func (n *NoDataReader) GetRuntimeStats() (map[string]float64, error) { return nil, nil }
-func (n *NoDataReader) GetBurndownParameters() (burndown.BurndownParameters, error) { 
-	return burndown.BurndownParameters{}, nil 
-}
-func (n *NoDataReader) GetProjectBurndownWithHeader() (burndown.BurndownHeader, string, [][]int, error) {
-	return burndown.BurndownHeader{}, "", nil, nil
-}
-func (n *NoDataReader) GetDeveloperStats() ([]readers.DeveloperStat, error) { return nil, nil }
-func (n *NoDataReader) GetLanguageStats() ([]readers.LanguageStat, error) { return nil, nil }
-func (n *NoDataReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTimeSeriesData, error) { return nil, nil }
+func TestSentiment(t *testing.T) {
+	tempDir := t.TempDir()
 
-func TestSentimentWithNoData(t *testing.T) {
-	// Create a mock reader with no data
-	noDataReader := &NoDataReader{}
+	if err := Sentiment(context.Background(), newMockSentimentReader(), tempDir, "week"); err != nil {
+		t.Fatalf("Sentiment analysis failed: %v", err)
+	}
+	for _, filename := range []string{"sentiment.png", "sentiment.json"} {
+		if _, err := os.Stat(filepath.Join(tempDir, filename)); err != nil {
+			t.Errorf("Expected output file %s was not created", filename)
+		}
+	}
 
-	// Create temp dir
-	tempDir, err := os.MkdirTemp("", "sentiment_no_data_test")
+	data, err := os.ReadFile(filepath.Join(tempDir, "sentiment.json"))
 	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
+		t.Fatal(err)
+	}
+	var report struct {
+		Type         string              `json:"type"`
+		Resample     string              `json:"resample"`
+		MostNegative []SentimentComments `json:"most_negative"`
 	}
-	defer os.RemoveAll(tempDir)
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if report.Type != "sentiment" || report.Resample != "week" || len(report.MostNegative) != 2 ||
+		report.MostNegative[0].Comments[0] != "this hack is terrible" {
+		t.Errorf("unexpected JSON report: %+v", report)
+	}
+}
 
-	// This should return an error when no data is available
-	err = Sentiment(noDataReader, tempDir)
+func TestSentimentWithNoData(t *testing.T) {
+	err := Sentiment(context.Background(), &MockSentimentReader{}, t.TempDir(), "month")
 	if err == nil {
 		t.Error("Expected error when no sentiment data is available, but got nil")
 	}
-}
\ No newline at end of file
+}
+
+func TestSentimentSnippet(t *testing.T) {
+	if got := sentimentSnippet("fix\n   this\tnow"); got != "fix this now" {
+		t.Errorf("expected the comment on one line, got %q", got)
+	}
+	long := sentimentSnippet(strings.Repeat("x", 200))
+	if len([]rune(long)) != sentimentSnippetLength {
+		t.Errorf("expected the snippet to be truncated to %d runes, got %d", sentimentSnippetLength, len(long))
+	}
+}
@@ -1,10 +1,13 @@
 package modes
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"sort"
 
+	"github.com/spf13/viper"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
@@ -27,20 +30,17 @@ type ShotnessResult struct {
 // Shotness generates code hotspot analysis showing which structural
 // units (functions, classes, etc.) have been modified most frequently.
 // Provides both text-based statistics (primary) and visualization (optional).
-func Shotness(reader readers.Reader, output string) error {
+// The chart is skipped when ctx is cancelled.
+func Shotness(ctx context.Context, reader readers.Reader, output string) error {
 	// Step 1: Read shotness records
 	records, err := reader.GetShotnessRecords()
 	if err != nil {
-		fmt.Printf("Warning: No shotness data available - %v\n", err)
-		fmt.Println("To generate shotness analysis, run hercules with the --shotness flag:")
-		fmt.Println("  hercules --burndown --shotness <repo> | labours -m shotness")
+		slog.WarnContext(ctx, "no shotness data available, run hercules with --shotness", "error", err)
 		return nil
 	}
 
 	if len(records) == 0 {
-		fmt.Println("No shotness records found in the data.")
-		fmt.Println("To generate shotness analysis, run hercules with the --shotness flag:")
-		fmt.Println("  hercules --burndown --shotness <repo> | labours -m shotness")
+		slog.WarnContext(ctx, "no shotness records found in the data, run hercules with --shotness")
 		return nil
 	}
 
@@ -51,9 +51,12 @@ func Shotness(reader readers.Reader, output string) error {
 	printShotnessStats(results)
 
 	// Step 4: Generate visualization (optional - only if output directory specified)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if output != "" {
-		if err := plotShotness(results, output); err != nil {
-			fmt.Printf("Warning: Failed to generate shotness plot: %v\n", err)
+		if err := plotShotness(ctx, results, output); err != nil {
+			slog.Warn("failed to generate shotness plot", "error", err)
 		}
 	}
 
@@ -107,7 +110,7 @@ func processShotnessRecords(records []readers.ShotnessRecord) []ShotnessResult {
 }
 
 // plotShotness creates a bar chart showing the hottest code spots by modification frequency
-func plotShotness(results []ShotnessResult, output string) error {
+func plotShotness(ctx context.Context, results []ShotnessResult, output string) error {
 	// Limit to top 20 hottest spots for better visualization
 	maxItems := 20
 	if len(results) > maxItems {
@@ -166,6 +169,7 @@ func plotShotness(results []ShotnessResult, output string) error {
 	p.X.Tick.Label.Rotation = 0.785398 // 45 degrees in radians
 	p.X.Tick.Label.XAlign = -0.5
 	p.X.Tick.Label.YAlign = -0.5
+	graphics.AddReportChart(ctx, p)
 	
 	// Save the plot with dynamic sizing
 	width, height := graphics.GetPlotSize(graphics.ChartTypeWide)
@@ -180,7 +184,9 @@ func plotShotness(results []ShotnessResult, output string) error {
 		return fmt.Errorf("failed to save shotness SVG: %v", err)
 	}
 	
-	fmt.Printf("Shotness charts saved to %s and %s\n", outputFile, svgFile)
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Shotness charts saved to %s and %s\n", outputFile, svgFile)
+	}
 	
 	// Print text summary
 	printShotnessSummary(results)
@@ -0,0 +1,540 @@
+package modes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"labours-go/internal/graphics"
+	"labours-go/internal/readers"
+)
+
+const (
+	// teamDepartureDays is how long a developer must have been inactive at the end of the
+	// history to count as departed
+	teamDepartureDays = 90
+	// teamSubstantialLines is the number of added or changed lines from which the
+	// contributions of a developer count as substantial
+	teamSubstantialLines = 100
+)
+
+var (
+	teamJoinerColor = color.RGBA{R: 0x8D, G: 0xB8, B: 0x43, A: 0xFF}
+	teamLeaverColor = color.RGBA{R: 0xE1, G: 0x4C, B: 0x35, A: 0xFF}
+)
+
+// TeamMember is the tenure of a developer. DaysToSubstantial is -1 for developers who
+// never reached teamSubstantialLines added or changed lines; SurvivingLines are the lines
+// of the developer alive at the last people burndown sample.
+type TeamMember struct {
+	Name              string    `json:"name"`
+	FirstTick         int       `json:"first_tick"`
+	LastTick          int       `json:"last_tick"`
+	FirstActive       time.Time `json:"first_active"`
+	LastActive        time.Time `json:"last_active"`
+	TenureDays        float64   `json:"tenure_days"`
+	DaysToSubstantial float64   `json:"days_to_substantial"`
+	ActiveTicks       int       `json:"active_ticks"`
+	Commits           int       `json:"commits"`
+	Lines             int       `json:"lines"` // added and changed
+	Departed          bool      `json:"departed"`
+	SurvivingLines    int       `json:"surviving_lines"`
+}
+
+// TeamPeriod is the team of a resampled period. Team counts the developers who joined by
+// the end of the period and had not left before it, Active those who committed in it.
+type TeamPeriod struct {
+	Date    time.Time `json:"date"`
+	Active  int       `json:"active"`
+	Team    int       `json:"team"`
+	Joiners []string  `json:"joiners"`
+	Leavers []string  `json:"leavers"`
+}
+
+// OwnershipSample is the code alive at a people burndown sample and the part of it owned
+// by developers who had departed by then
+type OwnershipSample struct {
+	Date     time.Time `json:"date"`
+	Total    int       `json:"total"`
+	Orphaned int       `json:"orphaned"`
+}
+
+// TeamHistory is the onboarding and attrition of the developers of a repository
+type TeamHistory struct {
+	Resample      string            `json:"resample"`
+	DepartureDays int               `json:"departure_days"`
+	End           time.Time         `json:"end"`
+	Members       []TeamMember      `json:"members"`
+	Periods       []TeamPeriod      `json:"periods"`
+	Ownership     []OwnershipSample `json:"ownership"`
+	OrphanedLines int               `json:"orphaned_lines"` // at the last sample
+	OrphanedShare float64           `json:"orphaned_share"`
+}
+
+// TeamDynamics charts when developers join and leave, the active contributors over time
+// and the surviving code owned by departed developers, and exports them as JSON. It stops
+// between periods and charts when ctx is cancelled.
+func TeamDynamics(ctx context.Context, reader readers.Reader, output, resample string) error {
+	history, err := LoadTeamDynamics(ctx, reader, resample)
+	if err != nil {
+		return err
+	}
+
+	if strings.ToLower(filepath.Ext(output)) == ".json" {
+		return saveTeamDynamicsAsJSON(output, history)
+	}
+	// An image path is used as prefix for all outputs, anything else as a directory
+	prefix, ext := filepath.Join(output, "team-dynamics"), ".png"
+	if e := filepath.Ext(output); e != "" {
+		prefix, ext = strings.TrimSuffix(output, e), e
+		if prefix == "" || strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix = filepath.Join(prefix, "team-dynamics")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(prefix), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %v", filepath.Dir(prefix), err)
+	}
+	if !viper.GetBool("quiet") {
+		printTeamDynamics(history)
+	}
+	if err := saveTeamDynamicsAsJSON(prefix+".json", history); err != nil {
+		return err
+	}
+
+	if err := plotTeamActivity(ctx, reader.GetName(), history, prefix+ext); err != nil {
+		return fmt.Errorf("failed to plot team activity: %v", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(history.Ownership) == 0 {
+		slog.WarnContext(ctx, "no people burndown data, run hercules with --burndown-people to chart orphaned code")
+		return nil
+	}
+	if err := plotOrphanedCode(ctx, reader.GetName(), history, prefix+"_orphaned"+ext); err != nil {
+		return fmt.Errorf("failed to plot orphaned code: %v", err)
+	}
+	return nil
+}
+
+// LoadTeamDynamics reads the developer ticks (hercules --devs) and, when present, the
+// people burndown (hercules --burndown-people) and resamples the team by resample
+func LoadTeamDynamics(ctx context.Context, reader readers.Reader, resample string) (*TeamHistory, error) {
+	devData, err := reader.GetDeveloperTimeSeriesData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get developer time series: %v", err)
+	}
+	if devData == nil || len(devData.Days) == 0 {
+		return nil, fmt.Errorf("no developer activity found - run hercules with --devs")
+	}
+	begin, end := reader.GetHeader()
+	history, err := buildTeamDynamics(ctx, devData, time.Unix(begin, 0).UTC(), time.Unix(end, 0).UTC(), resample)
+	if err != nil {
+		return nil, err
+	}
+
+	if people, err := reader.GetPeopleBurndown(); err == nil && len(people) > 0 {
+		params, err := reader.GetBurndownParameters()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get burndown parameters: %v", err)
+		}
+		addOrphanedCode(history, people, params.Sampling, params.TickSize, time.Unix(begin, 0).UTC())
+	}
+	return history, nil
+}
+
+// buildTeamDynamics finds the tenure of every developer and counts the team, the active
+// developers, the joiners and the leavers of each period, stopping between periods when
+// ctx is cancelled
+func buildTeamDynamics(ctx context.Context, devData *readers.DeveloperTimeSeriesData, begin, end time.Time,
+	resample string) (*TeamHistory, error) {
+	tickSize := devData.TickSize
+	if tickSize <= 0 {
+		tickSize = 86400 // hercules' default tick is one day
+	}
+	tickDate := func(tick int) time.Time {
+		return begin.Add(time.Duration(float64(tick) * tickSize * float64(time.Second)))
+	}
+	days := func(ticks int) float64 {
+		return float64(ticks) * tickSize / 86400
+	}
+
+	ticks := make([]int, 0, len(devData.Days))
+	for tick := range devData.Days {
+		if tick >= 0 {
+			ticks = append(ticks, tick)
+		}
+	}
+	sort.Ints(ticks)
+
+	members := make(map[int]*TeamMember)
+	activeTicks := make(map[int][]int) // the active ticks of every developer, in order
+	for _, tick := range ticks {
+		for devIdx, day := range devData.Days[tick] {
+			if devIdx < 0 || devIdx >= len(devData.People) {
+				continue
+			}
+			if day.Commits == 0 && day.LinesAdded+day.LinesRemoved+day.LinesModified == 0 {
+				continue
+			}
+			member := members[devIdx]
+			if member == nil {
+				member = &TeamMember{Name: devData.People[devIdx], FirstTick: tick, DaysToSubstantial: -1}
+				members[devIdx] = member
+			}
+			member.LastTick = tick
+			member.ActiveTicks++
+			member.Commits += day.Commits
+			member.Lines += day.LinesAdded + day.LinesModified
+			if member.DaysToSubstantial < 0 && member.Lines >= teamSubstantialLines {
+				member.DaysToSubstantial = days(tick - member.FirstTick)
+			}
+			activeTicks[devIdx] = append(activeTicks[devIdx], tick)
+		}
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no developer activity found")
+	}
+
+	history := &TeamHistory{Resample: resample, DepartureDays: teamDepartureDays, End: end}
+	for _, member := range members {
+		member.FirstActive = tickDate(member.FirstTick)
+		member.LastActive = tickDate(member.LastTick)
+		if history.End.Before(member.LastActive) {
+			history.End = member.LastActive
+		}
+	}
+	for _, member := range members {
+		member.TenureDays = days(member.LastTick - member.FirstTick + 1)
+		member.Departed = !member.LastActive.AddDate(0, 0, teamDepartureDays).After(history.End)
+		history.Members = append(history.Members, *member)
+	}
+	sort.Slice(history.Members, func(i, j int) bool {
+		a, b := history.Members[i], history.Members[j]
+		if a.FirstTick != b.FirstTick {
+			return a.FirstTick < b.FirstTick
+		}
+		return a.Name < b.Name
+	})
+
+	// Per tick periods would make every day a period, days are the finest resampling
+	if resample == "no" || resample == "raw" {
+		resample = "day"
+	}
+	start, err := resamplePeriodStart(begin, resample)
+	if err != nil {
+		return nil, err
+	}
+	// Periods only move forward, so every member keeps the index of its first active tick
+	// that is not before the current period
+	cursors := make(map[int]int, len(members))
+	for date := start; !date.After(history.End); date = resampleNextPeriod(date, resample) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		next := resampleNextPeriod(date, resample)
+		period := TeamPeriod{Date: date, Joiners: []string{}, Leavers: []string{}}
+		for devIdx, member := range members {
+			if !member.FirstActive.Before(next) {
+				continue
+			}
+			if member.Departed && member.LastActive.Before(date) {
+				continue
+			}
+			period.Team++
+			if !member.FirstActive.Before(date) {
+				period.Joiners = append(period.Joiners, member.Name)
+			}
+			if member.Departed && member.LastActive.Before(next) {
+				period.Leavers = append(period.Leavers, member.Name)
+			}
+			active, cursor := activeTicks[devIdx], cursors[devIdx]
+			for cursor < len(active) && tickDate(active[cursor]).Before(date) {
+				cursor++
+			}
+			cursors[devIdx] = cursor
+			if cursor < len(active) && tickDate(active[cursor]).Before(next) {
+				period.Active++
+			}
+		}
+		sort.Strings(period.Joiners)
+		sort.Strings(period.Leavers)
+		history.Periods = append(history.Periods, period)
+	}
+	return history, nil
+}
+
+// addOrphanedCode sums the surviving lines of every people burndown sample and the part
+// owned by the developers who had been inactive for teamDepartureDays at that sample.
+// People burndown matrices are [band][sample], samples are taken every sampling ticks.
+func addOrphanedCode(history *TeamHistory, people []readers.PeopleBurndown, sampling int,
+	tickSize float64, begin time.Time) {
+	if sampling <= 0 {
+		sampling = 1
+	}
+	if tickSize <= 0 {
+		tickSize = 86400
+	}
+	members := make(map[string]int, len(history.Members))
+	for i, member := range history.Members {
+		members[personKey(member.Name)] = i
+	}
+
+	samples := 0
+	for _, person := range people {
+		for _, band := range person.Matrix {
+			samples = max(samples, len(band))
+		}
+	}
+	if samples == 0 {
+		return
+	}
+	history.Ownership = make([]OwnershipSample, samples)
+	for s := range history.Ownership {
+		seconds := float64(s*sampling) * tickSize
+		history.Ownership[s].Date = begin.Add(time.Duration(seconds * float64(time.Second)))
+	}
+
+	for _, person := range people {
+		index, known := members[personKey(person.Person)]
+		for s := range history.Ownership {
+			lines := 0
+			for _, band := range person.Matrix {
+				if s < len(band) {
+					lines += band[s]
+				}
+			}
+			sample := &history.Ownership[s]
+			sample.Total += lines
+			if !known {
+				continue
+			}
+			member := &history.Members[index]
+			if s == samples-1 {
+				member.SurvivingLines = lines
+			}
+			if member.Departed && !member.LastActive.AddDate(0, 0, teamDepartureDays).After(sample.Date) {
+				sample.Orphaned += lines
+			}
+		}
+	}
+
+	// The last sample counts every departed developer, whenever it was taken
+	last := history.Ownership[samples-1]
+	for _, member := range history.Members {
+		if member.Departed {
+			history.OrphanedLines += member.SurvivingLines
+		}
+	}
+	if last.Total > 0 {
+		history.OrphanedShare = float64(history.OrphanedLines) / float64(last.Total)
+	}
+}
+
+// plotTeamActivity draws the team size and the active developers of every period, with the
+// joiners above and the leavers below zero
+func plotTeamActivity(ctx context.Context, name string, history *TeamHistory, output string) error {
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("%s team dynamics", name)
+	p.X.Label.Text = "Date"
+	p.Y.Label.Text = "Developers"
+	p.X.Tick.Marker = &graphics.TimeTicker{Format: "2006-01-02"}
+
+	team := make(plotter.XYs, len(history.Periods))
+	active := make(plotter.XYs, len(history.Periods))
+	var joiners, leavers plotter.XYs
+	peak, peakLeavers := 0, 0
+	for i, period := range history.Periods {
+		x := float64(period.Date.Unix())
+		peak = max(peak, max(period.Team, len(period.Joiners)))
+		peakLeavers = max(peakLeavers, len(period.Leavers))
+		team[i] = plotter.XY{X: x, Y: float64(period.Team)}
+		active[i] = plotter.XY{X: x, Y: float64(period.Active)}
+		if len(period.Joiners) > 0 {
+			joiners = append(joiners, plotter.XY{X: x, Y: float64(len(period.Joiners))})
+		}
+		if len(period.Leavers) > 0 {
+			leavers = append(leavers, plotter.XY{X: x, Y: -float64(len(period.Leavers))})
+		}
+	}
+
+	teamLine, err := plotter.NewLine(team)
+	if err != nil {
+		return err
+	}
+	teamLine.Color = graphics.ColorPalette[0]
+	teamLine.Width = vg.Points(2)
+	activeLine, err := plotter.NewLine(active)
+	if err != nil {
+		return err
+	}
+	activeLine.Color = graphics.ColorPalette[1]
+	activeLine.Width = vg.Points(2)
+	activeLine.Dashes = []vg.Length{vg.Points(5), vg.Points(3)}
+	p.Add(teamLine, activeLine)
+	p.Legend.Add("Team", teamLine)
+	p.Legend.Add("Active contributors", activeLine)
+
+	for _, flow := range []struct {
+		label string
+		pts   plotter.XYs
+		color color.Color
+		shape draw.GlyphDrawer
+	}{
+		{"Joiners", joiners, teamJoinerColor, draw.TriangleGlyph{}},
+		{"Leavers", leavers, teamLeaverColor, draw.CrossGlyph{}},
+	} {
+		if len(flow.pts) == 0 {
+			continue
+		}
+		scatter, err := plotter.NewScatter(flow.pts)
+		if err != nil {
+			return err
+		}
+		scatter.GlyphStyle.Color = flow.color
+		scatter.GlyphStyle.Shape = flow.shape
+		scatter.GlyphStyle.Radius = vg.Points(4)
+		p.Add(scatter)
+		p.Legend.Add(flow.label, scatter)
+	}
+	p.Add(plotter.NewGrid())
+	p.Y.Min, p.Y.Max = -float64(peakLeavers)-0.5, float64(peak)+0.5
+	p.Legend.Top = true
+	if err := graphics.AddTimeAnnotations(p, graphics.ActiveAnnotations()); err != nil {
+		return err
+	}
+	graphics.AddReportChart(ctx, p)
+
+	width, height := graphics.GetPlotSize(graphics.ChartTypeWide)
+	if err := graphics.SavePlotWithFormat(p, width, height, output); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved team dynamics plot to %s\n", output)
+	}
+	return nil
+}
+
+// plotOrphanedCode draws the surviving lines and the part of them owned by departed developers
+func plotOrphanedCode(ctx context.Context, name string, history *TeamHistory, output string) error {
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("%s orphaned code %.1f%%", name, history.OrphanedShare*100)
+	p.X.Label.Text = "Date"
+	p.Y.Label.Text = "Lines of code"
+	p.X.Tick.Marker = &graphics.TimeTicker{Format: "2006-01-02"}
+
+	for _, area := range []struct {
+		label string
+		value func(OwnershipSample) int
+		color color.Color
+	}{
+		{"Surviving code", func(s OwnershipSample) int { return s.Total }, graphics.ColorPalette[0]},
+		{"Owned by departed developers", func(s OwnershipSample) int { return s.Orphaned }, teamLeaverColor},
+	} {
+		pts := make(plotter.XYs, 0, len(history.Ownership)+2)
+		pts = append(pts, plotter.XY{X: float64(history.Ownership[0].Date.Unix())})
+		for _, sample := range history.Ownership {
+			pts = append(pts, plotter.XY{X: float64(sample.Date.Unix()), Y: float64(area.value(sample))})
+		}
+		pts = append(pts, plotter.XY{X: float64(history.Ownership[len(history.Ownership)-1].Date.Unix())})
+		polygon, err := plotter.NewPolygon(pts)
+		if err != nil {
+			return err
+		}
+		polygon.Color = area.color
+		polygon.LineStyle.Width = 0
+		p.Add(polygon)
+		p.Legend.Add(area.label, polygon)
+	}
+	p.Legend.Top = true
+	p.Legend.Left = true
+	if err := graphics.AddTimeAnnotations(p, graphics.ActiveAnnotations()); err != nil {
+		return err
+	}
+	graphics.AddReportChart(ctx, p)
+
+	width, height := graphics.GetPlotSize(graphics.ChartTypeWide)
+	if err := graphics.SavePlotWithFormat(p, width, height, output); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Printf("Saved orphaned code plot to %s\n", output)
+	}
+	return nil
+}
+
+// printTeamDynamics lists the tenure of every developer and the joiners and leavers
+func printTeamDynamics(history *TeamHistory) {
+	fmt.Printf("\n%-30s %-10s %-10s %8s %12s %10s  %s\n",
+		"Developer", "First", "Last", "Tenure", "Substantial", "Surviving", "Status")
+	fmt.Println(strings.Repeat("-", 96))
+	departed := 0
+	for _, member := range history.Members {
+		status := "active"
+		if member.Departed {
+			status = "departed"
+			departed++
+		}
+		substantial := "never"
+		if member.DaysToSubstantial >= 0 {
+			substantial = fmt.Sprintf("%.0fd", member.DaysToSubstantial)
+		}
+		fmt.Printf("%-30s %-10s %-10s %7.0fd %12s %10d  %s\n",
+			truncateTeamName(strings.SplitN(member.Name, "|", 2)[0]),
+			member.FirstActive.Format("2006-01-02"), member.LastActive.Format("2006-01-02"),
+			member.TenureDays, substantial, member.SurvivingLines, status)
+	}
+	fmt.Printf("\n%d developers, %d departed (inactive for %d days)\n",
+		len(history.Members), departed, history.DepartureDays)
+	if len(history.Ownership) > 0 {
+		fmt.Printf("Orphaned code: %d lines (%.1f%% of the surviving code)\n",
+			history.OrphanedLines, history.OrphanedShare*100)
+	}
+	fmt.Println()
+}
+
+func truncateTeamName(name string) string {
+	if runes := []rune(name); len(runes) > 30 {
+		return string(runes[:29]) + "…"
+	}
+	return name
+}
+
+func saveTeamDynamicsAsJSON(output string, history *TeamHistory) error {
+	data := struct {
+		Type string `json:"type"`
+		*TeamHistory
+	}{
+		Type:        "team-dynamics",
+		TeamHistory: history,
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON output file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write JSON data: %v", err)
+	}
+
+	if !viper.GetBool("quiet") {
+		fmt.Printf("JSON data saved to %s\n", output)
+	}
+	return nil
+}
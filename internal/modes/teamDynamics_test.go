@@ -0,0 +1,190 @@
+package modes
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"labours-go/internal/burndown"
+	"labours-go/internal/readers"
+)
+
+// MockTeamReader serves the developer ticks and the people burndown of team-dynamics
+type MockTeamReader struct {
+	MockSentimentReader
+	devs   *readers.DeveloperTimeSeriesData
+	people []readers.PeopleBurndown
+}
+
+func (m *MockTeamReader) GetDeveloperTimeSeriesData() (*readers.DeveloperTimeSeriesData, error) {
+	if m.devs == nil {
+		return m.MockSentimentReader.GetDeveloperTimeSeriesData()
+	}
+	return m.devs, nil
+}
+
+func (m *MockTeamReader) GetPeopleBurndown() ([]readers.PeopleBurndown, error) {
+	return m.people, nil
+}
+
+func (m *MockTeamReader) GetBurndownParameters() (burndown.BurndownParameters, error) {
+	return burndown.BurndownParameters{Sampling: 30, Granularity: 30, TickSize: 86400}, nil
+}
+
+// teamBurndown is a two band people burndown of the given lines per 30 day sample
+func teamBurndown(name string, lines []int) readers.PeopleBurndown {
+	bands := [][]int{make([]int, len(lines)), make([]int, len(lines))}
+	for s, n := range lines {
+		bands[0][s] = n / 2
+		bands[1][s] = n - n/2
+	}
+	return readers.PeopleBurndown{Person: name, Matrix: bands}
+}
+
+// newMockTeamReader covers 300 daily ticks from 2024-01-01: alice stays from the start,
+// bob leaves on March 1st and carol joins on April 10th
+func newMockTeamReader() *MockTeamReader {
+	begin := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(lines int) readers.DevDay { return readers.DevDay{Commits: 1, LinesAdded: lines} }
+	return &MockTeamReader{
+		MockSentimentReader: MockSentimentReader{
+			begin: begin.Unix(),
+			end:   begin.AddDate(0, 0, 300).Unix(),
+		},
+		devs: &readers.DeveloperTimeSeriesData{
+			People:   []string{"alice|a@x", "bob|b@x", "carol|c@x"},
+			TickSize: 86400,
+			Days: map[int]map[int]readers.DevDay{
+				0:   {0: day(50)},
+				5:   {1: day(150)},
+				10:  {0: day(60)},
+				40:  {1: day(10)},
+				60:  {1: day(10)},
+				100: {2: day(10)},
+				250: {0: day(10)},
+				280: {2: day(10), -1: day(1000)},
+			},
+		},
+		people: []readers.PeopleBurndown{
+			teamBurndown("alice|a@x", []int{200, 200, 200, 200, 200, 200, 200, 200, 200, 200, 200}),
+			teamBurndown("Bob|b@x", []int{100, 100, 100, 100, 100, 100, 100, 100, 100, 100, 100}),
+			teamBurndown("carol|c@x", []int{0, 0, 0, 0, 50, 50, 50, 50, 50, 50, 50}),
+		},
+	}
+}
+
+func TestLoadTeamDynamics(t *testing.T) {
+	history, err := LoadTeamDynamics(context.Background(), newMockTeamReader(), "month")
+	if err != nil {
+		t.Fatalf("LoadTeamDynamics failed: %v", err)
+	}
+
+	if len(history.Members) != 3 {
+		t.Fatalf("expected 3 developers, got %+v", history.Members)
+	}
+	alice, bob, carol := history.Members[0], history.Members[1], history.Members[2]
+	if alice.Name != "alice|a@x" || alice.Departed || alice.DaysToSubstantial != 10 ||
+		alice.TenureDays != 251 || alice.SurvivingLines != 200 {
+		t.Errorf("unexpected alice: %+v", alice)
+	}
+	if !bob.Departed || bob.DaysToSubstantial != 0 || bob.LastTick != 60 ||
+		!bob.LastActive.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected bob: %+v", bob)
+	}
+	if carol.Departed || carol.DaysToSubstantial != -1 || carol.ActiveTicks != 2 || carol.Commits != 2 {
+		t.Errorf("unexpected carol: %+v", carol)
+	}
+
+	if len(history.Periods) != 10 {
+		t.Fatalf("expected monthly periods from January to October, got %d", len(history.Periods))
+	}
+	want := []struct {
+		active, team     int
+		joiners, leavers int
+	}{
+		{2, 2, 2, 0}, // January: alice and bob join
+		{1, 2, 0, 0}, // February: only bob commits
+		{1, 2, 0, 1}, // March: bob leaves
+		{1, 2, 1, 0}, // April: carol joins
+		{0, 2, 0, 0},
+	}
+	for i, w := range want {
+		period := history.Periods[i]
+		if period.Active != w.active || period.Team != w.team ||
+			len(period.Joiners) != w.joiners || len(period.Leavers) != w.leavers {
+			t.Errorf("period %s: got %+v, want %+v", period.Date.Format("2006-01"), period, w)
+		}
+	}
+	if history.Periods[2].Leavers[0] != "bob|b@x" || history.Periods[3].Joiners[0] != "carol|c@x" {
+		t.Errorf("unexpected joiners and leavers: %+v", history.Periods[:4])
+	}
+
+	// bob counts as departed 90 days after March 1st, from the sample of tick 150
+	if len(history.Ownership) != 11 {
+		t.Fatalf("expected 11 ownership samples, got %d", len(history.Ownership))
+	}
+	if history.Ownership[4].Orphaned != 0 || history.Ownership[5].Orphaned != 100 || history.Ownership[10].Total != 350 {
+		t.Errorf("unexpected ownership: %+v", history.Ownership)
+	}
+	if history.OrphanedLines != 100 || math.Abs(history.OrphanedShare-100.0/350) > 1e-9 {
+		t.Errorf("expected 100 orphaned lines, got %d (%g)", history.OrphanedLines, history.OrphanedShare)
+	}
+}
+
+func TestLoadTeamDynamicsWithoutBurndown(t *testing.T) {
+	reader := newMockTeamReader()
+	reader.people = nil
+
+	history, err := LoadTeamDynamics(context.Background(), reader, "year")
+	if err != nil {
+		t.Fatalf("LoadTeamDynamics failed: %v", err)
+	}
+	if len(history.Ownership) != 0 || history.OrphanedLines != 0 {
+		t.Errorf("expected no ownership without people burndown, got %+v", history.Ownership)
+	}
+	if len(history.Periods) != 1 || len(history.Periods[0].Joiners) != 3 || len(history.Periods[0].Leavers) != 1 {
+		t.Errorf("unexpected yearly period: %+v", history.Periods)
+	}
+
+	if _, err := LoadTeamDynamics(context.Background(), reader, "fortnight"); err == nil {
+		t.Error("expected an error for an unsupported resampling")
+	}
+}
+
+func TestTeamDynamics(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := TeamDynamics(context.Background(), newMockTeamReader(), tempDir, "month"); err != nil {
+		t.Fatalf("TeamDynamics failed: %v", err)
+	}
+	for _, filename := range []string{"team-dynamics.png", "team-dynamics_orphaned.png", "team-dynamics.json"} {
+		if _, err := os.Stat(filepath.Join(tempDir, filename)); err != nil {
+			t.Errorf("Expected output file %s was not created", filename)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "team-dynamics.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Type    string       `json:"type"`
+		Members []TeamMember `json:"members"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if report.Type != "team-dynamics" || len(report.Members) != 3 || !report.Members[1].Departed {
+		t.Errorf("unexpected JSON report: %+v", report)
+	}
+}
+
+func TestTeamDynamicsWithNoData(t *testing.T) {
+	if err := TeamDynamics(context.Background(), &MockTeamReader{}, t.TempDir(), "month"); err == nil {
+		t.Error("Expected error when no developer data is available, but got nil")
+	}
+}
@@ -1139,6 +1139,112 @@ func (x *ShotnessAnalysisResults) GetRecords() []*ShotnessRecord {
 	return nil
 }
 
+// Comment sentiment of a tick
+type Sentiment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         float32                `protobuf:"fixed32,1,opt,name=value,proto3" json:"value,omitempty"`     // Sentiment of the comments from 0 (positive) to 1 (negative)
+	Comments      []string               `protobuf:"bytes,2,rep,name=comments,proto3" json:"comments,omitempty"` // The comments written during the tick
+	Commits       []string               `protobuf:"bytes,3,rep,name=commits,proto3" json:"commits,omitempty"`   // Hashes of the commits which introduced the comments
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sentiment) Reset() {
+	*x = Sentiment{}
+	mi := &file_pb_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sentiment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sentiment) ProtoMessage() {}
+
+func (x *Sentiment) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sentiment.ProtoReflect.Descriptor instead.
+func (*Sentiment) Descriptor() ([]byte, []int) {
+	return file_pb_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Sentiment) GetValue() float32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Sentiment) GetComments() []string {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *Sentiment) GetCommits() []string {
+	if x != nil {
+		return x.Commits
+	}
+	return nil
+}
+
+// Comment sentiment analysis results
+type CommentSentimentResults struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SentimentByTick map[int32]*Sentiment   `protobuf:"bytes,1,rep,name=sentiment_by_tick,json=sentimentByTick,proto3" json:"sentiment_by_tick,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CommentSentimentResults) Reset() {
+	*x = CommentSentimentResults{}
+	mi := &file_pb_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentSentimentResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentSentimentResults) ProtoMessage() {}
+
+func (x *CommentSentimentResults) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentSentimentResults.ProtoReflect.Descriptor instead.
+func (*CommentSentimentResults) Descriptor() ([]byte, []int) {
+	return file_pb_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CommentSentimentResults) GetSentimentByTick() map[int32]*Sentiment {
+	if x != nil {
+		return x.SentimentByTick
+	}
+	return nil
+}
+
 // Comprehensive analysis results that can contain multiple analysis types
 type AnalysisResults struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
@@ -1151,7 +1257,7 @@ type AnalysisResults struct {
 
 func (x *AnalysisResults) Reset() {
 	*x = AnalysisResults{}
-	mi := &file_pb_proto_msgTypes[17]
+	mi := &file_pb_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1163,7 +1269,7 @@ func (x *AnalysisResults) String() string {
 func (*AnalysisResults) ProtoMessage() {}
 
 func (x *AnalysisResults) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_msgTypes[17]
+	mi := &file_pb_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1176,7 +1282,7 @@ func (x *AnalysisResults) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AnalysisResults.ProtoReflect.Descriptor instead.
 func (*AnalysisResults) Descriptor() ([]byte, []int) {
-	return file_pb_proto_rawDescGZIP(), []int{17}
+	return file_pb_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *AnalysisResults) GetHeader() *Metadata {
@@ -1299,7 +1405,16 @@ const file_pb_proto_rawDesc = "" +
 	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"G\n" +
 	"\x17ShotnessAnalysisResults\x12,\n" +
-	"\arecords\x18\x01 \x03(\v2\x12.pb.ShotnessRecordR\arecords\"\xb3\x01\n" +
+	"\arecords\x18\x01 \x03(\v2\x12.pb.ShotnessRecordR\arecords\"W\n" +
+	"\tSentiment\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x02R\x05value\x12\x1a\n" +
+	"\bcomments\x18\x02 \x03(\tR\bcomments\x12\x18\n" +
+	"\acommits\x18\x03 \x03(\tR\acommits\"\xca\x01\n" +
+	"\x17CommentSentimentResults\x12\\\n" +
+	"\x11sentiment_by_tick\x18\x01 \x03(\v20.pb.CommentSentimentResults.SentimentByTickEntryR\x0fsentimentByTick\x1aQ\n" +
+	"\x14SentimentByTickEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12#\n" +
+	"\x05value\x18\x02 \x01(\v2\r.pb.SentimentR\x05value:\x028\x01\"\xb3\x01\n" +
 	"\x0fAnalysisResults\x12$\n" +
 	"\x06header\x18\x01 \x01(\v2\f.pb.MetadataR\x06header\x12=\n" +
 	"\bcontents\x18\x02 \x03(\v2!.pb.AnalysisResults.ContentsEntryR\bcontents\x1a;\n" +
@@ -1319,7 +1434,7 @@ func file_pb_proto_rawDescGZIP() []byte {
 	return file_pb_proto_rawDescData
 }
 
-var file_pb_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_pb_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_pb_proto_goTypes = []any{
 	(*BurndownSparseMatrixRow)(nil),   // 0: pb.BurndownSparseMatrixRow
 	(*BurndownSparseMatrix)(nil),      // 1: pb.BurndownSparseMatrix
@@ -1338,15 +1453,18 @@ var file_pb_proto_goTypes = []any{
 	(*DevsAnalysisResults)(nil),       // 14: pb.DevsAnalysisResults
 	(*ShotnessRecord)(nil),            // 15: pb.ShotnessRecord
 	(*ShotnessAnalysisResults)(nil),   // 16: pb.ShotnessAnalysisResults
-	(*AnalysisResults)(nil),           // 17: pb.AnalysisResults
-	nil,                               // 18: pb.FilesOwnership.ValueEntry
-	nil,                               // 19: pb.Metadata.RunTimePerItemEntry
-	nil,                               // 20: pb.DeveloperStat.LanguagesEntry
-	nil,                               // 21: pb.DevTick.LanguagesEntry
-	nil,                               // 22: pb.TickDevs.DevsEntry
-	nil,                               // 23: pb.DevsAnalysisResults.TicksEntry
-	nil,                               // 24: pb.ShotnessRecord.CountersEntry
-	nil,                               // 25: pb.AnalysisResults.ContentsEntry
+	(*Sentiment)(nil),                 // 17: pb.Sentiment
+	(*CommentSentimentResults)(nil),   // 18: pb.CommentSentimentResults
+	(*AnalysisResults)(nil),           // 19: pb.AnalysisResults
+	nil,                               // 20: pb.FilesOwnership.ValueEntry
+	nil,                               // 21: pb.Metadata.RunTimePerItemEntry
+	nil,                               // 22: pb.DeveloperStat.LanguagesEntry
+	nil,                               // 23: pb.DevTick.LanguagesEntry
+	nil,                               // 24: pb.TickDevs.DevsEntry
+	nil,                               // 25: pb.DevsAnalysisResults.TicksEntry
+	nil,                               // 26: pb.ShotnessRecord.CountersEntry
+	nil,                               // 27: pb.CommentSentimentResults.SentimentByTickEntry
+	nil,                               // 28: pb.AnalysisResults.ContentsEntry
 }
 var file_pb_proto_depIdxs = []int32{
 	0,  // 0: pb.BurndownSparseMatrix.rows:type_name -> pb.BurndownSparseMatrixRow
@@ -1355,29 +1473,31 @@ var file_pb_proto_depIdxs = []int32{
 	1,  // 3: pb.BurndownAnalysisResults.people:type_name -> pb.BurndownSparseMatrix
 	3,  // 4: pb.BurndownAnalysisResults.people_interaction:type_name -> pb.CompressedSparseRowMatrix
 	4,  // 5: pb.BurndownAnalysisResults.files_ownership:type_name -> pb.FilesOwnership
-	18, // 6: pb.FilesOwnership.value:type_name -> pb.FilesOwnership.ValueEntry
-	19, // 7: pb.Metadata.run_time_per_item:type_name -> pb.Metadata.RunTimePerItemEntry
+	20, // 6: pb.FilesOwnership.value:type_name -> pb.FilesOwnership.ValueEntry
+	21, // 7: pb.Metadata.run_time_per_item:type_name -> pb.Metadata.RunTimePerItemEntry
 	3,  // 8: pb.Couples.matrix:type_name -> pb.CompressedSparseRowMatrix
 	6,  // 9: pb.CouplesAnalysisResults.file_couples:type_name -> pb.Couples
 	6,  // 10: pb.CouplesAnalysisResults.people_couples:type_name -> pb.Couples
 	7,  // 11: pb.CouplesAnalysisResults.people_files:type_name -> pb.TouchedFiles
-	20, // 12: pb.DeveloperStat.languages:type_name -> pb.DeveloperStat.LanguagesEntry
+	22, // 12: pb.DeveloperStat.languages:type_name -> pb.DeveloperStat.LanguagesEntry
 	11, // 13: pb.DevTick.stats:type_name -> pb.LineStats
-	21, // 14: pb.DevTick.languages:type_name -> pb.DevTick.LanguagesEntry
-	22, // 15: pb.TickDevs.devs:type_name -> pb.TickDevs.DevsEntry
-	23, // 16: pb.DevsAnalysisResults.ticks:type_name -> pb.DevsAnalysisResults.TicksEntry
-	24, // 17: pb.ShotnessRecord.counters:type_name -> pb.ShotnessRecord.CountersEntry
+	23, // 14: pb.DevTick.languages:type_name -> pb.DevTick.LanguagesEntry
+	24, // 15: pb.TickDevs.devs:type_name -> pb.TickDevs.DevsEntry
+	25, // 16: pb.DevsAnalysisResults.ticks:type_name -> pb.DevsAnalysisResults.TicksEntry
+	26, // 17: pb.ShotnessRecord.counters:type_name -> pb.ShotnessRecord.CountersEntry
 	15, // 18: pb.ShotnessAnalysisResults.records:type_name -> pb.ShotnessRecord
-	5,  // 19: pb.AnalysisResults.header:type_name -> pb.Metadata
-	25, // 20: pb.AnalysisResults.contents:type_name -> pb.AnalysisResults.ContentsEntry
-	11, // 21: pb.DevTick.LanguagesEntry.value:type_name -> pb.LineStats
-	12, // 22: pb.TickDevs.DevsEntry.value:type_name -> pb.DevTick
-	13, // 23: pb.DevsAnalysisResults.TicksEntry.value:type_name -> pb.TickDevs
-	24, // [24:24] is the sub-list for method output_type
-	24, // [24:24] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	27, // 19: pb.CommentSentimentResults.sentiment_by_tick:type_name -> pb.CommentSentimentResults.SentimentByTickEntry
+	5,  // 20: pb.AnalysisResults.header:type_name -> pb.Metadata
+	28, // 21: pb.AnalysisResults.contents:type_name -> pb.AnalysisResults.ContentsEntry
+	11, // 22: pb.DevTick.LanguagesEntry.value:type_name -> pb.LineStats
+	12, // 23: pb.TickDevs.DevsEntry.value:type_name -> pb.DevTick
+	13, // 24: pb.DevsAnalysisResults.TicksEntry.value:type_name -> pb.TickDevs
+	17, // 25: pb.CommentSentimentResults.SentimentByTickEntry.value:type_name -> pb.Sentiment
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_pb_proto_init() }
@@ -1391,7 +1511,7 @@ func file_pb_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pb_proto_rawDesc), len(file_pb_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   26,
+			NumMessages:   29,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1,27 +1,134 @@
 package progress
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/schollz/progressbar/v3"
 )
 
+// Progress formats accepted by --progress
+const (
+	FormatBar  = "bar"
+	FormatJSON = "json"
+	FormatNone = "none"
+)
+
+var (
+	format      = FormatBar
+	eventOutput io.Writer = os.Stderr
+	eventMutex  sync.Mutex
+
+	// barsSuppressed counts the active SuppressBars calls
+	barsSuppressed atomic.Int32
+)
+
+// Event is a progress event written as one JSON line in the JSON format
+type Event struct {
+	Time      time.Time `json:"time"`
+	Event     string    `json:"event"` // start, progress or finish
+	Operation string    `json:"operation"`
+	Detail    string    `json:"detail,omitempty"` // current step of a multi-operation
+	Step      int       `json:"step"`
+	Total     int       `json:"total"`
+	Elapsed   float64   `json:"elapsed_seconds"`
+	ETA       float64   `json:"eta_seconds"` // -1 while unknown
+}
+
+// SetFormat selects how progress is reported: bars on the terminal, JSON events on
+// stderr or not at all. JSON events are written even when the estimator is disabled,
+// since they were asked for explicitly.
+func SetFormat(f string) error {
+	switch f {
+	case FormatBar, FormatJSON, FormatNone:
+		format = f
+		return nil
+	}
+	return fmt.Errorf("unknown progress format '%s', available: %s, %s, %s", f, FormatBar, FormatJSON, FormatNone)
+}
+
+// SetEventOutput sets where JSON progress events are written, stderr by default
+func SetEventOutput(w io.Writer) {
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+	eventOutput = w
+}
+
+// SuppressBars hides the bars of the estimators created until the returned function is
+// called. Modes running concurrently would otherwise draw their bars over each other;
+// JSON events are still written.
+func SuppressBars() (restore func()) {
+	barsSuppressed.Add(1)
+	return func() {
+		barsSuppressed.Add(-1)
+	}
+}
+
 // ProgressEstimator provides estimation and tracking for long-running operations
 type ProgressEstimator struct {
 	enabled         bool
 	currentBar      *progressbar.ProgressBar
 	totalOperations int
 	currentOperation int
+
+	// State of the JSON event stream
+	operation   string
+	step, total int
+	started     time.Time
+	lastPercent int
 }
 
 // NewProgressEstimator creates a new progress estimator
 func NewProgressEstimator(enabled bool) *ProgressEstimator {
 	return &ProgressEstimator{
-		enabled: enabled,
+		enabled: enabled && format == FormatBar && barsSuppressed.Load() == 0,
 	}
 }
 
+// startEvents begins the JSON event stream of an operation
+func (pe *ProgressEstimator) startEvents(operation string, total int) {
+	pe.operation, pe.step, pe.total = operation, 0, total
+	pe.started, pe.lastPercent = time.Now(), 0
+	pe.emit("start", "")
+}
+
+// setStep records the progress of the current operation and emits an event whenever
+// another percent is done, so that long loops do not flood the stream
+func (pe *ProgressEstimator) setStep(step int) {
+	pe.step = step
+	percent := 100
+	if pe.total > 0 {
+		percent = step * 100 / pe.total
+	}
+	if percent > pe.lastPercent || step == pe.total {
+		pe.lastPercent = percent
+		pe.emit("progress", "")
+	}
+}
+
+func (pe *ProgressEstimator) emit(event, detail string) {
+	elapsed := time.Since(pe.started).Seconds()
+	eta := -1.0
+	if pe.step > 0 && pe.total >= pe.step {
+		eta = elapsed / float64(pe.step) * float64(pe.total-pe.step)
+	}
+	line, err := json.Marshal(Event{
+		Time: time.Now(), Event: event, Operation: pe.operation, Detail: detail,
+		Step: pe.step, Total: pe.total, Elapsed: elapsed, ETA: eta,
+	})
+	if err != nil {
+		return
+	}
+	eventMutex.Lock()
+	defer eventMutex.Unlock()
+	fmt.Fprintln(eventOutput, string(line))
+}
+
 // OperationType represents different types of operations with different cost weights
 type OperationType int
 
@@ -82,6 +189,10 @@ func (pe *ProgressEstimator) EstimateProcessingSteps(dataSize int, complexity Op
 
 // StartOperation begins a new operation with progress tracking
 func (pe *ProgressEstimator) StartOperation(operationName string, estimatedSteps int) {
+	if format == FormatJSON {
+		pe.startEvents(operationName, estimatedSteps)
+		return
+	}
 	if !pe.enabled {
 		return
 	}
@@ -103,6 +214,10 @@ func (pe *ProgressEstimator) StartOperation(operationName string, estimatedSteps
 
 // UpdateProgress updates the current operation's progress
 func (pe *ProgressEstimator) UpdateProgress(increment int) {
+	if format == FormatJSON && pe.operation != "" {
+		pe.setStep(pe.step + increment)
+		return
+	}
 	if !pe.enabled || pe.currentBar == nil {
 		return
 	}
@@ -111,6 +226,10 @@ func (pe *ProgressEstimator) UpdateProgress(increment int) {
 
 // SetProgress sets the absolute progress value
 func (pe *ProgressEstimator) SetProgress(current int) {
+	if format == FormatJSON && pe.operation != "" {
+		pe.setStep(current)
+		return
+	}
 	if !pe.enabled || pe.currentBar == nil {
 		return
 	}
@@ -119,6 +238,11 @@ func (pe *ProgressEstimator) SetProgress(current int) {
 
 // FinishOperation completes the current operation
 func (pe *ProgressEstimator) FinishOperation() {
+	if format == FormatJSON && pe.operation != "" {
+		pe.emit("finish", "")
+		pe.operation = ""
+		return
+	}
 	if !pe.enabled || pe.currentBar == nil {
 		return
 	}
@@ -128,6 +252,11 @@ func (pe *ProgressEstimator) FinishOperation() {
 
 // StartMultiOperation begins tracking multiple operations
 func (pe *ProgressEstimator) StartMultiOperation(totalOperations int, operationName string) {
+	if format == FormatJSON {
+		pe.totalOperations, pe.currentOperation = totalOperations, 0
+		pe.startEvents(operationName, totalOperations)
+		return
+	}
 	if !pe.enabled {
 		return
 	}
@@ -151,6 +280,13 @@ func (pe *ProgressEstimator) StartMultiOperation(totalOperations int, operationN
 
 // NextOperation moves to the next operation in a multi-operation sequence
 func (pe *ProgressEstimator) NextOperation(operationName string) {
+	if format == FormatJSON && pe.operation != "" {
+		// Every step of a multi-operation is reported, named after the step
+		pe.currentOperation++
+		pe.step = pe.currentOperation
+		pe.emit("progress", operationName)
+		return
+	}
 	if !pe.enabled {
 		return
 	}
@@ -166,6 +302,13 @@ func (pe *ProgressEstimator) NextOperation(operationName string) {
 
 // FinishMultiOperation completes the multi-operation sequence
 func (pe *ProgressEstimator) FinishMultiOperation() {
+	if format == FormatJSON && pe.operation != "" {
+		pe.step = pe.total
+		pe.emit("finish", "")
+		pe.operation = ""
+		pe.totalOperations, pe.currentOperation = 0, 0
+		return
+	}
 	if !pe.enabled || pe.currentBar == nil {
 		return
 	}
@@ -177,6 +320,9 @@ func (pe *ProgressEstimator) FinishMultiOperation() {
 
 // SimpleProgress creates a simple progress bar for quick operations
 func (pe *ProgressEstimator) SimpleProgress(description string, total int) *progressbar.ProgressBar {
+	if format != FormatBar || barsSuppressed.Load() > 0 {
+		return progressbar.DefaultSilent(int64(total))
+	}
 	if !pe.enabled {
 		return progressbar.NewOptions(total, progressbar.OptionClearOnFinish())
 	}
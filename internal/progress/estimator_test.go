@@ -0,0 +1,53 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSONEvents(t *testing.T) {
+	if err := SetFormat(FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	SetEventOutput(&buf)
+	defer func() {
+		SetFormat(FormatBar)
+		SetEventOutput(os.Stderr)
+	}()
+
+	// Events are written even when bars are disabled
+	pe := NewProgressEstimator(false)
+	pe.StartOperation("Reading data", 200)
+	for i := 0; i < 200; i++ {
+		pe.UpdateProgress(1)
+	}
+	pe.FinishOperation()
+
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	// start, one event per percent and finish
+	if len(events) != 102 {
+		t.Fatalf("expected 102 events, got %d", len(events))
+	}
+	if events[0].Event != "start" || events[0].Total != 200 || events[0].ETA != -1 {
+		t.Errorf("unexpected start event %+v", events[0])
+	}
+	last := events[len(events)-1]
+	if last.Event != "finish" || last.Step != 200 || last.Operation != "Reading data" || last.ETA != 0 {
+		t.Errorf("unexpected finish event %+v", last)
+	}
+
+	if err := SetFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,316 @@
+package readers
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+	"labours-go/internal/pb"
+)
+
+// herculesSource is implemented by the readers of hercules files. It provides the values
+// of the file that the Reader getters adjust for plotting or do not expose, so that
+// conversion keeps them.
+type herculesSource interface {
+	// releaseVersion returns the hercules release that wrote the file, 0 if unknown
+	releaseVersion() int
+	// burndownParameters returns the stored granularity, sampling and tick size in seconds
+	burndownParameters() (granularity, sampling int, tickSize float64, ok bool)
+	// filesOwnership returns the lines owned by each person in each file of the burndown
+	filesOwnership() []map[int]int
+	// filesLines returns the number of lines of each file of the file couples
+	filesLines() []int
+	// peopleFiles returns the files touched by each person as indexes of the file couples
+	peopleFiles() [][]int
+}
+
+// herculesResults are the analysis results of a reader as hercules protobuf messages;
+// sections without data are nil
+type herculesResults struct {
+	header    *pb.Metadata
+	burndown  *pb.BurndownAnalysisResults
+	couples   *pb.CouplesAnalysisResults
+	devs      *pb.DevsAnalysisResults
+	shotness  *pb.ShotnessAnalysisResults
+	sentiment *pb.CommentSentimentResults
+}
+
+// collectResults converts the data returned by the reader's getters into hercules messages
+func collectResults(reader Reader) (*herculesResults, error) {
+	source, _ := reader.(herculesSource)
+	results := &herculesResults{
+		header:    collectHeader(reader, source),
+		burndown:  collectBurndown(reader, source),
+		couples:   collectCouples(reader, source),
+		devs:      collectDevs(reader),
+		shotness:  collectShotness(reader),
+		sentiment: collectSentiment(reader),
+	}
+	if results.burndown == nil && results.couples == nil && results.devs == nil && results.shotness == nil &&
+		results.sentiment == nil {
+		return nil, fmt.Errorf("no analysis results to convert")
+	}
+	return results, nil
+}
+
+func collectHeader(reader Reader, source herculesSource) *pb.Metadata {
+	header := &pb.Metadata{Repository: reader.GetName()}
+	header.BeginUnixTime, header.EndUnixTime = reader.GetHeader()
+	if source != nil {
+		// The metadata version of Protobuf files is the format version, not a release
+		header.Version = int32(source.releaseVersion())
+	}
+	if metadata, err := reader.GetMetadata(); err == nil {
+		if source == nil {
+			header.Version = int32(metadata.Version)
+		}
+		header.Hash = metadata.HerculesHash
+		header.Commits = int32(metadata.Commits)
+		header.RunTime = metadata.RunTime.Milliseconds()
+	}
+	if stats, err := reader.GetRuntimeStats(); err == nil && len(stats) > 0 {
+		header.RunTimePerItem = stats
+	}
+	return header
+}
+
+func collectBurndown(reader Reader, source herculesSource) *pb.BurndownAnalysisResults {
+	// The burndown getters return bands x samples like Python labours; hercules stores
+	// samples x bands
+	_, project := reader.GetProjectBurndown()
+	files, filesErr := reader.GetFilesBurndown()
+	people, peopleErr := reader.GetPeopleBurndown()
+	if len(project) == 0 && filesErr != nil && peopleErr != nil {
+		return nil
+	}
+
+	result := &pb.BurndownAnalysisResults{}
+	if source != nil {
+		if granularity, sampling, tickSize, ok := source.burndownParameters(); ok {
+			result.Granularity, result.Sampling = int32(granularity), int32(sampling)
+			result.TickSize = int64(tickSize * 1e9)
+		}
+		for _, owned := range source.filesOwnership() {
+			ownership := &pb.FilesOwnership{Value: make(map[int32]int32, len(owned))}
+			for person, lines := range owned {
+				ownership.Value[int32(person)] = int32(lines)
+			}
+			result.FilesOwnership = append(result.FilesOwnership, ownership)
+		}
+	}
+	if result.Granularity == 0 {
+		if params, err := reader.GetBurndownParameters(); err == nil {
+			result.Granularity, result.Sampling = int32(params.Granularity), int32(params.Sampling)
+			result.TickSize = int64(params.TickSize * 1e9)
+		}
+	}
+
+	if len(project) > 0 {
+		result.Project = toBurndownSparseMatrix("project", transposeMatrix(project))
+	}
+	for _, file := range files {
+		result.Files = append(result.Files, toBurndownSparseMatrix(file.Filename, transposeMatrix(file.Matrix)))
+	}
+	for _, person := range people {
+		result.People = append(result.People, toBurndownSparseMatrix(person.Person, transposeMatrix(person.Matrix)))
+	}
+	if _, interaction, err := reader.GetPeopleInteraction(); err == nil && len(interaction) > 0 {
+		result.PeopleInteraction = toCompressedSparseRowMatrix(interaction)
+	}
+	return result
+}
+
+func collectCouples(reader Reader, source herculesSource) *pb.CouplesAnalysisResults {
+	files, filesMatrix, filesErr := reader.GetFileCooccurrence()
+	people, peopleMatrix, peopleErr := reader.GetPeopleCooccurrence()
+	if filesErr != nil && peopleErr != nil {
+		return nil
+	}
+
+	result := &pb.CouplesAnalysisResults{}
+	if filesErr == nil {
+		result.FileCouples = &pb.Couples{Index: files, Matrix: toCompressedSparseRowMatrix(filesMatrix)}
+	}
+	if peopleErr == nil {
+		result.PeopleCouples = &pb.Couples{Index: people, Matrix: toCompressedSparseRowMatrix(peopleMatrix)}
+	}
+	if source != nil {
+		for _, lines := range source.filesLines() {
+			result.FilesLines = append(result.FilesLines, int32(lines))
+		}
+		for _, touched := range source.peopleFiles() {
+			personFiles := &pb.TouchedFiles{Files: make([]int32, len(touched))}
+			for i, file := range touched {
+				personFiles.Files[i] = int32(file)
+			}
+			result.PeopleFiles = append(result.PeopleFiles, personFiles)
+		}
+	}
+	return result
+}
+
+func collectDevs(reader Reader) *pb.DevsAnalysisResults {
+	data, err := reader.GetDeveloperTimeSeriesData()
+	if err != nil || data == nil {
+		return nil
+	}
+	result := &pb.DevsAnalysisResults{
+		DevIndex: data.People,
+		Ticks:    make(map[int32]*pb.TickDevs, len(data.Days)),
+		TickSize: int64(data.TickSize * 1e9),
+	}
+	for tick, devs := range data.Days {
+		tickDevs := &pb.TickDevs{Devs: make(map[int32]*pb.DevTick, len(devs))}
+		for dev, day := range devs {
+			devTick := &pb.DevTick{
+				Commits: int32(day.Commits),
+				Stats: &pb.LineStats{Added: int32(day.LinesAdded), Removed: int32(day.LinesRemoved),
+					Changed: int32(day.LinesModified)},
+			}
+			if len(day.Languages) > 0 {
+				devTick.Languages = make(map[string]*pb.LineStats, len(day.Languages))
+				for language, stats := range day.Languages {
+					// hercules' YAML names the files without a detected language "none"
+					if language == noLanguage {
+						language = ""
+					}
+					lineStats := &pb.LineStats{}
+					for i, value := range []*int32{&lineStats.Added, &lineStats.Removed, &lineStats.Changed} {
+						if i < len(stats) {
+							*value = int32(stats[i])
+						}
+					}
+					devTick.Languages[language] = lineStats
+				}
+			}
+			tickDevs.Devs[int32(dev)] = devTick
+		}
+		result.Ticks[int32(tick)] = tickDevs
+	}
+	return result
+}
+
+// noLanguage is the YAML name of the language of files hercules did not detect
+const noLanguage = "none"
+
+func collectShotness(reader Reader) *pb.ShotnessAnalysisResults {
+	records, err := reader.GetShotnessRecords()
+	if err != nil || len(records) == 0 {
+		return nil
+	}
+	result := &pb.ShotnessAnalysisResults{Records: make([]*pb.ShotnessRecord, len(records))}
+	for i, record := range records {
+		result.Records[i] = &pb.ShotnessRecord{Type: record.Type, Name: record.Name, File: record.File,
+			Counters: record.Counters}
+	}
+	return result
+}
+
+func collectSentiment(reader Reader) *pb.CommentSentimentResults {
+	sentiment, err := reader.GetCommentSentiment()
+	if err != nil || len(sentiment) == 0 {
+		return nil
+	}
+	result := &pb.CommentSentimentResults{SentimentByTick: make(map[int32]*pb.Sentiment, len(sentiment))}
+	for tick, value := range sentiment {
+		result.SentimentByTick[int32(tick)] = &pb.Sentiment{Value: float32(value.Value), Comments: value.Comments,
+			Commits: value.Commits}
+	}
+	return result
+}
+
+// ToProtobuf converts the analysis results of a reader into the hercules protobuf format.
+// The header always declares the protobuf format version labours reads.
+func ToProtobuf(reader Reader) (*pb.AnalysisResults, error) {
+	results, err := collectResults(reader)
+	if err != nil {
+		return nil, err
+	}
+	header := results.header
+	header.Version = protobufFormatVersion
+	message := &pb.AnalysisResults{Header: header, Contents: map[string][]byte{}}
+	for _, section := range []struct {
+		name    string
+		present bool
+		message proto.Message
+	}{
+		{"Burndown", results.burndown != nil, results.burndown},
+		{"Couples", results.couples != nil, results.couples},
+		{"Devs", results.devs != nil, results.devs},
+		{"Shotness", results.shotness != nil, results.shotness},
+		{"Sentiment", results.sentiment != nil, results.sentiment},
+	} {
+		if !section.present {
+			continue
+		}
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(section.message)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding %s: %v", section.name, err)
+		}
+		message.Contents[section.name] = data
+	}
+	return message, nil
+}
+
+// WriteProtobuf writes the analysis results of a reader as a hercules protobuf file
+func WriteProtobuf(w io.Writer, reader Reader) error {
+	message, err := ToProtobuf(reader)
+	if err != nil {
+		return err
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("error encoding Protobuf: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("error writing Protobuf: %v", err)
+	}
+	return nil
+}
+
+// toBurndownSparseMatrix stores a samples x bands matrix like hercules: negative values
+// are clipped and the trailing zeros of every row are omitted
+func toBurndownSparseMatrix(name string, matrix [][]int) *pb.BurndownSparseMatrix {
+	result := &pb.BurndownSparseMatrix{Name: name, NumberOfRows: int32(len(matrix)),
+		Rows: make([]*pb.BurndownSparseMatrixRow, len(matrix))}
+	for y, row := range matrix {
+		result.NumberOfColumns = max(result.NumberOfColumns, int32(len(row)))
+		end := len(row)
+		for end > 0 && row[end-1] <= 0 {
+			end--
+		}
+		columns := make([]uint32, end)
+		for x, value := range row[:end] {
+			columns[x] = uint32(max(value, 0))
+		}
+		result.Rows[y] = &pb.BurndownSparseMatrixRow{Columns: columns}
+	}
+	return result
+}
+
+// toCompressedSparseRowMatrix stores the non-zero values of a dense matrix
+func toCompressedSparseRowMatrix(matrix [][]int) *pb.CompressedSparseRowMatrix {
+	result := &pb.CompressedSparseRowMatrix{NumberOfRows: int32(len(matrix)), Indptr: make([]int64, 1, len(matrix)+1)}
+	for _, row := range matrix {
+		result.NumberOfColumns = max(result.NumberOfColumns, int32(len(row)))
+		for x, value := range row {
+			if value != 0 {
+				result.Data = append(result.Data, int64(value))
+				result.Indices = append(result.Indices, int32(x))
+			}
+		}
+		result.Indptr = append(result.Indptr, int64(len(result.Data)))
+	}
+	return result
+}
+
+// sortedKeys returns the keys of a map with int keys in ascending order
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
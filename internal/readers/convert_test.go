@@ -0,0 +1,193 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"labours-go/internal/pb"
+)
+
+func readFile(t *testing.T, path string) Reader {
+	t.Helper()
+	reader, err := DetectAndReadInput(path, "auto")
+	require.NoError(t, err)
+	return reader
+}
+
+// convertToProtobuf writes the reader as Protobuf and reads the result back
+func convertToProtobuf(t *testing.T, reader Reader) *ProtobufReader {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, WriteProtobuf(&buf, reader))
+	converted := &ProtobufReader{}
+	require.NoError(t, converted.Read(&buf))
+	return converted
+}
+
+// convertToYAML writes the reader as YAML and reads the result back
+func convertToYAML(t *testing.T, reader Reader) (*YamlReader, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, WriteYAML(&buf, reader))
+	text := buf.String()
+	converted := &YamlReader{}
+	require.NoError(t, converted.Read(&buf))
+	return converted, text
+}
+
+func TestConvertBurndownExamples(t *testing.T) {
+	pbReader := readFile(t, "../../example_data/hercules_burndown.pb")
+	yamlReader := readFile(t, "../../example_data/hercules_burndown.yaml")
+	_, project := pbReader.GetProjectBurndown()
+	require.NotEmpty(t, project)
+
+	for name, reader := range map[string]Reader{"pb": pbReader, "yaml": yamlReader} {
+		t.Run(name, func(t *testing.T) {
+			for format, converted := range map[string]Reader{
+				"pb":   convertToProtobuf(t, reader),
+				"yaml": func() Reader { r, _ := convertToYAML(t, reader); return r }(),
+			} {
+				_, convertedProject := converted.GetProjectBurndown()
+				assert.Equal(t, project, convertedProject, format)
+				assert.Equal(t, reader.GetName(), converted.GetName(), format)
+				begin, end := reader.GetHeader()
+				convertedBegin, convertedEnd := converted.GetHeader()
+				assert.Equal(t, begin, convertedBegin, format)
+				assert.Equal(t, end, convertedEnd, format)
+			}
+		})
+	}
+}
+
+func TestConvertProtobufRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("../../example_data/hercules_burndown.pb")
+	require.NoError(t, err)
+	original := &pb.AnalysisResults{}
+	require.NoError(t, proto.Unmarshal(data, original))
+
+	// Through YAML and back again keeps every section of the original file
+	yamlReader, _ := convertToYAML(t, readFile(t, "../../example_data/hercules_burndown.pb"))
+	converted, err := ToProtobuf(yamlReader)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Header.Repository, converted.Header.Repository)
+	assert.Equal(t, original.Header.Hash, converted.Header.Hash)
+	assert.Equal(t, original.Header.Version, converted.Header.Version)
+	assert.Equal(t, original.Header.Commits, converted.Header.Commits)
+	assert.Equal(t, original.Header.RunTime, converted.Header.RunTime)
+	assert.Equal(t, original.Header.RunTimePerItem, converted.Header.RunTimePerItem)
+	require.Len(t, converted.Contents, len(original.Contents))
+	for name, section := range original.Contents {
+		var want, got pb.BurndownAnalysisResults
+		require.Equal(t, "Burndown", name)
+		require.NoError(t, proto.Unmarshal(section, &want))
+		require.NoError(t, proto.Unmarshal(converted.Contents[name], &got))
+		assert.True(t, proto.Equal(&want, &got), "%v\n%v", &want, &got)
+	}
+}
+
+func TestConvertYAMLRoundTrip(t *testing.T) {
+	for _, path := range []string{
+		"../../example_data/hercules_burndown.yaml",
+		"../../data/labours-go_burndown.yaml",
+		"../../data/labours-go_couples.yaml",
+		"../../data/labours-go_devs.yaml",
+	} {
+		t.Run(path, func(t *testing.T) {
+			reader := readFile(t, path)
+			converted, _ := convertToYAML(t, convertToProtobuf(t, reader))
+
+			params, err := reader.GetBurndownParameters()
+			convertedParams, convertedErr := converted.GetBurndownParameters()
+			assert.Equal(t, err == nil, convertedErr == nil)
+			assert.Equal(t, params, convertedParams)
+			metadata, _ := reader.GetMetadata()
+			convertedMetadata, _ := converted.GetMetadata()
+			assert.Equal(t, metadata, convertedMetadata)
+
+			files, _ := reader.GetFilesBurndown()
+			convertedFiles, _ := converted.GetFilesBurndown()
+			assert.Equal(t, files, convertedFiles)
+			people, ownership, _ := reader.GetOwnershipBurndown()
+			convertedPeople, convertedOwnership, _ := converted.GetOwnershipBurndown()
+			assert.Equal(t, people, convertedPeople)
+			assert.Equal(t, ownership, convertedOwnership)
+			_, interaction, _ := reader.GetPeopleInteraction()
+			_, convertedInteraction, _ := converted.GetPeopleInteraction()
+			assert.Equal(t, interaction, convertedInteraction)
+			assert.Equal(t, reader.(*YamlReader).filesOwnership(), converted.filesOwnership())
+
+			fileIndex, fileMatrix, _ := reader.GetFileCooccurrence()
+			convertedIndex, convertedMatrix, _ := converted.GetFileCooccurrence()
+			assert.Equal(t, fileIndex, convertedIndex)
+			assert.Equal(t, fileMatrix, convertedMatrix)
+			assert.Equal(t, reader.(*YamlReader).filesLines(), converted.filesLines())
+			assert.Equal(t, reader.(*YamlReader).peopleFiles(), converted.peopleFiles())
+
+			devs, _ := reader.GetDeveloperTimeSeriesData()
+			convertedDevs, _ := converted.GetDeveloperTimeSeriesData()
+			assert.Equal(t, devs, convertedDevs)
+		})
+	}
+}
+
+func TestConvertDevsLanguages(t *testing.T) {
+	reader := readFile(t, "../../data/labours-go_devs.yaml")
+	message, err := ToProtobuf(reader)
+	require.NoError(t, err)
+	devs := &pb.DevsAnalysisResults{}
+	require.NoError(t, proto.Unmarshal(message.Contents["Devs"], devs))
+
+	// hercules stores the files without a language under "" in Protobuf and "none" in YAML
+	languages := map[string]bool{}
+	for _, tick := range devs.Ticks {
+		for _, dev := range tick.Devs {
+			for language := range dev.Languages {
+				languages[language] = true
+			}
+		}
+	}
+	assert.NotContains(t, languages, noLanguage)
+	_, text := convertToYAML(t, convertToProtobuf(t, reader))
+	assert.NotContains(t, text, `"": [`)
+}
+
+func TestConvertSentiment(t *testing.T) {
+	reader, err := readYAML(t, yamlSchemaDocument)
+	require.NoError(t, err)
+	sentiment, err := reader.GetCommentSentiment()
+	require.NoError(t, err)
+
+	converted := convertToProtobuf(t, reader)
+	convertedSentiment, err := converted.GetCommentSentiment()
+	require.NoError(t, err)
+	assert.Equal(t, sentiment, convertedSentiment)
+
+	yamlReader, text := convertToYAML(t, converted)
+	assert.Contains(t, text, "Sentiment:\n  4: [0.6250, [abc123,def456], \"nice|fine\"]\n")
+	yamlSentiment, err := yamlReader.GetCommentSentiment()
+	require.NoError(t, err)
+	assert.Equal(t, sentiment, yamlSentiment)
+}
+
+func TestWriteYAMLMatrix(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writeYAMLMatrix(w, 2, `"project"`, [][]int{{1200, 0}, {7, 35}})
+	writeYAMLMatrix(w, 4, `"empty"`, nil)
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "  \"project\": |-\n"+
+		"    1200    0\n"+
+		"       7   35\n"+
+		"    \"empty\": \"\"\n", buf.String())
+}
+
+func TestConvertNoResults(t *testing.T) {
+	_, err := ToProtobuf(&YamlReader{})
+	assert.EqualError(t, err, "no analysis results to convert")
+}
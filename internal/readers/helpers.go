@@ -40,7 +40,7 @@ func DetectAndReadInput(input string, format string) (Reader, error) {
 
 	// Read the input using the Reader
 	if err := reader.Read(file); err != nil {
-		return nil, fmt.Errorf("error reading input with %s reader: %v", format, err)
+		return nil, fmt.Errorf("error reading input with %s reader: %w", format, err)
 	}
 
 	return reader, nil
@@ -0,0 +1,450 @@
+package readers
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PeopleMap resolves developer aliases (names and emails) to a canonical identity.
+// Hercules joins all known names and emails of a developer with "|", e.g.
+// "alice|alice@corp.com", so an identity is resolved by looking up each part.
+type PeopleMap struct {
+	aliases map[string]string // lowercased alias -> canonical name
+	pairs   map[string]string // lowercased "name\x00email" -> canonical name
+}
+
+// NewPeopleMap creates an empty people map.
+func NewPeopleMap() *PeopleMap {
+	return &PeopleMap{aliases: make(map[string]string), pairs: make(map[string]string)}
+}
+
+// Add registers an alias for the given canonical name.
+func (m *PeopleMap) Add(canonical, alias string) {
+	alias = strings.ToLower(strings.TrimSpace(alias))
+	if alias == "" {
+		return
+	}
+	m.aliases[alias] = canonical
+}
+
+// AddPair registers a name and an email that resolve to the canonical name only when an
+// identity has both, like the commit name and email of a four-field mailmap entry.
+func (m *PeopleMap) AddPair(canonical, name, email string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	email = strings.ToLower(strings.TrimSpace(email))
+	if name == "" || email == "" {
+		return
+	}
+	m.pairs[name+"\x00"+email] = canonical
+}
+
+// Len returns the number of registered aliases.
+func (m *PeopleMap) Len() int {
+	return len(m.aliases) + len(m.pairs)
+}
+
+// Resolve returns the canonical name for a hercules identity, or the identity itself
+// if none of its parts is a known alias.
+func (m *PeopleMap) Resolve(identity string) string {
+	if m == nil {
+		return identity
+	}
+	parts := strings.Split(identity, "|")
+	for i := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(parts[i]))
+	}
+	for _, name := range parts {
+		for _, email := range parts {
+			if canonical, ok := m.pairs[name+"\x00"+email]; ok {
+				return canonical
+			}
+		}
+	}
+	if canonical, ok := m.aliases[strings.ToLower(strings.TrimSpace(identity))]; ok {
+		return canonical
+	}
+	for _, part := range parts {
+		if canonical, ok := m.aliases[part]; ok {
+			return canonical
+		}
+	}
+	return identity
+}
+
+// LoadPeopleMap loads a people map from a file. Files with a .yaml/.yml extension are
+// parsed as YAML ("canonical: [alias, ...]"), everything else as a git mailmap.
+func LoadPeopleMap(path string) (*PeopleMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening people map %s: %v", path, err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParsePeopleMapYAML(file)
+	default:
+		return ParseMailmap(file)
+	}
+}
+
+// ParsePeopleMapYAML parses a YAML people map of the form
+//
+//	Alice Smith:
+//	  - alice
+//	  - alice@corp.com
+func ParsePeopleMapYAML(r io.Reader) (*PeopleMap, error) {
+	var raw map[string][]string
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error decoding people map YAML: %v", err)
+	}
+
+	m := NewPeopleMap()
+	for canonical, aliases := range raw {
+		m.Add(canonical, canonical)
+		for _, alias := range aliases {
+			m.Add(canonical, alias)
+		}
+	}
+	return m, nil
+}
+
+// ParseMailmap parses a git mailmap. All forms documented in gitmailmap(5) are supported:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+//
+// The canonical name is the proper name, or the proper email if no name is given. Like
+// git, the last form only matches commits with both the commit name and email.
+func ParseMailmap(r io.Reader) (*PeopleMap, error) {
+	m := NewPeopleMap()
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		names, emails, err := splitMailmapLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("mailmap line %d: %v", lineNo, err)
+		}
+
+		properName := names[0]
+		canonical := properName
+		if canonical == "" {
+			canonical = emails[0]
+		}
+		if properName != "" {
+			m.Add(canonical, properName)
+		}
+		m.Add(canonical, emails[0])
+		if len(emails) > 1 {
+			if len(names) > 1 && names[1] != "" {
+				m.AddPair(canonical, names[1], emails[1])
+			} else {
+				m.Add(canonical, emails[1])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading mailmap: %v", err)
+	}
+	return m, nil
+}
+
+// splitMailmapLine splits a mailmap line into the names preceding each <email> and the emails.
+func splitMailmapLine(line string) (names []string, emails []string, err error) {
+	rest := line
+	for {
+		open := strings.Index(rest, "<")
+		if open < 0 {
+			break
+		}
+		closing := strings.Index(rest[open:], ">")
+		if closing < 0 {
+			return nil, nil, fmt.Errorf("unterminated email in %q", line)
+		}
+		names = append(names, strings.TrimSpace(rest[:open]))
+		emails = append(emails, strings.TrimSpace(rest[open+1:open+closing]))
+		rest = rest[open+closing+1:]
+	}
+	if len(emails) == 0 || len(emails) > 2 {
+		return nil, nil, fmt.Errorf("expected one or two <email> entries in %q", line)
+	}
+	return names, emails, nil
+}
+
+// Pseudonym returns a stable pseudonym for a developer name, suitable for sharing reports.
+func Pseudonym(name string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(name))))
+	return "dev-" + hex.EncodeToString(sum[:4])
+}
+
+// IdentityReader wraps a Reader and merges developer identities in every people-based
+// getter: matrices are summed and indices remapped consistently across people burndown,
+// ownership, people interaction, people co-occurrence and devs ticks.
+type IdentityReader struct {
+	Reader
+	resolve func(string) string
+}
+
+// NewIdentityReader wraps base so that identities are merged through peopleMap (may be nil)
+// and, if anonymize is set, replaced with stable pseudonyms.
+func NewIdentityReader(base Reader, peopleMap *PeopleMap, anonymize bool) *IdentityReader {
+	return &IdentityReader{
+		Reader: base,
+		resolve: func(identity string) string {
+			name := peopleMap.Resolve(identity)
+			if anonymize {
+				return Pseudonym(name)
+			}
+			return name
+		},
+	}
+}
+
+// identityGroups maps each name to a group index, keeping groups in first-appearance order.
+func identityGroups(names []string, resolve func(string) string) ([]string, []int) {
+	var groups []string
+	groupIndex := make(map[string]int)
+	mapping := make([]int, len(names))
+	for i, name := range names {
+		resolved := resolve(name)
+		idx, exists := groupIndex[resolved]
+		if !exists {
+			idx = len(groups)
+			groupIndex[resolved] = idx
+			groups = append(groups, resolved)
+		}
+		mapping[i] = idx
+	}
+	return groups, mapping
+}
+
+// addMatrix adds src into dst element-wise, growing dst as needed.
+func addMatrix(dst, src [][]int) [][]int {
+	for len(dst) < len(src) {
+		dst = append(dst, nil)
+	}
+	for i, row := range src {
+		if len(dst[i]) < len(row) {
+			grown := make([]int, len(row))
+			copy(grown, dst[i])
+			dst[i] = grown
+		}
+		for j, val := range row {
+			dst[i][j] += val
+		}
+	}
+	return dst
+}
+
+// remapIndex maps an index of the original people axis to the merged axis. Indices past the
+// people list (extra rows/columns hercules appends) are kept after the merged groups.
+func remapIndex(idx int, mapping []int, numGroups int) int {
+	if idx < len(mapping) {
+		return mapping[idx]
+	}
+	return numGroups + idx - len(mapping)
+}
+
+// GetPeopleBurndown merges the burndown matrices of aliased developers.
+func (r *IdentityReader) GetPeopleBurndown() ([]PeopleBurndown, error) {
+	people, err := r.Reader.GetPeopleBurndown()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(people))
+	for i, person := range people {
+		names[i] = person.Person
+	}
+	groups, mapping := identityGroups(names, r.resolve)
+
+	merged := make([]PeopleBurndown, len(groups))
+	for i, group := range groups {
+		merged[i].Person = group
+	}
+	for i, person := range people {
+		merged[mapping[i]].Matrix = addMatrix(merged[mapping[i]].Matrix, person.Matrix)
+	}
+	return merged, nil
+}
+
+// GetOwnershipBurndown merges the ownership matrices of aliased developers.
+func (r *IdentityReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) {
+	sequence, ownership, err := r.Reader.GetOwnershipBurndown()
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, mapping := identityGroups(sequence, r.resolve)
+
+	merged := make(map[string][][]int, len(groups))
+	for i, name := range sequence {
+		group := groups[mapping[i]]
+		merged[group] = addMatrix(merged[group], ownership[name])
+	}
+	return groups, merged, nil
+}
+
+// GetPeopleInteraction merges rows and columns of the overwrites matrix.
+func (r *IdentityReader) GetPeopleInteraction() ([]string, [][]int, error) {
+	people, matrix, err := r.Reader.GetPeopleInteraction()
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, mapping := identityGroups(people, r.resolve)
+	return groups, mergeInteractionMatrix(matrix, mapping, len(groups)), nil
+}
+
+// GetPeopleCooccurrence merges rows and columns of the people co-occurrence matrix.
+func (r *IdentityReader) GetPeopleCooccurrence() ([]string, [][]int, error) {
+	people, matrix, err := r.Reader.GetPeopleCooccurrence()
+	if err != nil {
+		return nil, nil, err
+	}
+	groups, mapping := identityGroups(people, r.resolve)
+	return groups, mergeCooccurrenceMatrix(matrix, mapping, len(groups)), nil
+}
+
+// mergeInteractionMatrix merges rows and columns of a people interaction matrix according to
+// mapping. Hercules stores it as people x (people + 2), the first two columns being totals
+// which are summed as-is.
+func mergeInteractionMatrix(matrix [][]int, mapping []int, numGroups int) [][]int {
+	offset := 0
+	if len(matrix) > 0 && len(matrix[0]) == len(mapping)+2 {
+		offset = 2
+	}
+	merged := make([][]int, numGroups)
+	for i := range merged {
+		merged[i] = make([]int, numGroups+offset)
+	}
+	for i, row := range matrix {
+		if i >= len(mapping) {
+			break
+		}
+		for j, val := range row {
+			col := j
+			if j >= offset {
+				col = offset + remapIndex(j-offset, mapping, numGroups)
+			}
+			if col < len(merged[mapping[i]]) {
+				merged[mapping[i]][col] += val
+			}
+		}
+	}
+	return merged
+}
+
+// mergeCooccurrenceMatrix merges rows and columns of a square people matrix according to
+// mapping, keeping any extra trailing rows hercules appends.
+func mergeCooccurrenceMatrix(matrix [][]int, mapping []int, numGroups int) [][]int {
+	size := numGroups
+	if extra := len(matrix) - len(mapping); extra > 0 {
+		size += extra
+	}
+	merged := make([][]int, size)
+	for i := range merged {
+		merged[i] = make([]int, size)
+	}
+	for i, row := range matrix {
+		mi := remapIndex(i, mapping, numGroups)
+		for j, val := range row {
+			mj := remapIndex(j, mapping, numGroups)
+			if mi < size && mj < size {
+				merged[mi][mj] += val
+			}
+		}
+	}
+	return merged
+}
+
+// GetDeveloperStats merges the aggregated statistics of aliased developers.
+func (r *IdentityReader) GetDeveloperStats() ([]DeveloperStat, error) {
+	stats, err := r.Reader.GetDeveloperStats()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(stats))
+	for i, stat := range stats {
+		names[i] = stat.Name
+	}
+	groups, mapping := identityGroups(names, r.resolve)
+
+	merged := make([]DeveloperStat, len(groups))
+	for i, group := range groups {
+		merged[i] = DeveloperStat{Name: group, Languages: make(map[string]int)}
+	}
+	for i, stat := range stats {
+		m := &merged[mapping[i]]
+		m.Commits += stat.Commits
+		m.LinesAdded += stat.LinesAdded
+		m.LinesRemoved += stat.LinesRemoved
+		m.LinesModified += stat.LinesModified
+		m.FilesTouched += stat.FilesTouched
+		for lang, lines := range stat.Languages {
+			m.Languages[lang] += lines
+		}
+	}
+	return merged, nil
+}
+
+// GetDeveloperTimeSeriesData remaps developer indices in every tick and sums merged activity.
+func (r *IdentityReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error) {
+	data, err := r.Reader.GetDeveloperTimeSeriesData()
+	if err != nil || data == nil {
+		return data, err
+	}
+	groups, mapping := identityGroups(data.People, r.resolve)
+
+	days := make(map[int]map[int]DevDay, len(data.Days))
+	for tick, devs := range data.Days {
+		merged := make(map[int]DevDay, len(devs))
+		for devIdx, day := range devs {
+			idx := remapIndex(devIdx, mapping, len(groups))
+			merged[idx] = mergeDevDays(merged[idx], day)
+		}
+		days[tick] = merged
+	}
+	return &DeveloperTimeSeriesData{People: groups, Days: days, TickSize: data.TickSize}, nil
+}
+
+// mergeDevDays sums two DevDay records including their per-language statistics.
+func mergeDevDays(a, b DevDay) DevDay {
+	result := DevDay{
+		Commits:       a.Commits + b.Commits,
+		LinesAdded:    a.LinesAdded + b.LinesAdded,
+		LinesRemoved:  a.LinesRemoved + b.LinesRemoved,
+		LinesModified: a.LinesModified + b.LinesModified,
+		Languages:     make(map[string][]int),
+	}
+	for _, langs := range []map[string][]int{a.Languages, b.Languages} {
+		for lang, stats := range langs {
+			sum := result.Languages[lang]
+			for len(sum) < len(stats) {
+				sum = append(sum, 0)
+			}
+			for i, val := range stats {
+				sum[i] += val
+			}
+			result.Languages[lang] = sum
+		}
+	}
+	return result
+}
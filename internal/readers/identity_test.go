@@ -0,0 +1,164 @@
+package readers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityTestReader provides people data for identity merging tests
+type identityTestReader struct {
+	Reader
+}
+
+func (r *identityTestReader) GetPeopleBurndown() ([]PeopleBurndown, error) {
+	return []PeopleBurndown{
+		{Person: "alice|alice@home.org", Matrix: [][]int{{1, 2}, {3, 4}}},
+		{Person: "bob|bob@corp.com", Matrix: [][]int{{5, 6}, {7, 8}}},
+		{Person: "Alice Smith|alice@corp.com", Matrix: [][]int{{10, 20}, {30, 40}}},
+	}, nil
+}
+
+func (r *identityTestReader) GetPeopleInteraction() ([]string, [][]int, error) {
+	people := []string{"alice|alice@home.org", "bob|bob@corp.com", "Alice Smith|alice@corp.com"}
+	matrix := [][]int{
+		{100, 1, 10, 2, 3},
+		{200, 4, 5, 20, 6},
+		{300, 7, 8, 9, 30},
+	}
+	return people, matrix, nil
+}
+
+func (r *identityTestReader) GetPeopleCooccurrence() ([]string, [][]int, error) {
+	people := []string{"alice|alice@home.org", "bob|bob@corp.com", "Alice Smith|alice@corp.com"}
+	matrix := [][]int{
+		{1, 2, 3, 4},
+		{2, 5, 6, 7},
+		{3, 6, 8, 9},
+		{4, 7, 9, 10},
+	}
+	return people, matrix, nil
+}
+
+func (r *identityTestReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error) {
+	return &DeveloperTimeSeriesData{
+		People: []string{"alice|alice@home.org", "bob|bob@corp.com", "Alice Smith|alice@corp.com"},
+		Days: map[int]map[int]DevDay{
+			0: {
+				0: {Commits: 1, LinesAdded: 10, Languages: map[string][]int{"Go": {10, 0, 0}}},
+				2: {Commits: 2, LinesAdded: 20, Languages: map[string][]int{"Go": {20, 1, 0}}},
+			},
+			1: {
+				1: {Commits: 3, LinesRemoved: 5, Languages: map[string][]int{}},
+			},
+		},
+	}, nil
+}
+
+const testMailmap = `# comment line
+Alice Smith <alice@corp.com> <alice@home.org>
+Bob <bob@corp.com>
+`
+
+func TestParseMailmap(t *testing.T) {
+	peopleMap, err := ParseMailmap(strings.NewReader(testMailmap))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Alice Smith", peopleMap.Resolve("alice|alice@home.org"))
+	assert.Equal(t, "Alice Smith", peopleMap.Resolve("ALICE@CORP.COM"))
+	assert.Equal(t, "Bob", peopleMap.Resolve("bob|bob@corp.com"))
+	assert.Equal(t, "carol|carol@corp.com", peopleMap.Resolve("carol|carol@corp.com"))
+
+	_, err = ParseMailmap(strings.NewReader("Broken <unterminated\n"))
+	assert.Error(t, err)
+}
+
+func TestParseMailmapCommitNameAndEmail(t *testing.T) {
+	peopleMap, err := ParseMailmap(strings.NewReader("Alice Smith <alice@corp.com> alice <alice@home.org>\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Alice Smith", peopleMap.Resolve("alice|alice@home.org"))
+	assert.Equal(t, "Alice Smith", peopleMap.Resolve("Alice Smith|alice@corp.com"))
+	// Another author named alice, or alice@home.org under another name, is not merged
+	assert.Equal(t, "alice|alice@elsewhere.org", peopleMap.Resolve("alice|alice@elsewhere.org"))
+	assert.Equal(t, "bob|alice@home.org", peopleMap.Resolve("bob|alice@home.org"))
+}
+
+func TestParsePeopleMapYAML(t *testing.T) {
+	peopleMap, err := ParsePeopleMapYAML(strings.NewReader("Alice Smith:\n  - alice\n  - alice@home.org\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Alice Smith", peopleMap.Resolve("alice|alice@home.org"))
+	assert.Equal(t, "Alice Smith", peopleMap.Resolve("Alice Smith|alice@corp.com"))
+}
+
+func TestIdentityReaderMergesPeople(t *testing.T) {
+	peopleMap, err := ParseMailmap(strings.NewReader(testMailmap))
+	require.NoError(t, err)
+	reader := NewIdentityReader(&identityTestReader{}, peopleMap, false)
+
+	t.Run("PeopleBurndown", func(t *testing.T) {
+		people, err := reader.GetPeopleBurndown()
+		require.NoError(t, err)
+		require.Len(t, people, 2)
+		assert.Equal(t, "Alice Smith", people[0].Person)
+		assert.Equal(t, [][]int{{11, 22}, {33, 44}}, people[0].Matrix)
+		assert.Equal(t, "Bob", people[1].Person)
+		assert.Equal(t, [][]int{{5, 6}, {7, 8}}, people[1].Matrix)
+	})
+
+	t.Run("PeopleInteraction", func(t *testing.T) {
+		people, matrix, err := reader.GetPeopleInteraction()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alice Smith", "Bob"}, people)
+		// Columns: self, unknown, Alice Smith, Bob
+		assert.Equal(t, [][]int{
+			{400, 8, 10 + 3 + 8 + 30, 2 + 9},
+			{200, 4, 5 + 6, 20},
+		}, matrix)
+	})
+
+	t.Run("PeopleCooccurrence", func(t *testing.T) {
+		people, matrix, err := reader.GetPeopleCooccurrence()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alice Smith", "Bob"}, people)
+		// The extra trailing row/column is preserved after the merged people
+		assert.Equal(t, [][]int{
+			{1 + 3 + 3 + 8, 2 + 6, 4 + 9},
+			{2 + 6, 5, 7},
+			{4 + 9, 7, 10},
+		}, matrix)
+	})
+
+	t.Run("DeveloperTimeSeries", func(t *testing.T) {
+		data, err := reader.GetDeveloperTimeSeriesData()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alice Smith", "Bob"}, data.People)
+		require.Len(t, data.Days[0], 1)
+		assert.Equal(t, 3, data.Days[0][0].Commits)
+		assert.Equal(t, 30, data.Days[0][0].LinesAdded)
+		assert.Equal(t, []int{30, 1, 0}, data.Days[0][0].Languages["Go"])
+		assert.Equal(t, 3, data.Days[1][1].Commits)
+	})
+}
+
+func TestIdentityReaderAnonymize(t *testing.T) {
+	reader := NewIdentityReader(&identityTestReader{}, nil, true)
+
+	people, err := reader.GetPeopleBurndown()
+	require.NoError(t, err)
+	require.Len(t, people, 3)
+	for _, person := range people {
+		assert.True(t, strings.HasPrefix(person.Person, "dev-"), "expected pseudonym, got %s", person.Person)
+	}
+
+	// Pseudonyms must be stable across getters so that charts can be cross-referenced
+	names, _, err := reader.GetPeopleInteraction()
+	require.NoError(t, err)
+	for i, person := range people {
+		assert.Equal(t, person.Person, names[i])
+	}
+	assert.Equal(t, Pseudonym("Alice Smith"), Pseudonym(" alice smith "))
+}
@@ -3,6 +3,7 @@ package readers
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/spf13/viper"
 	"google.golang.org/protobuf/proto"
@@ -59,6 +60,28 @@ func (r *ProtobufReader) GetHeader() (int64, int64) {
 	return 0, 0
 }
 
+// GetMetadata retrieves the description of the hercules run from the Protobuf header
+func (r *ProtobufReader) GetMetadata() (Metadata, error) {
+	header := r.data.Header
+	if header == nil {
+		return Metadata{}, fmt.Errorf("missing metadata in Protobuf data")
+	}
+	metadata := Metadata{
+		Version:      int(header.Version),
+		HerculesHash: header.Hash,
+		Repository:   header.Repository,
+		Commits:      int(header.Commits),
+		RunTime:      time.Duration(header.RunTime) * time.Millisecond,
+	}
+	if header.BeginUnixTime > 0 {
+		metadata.BeginTime = time.Unix(header.BeginUnixTime, 0)
+	}
+	if header.EndUnixTime > 0 {
+		metadata.EndTime = time.Unix(header.EndUnixTime, 0)
+	}
+	return metadata, nil
+}
+
 // GetProjectBurndown retrieves the project-level burndown matrix
 func (r *ProtobufReader) GetProjectBurndown() (string, [][]int) {
 	// Parse burndown data from Contents
@@ -248,6 +271,24 @@ func (r *ProtobufReader) GetRuntimeStats() (map[string]float64, error) {
 	return runtimeStats, nil
 }
 
+// GetCommentSentiment returns the comment sentiment by tick, keyed like Python's get_sentiment
+func (r *ProtobufReader) GetCommentSentiment() (map[int]SentimentTick, error) {
+	sentimentData := r.parseCommentSentimentResults()
+	if sentimentData == nil || len(sentimentData.SentimentByTick) == 0 {
+		return nil, fmt.Errorf("no sentiment data found - run hercules with --sentiment")
+	}
+
+	ticks := make(map[int]SentimentTick, len(sentimentData.SentimentByTick))
+	for tick, sentiment := range sentimentData.SentimentByTick {
+		ticks[int(tick)] = SentimentTick{
+			Value:    float64(sentiment.GetValue()),
+			Comments: sentiment.GetComments(),
+			Commits:  sentiment.GetCommits(),
+		}
+	}
+	return ticks, nil
+}
+
 // GetDeveloperTimeSeriesData returns Python-compatible time series data for protobuf files
 // This now parses real temporal data from DevsAnalysisResults.Ticks (matches Python's approach)
 func (r *ProtobufReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error) {
@@ -310,8 +351,9 @@ func (r *ProtobufReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData,
 	
 	// Return the same format as Python: (people, days)
 	return &DeveloperTimeSeriesData{
-		People: people,
-		Days:   days,
+		People:   people,
+		Days:     days,
+		TickSize: float64(devsData.TickSize) / 1e9, // Convert nanoseconds to seconds
 	}, nil
 }
 
@@ -532,3 +574,78 @@ func (r *ProtobufReader) parseDevsAnalysisResults() *pb.DevsAnalysisResults {
 	
 	return &devsData
 }
+
+// parseCommentSentimentResults extracts and parses sentiment data from the Contents map
+func (r *ProtobufReader) parseCommentSentimentResults() *pb.CommentSentimentResults {
+	if r.data == nil || r.data.Contents == nil {
+		return nil
+	}
+
+	sentimentBytes, exists := r.data.Contents["Sentiment"]
+	if !exists {
+		return nil
+	}
+
+	var sentimentData pb.CommentSentimentResults
+	if err := proto.Unmarshal(sentimentBytes, &sentimentData); err != nil {
+		return nil
+	}
+
+	return &sentimentData
+}
+
+// releaseVersion is unknown, Protobuf files only record their format version, see herculesSource
+func (r *ProtobufReader) releaseVersion() int {
+	return 0
+}
+
+// burndownParameters returns the burndown parameters as stored
+func (r *ProtobufReader) burndownParameters() (int, int, float64, bool) {
+	burndownData := r.parseBurndownAnalysisResults()
+	if burndownData == nil || burndownData.Granularity <= 0 {
+		return 0, 0, 0, false
+	}
+	return int(burndownData.Granularity), int(burndownData.Sampling), float64(burndownData.TickSize) / 1e9, true
+}
+
+func (r *ProtobufReader) filesOwnership() []map[int]int {
+	burndownData := r.parseBurndownAnalysisResults()
+	if burndownData == nil {
+		return nil
+	}
+	result := make([]map[int]int, len(burndownData.FilesOwnership))
+	for i, ownership := range burndownData.FilesOwnership {
+		result[i] = make(map[int]int, len(ownership.GetValue()))
+		for person, lines := range ownership.GetValue() {
+			result[i][int(person)] = int(lines)
+		}
+	}
+	return result
+}
+
+func (r *ProtobufReader) filesLines() []int {
+	couplesData := r.parseCouplesAnalysisResults()
+	if couplesData == nil {
+		return nil
+	}
+	result := make([]int, len(couplesData.FilesLines))
+	for i, lines := range couplesData.FilesLines {
+		result[i] = int(lines)
+	}
+	return result
+}
+
+func (r *ProtobufReader) peopleFiles() [][]int {
+	couplesData := r.parseCouplesAnalysisResults()
+	if couplesData == nil {
+		return nil
+	}
+	result := make([][]int, len(couplesData.PeopleFiles))
+	for i, touched := range couplesData.PeopleFiles {
+		result[i] = make([]int, len(touched.GetFiles()))
+		for j, file := range touched.GetFiles() {
+			result[i][j] = int(file)
+		}
+	}
+	return result
+}
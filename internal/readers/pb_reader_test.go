@@ -122,6 +122,26 @@ func TestProtobufReader_GetProjectBurndown(t *testing.T) {
 	}
 }
 
+func TestProtobufReader_GetMetadata(t *testing.T) {
+	reader := createTestProtobufReader(t)
+
+	metadata, err := reader.GetMetadata()
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if metadata.Repository != "test-repo" {
+		t.Errorf("Expected repository test-repo, got %q", metadata.Repository)
+	}
+	if metadata.BeginTime.Unix() != 1640995200 || metadata.EndTime.Unix() != 1672531200 {
+		t.Errorf("Unexpected commit range %v - %v", metadata.BeginTime, metadata.EndTime)
+	}
+
+	empty := &ProtobufReader{data: &pb.AnalysisResults{}}
+	if _, err := empty.GetMetadata(); err == nil {
+		t.Error("Expected an error for data without a header")
+	}
+}
+
 func TestProtobufReader_GetFilesBurndown(t *testing.T) {
 	reader := createTestProtobufReader(t)
 
@@ -2,6 +2,8 @@ package readers
 
 import (
 	"io"
+	"time"
+
 	"labours-go/internal/burndown"
 )
 
@@ -9,6 +11,7 @@ type Reader interface {
 	Read(file io.Reader) error
 	GetName() string
 	GetHeader() (int64, int64)
+	GetMetadata() (Metadata, error)
 	GetProjectBurndown() (string, [][]int)
 	// Python-compatible methods
 	GetBurndownParameters() (burndown.BurndownParameters, error)
@@ -25,6 +28,18 @@ type Reader interface {
 	GetLanguageStats() ([]LanguageStat, error)
 	GetRuntimeStats() (map[string]float64, error)
 	GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error)
+	GetCommentSentiment() (map[int]SentimentTick, error)
+}
+
+// Metadata describes the hercules run that produced the analysis results
+type Metadata struct {
+	Version      int
+	HerculesHash string // git hash of the hercules build
+	Repository   string
+	BeginTime    time.Time // first analysed commit
+	EndTime      time.Time // last analysed commit
+	Commits      int
+	RunTime      time.Duration
 }
 
 type FileBurndown struct {
@@ -70,6 +85,14 @@ type DevDay struct {
 
 // DeveloperTimeSeriesData represents Python-compatible developer time series data
 type DeveloperTimeSeriesData struct {
-	People []string              // List of developer names
-	Days   map[int]map[int]DevDay // {day: {dev_index: DevDay}}
+	People   []string               // List of developer names
+	Days     map[int]map[int]DevDay // {day: {dev_index: DevDay}}
+	TickSize float64                // Tick size in seconds (0 if unknown)
+}
+
+// SentimentTick is the sentiment of the comments added during a tick (hercules --sentiment)
+type SentimentTick struct {
+	Value    float64  // from 0 (positive) to 1 (negative), like Python labours reads it
+	Comments []string // the comments the sentiment was evaluated on
+	Commits  []string // hashes of the commits that added the comments
 }
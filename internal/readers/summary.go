@@ -0,0 +1,172 @@
+package readers
+
+import (
+	"fmt"
+	"time"
+)
+
+// DataSource tells whether a Reader getter returns data for the input
+type DataSource struct {
+	Getter    string `json:"getter"`
+	Available bool   `json:"available"`
+	Detail    string `json:"detail,omitempty"` // size of the data, or why it is missing
+}
+
+// Summary describes the contents of a hercules file as modes see them through a Reader
+type Summary struct {
+	Repository    string        `json:"repository"`
+	Version       int           `json:"version"`
+	HerculesHash  string        `json:"hercules_hash,omitempty"`
+	BeginUnixTime int64         `json:"begin_unix_time,omitempty"` // first analysed commit
+	EndUnixTime   int64         `json:"end_unix_time,omitempty"`   // last analysed commit
+	Commits       int           `json:"commits"`
+	RunTime       time.Duration `json:"run_time_ns,omitempty"`
+
+	Granularity int     `json:"granularity,omitempty"`
+	Sampling    int     `json:"sampling,omitempty"`
+	TickSize    float64 `json:"tick_size,omitempty"` // seconds
+
+	Files           int `json:"files"`
+	People          int `json:"people"`
+	ShotnessRecords int `json:"shotness_records"`
+
+	Data []DataSource `json:"data"`
+}
+
+// HasData reports whether the getter returned data
+func (s *Summary) HasData(getter string) bool {
+	for _, source := range s.Data {
+		if source.Getter == getter {
+			return source.Available
+		}
+	}
+	return false
+}
+
+// Summarize calls every data getter of the reader and records what it returns
+func Summarize(reader Reader) *Summary {
+	summary := &Summary{Repository: reader.GetName(), Data: []DataSource{}}
+	if metadata, err := reader.GetMetadata(); err == nil {
+		summary.Version = metadata.Version
+		summary.HerculesHash = metadata.HerculesHash
+		if !metadata.BeginTime.IsZero() {
+			summary.BeginUnixTime = metadata.BeginTime.Unix()
+		}
+		if !metadata.EndTime.IsZero() {
+			summary.EndUnixTime = metadata.EndTime.Unix()
+		}
+		summary.Commits = metadata.Commits
+		summary.RunTime = metadata.RunTime
+	}
+	if params, err := reader.GetBurndownParameters(); err == nil {
+		summary.Granularity = params.Granularity
+		summary.Sampling = params.Sampling
+		summary.TickSize = params.TickSize
+	}
+
+	// Readers of incomplete files may panic in getters without an error result
+	record := func(getter string, get func() (string, error)) {
+		source := DataSource{Getter: getter}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					source.Detail = fmt.Sprintf("failed: %v", r)
+				}
+			}()
+			detail, err := get()
+			if err != nil {
+				source.Detail = err.Error()
+				return
+			}
+			source.Available = detail != ""
+			source.Detail = detail
+			if !source.Available {
+				source.Detail = "empty"
+			}
+		}()
+		summary.Data = append(summary.Data, source)
+	}
+
+	record("GetProjectBurndown", func() (string, error) {
+		_, matrix := reader.GetProjectBurndown()
+		if len(matrix) == 0 {
+			return "", nil
+		}
+		return fmt.Sprintf("%dx%d", len(matrix), len(matrix[0])), nil
+	})
+	record("GetFilesBurndown", func() (string, error) {
+		files, err := reader.GetFilesBurndown()
+		summary.Files = max(summary.Files, len(files))
+		return count(len(files), "file", "files"), err
+	})
+	record("GetPeopleBurndown", func() (string, error) {
+		people, err := reader.GetPeopleBurndown()
+		summary.People = max(summary.People, len(people))
+		return count(len(people), "person", "people"), err
+	})
+	record("GetOwnershipBurndown", func() (string, error) {
+		people, _, err := reader.GetOwnershipBurndown()
+		return count(len(people), "person", "people"), err
+	})
+	record("GetPeopleInteraction", func() (string, error) {
+		people, _, err := reader.GetPeopleInteraction()
+		return count(len(people), "person", "people"), err
+	})
+	record("GetFileCooccurrence", func() (string, error) {
+		files, _, err := reader.GetFileCooccurrence()
+		summary.Files = max(summary.Files, len(files))
+		return count(len(files), "file", "files"), err
+	})
+	record("GetPeopleCooccurrence", func() (string, error) {
+		people, _, err := reader.GetPeopleCooccurrence()
+		summary.People = max(summary.People, len(people))
+		return count(len(people), "person", "people"), err
+	})
+	record("GetShotnessCooccurrence", func() (string, error) {
+		names, _, err := reader.GetShotnessCooccurrence()
+		return count(len(names), "unit", "units"), err
+	})
+	record("GetShotnessRecords", func() (string, error) {
+		records, err := reader.GetShotnessRecords()
+		summary.ShotnessRecords = len(records)
+		return count(len(records), "record", "records"), err
+	})
+	record("GetDeveloperStats", func() (string, error) {
+		stats, err := reader.GetDeveloperStats()
+		summary.People = max(summary.People, len(stats))
+		return count(len(stats), "developer", "developers"), err
+	})
+	record("GetDeveloperTimeSeriesData", func() (string, error) {
+		data, err := reader.GetDeveloperTimeSeriesData()
+		if err != nil || data == nil {
+			return "", err
+		}
+		summary.People = max(summary.People, len(data.People))
+		if summary.TickSize == 0 {
+			summary.TickSize = data.TickSize
+		}
+		return fmt.Sprintf("%s, %s", plural(len(data.People), "developer", "developers"),
+			plural(len(data.Days), "tick", "ticks")), nil
+	})
+	record("GetLanguageStats", func() (string, error) {
+		stats, err := reader.GetLanguageStats()
+		return count(len(stats), "language", "languages"), err
+	})
+	record("GetRuntimeStats", func() (string, error) {
+		stats, err := reader.GetRuntimeStats()
+		return count(len(stats), "item", "items"), err
+	})
+	record("GetCommentSentiment", func() (string, error) {
+		sentiment, err := reader.GetCommentSentiment()
+		return count(len(sentiment), "tick", "ticks"), err
+	})
+	return summary
+}
+
+// count describes a non-empty collection, empty collections have no data
+func count(n int, singular, pluralForm string) string {
+	if n == 0 {
+		return ""
+	}
+	return plural(n, singular, pluralForm)
+}
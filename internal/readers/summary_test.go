@@ -0,0 +1,55 @@
+package readers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeProtobuf(t *testing.T) {
+	reader, err := DetectAndReadInput("../../example_data/hercules_couples.pb", "auto")
+	require.NoError(t, err)
+	summary := Summarize(reader)
+
+	assert.Equal(t, "/home/christian/Code/labours-go", summary.Repository)
+	assert.Equal(t, 2, summary.Version)
+	assert.Equal(t, "ca6b32fe793d297d4b256e9637f2fdaa08d8a6a7", summary.HerculesHash)
+	assert.Equal(t, 8, summary.Commits)
+	assert.Equal(t, 88*time.Millisecond, summary.RunTime)
+	assert.Less(t, summary.BeginUnixTime, summary.EndUnixTime)
+	assert.Equal(t, 45, summary.Files)
+	assert.Equal(t, 1, summary.People)
+
+	assert.True(t, summary.HasData("GetFileCooccurrence"))
+	assert.True(t, summary.HasData("GetPeopleCooccurrence"))
+	assert.False(t, summary.HasData("GetProjectBurndown"))
+	assert.False(t, summary.HasData("GetUnknown"))
+	assert.Len(t, summary.Data, 14)
+	assert.Equal(t, DataSource{Getter: "GetProjectBurndown", Detail: "empty"}, summary.Data[0])
+	assert.Equal(t, DataSource{Getter: "GetFileCooccurrence", Available: true, Detail: "45 files"}, summary.Data[5])
+}
+
+func TestSummarizeYAML(t *testing.T) {
+	reader, err := DetectAndReadInput("../../data/labours-go_burndown.yaml", "auto")
+	require.NoError(t, err)
+	summary := Summarize(reader)
+
+	assert.Equal(t, 30, summary.Granularity)
+	assert.Equal(t, 30, summary.Sampling)
+	assert.Equal(t, 86400.0, summary.TickSize)
+	assert.Equal(t, 66, summary.Files)
+	assert.Equal(t, 1, summary.People)
+	for _, getter := range []string{"GetProjectBurndown", "GetFilesBurndown", "GetPeopleBurndown",
+		"GetOwnershipBurndown", "GetPeopleInteraction"} {
+		assert.True(t, summary.HasData(getter), getter)
+	}
+	assert.Equal(t, DataSource{Getter: "GetDeveloperStats", Detail: "missing Devs data in YAML"}, summary.Data[9])
+
+	reader, err = DetectAndReadInput("../../example_data/hercules_devs.yaml", "auto")
+	require.NoError(t, err)
+	summary = Summarize(reader)
+	assert.Equal(t, 86400.0, summary.TickSize, "the tick size of Devs is used without Burndown")
+	assert.True(t, summary.HasData("GetDeveloperTimeSeriesData"))
+}
@@ -0,0 +1,479 @@
+package readers
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnassignedTeam collects developers who are not a member of any team at the given time.
+const UnassignedTeam = "Unassigned"
+
+// TeamMembership is a (possibly time-bounded) membership of a developer in a team.
+// Zero From/Until mean the membership is unbounded on that side.
+type TeamMembership struct {
+	Team  string
+	From  time.Time
+	Until time.Time
+}
+
+// Contains reports whether the membership is active at the given time.
+func (m TeamMembership) Contains(at time.Time) bool {
+	if !m.From.IsZero() && at.Before(m.From) {
+		return false
+	}
+	if !m.Until.IsZero() && at.After(m.Until) {
+		return false
+	}
+	return true
+}
+
+// TeamMap resolves developers to teams. Members are matched case-insensitively against each
+// "|"-separated part of a hercules identity, like PeopleMap.
+type TeamMap struct {
+	teams   []string                    // team names in declaration order
+	members map[string][]TeamMembership // lowercased alias -> memberships
+}
+
+// NewTeamMap creates an empty team map.
+func NewTeamMap() *TeamMap {
+	return &TeamMap{members: make(map[string][]TeamMembership)}
+}
+
+// Add registers a membership of member in the team.
+func (m *TeamMap) Add(member string, membership TeamMembership) {
+	member = strings.ToLower(strings.TrimSpace(member))
+	if member == "" {
+		return
+	}
+	found := false
+	for _, team := range m.teams {
+		if team == membership.Team {
+			found = true
+			break
+		}
+	}
+	if !found {
+		m.teams = append(m.teams, membership.Team)
+	}
+	m.members[member] = append(m.members[member], membership)
+}
+
+// Teams returns the team names in declaration order.
+func (m *TeamMap) Teams() []string {
+	return m.teams
+}
+
+// TeamOf returns the team of a developer at the given time, or UnassignedTeam. If at is zero,
+// time bounds are ignored and the last declared membership wins.
+func (m *TeamMap) TeamOf(identity string, at time.Time) string {
+	memberships, ok := m.members[strings.ToLower(strings.TrimSpace(identity))]
+	if !ok {
+		for _, part := range strings.Split(identity, "|") {
+			if memberships, ok = m.members[strings.ToLower(strings.TrimSpace(part))]; ok {
+				break
+			}
+		}
+	}
+	if at.IsZero() && len(memberships) > 0 {
+		return memberships[len(memberships)-1].Team
+	}
+	for _, membership := range memberships {
+		if membership.Contains(at) {
+			return membership.Team
+		}
+	}
+	return UnassignedTeam
+}
+
+// teamFile is the on-disk format of a team mapping:
+//
+//	teams:
+//	  Platform:
+//	    - alice@corp.com
+//	    - name: bob
+//	      from: 2021-01-01
+//	      until: 2022-06-30
+type teamFile struct {
+	Teams yaml.Node `yaml:"teams"`
+}
+
+type teamMemberEntry struct {
+	Name  string `yaml:"name"`
+	From  string `yaml:"from"`
+	Until string `yaml:"until"`
+}
+
+// LoadTeamMap loads a YAML team mapping file.
+func LoadTeamMap(path string) (*TeamMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening team map %s: %v", path, err)
+	}
+	defer file.Close()
+	return ParseTeamMap(file)
+}
+
+// ParseTeamMap parses a YAML team mapping. Members are either plain names/emails or
+// mappings with name, from and until (YYYY-MM-DD, inclusive).
+func ParseTeamMap(r io.Reader) (*TeamMap, error) {
+	var raw teamFile
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error decoding team map YAML: %v", err)
+	}
+	if raw.Teams.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("team map must contain a \"teams\" mapping")
+	}
+
+	m := NewTeamMap()
+	// Mapping nodes alternate key and value nodes; iterating them keeps declaration order
+	for i := 0; i+1 < len(raw.Teams.Content); i += 2 {
+		team := raw.Teams.Content[i].Value
+		membersNode := raw.Teams.Content[i+1]
+		if membersNode.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("line %d: members of team %q must be a list", membersNode.Line, team)
+		}
+		for _, memberNode := range membersNode.Content {
+			if memberNode.Kind == yaml.ScalarNode {
+				m.Add(memberNode.Value, TeamMembership{Team: team})
+				continue
+			}
+
+			var entry teamMemberEntry
+			if err := memberNode.Decode(&entry); err != nil {
+				return nil, fmt.Errorf("line %d: invalid member of team %q: %v", memberNode.Line, team, err)
+			}
+			if entry.Name == "" {
+				return nil, fmt.Errorf("line %d: member of team %q has no name", memberNode.Line, team)
+			}
+			membership := TeamMembership{Team: team}
+			var err error
+			if membership.From, err = parseTeamDate(entry.From, false); err != nil {
+				return nil, fmt.Errorf("line %d: %v", memberNode.Line, err)
+			}
+			if membership.Until, err = parseTeamDate(entry.Until, true); err != nil {
+				return nil, fmt.Errorf("line %d: %v", memberNode.Line, err)
+			}
+			m.Add(entry.Name, membership)
+		}
+	}
+	return m, nil
+}
+
+// parseTeamDate parses a YYYY-MM-DD date. Until dates are inclusive, so they are moved to
+// the last instant of the day.
+func parseTeamDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		date = date.Add(24*time.Hour - time.Nanosecond)
+	}
+	return date, nil
+}
+
+// TeamReader wraps a Reader and aggregates every people-based getter by team. Burndown bands,
+// ownership samples and devs ticks are attributed to the team the developer belonged to at
+// that time; interaction and co-occurrence matrices use the membership at the end of the
+// analysed period.
+type TeamReader struct {
+	Reader
+	teams      *TeamMap
+	begin, end time.Time
+	// burndown bands span granularity ticks and samples are taken every sampling ticks
+	granularity, sampling int
+	tickSize              float64 // seconds
+}
+
+// NewTeamReader wraps base so that people are aggregated into the teams of teamMap.
+func NewTeamReader(base Reader, teamMap *TeamMap) *TeamReader {
+	// hercules' defaults, for inputs without burndown parameters
+	r := &TeamReader{Reader: base, teams: teamMap, granularity: 30, sampling: 30, tickSize: 86400}
+	if begin, end := base.GetHeader(); end > begin {
+		r.begin, r.end = time.Unix(begin, 0), time.Unix(end, 0)
+	}
+	if params, err := base.GetBurndownParameters(); err == nil {
+		if params.Granularity > 0 {
+			r.granularity = params.Granularity
+		}
+		if params.Sampling > 0 {
+			r.sampling = params.Sampling
+		}
+		if params.TickSize > 0 {
+			r.tickSize = params.TickSize
+		}
+	}
+	return r
+}
+
+// tickTime returns the time of a tick of tickSize seconds from the beginning of the analysed
+// period. It returns the zero time if the header is unknown.
+func (r *TeamReader) tickTime(tick int, tickSize float64) time.Time {
+	if r.end.IsZero() {
+		return r.end
+	}
+	return r.begin.Add(time.Duration(float64(tick) * tickSize * float64(time.Second)))
+}
+
+// teamIndex returns the index of team in teams, appending it if necessary.
+func teamIndex(teams *[]string, team string) int {
+	for i, existing := range *teams {
+		if existing == team {
+			return i
+		}
+	}
+	*teams = append(*teams, team)
+	return len(*teams) - 1
+}
+
+// orderTeams returns the teams present in used, in team map declaration order with
+// UnassignedTeam last, together with the remapping from the indices in used.
+func (r *TeamReader) orderTeams(used []string) ([]string, []int) {
+	var ordered []string
+	for _, team := range append(append([]string{}, r.teams.Teams()...), UnassignedTeam) {
+		for _, u := range used {
+			if u == team {
+				ordered = append(ordered, team)
+				break
+			}
+		}
+	}
+	remap := make([]int, len(used))
+	for i, team := range used {
+		remap[i] = teamIndex(&ordered, team)
+	}
+	return ordered, remap
+}
+
+// teamsAtEnd groups people by their team at the end of the analysed period.
+func (r *TeamReader) teamsAtEnd(people []string) ([]string, []int) {
+	var used []string
+	mapping := make([]int, len(people))
+	for i, person := range people {
+		mapping[i] = teamIndex(&used, r.teams.TeamOf(person, r.end))
+	}
+	teams, remap := r.orderTeams(used)
+	for i := range mapping {
+		mapping[i] = remap[mapping[i]]
+	}
+	return teams, mapping
+}
+
+// addMatrixRow adds row into dst[index], growing dst as needed.
+func addMatrixRow(dst [][]int, index int, row []int) [][]int {
+	for len(dst) <= index {
+		dst = append(dst, nil)
+	}
+	if len(dst[index]) < len(row) {
+		grown := make([]int, len(row))
+		copy(grown, dst[index])
+		dst[index] = grown
+	}
+	for j, val := range row {
+		dst[index][j] += val
+	}
+	return dst
+}
+
+// padMatrix makes sure matrix has the given number of rows, each at least width wide.
+func padMatrix(matrix [][]int, rows, width int) [][]int {
+	for len(matrix) < rows {
+		matrix = append(matrix, nil)
+	}
+	for i := range matrix {
+		if len(matrix[i]) < width {
+			grown := make([]int, width)
+			copy(grown, matrix[i])
+			matrix[i] = grown
+		}
+	}
+	return matrix
+}
+
+// GetPeopleBurndown aggregates people burndown matrices by team. Each band (row) is
+// attributed to the team the developer belonged to when the band's lines were written.
+func (r *TeamReader) GetPeopleBurndown() ([]PeopleBurndown, error) {
+	people, err := r.Reader.GetPeopleBurndown()
+	if err != nil {
+		return nil, err
+	}
+
+	var used []string
+	var matrices [][][]int
+	rows, width := 0, 0
+	for _, person := range people {
+		for band, row := range person.Matrix {
+			idx := teamIndex(&used, r.teams.TeamOf(person.Person, r.tickTime(band*r.granularity, r.tickSize)))
+			for len(matrices) <= idx {
+				matrices = append(matrices, nil)
+			}
+			matrices[idx] = addMatrixRow(matrices[idx], band, row)
+			if len(row) > width {
+				width = len(row)
+			}
+		}
+		if len(person.Matrix) > rows {
+			rows = len(person.Matrix)
+		}
+	}
+
+	teams, remap := r.orderTeams(used)
+	result := make([]PeopleBurndown, len(teams))
+	for i, matrix := range matrices {
+		result[remap[i]] = PeopleBurndown{Person: teams[remap[i]], Matrix: padMatrix(matrix, rows, width)}
+	}
+	return result, nil
+}
+
+// GetOwnershipBurndown aggregates ownership by team. Each sample (row) is attributed to the
+// team the developer belonged to at that time, giving team ownership over time.
+func (r *TeamReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) {
+	sequence, ownership, err := r.Reader.GetOwnershipBurndown()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make(map[string][][]int)
+	var used []string
+	rows, width := 0, 0
+	for _, person := range sequence {
+		matrix := ownership[person]
+		for sample, row := range matrix {
+			team := r.teams.TeamOf(person, r.tickTime(sample*r.sampling, r.tickSize))
+			teamIndex(&used, team)
+			result[team] = addMatrixRow(result[team], sample, row)
+			if len(row) > width {
+				width = len(row)
+			}
+		}
+		if len(matrix) > rows {
+			rows = len(matrix)
+		}
+	}
+	for team, matrix := range result {
+		result[team] = padMatrix(matrix, rows, width)
+	}
+
+	teams, _ := r.orderTeams(used)
+	return teams, result, nil
+}
+
+// GetPeopleInteraction returns the team-to-team overwrites matrix.
+func (r *TeamReader) GetPeopleInteraction() ([]string, [][]int, error) {
+	people, matrix, err := r.Reader.GetPeopleInteraction()
+	if err != nil {
+		return nil, nil, err
+	}
+	teams, mapping := r.teamsAtEnd(people)
+	return teams, mergeInteractionMatrix(matrix, mapping, len(teams)), nil
+}
+
+// GetPeopleCooccurrence returns the team co-occurrence matrix.
+func (r *TeamReader) GetPeopleCooccurrence() ([]string, [][]int, error) {
+	people, matrix, err := r.Reader.GetPeopleCooccurrence()
+	if err != nil {
+		return nil, nil, err
+	}
+	teams, mapping := r.teamsAtEnd(people)
+	return teams, mergeCooccurrenceMatrix(matrix, mapping, len(teams)), nil
+}
+
+// GetDeveloperTimeSeriesData aggregates the devs ticks by the team each developer belonged
+// to on that tick.
+func (r *TeamReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error) {
+	data, err := r.Reader.GetDeveloperTimeSeriesData()
+	if err != nil || data == nil {
+		return data, err
+	}
+
+	tickSize := data.TickSize
+	if tickSize <= 0 {
+		tickSize = r.tickSize
+	}
+
+	var used []string
+	days := make(map[int]map[int]DevDay, len(data.Days))
+	for tick, devs := range data.Days {
+		at := r.tickTime(tick, tickSize)
+		merged := make(map[int]DevDay, len(devs))
+		for devIdx, day := range devs {
+			team := UnassignedTeam
+			if devIdx < len(data.People) {
+				team = r.teams.TeamOf(data.People[devIdx], at)
+			}
+			idx := teamIndex(&used, team)
+			merged[idx] = mergeDevDays(merged[idx], day)
+		}
+		days[tick] = merged
+	}
+
+	teams, remap := r.orderTeams(used)
+	for tick, devs := range days {
+		remapped := make(map[int]DevDay, len(devs))
+		for idx, day := range devs {
+			remapped[remap[idx]] = day
+		}
+		days[tick] = remapped
+	}
+	return &DeveloperTimeSeriesData{People: teams, Days: days, TickSize: data.TickSize}, nil
+}
+
+// GetDeveloperStats aggregates developer statistics by team. When devs ticks are available
+// they are used so that time-bounded memberships are honoured.
+func (r *TeamReader) GetDeveloperStats() ([]DeveloperStat, error) {
+	if data, err := r.GetDeveloperTimeSeriesData(); err == nil && data != nil && len(data.Days) > 0 {
+		stats := make([]DeveloperStat, len(data.People))
+		for i, team := range data.People {
+			stats[i] = DeveloperStat{Name: team, Languages: make(map[string]int)}
+		}
+		for _, devs := range data.Days {
+			for idx, day := range devs {
+				stat := &stats[idx]
+				stat.Commits += day.Commits
+				stat.LinesAdded += day.LinesAdded
+				stat.LinesRemoved += day.LinesRemoved
+				stat.LinesModified += day.LinesModified
+				for lang, lines := range day.Languages {
+					if len(lines) > 0 {
+						stat.Languages[lang] += lines[0]
+					}
+				}
+			}
+		}
+		return stats, nil
+	}
+
+	stats, err := r.Reader.GetDeveloperStats()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(stats))
+	for i, stat := range stats {
+		names[i] = stat.Name
+	}
+	teams, mapping := r.teamsAtEnd(names)
+	merged := make([]DeveloperStat, len(teams))
+	for i, team := range teams {
+		merged[i] = DeveloperStat{Name: team, Languages: make(map[string]int)}
+	}
+	for i, stat := range stats {
+		m := &merged[mapping[i]]
+		m.Commits += stat.Commits
+		m.LinesAdded += stat.LinesAdded
+		m.LinesRemoved += stat.LinesRemoved
+		m.LinesModified += stat.LinesModified
+		m.FilesTouched += stat.FilesTouched
+		for lang, lines := range stat.Languages {
+			m.Languages[lang] += lines
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,119 @@
+package readers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"labours-go/internal/burndown"
+)
+
+// teamTestReader provides people data spanning 2020-01-01 to 2020-12-31 with daily ticks,
+// burndown bands and samples every 183 days
+type teamTestReader struct {
+	identityTestReader
+}
+
+func (r *teamTestReader) GetBurndownParameters() (burndown.BurndownParameters, error) {
+	return burndown.BurndownParameters{Sampling: 183, Granularity: 183, TickSize: 86400}, nil
+}
+
+func (r *teamTestReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error) {
+	data, err := r.identityTestReader.GetDeveloperTimeSeriesData()
+	if err != nil {
+		return nil, err
+	}
+	data.TickSize = 86400
+	data.Days[200] = map[int]DevDay{1: {Commits: 4, LinesAdded: 8, Languages: map[string][]int{}}}
+	return data, nil
+}
+
+func (r *teamTestReader) GetHeader() (int64, int64) {
+	return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC).Unix()
+}
+
+func (r *teamTestReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) {
+	sequence := []string{"alice|alice@home.org", "bob|bob@corp.com"}
+	ownership := map[string][][]int{
+		"alice|alice@home.org": {{1, 0}, {2, 3}},
+		"bob|bob@corp.com":     {{4, 0}, {5, 6}},
+	}
+	return sequence, ownership, nil
+}
+
+const testTeams = `teams:
+  Platform:
+    - alice@home.org
+    - name: bob
+      until: 2020-06-30
+  Frontend:
+    - name: bob
+      from: 2020-07-01
+`
+
+func TestParseTeamMap(t *testing.T) {
+	teamMap, err := ParseTeamMap(strings.NewReader(testTeams))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Platform", "Frontend"}, teamMap.Teams())
+	assert.Equal(t, "Platform", teamMap.TeamOf("alice|alice@home.org", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Platform", teamMap.TeamOf("bob|bob@corp.com", time.Date(2020, 6, 30, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Frontend", teamMap.TeamOf("bob|bob@corp.com", time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, UnassignedTeam, teamMap.TeamOf("carol", time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = ParseTeamMap(strings.NewReader("teams:\n  Platform:\n    - name: bob\n      from: 01/07/2020\n"))
+	assert.Error(t, err)
+	_, err = ParseTeamMap(strings.NewReader("people: []\n"))
+	assert.Error(t, err)
+}
+
+func TestTeamReaderAggregates(t *testing.T) {
+	teamMap, err := ParseTeamMap(strings.NewReader(testTeams))
+	require.NoError(t, err)
+	reader := NewTeamReader(&teamTestReader{}, teamMap)
+
+	t.Run("PeopleBurndown", func(t *testing.T) {
+		people, err := reader.GetPeopleBurndown()
+		require.NoError(t, err)
+		require.Len(t, people, 3)
+		// Bob's first band was written while on Platform, the second one from July 2nd on Frontend
+		assert.Equal(t, "Platform", people[0].Person)
+		assert.Equal(t, [][]int{{1 + 5, 2 + 6}, {3, 4}}, people[0].Matrix)
+		assert.Equal(t, "Frontend", people[1].Person)
+		assert.Equal(t, [][]int{{0, 0}, {7, 8}}, people[1].Matrix)
+		assert.Equal(t, UnassignedTeam, people[2].Person)
+		assert.Equal(t, [][]int{{10, 20}, {30, 40}}, people[2].Matrix)
+	})
+
+	t.Run("OwnershipOverTime", func(t *testing.T) {
+		teams, ownership, err := reader.GetOwnershipBurndown()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Platform", "Frontend"}, teams)
+		assert.Equal(t, [][]int{{5, 0}, {2, 3}}, ownership["Platform"])
+		assert.Equal(t, [][]int{{0, 0}, {5, 6}}, ownership["Frontend"])
+	})
+
+	t.Run("TeamInteraction", func(t *testing.T) {
+		teams, matrix, err := reader.GetPeopleInteraction()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Platform", "Frontend", UnassignedTeam}, teams)
+		// Columns: self, unknown, Platform, Frontend, Unassigned
+		assert.Equal(t, []int{100, 1, 10, 2, 3}, matrix[0])
+		assert.Equal(t, []int{200, 4, 5, 20, 6}, matrix[1])
+		assert.Equal(t, []int{300, 7, 8, 9, 30}, matrix[2])
+	})
+
+	t.Run("DeveloperStats", func(t *testing.T) {
+		stats, err := reader.GetDeveloperStats()
+		require.NoError(t, err)
+		commits := make(map[string]int)
+		for _, stat := range stats {
+			commits[stat.Name] = stat.Commits
+		}
+		// Tick 0 is January 1st (alice on Platform), tick 1 January 2nd (bob on Platform) and
+		// tick 200 July 19th (bob on Frontend)
+		assert.Equal(t, map[string]int{"Platform": 1 + 3, "Frontend": 4, UnassignedTeam: 2}, commits)
+	})
+}
@@ -0,0 +1,329 @@
+package readers
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// herculesVersion is the hercules release whose YAML output labours reads
+const herculesVersion = 10
+
+// protobufFormatVersion is the version of the protobuf format labours reads
+const protobufFormatVersion = 2
+
+// Severities of validation findings
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// maxRepeats is how many occurrences of the same problem are reported individually
+const maxRepeats = 3
+
+// Finding is a problem found in a hercules file
+type Finding struct {
+	Severity string `json:"severity"`
+	Section  string `json:"section"` // analysis section, empty for the file itself
+	Message  string `json:"message"`
+}
+
+// SectionInfo describes an analysis section present in a hercules file
+type SectionInfo struct {
+	Name    string `json:"name"`
+	Bytes   int    `json:"bytes,omitempty"` // encoded size, protobuf only
+	Checked bool   `json:"checked"`         // labours reads the section and validated it
+	Summary string `json:"summary,omitempty"`
+}
+
+// ValidationReport is the result of ValidateInput
+type ValidationReport struct {
+	Input      string        `json:"input"`
+	Format     string        `json:"format"`
+	Version    int           `json:"version"`
+	Repository string        `json:"repository,omitempty"`
+	Sections   []SectionInfo `json:"sections"`
+	Findings   []Finding     `json:"findings"`
+	Errors     int           `json:"errors"`
+	Warnings   int           `json:"warnings"`
+
+	begin, end  int64   // time span of the history from the header
+	tickSeconds float64 // tick size of the history, 0 if unknown
+	ticks       int     // ticks between begin and end, -1 if unknown
+}
+
+func (r *ValidationReport) errorf(section, format string, args ...any) {
+	r.Findings = append(r.Findings, Finding{SeverityError, section, fmt.Sprintf(format, args...)})
+	r.Errors++
+}
+
+func (r *ValidationReport) warnf(section, format string, args ...any) {
+	r.Findings = append(r.Findings, Finding{SeverityWarning, section, fmt.Sprintf(format, args...)})
+	r.Warnings++
+}
+
+// repeated reports the first occurrences of a problem and counts the rest
+type repeated struct {
+	report  *ValidationReport
+	error   bool
+	section string
+	count   int
+}
+
+func (p *repeated) add(format string, args ...any) {
+	p.count++
+	if p.count > maxRepeats {
+		return
+	}
+	if p.error {
+		p.report.errorf(p.section, format, args...)
+	} else {
+		p.report.warnf(p.section, format, args...)
+	}
+}
+
+func (p *repeated) flush(what string) {
+	if more := p.count - maxRepeats; more > 0 {
+		if p.error {
+			p.report.errorf(p.section, "%d more %s", more, what)
+		} else {
+			p.report.warnf(p.section, "%d more %s", more, what)
+		}
+	}
+}
+
+// ValidateInput checks that a hercules file is complete and consistent before modes read
+// it. format is "auto", "pb" or "yaml". The error is only set if the input cannot be read
+// at all; problems of the file are findings of the report.
+func ValidateInput(input, format string) (*ValidationReport, error) {
+	var file io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return nil, fmt.Errorf("error opening file %s: %v", input, err)
+		}
+		defer f.Close()
+		file = f
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", input, err)
+	}
+
+	format = strings.ToLower(format)
+	if format == "auto" {
+		format = "pb"
+		if isYAML(data[:min(len(data), 16)]) {
+			format = "yaml"
+		}
+	}
+	report := &ValidationReport{Input: input, Format: format, Sections: []SectionInfo{}, Findings: []Finding{}, ticks: -1}
+	switch format {
+	case "pb":
+		validateProtobuf(data, report)
+	case "yaml":
+		validateYAML(data, report)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	return report, nil
+}
+
+// checkHeader checks the time span and the commits of the hercules run
+func (r *ValidationReport) checkHeader(begin, end int64, commits int) {
+	r.begin, r.end = begin, end
+	switch {
+	case begin <= 0 || end <= 0:
+		r.warnf("", "begin_unix_time or end_unix_time is missing, dates cannot be computed")
+	case end < begin:
+		r.errorf("", "end_unix_time %d is before begin_unix_time %d", end, begin)
+	}
+	if commits < 0 {
+		r.errorf("", "negative number of commits %d", commits)
+	} else if commits == 0 {
+		r.warnf("", "no commits were analysed")
+	}
+}
+
+// checkFormatVersion checks the version of the protobuf format in the header
+func (r *ValidationReport) checkFormatVersion(version int) {
+	r.Version = version
+	switch {
+	case version <= 0:
+		r.errorf("", "the format version is missing, this file was not written by hercules")
+	case version < protobufFormatVersion:
+		r.warnf("", "protobuf format version %d is older than version %d read by labours; sections may be missing or differ",
+			version, protobufFormatVersion)
+	case version > protobufFormatVersion:
+		r.errorf("", "protobuf format version %d is newer than version %d read by labours", version, protobufFormatVersion)
+	}
+}
+
+// checkReleaseVersion checks the hercules release in the header of a YAML document
+func (r *ValidationReport) checkReleaseVersion(version int) {
+	r.Version = version
+	switch {
+	case version == 0:
+		r.warnf("", "written by a development build of hercules, format compatibility cannot be checked")
+	case version < 0:
+		r.errorf("", "invalid hercules version %d", version)
+	case version < herculesVersion:
+		r.warnf("", "written by hercules v%d, labours reads the output of v%d; sections may be missing or differ", version, herculesVersion)
+	case version > herculesVersion:
+		r.warnf("", "written by hercules v%d, newer than v%d read by labours", version, herculesVersion)
+	}
+}
+
+// setTickSize records the tick size of the history in seconds, so that ticks can be
+// checked against the time span of the header. The first known tick size is kept.
+func (r *ValidationReport) setTickSize(seconds float64) {
+	if seconds <= 0 || r.tickSeconds > 0 {
+		return
+	}
+	r.tickSeconds = seconds
+	if r.begin > 0 && r.end >= r.begin {
+		r.ticks = int(float64(r.end-r.begin) / seconds)
+	}
+}
+
+// matrixShape is the size of a matrix as declared or as parsed
+type matrixShape struct {
+	name       string
+	rows, cols int
+}
+
+// checkBurndown checks the parameters and the matrix sizes of the burndown section
+func (r *ValidationReport) checkBurndown(granularity, sampling int, project *matrixShape, files, people []matrixShape,
+	peopleSequence int, interaction *matrixShape, ownership int) {
+	const section = "Burndown"
+	if granularity <= 0 || sampling <= 0 {
+		r.errorf(section, "granularity %d and sampling %d must be positive", granularity, sampling)
+		return
+	}
+	if sampling > granularity {
+		r.warnf(section, "sampling %d is larger than granularity %d", sampling, granularity)
+	}
+
+	shape := func(what string, m matrixShape) {
+		if m.rows == 0 {
+			return
+		}
+		if bands := (m.rows*sampling + granularity - 1) / granularity; m.cols > bands+1 {
+			r.warnf(section, "%s has %d age bands, more than %d samples of %d ticks fill with bands of %d ticks",
+				what, m.cols, m.rows, sampling, granularity)
+		}
+		if project != nil && (m.rows != project.rows || m.cols != project.cols) {
+			r.warnf(section, "%s is %dx%d, the project burndown is %dx%d", what, m.rows, m.cols, project.rows, project.cols)
+		}
+	}
+	if project == nil || project.rows == 0 {
+		r.warnf(section, "no project burndown, burndown-project cannot be plotted")
+		project = nil
+	} else {
+		shape("project", *project)
+		if covered := project.rows * sampling; r.ticks >= 0 && covered < r.ticks {
+			r.warnf(section, "the burndown covers %d ticks, but the history spans %d ticks", covered, r.ticks)
+		}
+	}
+	r.checkNames(section, "files", files)
+	for i, file := range files {
+		shape(fmt.Sprintf("files[%d] %q", i, file.name), file)
+	}
+	r.checkNames(section, "people", people)
+	for i, person := range people {
+		shape(fmt.Sprintf("people[%d] %q", i, person.name), person)
+	}
+	if peopleSequence >= 0 && peopleSequence != len(people) {
+		r.errorf(section, "people_sequence has %d names, but there are %d people burndowns", peopleSequence, len(people))
+	}
+	if interaction != nil {
+		// Every person's row has two extra columns: own lines and lines of missing authors
+		if interaction.rows != len(people) || interaction.cols != len(people)+2 {
+			r.errorf(section, "people_interaction is %dx%d, expected %dx%d for %d people",
+				interaction.rows, interaction.cols, len(people), len(people)+2, len(people))
+		}
+	} else if len(people) > 0 {
+		r.warnf(section, "people_interaction is missing, overwrites-matrix cannot be plotted")
+	}
+	if ownership > 0 && ownership != len(files) {
+		r.errorf(section, "files_ownership has %d entries, but there are %d files", ownership, len(files))
+	}
+}
+
+// checkNames reports empty and duplicate names of matrices
+func (r *ValidationReport) checkNames(section, what string, matrices []matrixShape) {
+	seen := make(map[string]int, len(matrices))
+	duplicates := repeated{report: r, section: section}
+	for i, m := range matrices {
+		if m.name == "" {
+			duplicates.add("%s[%d] has no name", what, i)
+		} else if first, ok := seen[m.name]; ok {
+			duplicates.add("%s[%d] %q is also %s[%d]", what, i, m.name, what, first)
+		} else {
+			seen[m.name] = i
+		}
+	}
+	duplicates.flush("unnamed or duplicate " + what)
+}
+
+// checkCoocc checks that a co-occurrence matrix is square and matches its index. The
+// people matrix of hercules has an extra row for unmatched authors.
+func (r *ValidationReport) checkCoocc(what string, index int, rows, cols int, extraRow bool) {
+	const section = "Couples"
+	if rows != cols {
+		r.errorf(section, "%s matrix is %dx%d, not square", what, rows, cols)
+	}
+	if rows != index && !(extraRow && rows == index+1) {
+		r.errorf(section, "%s matrix has %d rows, but the index has %d names", what, rows, index)
+	}
+}
+
+// devTick is a developer's entry in a tick of the Devs section
+type devTick struct {
+	tick, dev int
+}
+
+// checkDevs checks developer and tick indexes of the Devs section
+func (r *ValidationReport) checkDevs(people int, ticks []devTick) {
+	const section = "Devs"
+	if people == 0 {
+		r.errorf(section, "no developers in the people index")
+	}
+	devs := repeated{report: r, error: true, section: section}
+	negative := repeated{report: r, error: true, section: section}
+	late := repeated{report: r, section: section}
+	for _, t := range ticks {
+		// hercules writes -1 for commits without a matched author
+		if t.dev < -1 || t.dev >= people {
+			devs.add("tick %d refers to developer %d, but the index has %d names", t.tick, t.dev, people)
+		}
+		if t.tick < 0 {
+			negative.add("negative tick %d", t.tick)
+		} else if r.ticks >= 0 && t.tick > r.ticks+1 {
+			late.add("tick %d is after the end of the history at tick %d", t.tick, r.ticks)
+		}
+	}
+	devs.flush("unknown developers")
+	negative.flush("negative ticks")
+	late.flush("ticks after the end of the history")
+}
+
+// addSection lists a section of the input in the report
+func (r *ValidationReport) addSection(name string, size int, checked bool, summary string) {
+	r.Sections = append(r.Sections, SectionInfo{Name: name, Bytes: size, Checked: checked, Summary: summary})
+}
+
+// plural formats a count with a singular or plural noun
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
+
+// firstLine returns the first line of an error message
+func firstLine(err error) string {
+	message, _, _ := strings.Cut(err.Error(), "\n")
+	return message
+}
@@ -0,0 +1,339 @@
+package readers
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"labours-go/internal/pb"
+)
+
+// validateProtobuf checks a hercules protobuf file. A truncated file is checked up to its
+// last complete field, so that the intact sections are still reported.
+func validateProtobuf(data []byte, report *ValidationReport) {
+	var results pb.AnalysisResults
+	truncated := ""
+	if err := proto.Unmarshal(data, &results); err != nil {
+		complete, stop := completeFields(data)
+		if stop == nil {
+			report.errorf("", "cannot decode protobuf: %v", err)
+			return
+		}
+		report.errorf("", "the file is truncated or corrupt at byte %d of %d: %s; only the fields before it are checked",
+			complete, len(data), firstLine(stop))
+		results.Reset()
+		if err := proto.Unmarshal(data[:complete], &results); err != nil {
+			report.errorf("", "cannot decode protobuf: %v", err)
+			return
+		}
+		truncated = truncatedSection(data[complete:])
+	}
+
+	header := results.Header
+	if header == nil {
+		report.errorf("", "the header (Metadata) is missing, this is not a hercules file")
+	} else {
+		report.Repository = header.Repository
+		report.checkFormatVersion(int(header.Version))
+		report.checkHeader(header.BeginUnixTime, header.EndUnixTime, int(header.Commits))
+	}
+	if len(results.Contents) == 0 && truncated == "" {
+		report.errorf("", "no analysis results in contents")
+		return
+	}
+
+	names := make([]string, 0, len(results.Contents))
+	for name := range results.Contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content := results.Contents[name]
+		var message proto.Message
+		switch name {
+		case "Burndown":
+			message = &pb.BurndownAnalysisResults{}
+		case "Couples":
+			message = &pb.CouplesAnalysisResults{}
+		case "Devs":
+			message = &pb.DevsAnalysisResults{}
+		case "Shotness":
+			message = &pb.ShotnessAnalysisResults{}
+		case "Sentiment":
+			message = &pb.CommentSentimentResults{}
+		default:
+			report.addSection(name, len(content), false, "not read by labours")
+			continue
+		}
+		if err := proto.Unmarshal(content, message); err != nil {
+			report.addSection(name, len(content), false, "")
+			report.errorf(name, "cannot decode the section: %v", err)
+			continue
+		}
+		var summary string
+		switch section := message.(type) {
+		case *pb.BurndownAnalysisResults:
+			summary = validateBurndownProtobuf(section, report)
+		case *pb.CouplesAnalysisResults:
+			summary = validateCouplesProtobuf(section, report)
+		case *pb.DevsAnalysisResults:
+			summary = validateDevsProtobuf(section, report)
+		case *pb.ShotnessAnalysisResults:
+			summary = validateShotness(report, len(section.Records), func(i int) (string, string, map[int32]int32) {
+				return section.Records[i].Name, section.Records[i].File, section.Records[i].Counters
+			})
+		case *pb.CommentSentimentResults:
+			values := make(map[int]float64, len(section.SentimentByTick))
+			for tick, sentiment := range section.SentimentByTick {
+				values[int(tick)] = float64(sentiment.GetValue())
+			}
+			summary = validateSentiment(report, values)
+		}
+		report.addSection(name, len(content), true, summary)
+	}
+	if truncated != "" {
+		report.addSection(truncated, 0, false, "truncated")
+		report.errorf(truncated, "the section is cut off and cannot be read")
+	}
+}
+
+// completeFields returns the length of the complete top-level fields at the start of data
+// and the error that stopped the scan, nil if all fields are complete
+func completeFields(data []byte) (int, error) {
+	offset := 0
+	for offset < len(data) {
+		number, kind, n := protowire.ConsumeTag(data[offset:])
+		if n < 0 {
+			return offset, protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(number, kind, data[offset+n:])
+		if m < 0 {
+			return offset, protowire.ParseError(m)
+		}
+		offset += n + m
+	}
+	return offset, nil
+}
+
+// truncatedSection returns the name of the contents entry at the start of data, if its key
+// is intact
+func truncatedSection(data []byte) string {
+	number, kind, n := protowire.ConsumeTag(data)
+	if n < 0 || number != 2 || kind != protowire.BytesType { // AnalysisResults.contents
+		return ""
+	}
+	_, m := protowire.ConsumeVarint(data[n:])
+	if m < 0 {
+		return ""
+	}
+	entry := data[n+m:]
+	number, kind, n = protowire.ConsumeTag(entry)
+	if n < 0 || number != 1 || kind != protowire.BytesType { // the map key
+		return ""
+	}
+	key, m := protowire.ConsumeBytes(entry[n:])
+	if m < 0 {
+		return ""
+	}
+	return string(key)
+}
+
+func validateBurndownProtobuf(burndownData *pb.BurndownAnalysisResults, report *ValidationReport) string {
+	report.setTickSize(float64(burndownData.TickSize) / 1e9) // nanoseconds
+	var project *matrixShape
+	if burndownData.Project != nil {
+		shape := checkSparseMatrix(report, "project", burndownData.Project)
+		project = &shape
+	}
+	files := make([]matrixShape, len(burndownData.Files))
+	for i, file := range burndownData.Files {
+		files[i] = checkSparseMatrix(report, fmt.Sprintf("files[%d] %q", i, file.Name), file)
+	}
+	people := make([]matrixShape, len(burndownData.People))
+	for i, person := range burndownData.People {
+		people[i] = checkSparseMatrix(report, fmt.Sprintf("people[%d] %q", i, person.Name), person)
+	}
+	var interaction *matrixShape
+	if burndownData.PeopleInteraction != nil {
+		checkCSR(report, "Burndown", "people_interaction", burndownData.PeopleInteraction)
+		interaction = &matrixShape{rows: int(burndownData.PeopleInteraction.NumberOfRows),
+			cols: int(burndownData.PeopleInteraction.NumberOfColumns)}
+	}
+	if burndownData.TickSize <= 0 {
+		report.warnf("Burndown", "tick_size is missing, ticks are assumed to be days")
+	}
+	// The protobuf format has no people sequence, the names of the people burndowns are used
+	report.checkBurndown(int(burndownData.Granularity), int(burndownData.Sampling), project, files, people,
+		-1, interaction, len(burndownData.FilesOwnership))
+
+	summary := fmt.Sprintf("%s, %s", plural(len(files), "file", "files"), plural(len(people), "person", "people"))
+	if project != nil {
+		summary = fmt.Sprintf("%dx%d project, %s", project.rows, project.cols, summary)
+	}
+	return summary
+}
+
+// checkSparseMatrix checks a burndown matrix against its declared size and returns the size
+func checkSparseMatrix(report *ValidationReport, what string, matrix *pb.BurndownSparseMatrix) matrixShape {
+	shape := matrixShape{name: matrix.Name, rows: int(matrix.NumberOfRows), cols: int(matrix.NumberOfColumns)}
+	if shape.rows < 0 || shape.cols < 0 {
+		report.errorf("Burndown", "%s has a negative size %dx%d", what, shape.rows, shape.cols)
+		return matrixShape{name: matrix.Name}
+	}
+	if len(matrix.Rows) > shape.rows {
+		report.errorf("Burndown", "%s has %d rows, more than number_of_rows %d", what, len(matrix.Rows), shape.rows)
+	}
+	wide := repeated{report: report, error: true, section: "Burndown"}
+	for y, row := range matrix.Rows {
+		if len(row.Columns) > shape.cols {
+			wide.add("%s row %d has %d columns, more than number_of_columns %d", what, y, len(row.Columns), shape.cols)
+		}
+	}
+	wide.flush(what + " rows with too many columns")
+	return shape
+}
+
+// checkCSR checks the structure of a compressed sparse row matrix
+func checkCSR(report *ValidationReport, section, what string, matrix *pb.CompressedSparseRowMatrix) {
+	rows, cols := int(matrix.NumberOfRows), int(matrix.NumberOfColumns)
+	if rows < 0 || cols < 0 {
+		report.errorf(section, "%s has a negative size %dx%d", what, rows, cols)
+		return
+	}
+	if len(matrix.Indptr) != rows+1 && !(rows == 0 && len(matrix.Indptr) == 0) {
+		report.errorf(section, "%s has %d indptr entries, expected number_of_rows+1 = %d", what, len(matrix.Indptr), rows+1)
+		return
+	}
+	if len(matrix.Data) != len(matrix.Indices) {
+		report.errorf(section, "%s has %d values but %d column indices", what, len(matrix.Data), len(matrix.Indices))
+		return
+	}
+	if len(matrix.Indptr) == 0 {
+		return
+	}
+	if matrix.Indptr[0] != 0 {
+		report.errorf(section, "%s indptr starts at %d instead of 0", what, matrix.Indptr[0])
+	}
+	for i := 1; i < len(matrix.Indptr); i++ {
+		if matrix.Indptr[i] < matrix.Indptr[i-1] {
+			report.errorf(section, "%s indptr is not monotonic at row %d: %d after %d", what, i-1, matrix.Indptr[i], matrix.Indptr[i-1])
+			return
+		}
+	}
+	if last := matrix.Indptr[len(matrix.Indptr)-1]; last != int64(len(matrix.Data)) {
+		report.errorf(section, "%s indptr ends at %d, but there are %d values", what, last, len(matrix.Data))
+	}
+	columns := repeated{report: report, error: true, section: section}
+	for i, column := range matrix.Indices {
+		if column < 0 || int(column) >= cols {
+			columns.add("%s column index %d at position %d is outside of %d columns", what, column, i, cols)
+		}
+	}
+	columns.flush(what + " column indices out of range")
+}
+
+func validateCouplesProtobuf(couplesData *pb.CouplesAnalysisResults, report *ValidationReport) string {
+	files, people := 0, 0
+	for _, couples := range []struct {
+		what     string
+		data     *pb.Couples
+		extraRow bool
+		count    *int
+	}{
+		{"file_couples", couplesData.FileCouples, false, &files},
+		{"people_couples", couplesData.PeopleCouples, true, &people},
+	} {
+		if couples.data == nil || couples.data.Matrix == nil {
+			report.warnf("Couples", "%s is missing", couples.what)
+			continue
+		}
+		*couples.count = len(couples.data.Index)
+		matrix := couples.data.Matrix
+		checkCSR(report, "Couples", couples.what, matrix)
+		report.checkCoocc(couples.what, len(couples.data.Index), int(matrix.NumberOfRows), int(matrix.NumberOfColumns), couples.extraRow)
+	}
+	if len(couplesData.FilesLines) > 0 && len(couplesData.FilesLines) != files {
+		report.errorf("Couples", "files_lines has %d entries, but file_couples has %d files", len(couplesData.FilesLines), files)
+	}
+	if len(couplesData.PeopleFiles) > people+1 {
+		report.errorf("Couples", "people_files has %d entries, but people_couples has %d people", len(couplesData.PeopleFiles), people)
+	}
+	touched := repeated{report: report, error: true, section: "Couples"}
+	for person, personFiles := range couplesData.PeopleFiles {
+		for _, file := range personFiles.Files {
+			if file < 0 || int(file) >= files {
+				touched.add("people_files[%d] refers to file %d, but file_couples has %d files", person, file, files)
+			}
+		}
+	}
+	touched.flush("unknown files in people_files")
+	return fmt.Sprintf("%s, %s", plural(files, "file", "files"), plural(people, "person", "people"))
+}
+
+func validateDevsProtobuf(devsData *pb.DevsAnalysisResults, report *ValidationReport) string {
+	report.setTickSize(float64(devsData.TickSize) / 1e9) // nanoseconds
+	var ticks []devTick
+	missing := repeated{report: report, error: true, section: "Devs"}
+	for tick, tickDevs := range devsData.Ticks {
+		if tickDevs == nil {
+			continue
+		}
+		for dev, stats := range tickDevs.Devs {
+			ticks = append(ticks, devTick{tick: int(tick), dev: int(dev)})
+			if stats == nil || stats.Stats == nil {
+				missing.add("tick %d developer %d has no line stats", tick, dev)
+			}
+		}
+	}
+	missing.flush("developer ticks without line stats")
+	sort.Slice(ticks, func(i, j int) bool {
+		return ticks[i].tick < ticks[j].tick || (ticks[i].tick == ticks[j].tick && ticks[i].dev < ticks[j].dev)
+	})
+	report.checkDevs(len(devsData.DevIndex), ticks)
+	return fmt.Sprintf("%s, %s", plural(len(devsData.DevIndex), "developer", "developers"), plural(len(devsData.Ticks), "tick", "ticks"))
+}
+
+// validateShotness checks the records of the Shotness section
+func validateShotness(report *ValidationReport, n int, record func(i int) (name, file string, counters map[int32]int32)) string {
+	unnamed := repeated{report: report, section: "Shotness"}
+	negative := repeated{report: report, error: true, section: "Shotness"}
+	for i := 0; i < n; i++ {
+		name, file, counters := record(i)
+		if name == "" || file == "" {
+			unnamed.add("record %d has no name or file", i)
+		}
+		for tick := range counters {
+			if tick < 0 {
+				negative.add("record %d %q has a counter for negative tick %d", i, name, tick)
+			}
+		}
+	}
+	unnamed.flush("records without name or file")
+	negative.flush("counters for negative ticks")
+	return plural(n, "record", "records")
+}
+
+// validateSentiment checks the ticks and values of the comment sentiment
+func validateSentiment(report *ValidationReport, sentiment map[int]float64) string {
+	ticks := make([]int, 0, len(sentiment))
+	for tick := range sentiment {
+		ticks = append(ticks, tick)
+	}
+	sort.Ints(ticks)
+	invalid := repeated{report: report, error: true, section: "Sentiment"}
+	late := repeated{report: report, section: "Sentiment"}
+	for _, tick := range ticks {
+		if tick < 0 {
+			invalid.add("negative tick %d", tick)
+		} else if report.ticks >= 0 && tick > report.ticks+1 {
+			late.add("tick %d is after the end of the history at tick %d", tick, report.ticks)
+		}
+		if value := sentiment[tick]; value < 0 || value > 1 {
+			invalid.add("tick %d has sentiment %g outside of [0, 1]", tick, value)
+		}
+	}
+	invalid.flush("invalid sentiment ticks")
+	late.flush("ticks after the end of the history")
+	return plural(len(ticks), "tick", "ticks")
+}
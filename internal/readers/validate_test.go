@@ -0,0 +1,254 @@
+package readers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"labours-go/internal/pb"
+)
+
+// validateData writes data to a temporary file and validates it
+func validateData(t *testing.T, name, format string, data []byte) *ValidationReport {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	report, err := ValidateInput(path, format)
+	require.NoError(t, err)
+	return report
+}
+
+// findings returns the messages of a report by section
+func findings(report *ValidationReport, severity string) map[string][]string {
+	result := map[string][]string{}
+	for _, finding := range report.Findings {
+		if finding.Severity == severity {
+			result[finding.Section] = append(result[finding.Section], finding.Message)
+		}
+	}
+	return result
+}
+
+func marshalResults(t *testing.T, header *pb.Metadata, sections map[string]proto.Message) []byte {
+	t.Helper()
+	results := &pb.AnalysisResults{Header: header, Contents: map[string][]byte{}}
+	for name, section := range sections {
+		data, err := proto.Marshal(section)
+		require.NoError(t, err)
+		results.Contents[name] = data
+	}
+	data, err := proto.Marshal(results)
+	require.NoError(t, err)
+	return data
+}
+
+func TestValidateExamples(t *testing.T) {
+	for _, path := range []string{
+		"../../example_data/hercules_burndown.pb",
+		"../../example_data/hercules_couples.pb",
+		"../../example_data/hercules_devs.pb",
+		"../../example_data/hercules_burndown.yaml",
+		"../../data/labours-go_burndown.yaml",
+		"../../data/labours-go_couples.yaml",
+		"../../data/labours-go_devs.yaml",
+	} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			report, err := ValidateInput(path, "auto")
+			require.NoError(t, err)
+			assert.Zero(t, report.Errors, "%v", report.Findings)
+			require.Len(t, report.Sections, 1)
+			assert.True(t, report.Sections[0].Checked)
+		})
+	}
+
+	report, err := ValidateInput("../../example_data/hercules_couples.pb", "auto")
+	require.NoError(t, err)
+	assert.Equal(t, "pb", report.Format)
+	assert.Equal(t, 2, report.Version)
+	assert.Equal(t, SectionInfo{Name: "Couples", Bytes: report.Sections[0].Bytes, Checked: true,
+		Summary: "45 files, 1 person"}, report.Sections[0])
+}
+
+func TestValidateTruncatedProtobuf(t *testing.T) {
+	data, err := os.ReadFile("../../example_data/hercules_couples.pb")
+	require.NoError(t, err)
+	report := validateData(t, "truncated.pb", "auto", data[:len(data)/2])
+
+	assert.Equal(t, 2, report.Errors)
+	assert.Equal(t, "/home/christian/Code/labours-go", report.Repository, "the header is still read")
+	assert.Contains(t, findings(report, SeverityError)[""][0], "the file is truncated or corrupt at byte")
+	assert.Equal(t, []string{"the section is cut off and cannot be read"}, findings(report, SeverityError)["Couples"])
+	assert.Equal(t, []SectionInfo{{Name: "Couples", Summary: "truncated"}}, report.Sections)
+}
+
+func TestValidateProtobufStructure(t *testing.T) {
+	header := &pb.Metadata{Version: 3, BeginUnixTime: 1600000000, EndUnixTime: 1600000000 + 10*86400, Commits: 5}
+	burndown := &pb.BurndownAnalysisResults{
+		Granularity: 30,
+		Sampling:    30,
+		TickSize:    86400 * 1e9,
+		Project: &pb.BurndownSparseMatrix{Name: "project", NumberOfRows: 1, NumberOfColumns: 1, Rows: []*pb.BurndownSparseMatrixRow{
+			{Columns: []uint32{10}}, {Columns: []uint32{5, 5}},
+		}},
+		People: []*pb.BurndownSparseMatrix{
+			{Name: "alice", NumberOfRows: 1, NumberOfColumns: 1},
+			{Name: "alice", NumberOfRows: 1, NumberOfColumns: 1},
+		},
+		PeopleInteraction: &pb.CompressedSparseRowMatrix{NumberOfRows: 2, NumberOfColumns: 4,
+			Data: []int64{1, 2, 3}, Indices: []int32{0, 7, 1}, Indptr: []int64{0, 2, 1}},
+	}
+	couples := &pb.CouplesAnalysisResults{
+		FileCouples: &pb.Couples{Index: []string{"a", "b", "c"}, Matrix: &pb.CompressedSparseRowMatrix{
+			NumberOfRows: 2, NumberOfColumns: 2, Indptr: []int64{0, 0, 0}}},
+		FilesLines: []int32{1, 2},
+	}
+	devs := &pb.DevsAnalysisResults{
+		DevIndex: []string{"alice"},
+		TickSize: 86400 * 1e9,
+		Ticks: map[int32]*pb.TickDevs{
+			0:  {Devs: map[int32]*pb.DevTick{-1: {Commits: 1, Stats: &pb.LineStats{}}, 0: {Commits: 1}}},
+			40: {Devs: map[int32]*pb.DevTick{3: {Commits: 1, Stats: &pb.LineStats{}}}},
+		},
+	}
+	report := validateData(t, "broken.pb", "auto", marshalResults(t, header, map[string]proto.Message{
+		"Burndown": burndown, "Couples": couples, "Devs": devs, "Typos": &pb.Metadata{},
+	}))
+
+	errors := findings(report, SeverityError)
+	assert.Equal(t, []string{"protobuf format version 3 is newer than version 2 read by labours"}, errors[""])
+	assert.Equal(t, []string{
+		"project has 2 rows, more than number_of_rows 1",
+		"project row 1 has 2 columns, more than number_of_columns 1",
+		"people_interaction indptr is not monotonic at row 1: 1 after 2",
+	}, errors["Burndown"])
+	assert.Equal(t, []string{
+		"file_couples matrix has 2 rows, but the index has 3 names",
+		"files_lines has 2 entries, but file_couples has 3 files",
+	}, errors["Couples"])
+	assert.Equal(t, []string{
+		"tick 0 developer 0 has no line stats",
+		"tick 40 refers to developer 3, but the index has 1 names",
+	}, errors["Devs"])
+
+	warnings := findings(report, SeverityWarning)
+	assert.Contains(t, warnings["Burndown"], `people[1] "alice" is also people[0]`)
+	assert.Contains(t, warnings["Couples"], "people_couples is missing")
+	assert.Equal(t, []string{"tick 40 is after the end of the history at tick 10"}, warnings["Devs"])
+
+	names := make([]string, len(report.Sections))
+	for i, section := range report.Sections {
+		names[i] = section.Name
+	}
+	assert.Equal(t, []string{"Burndown", "Couples", "Devs", "Typos"}, names)
+	assert.Equal(t, SectionInfo{Name: "Typos", Bytes: report.Sections[3].Bytes, Summary: "not read by labours"}, report.Sections[3])
+}
+
+func TestValidateCSR(t *testing.T) {
+	tests := []struct {
+		name   string
+		matrix *pb.CompressedSparseRowMatrix
+		want   []string
+	}{
+		{"valid", &pb.CompressedSparseRowMatrix{NumberOfRows: 2, NumberOfColumns: 2,
+			Data: []int64{1, 2}, Indices: []int32{1, 0}, Indptr: []int64{0, 1, 2}}, nil},
+		{"empty", &pb.CompressedSparseRowMatrix{}, nil},
+		{"indptr length", &pb.CompressedSparseRowMatrix{NumberOfRows: 2, NumberOfColumns: 2, Indptr: []int64{0, 0}},
+			[]string{"m has 2 indptr entries, expected number_of_rows+1 = 3"}},
+		{"values", &pb.CompressedSparseRowMatrix{NumberOfRows: 1, NumberOfColumns: 2,
+			Data: []int64{1, 2}, Indices: []int32{1}, Indptr: []int64{0, 1}},
+			[]string{"m has 2 values but 1 column indices"}},
+		{"indptr bounds", &pb.CompressedSparseRowMatrix{NumberOfRows: 1, NumberOfColumns: 2,
+			Data: []int64{1}, Indices: []int32{1}, Indptr: []int64{1, 2}},
+			[]string{"m indptr starts at 1 instead of 0", "m indptr ends at 2, but there are 1 values"}},
+		{"columns", &pb.CompressedSparseRowMatrix{NumberOfRows: 1, NumberOfColumns: 1,
+			Data: []int64{1, 1, 1, 1, 1}, Indices: []int32{-1, 1, 2, 3, 4}, Indptr: []int64{0, 5}},
+			[]string{
+				"m column index -1 at position 0 is outside of 1 columns",
+				"m column index 1 at position 1 is outside of 1 columns",
+				"m column index 2 at position 2 is outside of 1 columns",
+				"2 more m column indices out of range",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &ValidationReport{}
+			checkCSR(report, "S", "m", tt.matrix)
+			assert.Equal(t, tt.want, findings(report, SeverityError)["S"])
+		})
+	}
+}
+
+func TestValidateYAML(t *testing.T) {
+	report := validateData(t, "broken.yaml", "auto", []byte(`hercules:
+  version: 10
+  repository: "test"
+  begin_unix_time: 1600000000
+  end_unix_time: 1600864000
+  commits: 3
+Burndown:
+  granularity: 30
+  sampling: 30
+  tick_size: 86400
+  "project": |-
+    10
+  people_sequence:
+    - "alice"
+    - "bob"
+  people:
+    "alice": |-
+      5
+  people_interaction: |-
+    5 0 0
+Couples:
+  files_coocc:
+    index: ["a", "b"]
+    lines: [1, 2, 3]
+    matrix:
+      - {0: 1}
+      - {1: 1}
+  people_coocc:
+    index: ["alice"]
+    matrix:
+      - {0: 2}
+      - {}
+Sentiment:
+  -1: [0.5, [abc], "good"]
+  2: [1.5, [def], "great"]
+Custom:
+  anything: 1
+Devs:
+  ticks:
+    0:
+      0: [4, 10, 0, 2, {Go: [3, 0, x]}]
+`))
+
+	assert.Equal(t, "yaml", report.Format)
+	assert.Equal(t, 10, report.Version)
+	errors := findings(report, SeverityError)
+	assert.Equal(t, []string{`line 41, column 36: expected an integer, got "x"; only the sections before it are checked`}, errors[""])
+	assert.Equal(t, []string{"people_sequence has 2 names, but there are 1 people burndowns"}, errors["Burndown"])
+	assert.Equal(t, []string{"files_coocc has 3 line counts, but 2 files"}, errors["Couples"], "the people matrix has a row for unmatched authors")
+	assert.Equal(t, []string{"negative tick -1", "tick 2 has sentiment 1.5 outside of [0, 1]"}, errors["Sentiment"])
+	assert.Empty(t, findings(report, SeverityWarning)["Burndown"])
+
+	names := make([]string, len(report.Sections))
+	for i, section := range report.Sections {
+		names[i] = section.Name
+	}
+	assert.Equal(t, []string{"Burndown", "Couples", "Sentiment", "Custom"}, names, "the malformed Devs section is not listed")
+}
+
+func TestValidateInputErrors(t *testing.T) {
+	_, err := ValidateInput("../../example_data/hercules_devs.pb", "json")
+	assert.EqualError(t, err, "unsupported format: json")
+	_, err = ValidateInput("missing.pb", "auto")
+	assert.Error(t, err)
+
+	report := validateData(t, "empty.yaml", "yaml", []byte("# no results\n"))
+	assert.Equal(t, []string{"empty YAML document; only the sections before it are checked",
+		"the hercules header is missing, this is not a hercules file",
+		"no analysis results in the document"}, findings(report, SeverityError)[""])
+}
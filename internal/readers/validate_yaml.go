@@ -0,0 +1,176 @@
+package readers
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// validateYAML checks a hercules YAML document. Decoding stops at the first malformed
+// value, the sections before it are still checked.
+func validateYAML(data []byte, report *ValidationReport) {
+	var doc yamlDocument
+	if err := decodeYAML(bytes.NewReader(data), &doc); err != nil {
+		report.errorf("", "%s; only the sections before it are checked", firstLine(err))
+	}
+
+	if doc.Header == nil {
+		report.errorf("", "the hercules header is missing, this is not a hercules file")
+	} else {
+		report.Repository = doc.Header.Repository
+		report.checkReleaseVersion(doc.Header.Version)
+		report.checkHeader(doc.Header.BeginUnixTime, doc.Header.EndUnixTime, doc.Header.Commits)
+	}
+
+	for _, name := range doc.Sections {
+		var summary string
+		switch name {
+		case "hercules":
+			continue
+		case "Burndown":
+			summary = validateBurndownYAML(doc.Burndown, report)
+		case "Couples":
+			summary = validateCouplesYAML(doc.Couples, report)
+		case "Devs":
+			summary = validateDevsYAML(doc.Devs, report)
+		case "Shotness":
+			summary = validateShotness(report, len(doc.Shotness), func(i int) (string, string, map[int32]int32) {
+				return doc.Shotness[i].Name, doc.Shotness[i].File, doc.Shotness[i].Counters
+			})
+		case "Sentiment":
+			values := make(map[int]float64, len(doc.Sentiment))
+			for tick, sentiment := range doc.Sentiment {
+				values[tick] = sentiment.Value
+			}
+			summary = validateSentiment(report, values)
+		default:
+			report.addSection(name, 0, false, "not read by labours")
+			continue
+		}
+		report.addSection(name, 0, true, summary)
+	}
+	if len(report.Sections) == 0 {
+		report.errorf("", "no analysis results in the document")
+	}
+}
+
+// yamlShape returns the size of a parsed matrix
+func yamlShape(name string, matrix [][]int) matrixShape {
+	shape := matrixShape{name: name, rows: len(matrix)}
+	if shape.rows > 0 {
+		shape.cols = len(matrix[0])
+	}
+	return shape
+}
+
+func validateBurndownYAML(burndownData *yamlBurndown, report *ValidationReport) string {
+	if burndownData == nil {
+		report.errorf("Burndown", "the section is empty")
+		return ""
+	}
+	report.setTickSize(burndownData.TickSize) // seconds
+	var project *matrixShape
+	if len(burndownData.Project) > 0 {
+		shape := yamlShape("project", burndownData.Project)
+		project = &shape
+	}
+	files := make([]matrixShape, len(burndownData.Files))
+	for i, file := range burndownData.Files {
+		files[i] = yamlShape(file.Name, file.Matrix)
+	}
+	people := make([]matrixShape, len(burndownData.People))
+	for i, person := range burndownData.People {
+		people[i] = yamlShape(person.Name, person.Matrix)
+	}
+	peopleSequence := -1
+	if burndownData.PeopleSequence != nil {
+		peopleSequence = len(burndownData.PeopleSequence)
+	}
+	var interaction *matrixShape
+	if burndownData.PeopleInteraction != nil {
+		shape := yamlShape("people_interaction", burndownData.PeopleInteraction)
+		interaction = &shape
+	}
+	if burndownData.TickSize <= 0 {
+		report.warnf("Burndown", "tick_size is missing, ticks are assumed to be days")
+	}
+	report.checkBurndown(burndownData.Granularity, burndownData.Sampling, project, files, people,
+		peopleSequence, interaction, len(burndownData.FilesOwnership))
+
+	summary := fmt.Sprintf("%s, %s", plural(len(files), "file", "files"), plural(len(people), "person", "people"))
+	if project != nil {
+		summary = fmt.Sprintf("%dx%d project, %s", project.rows, project.cols, summary)
+	}
+	return summary
+}
+
+func validateCouplesYAML(couplesData *yamlCouples, report *ValidationReport) string {
+	if couplesData == nil {
+		report.errorf("Couples", "the section is empty")
+		return ""
+	}
+	files, people := 0, 0
+	for _, couples := range []struct {
+		what     string
+		index    []string
+		matrix   [][]int
+		extraRow bool
+		count    *int
+	}{
+		{"files_coocc", cooccIndex(couplesData.FilesCoocc, couplesData.FileCouplesIndex),
+			cooccMatrix(couplesData.FilesCoocc, couplesData.FileCouplesMatrix), false, &files},
+		{"people_coocc", cooccIndex(couplesData.PeopleCoocc, couplesData.PeopleCouplesIndex),
+			cooccMatrix(couplesData.PeopleCoocc, couplesData.PeopleCouplesMatrix), true, &people},
+	} {
+		if couples.index == nil && couples.matrix == nil {
+			report.warnf("Couples", "%s is missing", couples.what)
+			continue
+		}
+		*couples.count = len(couples.index)
+		shape := yamlShape(couples.what, couples.matrix)
+		report.checkCoocc(couples.what, len(couples.index), shape.rows, shape.cols, couples.extraRow)
+	}
+	if couplesData.FilesCoocc != nil && len(couplesData.FilesCoocc.Lines) > 0 && len(couplesData.FilesCoocc.Lines) != files {
+		report.errorf("Couples", "files_coocc has %d line counts, but %d files", len(couplesData.FilesCoocc.Lines), files)
+	}
+	return fmt.Sprintf("%s, %s", plural(files, "file", "files"), plural(people, "person", "people"))
+}
+
+// cooccIndex returns the index of a co-occurrence matrix in the current or the legacy layout
+func cooccIndex(coocc *yamlCoocc, legacy []string) []string {
+	if coocc != nil {
+		return coocc.Index
+	}
+	return legacy
+}
+
+// cooccMatrix returns a co-occurrence matrix in the current or the legacy layout
+func cooccMatrix(coocc *yamlCoocc, legacy yamlMatrix) [][]int {
+	if coocc != nil {
+		return coocc.Matrix
+	}
+	return legacy
+}
+
+func validateDevsYAML(devsData *yamlDevs, report *ValidationReport) string {
+	if devsData == nil {
+		report.errorf("Devs", "the section is empty")
+		return ""
+	}
+	report.setTickSize(devsData.TickSize) // seconds
+	people := devsData.People
+	if len(people) == 0 {
+		people = devsData.DevIndex
+	}
+	var ticks []devTick
+	for tick, tickDevs := range devsData.Ticks {
+		for dev := range tickDevs {
+			ticks = append(ticks, devTick{tick: tick, dev: dev})
+		}
+	}
+	sort.Slice(ticks, func(i, j int) bool {
+		return ticks[i].tick < ticks[j].tick || (ticks[i].tick == ticks[j].tick && ticks[i].dev < ticks[j].dev)
+	})
+	report.checkDevs(len(people), ticks)
+	return fmt.Sprintf("%s, %s", plural(len(people), "developer", "developers"), plural(len(devsData.Ticks), "tick", "ticks"))
+}
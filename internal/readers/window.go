@@ -0,0 +1,325 @@
+package readers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"labours-go/internal/burndown"
+)
+
+// TimeWindowReader wraps a Reader and crops every time-based getter to a date range:
+// burndown samples, devs ticks and shotness counters outside the window are dropped and
+// the remaining ticks are counted from the start of the window.
+//
+// The window starts at the last burndown sample that is also a band boundary before the
+// start date. The bands before the window are added to its first band, like hercules
+// attributes all the lines that exist at its first commit to the first band. Interaction
+// and co-occurrence matrices have no time axis and keep covering the whole history.
+type TimeWindowReader struct {
+	Reader
+	begin, end int64 // unix times of the window
+	tickSize   float64
+	first      int // first tick of the window
+	last       int // last tick of the window
+	ticks      int // last tick of the wrapped reader
+	cropped    bool
+}
+
+// NewTimeWindowReader wraps base so that its data covers the ticks from start to end.
+// A nil start or end leaves that side of the analysed period unchanged.
+func NewTimeWindowReader(base Reader, start, end *time.Time) (*TimeWindowReader, error) {
+	begin, last := base.GetHeader()
+	if begin <= 0 || last < begin {
+		return nil, fmt.Errorf("the analysed period is unknown, cannot apply a time window")
+	}
+	if start != nil && end != nil && end.Before(*start) {
+		return nil, fmt.Errorf("the end of the time window %s is before its start %s",
+			end.Format(time.DateOnly), start.Format(time.DateOnly))
+	}
+
+	r := &TimeWindowReader{Reader: base, tickSize: 86400}
+	step := 1 // ticks between the possible starts of the window
+	if granularity, sampling, tickSize, ok := windowParameters(base); ok {
+		r.tickSize = tickSize
+		step = lcm(granularity, sampling)
+	} else if data, err := base.GetDeveloperTimeSeriesData(); err == nil && data != nil && data.TickSize > 0 {
+		r.tickSize = data.TickSize
+	}
+
+	r.ticks = int(float64(last-begin) / r.tickSize)
+	r.first, r.last = 0, r.ticks
+	if start != nil && start.Unix() > begin {
+		r.first = int(float64(start.Unix()-begin)/r.tickSize) / step * step
+	}
+	if end != nil && end.Unix() < last {
+		r.last = int(math.Floor(float64(end.Unix()-begin) / r.tickSize))
+	}
+	if r.first > r.ticks || r.last < 0 {
+		return nil, fmt.Errorf("the time window does not overlap the analysed period %s to %s",
+			time.Unix(begin, 0).UTC().Format(time.DateOnly), time.Unix(last, 0).UTC().Format(time.DateOnly))
+	}
+
+	r.begin = begin + int64(float64(r.first)*r.tickSize)
+	r.end = last
+	if r.last < r.ticks {
+		r.end = end.Unix()
+	}
+	r.cropped = r.first > 0 || r.last < r.ticks
+	return r, nil
+}
+
+// windowParameters returns the burndown parameters the tick axis of base is built on
+func windowParameters(base Reader) (granularity, sampling int, tickSize float64, ok bool) {
+	if source, isSource := base.(herculesSource); isSource {
+		granularity, sampling, tickSize, ok = source.burndownParameters()
+	} else if params, err := base.GetBurndownParameters(); err == nil {
+		granularity, sampling, tickSize, ok = params.Granularity, params.Sampling, params.TickSize, true
+	}
+	ok = ok && granularity > 0 && sampling > 0 && tickSize > 0
+	return granularity, sampling, tickSize, ok
+}
+
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
+
+// contains tells whether a tick of the wrapped reader is inside the window
+func (r *TimeWindowReader) contains(tick int) bool {
+	return tick >= r.first && tick <= r.last
+}
+
+// cropBurndown crops a bands x samples matrix to the window
+func (r *TimeWindowReader) cropBurndown(matrix [][]int) [][]int {
+	if !r.cropped || len(matrix) == 0 {
+		return matrix
+	}
+	granularity, sampling, _, ok := windowParameters(r.Reader)
+	if !ok {
+		granularity, sampling = 1, 1
+	}
+	firstSample, lastSample := r.first/sampling, r.last/sampling
+	firstBand, lastBand := r.first/granularity, lastSample*sampling/granularity
+	width := 0
+	for _, row := range matrix {
+		width = max(width, min(len(row), lastSample+1)-firstSample)
+	}
+	width = max(width, 0)
+
+	result := [][]int{make([]int, width)}
+	for band, row := range matrix {
+		if band > lastBand {
+			break
+		}
+		if band > firstBand {
+			result = append(result, make([]int, width))
+		}
+		cropped := result[len(result)-1]
+		for x := range cropped {
+			if firstSample+x < len(row) {
+				cropped[x] += row[firstSample+x]
+			}
+		}
+	}
+	return result
+}
+
+func (r *TimeWindowReader) GetHeader() (int64, int64) {
+	return r.begin, r.end
+}
+
+// GetMetadata returns the metadata of the window. The commits of a cropped window are
+// counted from the devs ticks if the file has them.
+func (r *TimeWindowReader) GetMetadata() (Metadata, error) {
+	metadata, err := r.Reader.GetMetadata()
+	if err != nil {
+		return metadata, err
+	}
+	metadata.BeginTime, metadata.EndTime = time.Unix(r.begin, 0), time.Unix(r.end, 0)
+	if data, err := r.GetDeveloperTimeSeriesData(); err == nil && data != nil && r.cropped {
+		metadata.Commits = 0
+		for _, devs := range data.Days {
+			for _, day := range devs {
+				metadata.Commits += day.Commits
+			}
+		}
+	}
+	return metadata, nil
+}
+
+func (r *TimeWindowReader) GetProjectBurndown() (string, [][]int) {
+	name, matrix := r.Reader.GetProjectBurndown()
+	return name, r.cropBurndown(matrix)
+}
+
+func (r *TimeWindowReader) GetProjectBurndownWithHeader() (burndown.BurndownHeader, string, [][]int, error) {
+	header, name, matrix, err := r.Reader.GetProjectBurndownWithHeader()
+	if err != nil {
+		return header, name, matrix, err
+	}
+	header.Start, header.Last = r.begin, r.end
+	return header, name, r.cropBurndown(matrix), nil
+}
+
+func (r *TimeWindowReader) GetFilesBurndown() ([]FileBurndown, error) {
+	files, err := r.Reader.GetFilesBurndown()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]FileBurndown, len(files))
+	for i, file := range files {
+		result[i] = FileBurndown{Filename: file.Filename, Matrix: r.cropBurndown(file.Matrix)}
+	}
+	return result, nil
+}
+
+func (r *TimeWindowReader) GetPeopleBurndown() ([]PeopleBurndown, error) {
+	people, err := r.Reader.GetPeopleBurndown()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]PeopleBurndown, len(people))
+	for i, person := range people {
+		result[i] = PeopleBurndown{Person: person.Person, Matrix: r.cropBurndown(person.Matrix)}
+	}
+	return result, nil
+}
+
+// GetOwnershipBurndown crops the ownership matrices, which are samples x bands
+func (r *TimeWindowReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) {
+	people, ownership, err := r.Reader.GetOwnershipBurndown()
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make(map[string][][]int, len(ownership))
+	for person, matrix := range ownership {
+		result[person] = transposeMatrix(r.cropBurndown(transposeMatrix(matrix)))
+	}
+	return people, result, nil
+}
+
+func (r *TimeWindowReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error) {
+	data, err := r.Reader.GetDeveloperTimeSeriesData()
+	if err != nil || data == nil {
+		return data, err
+	}
+	days := make(map[int]map[int]DevDay, len(data.Days))
+	for tick, devs := range data.Days {
+		if r.contains(tick) {
+			days[tick-r.first] = devs
+		}
+	}
+	return &DeveloperTimeSeriesData{People: data.People, Days: days, TickSize: data.TickSize}, nil
+}
+
+// GetDeveloperStats aggregates the devs ticks of the window if the file has them.
+// Developers without commits in the window are left out.
+func (r *TimeWindowReader) GetDeveloperStats() ([]DeveloperStat, error) {
+	data, err := r.GetDeveloperTimeSeriesData()
+	if err != nil || data == nil || !r.cropped {
+		return r.Reader.GetDeveloperStats()
+	}
+	stats := make([]DeveloperStat, len(data.People))
+	for i, person := range data.People {
+		stats[i] = DeveloperStat{Name: person, Languages: make(map[string]int)}
+	}
+	for _, devs := range data.Days {
+		for idx, day := range devs {
+			if idx < 0 || idx >= len(stats) {
+				continue
+			}
+			stat := &stats[idx]
+			stat.Commits += day.Commits
+			stat.LinesAdded += day.LinesAdded
+			stat.LinesRemoved += day.LinesRemoved
+			stat.LinesModified += day.LinesModified
+			for lang, lines := range day.Languages {
+				if len(lines) > 0 {
+					stat.Languages[lang] += lines[0]
+				}
+			}
+		}
+	}
+	active := stats[:0]
+	for _, stat := range stats {
+		if stat.Commits > 0 {
+			active = append(active, stat)
+		}
+	}
+	return active, nil
+}
+
+// GetShotnessRecords keeps the counters of the window; units modified only outside of
+// it stay in the list so that their indexes match the shotness co-occurrence
+func (r *TimeWindowReader) GetShotnessRecords() ([]ShotnessRecord, error) {
+	records, err := r.Reader.GetShotnessRecords()
+	if err != nil {
+		return records, err
+	}
+	result := make([]ShotnessRecord, len(records))
+	for i, record := range records {
+		counters := make(map[int32]int32, len(record.Counters))
+		for tick, count := range record.Counters {
+			if r.contains(int(tick)) {
+				counters[tick-int32(r.first)] = count
+			}
+		}
+		record.Counters = counters
+		result[i] = record
+	}
+	return result, nil
+}
+
+func (r *TimeWindowReader) GetCommentSentiment() (map[int]SentimentTick, error) {
+	sentiment, err := r.Reader.GetCommentSentiment()
+	if err != nil {
+		return sentiment, err
+	}
+	result := make(map[int]SentimentTick, len(sentiment))
+	for tick, value := range sentiment {
+		if r.contains(tick) {
+			result[tick-r.first] = value
+		}
+	}
+	return result, nil
+}
+
+// releaseVersion implements herculesSource so that a window can be converted
+func (r *TimeWindowReader) releaseVersion() int {
+	if source, ok := r.Reader.(herculesSource); ok {
+		return source.releaseVersion()
+	}
+	return 0
+}
+
+func (r *TimeWindowReader) burndownParameters() (int, int, float64, bool) {
+	return windowParameters(r.Reader)
+}
+
+// filesOwnership is the ownership at the end of the analysed period, so it is only
+// kept if the window does not end earlier
+func (r *TimeWindowReader) filesOwnership() []map[int]int {
+	source, ok := r.Reader.(herculesSource)
+	if !ok || r.last < r.ticks {
+		return nil
+	}
+	return source.filesOwnership()
+}
+
+func (r *TimeWindowReader) filesLines() []int {
+	if source, ok := r.Reader.(herculesSource); ok {
+		return source.filesLines()
+	}
+	return nil
+}
+
+func (r *TimeWindowReader) peopleFiles() [][]int {
+	if source, ok := r.Reader.(herculesSource); ok {
+		return source.peopleFiles()
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package readers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// windowYAML has five burndown samples and bands of two days each, devs on days 1, 4, 6
+// and 7, a shotness unit modified on days 1 and 5 and comments on days 2 and 5
+const windowYAML = `hercules:
+  version: 10
+  repository: window
+  begin_unix_time: 1600000000
+  end_unix_time: 1600691200
+  commits: 7
+Burndown:
+  granularity: 2
+  sampling: 2
+  tick_size: 86400
+  "project": |-
+    10 0 0 0 0
+     9 5 0 0 0
+     8 4 6 0 0
+     7 4 5 3 0
+     6 3 5 2 4
+  files_ownership:
+    - 0: 20
+Devs:
+  ticks:
+    1:
+      0: [1, 10, 0, 0, {Go: [10, 0, 0]}]
+    4:
+      0: [2, 20, 5, 1, {Go: [20, 5, 1]}]
+    6:
+      1: [3, 30, 0, 2, {}]
+    7:
+      0: [1, 1, 1, 1, {}]
+  people:
+  - "alice"
+  - "bob"
+  tick_size: 86400
+Shotness:
+  - name: "main"
+    file: "main.go"
+    internal_role: "Function"
+    counters: {1: 2, 5: 3}
+Sentiment:
+  2: [0.2500, [aaa], "good"]
+  5: [0.7500, [bbb], "bad"]
+`
+
+func windowReader(t *testing.T, start, end time.Duration) *TimeWindowReader {
+	t.Helper()
+	reader := &YamlReader{}
+	require.NoError(t, reader.Read(strings.NewReader(windowYAML)))
+	begin := time.Unix(1600000000, 0)
+	startTime, endTime := begin.Add(start), begin.Add(end)
+	window, err := NewTimeWindowReader(reader, &startTime, &endTime)
+	require.NoError(t, err)
+	return window
+}
+
+func TestTimeWindowBurndown(t *testing.T) {
+	// The start on day 5 snaps to the band boundary on day 4, the end falls on day 6
+	window := windowReader(t, 5*24*time.Hour+time.Hour, 6*24*time.Hour+12*time.Hour)
+	begin, end := window.GetHeader()
+	assert.Equal(t, int64(1600000000+4*86400), begin)
+	assert.Equal(t, int64(1600000000+6*86400+12*3600), end)
+
+	// The bands of days 0 to 5 are merged into the first band of the window
+	_, project := window.GetProjectBurndown()
+	assert.Equal(t, [][]int{{18, 16}, {0, 3}}, project)
+	header, _, matrix, err := window.GetProjectBurndownWithHeader()
+	require.NoError(t, err)
+	assert.Equal(t, project, matrix)
+	assert.Equal(t, begin, header.Start)
+	assert.Equal(t, end, header.Last)
+}
+
+func TestTimeWindowUncropped(t *testing.T) {
+	window := windowReader(t, -24*time.Hour, 30*24*time.Hour)
+	_, project := window.GetProjectBurndown()
+	_, original := window.Reader.GetProjectBurndown()
+	assert.Equal(t, original, project)
+	metadata, err := window.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, 7, metadata.Commits)
+	assert.Equal(t, []map[int]int{{0: 20}}, window.filesOwnership())
+}
+
+func TestTimeWindowDevsAndShotness(t *testing.T) {
+	window := windowReader(t, 4*24*time.Hour, 6*24*time.Hour)
+
+	data, err := window.GetDeveloperTimeSeriesData()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, data.People)
+	assert.Len(t, data.Days, 2)
+	assert.Equal(t, 2, data.Days[0][0].Commits)
+	assert.Equal(t, 3, data.Days[2][1].Commits)
+
+	stats, err := window.GetDeveloperStats()
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, DeveloperStat{Name: "alice", Commits: 2, LinesAdded: 20, LinesRemoved: 5, LinesModified: 1,
+		Languages: map[string]int{"Go": 20}}, stats[0])
+	metadata, err := window.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, 5, metadata.Commits)
+	assert.Equal(t, time.Unix(1600000000+4*86400, 0), metadata.BeginTime)
+
+	records, err := window.GetShotnessRecords()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, map[int32]int32{1: 3}, records[0].Counters)
+}
+
+func TestTimeWindowSentiment(t *testing.T) {
+	window := windowReader(t, 4*24*time.Hour, 6*24*time.Hour)
+	sentiment, err := window.GetCommentSentiment()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]SentimentTick{1: {Value: 0.75, Comments: []string{"bad"}, Commits: []string{"bbb"}}},
+		sentiment)
+}
+
+func TestTimeWindowSlice(t *testing.T) {
+	window := windowReader(t, 4*24*time.Hour, 6*24*time.Hour)
+	var buf bytes.Buffer
+	require.NoError(t, WriteYAML(&buf, window))
+	sliced := &YamlReader{}
+	require.NoError(t, sliced.Read(&buf))
+
+	begin, end := sliced.GetHeader()
+	windowBegin, windowEnd := window.GetHeader()
+	assert.Equal(t, windowBegin, begin)
+	assert.Equal(t, windowEnd, end)
+	_, project := sliced.GetProjectBurndown()
+	_, windowProject := window.GetProjectBurndown()
+	assert.Equal(t, windowProject, project)
+	data, err := sliced.GetDeveloperTimeSeriesData()
+	require.NoError(t, err)
+	assert.Len(t, data.Days, 2)
+	// The files ownership describes the end of the whole history
+	assert.Empty(t, sliced.filesOwnership())
+}
+
+func TestTimeWindowErrors(t *testing.T) {
+	reader := &YamlReader{}
+	require.NoError(t, reader.Read(strings.NewReader(windowYAML)))
+	late := time.Unix(1700000000, 0)
+	_, err := NewTimeWindowReader(reader, &late, nil)
+	assert.EqualError(t, err, "the time window does not overlap the analysed period 2020-09-13 to 2020-09-21")
+
+	early := time.Unix(1500000000, 0)
+	_, err = NewTimeWindowReader(reader, &late, &early)
+	assert.ErrorContains(t, err, "is before its start")
+
+	_, err = NewTimeWindowReader(&YamlReader{}, &early, nil)
+	assert.EqualError(t, err, "the analysed period is unknown, cannot apply a time window")
+}
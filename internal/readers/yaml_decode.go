@@ -0,0 +1,421 @@
+package readers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseError reports malformed YAML input at its position in the document
+type ParseError struct {
+	Line   int
+	Column int // 0 if unknown
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+func nodeError(node *yaml.Node, format string, args ...any) *ParseError {
+	return &ParseError{Line: node.Line, Column: node.Column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// scalarInt decodes an integer scalar
+func scalarInt(node *yaml.Node) (int, error) {
+	var value int
+	if node.Kind != yaml.ScalarNode || node.Decode(&value) != nil {
+		return 0, nodeError(node, "expected an integer, got %q", node.Value)
+	}
+	return value, nil
+}
+
+// matrixError is a malformed row of a text matrix; the section decoder resolves its
+// position from the raw lines of the block
+type matrixError struct {
+	node  *yaml.Node
+	row   int // line of the text
+	field int // whitespace separated field of the line
+	msg   string
+}
+
+func (e *matrixError) Error() string {
+	return fmt.Sprintf("line %d: matrix row %d: %s", e.node.Line, e.row+1, e.msg)
+}
+
+// decodeYAML decodes a hercules YAML document into doc one top-level section at a time.
+// hercules writes every analysis as a block mapping under a key in the first column, so
+// the input is split before such keys and only the text and nodes of a single section are
+// held while it is converted; "---" and "..." lines separate sections as well. The
+// Burndown section is split further, see burndownEntries. Documents written in flow style
+// are decoded whole. Anchors cannot be referenced across sections or Burndown entries.
+func decodeYAML(r io.Reader, doc *yamlDocument) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	var section bytes.Buffer
+	var burndown *blockEntries
+	start, line := 1, 0
+	content, flow := false, false
+	seen := make(map[string]int)
+	sections := 0
+	flush := func() error {
+		defer section.Reset()
+		if !content {
+			return nil
+		}
+		content = false
+		sections++
+		if burndown != nil {
+			entries := burndown
+			burndown = nil
+			if err := entries.finish(); err != nil {
+				return err
+			}
+			doc.Sections = append(doc.Sections, "Burndown")
+			return nil
+		}
+		return decodeYAMLSection(section.Bytes(), start, doc, seen)
+	}
+
+	for {
+		text, err := reader.ReadBytes('\n')
+		if len(text) > 0 {
+			line++
+			trimmed := bytes.TrimRight(text, " \t\r\n")
+			marker := string(trimmed) == "---" || string(trimmed) == "..."
+			if !flow && (marker || (content && startsSection(text))) {
+				if err := flush(); err != nil {
+					return err
+				}
+				start = line
+			}
+			switch {
+			case marker && !flow:
+				// Keep the line numbers of the section
+				section.WriteByte('\n')
+			case burndown != nil:
+				if err := burndown.write(text, line); err != nil {
+					return err
+				}
+			case len(bytes.TrimSpace(trimmed)) == 0 || bytes.HasPrefix(bytes.TrimSpace(trimmed), []byte("#")):
+				section.Write(text)
+			case !content && !flow && string(trimmed) == "Burndown:":
+				key := &yaml.Node{Kind: yaml.ScalarNode, Value: "Burndown", Line: line, Column: 1}
+				if err := uniqueKey(seen, key, "section"); err != nil {
+					return err
+				}
+				content = true
+				burndown = burndownEntries(doc)
+			default:
+				if !content && sections == 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+					flow = true
+				}
+				content = true
+				section.Write(text)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading YAML: %v", err)
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if sections == 0 {
+		return errors.New("empty YAML document")
+	}
+	return nil
+}
+
+// startsSection reports whether a line is a key in the first column
+func startsSection(line []byte) bool {
+	c := line[0]
+	return c == '"' || c == '\'' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// decodeYAMLSection decodes the top-level mapping in text, which starts at line start of
+// the document
+func decodeYAMLSection(text []byte, start int, doc *yamlDocument, seen map[string]int) error {
+	mapping, err := parseMapping(text, start, "analysis results")
+	if err != nil || mapping == nil {
+		return err
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if err := uniqueKey(seen, key, "section"); err != nil {
+			return err
+		}
+		if err := doc.decodeSection(key.Value, value); err != nil {
+			return positionError(err, value, text, start)
+		}
+	}
+	return nil
+}
+
+// parseMapping parses text, which starts at line start of the document, and returns the
+// mapping at its root, or nil if the text has no content
+func parseMapping(text []byte, start int, what string) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(text, &root); err != nil {
+		return nil, syntaxError(err, start-1)
+	}
+	shiftLines(&root, start-1)
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, nodeError(mapping, "expected a mapping of %s", what)
+	}
+	return mapping, nil
+}
+
+// uniqueKey records the line of key in seen, which holds the keys decoded before from the
+// same mapping
+func uniqueKey(seen map[string]int, key *yaml.Node, kind string) error {
+	if line, ok := seen[key.Value]; ok {
+		return nodeError(key, "%s %s is already defined at line %d", kind, key.Value, line)
+	}
+	seen[key.Value] = key.Line
+	return nil
+}
+
+// blockEntries splits the lines of a block mapping into its entries while they are read.
+// An entry runs from a key indented like the first one up to the next such key; it is
+// buffered and decoded on its own, unless open returns a stream for the entries of its
+// value.
+type blockEntries struct {
+	indent int           // column of the keys, -1 before the first one
+	start  int           // line of the current entry, 0 if there is none
+	text   bytes.Buffer  // lines of the current entry
+	value  *blockEntries // entries of the value of the current entry
+	decode func(text []byte, start int) error
+	open   func(header []byte, line int) (*blockEntries, error)
+}
+
+func newBlockEntries(decode func(text []byte, start int) error) *blockEntries {
+	return &blockEntries{indent: -1, decode: decode}
+}
+
+// write adds a line of the mapping
+func (b *blockEntries) write(text []byte, line int) error {
+	indent, item, ok := lineIndent(text)
+	if ok && (b.indent < 0 || (indent == b.indent && !item)) {
+		if err := b.finish(); err != nil {
+			return err
+		}
+		b.indent, b.start = indent, line
+		if b.open != nil {
+			value, err := b.open(text, line)
+			if err != nil {
+				return err
+			}
+			if b.value = value; value != nil {
+				return nil
+			}
+		}
+	}
+	switch {
+	case b.value != nil:
+		return b.value.write(text, line)
+	case b.start > 0:
+		b.text.Write(text)
+	}
+	return nil
+}
+
+// finish decodes the current entry
+func (b *blockEntries) finish() error {
+	defer b.text.Reset()
+	value, start := b.value, b.start
+	b.value, b.start = nil, 0
+	switch {
+	case value != nil:
+		return value.finish()
+	case start > 0:
+		return b.decode(b.text.Bytes(), start)
+	}
+	return nil
+}
+
+// lineIndent returns the indentation of a line and whether it is a sequence item; ok is
+// false for blank lines and comments
+func lineIndent(text []byte) (indent int, item, ok bool) {
+	trimmed := bytes.TrimLeft(text, " ")
+	content := bytes.TrimSpace(trimmed)
+	if len(content) == 0 || content[0] == '#' {
+		return 0, false, false
+	}
+	item = content[0] == '-' && (len(content) == 1 || content[1] == ' ' || content[1] == '\t')
+	return len(text) - len(trimmed), item, true
+}
+
+// burndownEntries decodes the lines of a Burndown section into doc one entry at a time.
+// The files and people entries hold a matrix for every file and developer, so the
+// matrices under them are decoded one by one as well, unless they are written in flow
+// style.
+func burndownEntries(doc *yamlDocument) *blockEntries {
+	burndown := &yamlBurndown{}
+	seen := make(map[string]int)
+	entries := newBlockEntries(func(text []byte, start int) error {
+		doc.Burndown = burndown
+		mapping, err := parseMapping(text, start, "burndown results")
+		if err != nil || mapping == nil {
+			return err
+		}
+		for i := 0; i < len(mapping.Content); i += 2 {
+			if err := uniqueKey(seen, mapping.Content[i], "key"); err != nil {
+				return err
+			}
+		}
+		if err := mapping.Decode(burndown); err != nil {
+			return positionError(err, mapping, text, start)
+		}
+		return nil
+	})
+	entries.open = func(header []byte, line int) (*blockEntries, error) {
+		var matrices *yamlMatrices
+		name := string(bytes.TrimSpace(header))
+		switch name {
+		case "files:":
+			matrices = &burndown.Files
+		case "people:":
+			matrices = &burndown.People
+		default:
+			return nil, nil
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: strings.TrimSuffix(name, ":"), Line: line,
+			Column: len(header) - len(bytes.TrimLeft(header, " ")) + 1}
+		if err := uniqueKey(seen, key, "key"); err != nil {
+			return nil, err
+		}
+		doc.Burndown = burndown
+		*matrices = yamlMatrices{}
+		names := make(map[string]int)
+		return newBlockEntries(func(text []byte, start int) error {
+			mapping, err := parseMapping(text, start, "names to matrices")
+			if err != nil || mapping == nil {
+				return err
+			}
+			for i := 0; i+1 < len(mapping.Content); i += 2 {
+				name, value := mapping.Content[i], mapping.Content[i+1]
+				if err := uniqueKey(names, name, "key"); err != nil {
+					return err
+				}
+				var matrix yamlMatrix
+				if err := value.Decode(&matrix); err != nil {
+					return positionError(err, value, text, start)
+				}
+				*matrices = append(*matrices, namedMatrix{Name: name.Value, Matrix: matrix})
+			}
+			return nil
+		}), nil
+	}
+	return entries
+}
+
+func shiftLines(node *yaml.Node, offset int) {
+	node.Line += offset
+	for _, child := range node.Content {
+		shiftLines(child, offset)
+	}
+}
+
+var errorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// syntaxError rebases the line of a yaml.v3 parser error to the document
+func syntaxError(err error, offset int) error {
+	match := errorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &ParseError{Line: line + offset, Msg: match[2]}
+}
+
+// positionError converts a decoding error of the value node into a ParseError
+func positionError(err error, node *yaml.Node, text []byte, start int) error {
+	var parseErr *ParseError
+	var matrixErr *matrixError
+	var typeErr *yaml.TypeError
+	switch {
+	case errors.As(err, &parseErr):
+		return parseErr
+	case errors.As(err, &matrixErr):
+		return matrixErr.position(text, start)
+	case errors.As(err, &typeErr) && len(typeErr.Errors) > 0:
+		// "line 12: cannot unmarshal !!str `abc` into int"
+		match := errorLine.FindStringSubmatch(typeErr.Errors[0])
+		if match == nil {
+			return err
+		}
+		line, _ := strconv.Atoi(match[1])
+		result := &ParseError{Line: line, Msg: match[2]}
+		if value := strings.Split(match[2], "`"); len(value) == 3 {
+			result.Column = findColumn(node, line, strings.TrimSuffix(value[1], "..."))
+		}
+		if more := len(typeErr.Errors) - 1; more > 0 {
+			result.Msg += fmt.Sprintf(" (and %d more errors)", more)
+		}
+		return result
+	}
+	return err
+}
+
+// position locates the malformed row of a literal block in the raw text of the section
+func (e *matrixError) position(text []byte, start int) *ParseError {
+	result := &ParseError{Line: e.node.Line, Column: e.node.Column, Msg: e.msg}
+	if e.node.Style&yaml.LiteralStyle == 0 {
+		return result
+	}
+	// The rows of a literal block start on the line after its indicator
+	line := e.node.Line + 1 + e.row
+	lines := bytes.Split(text, []byte("\n"))
+	if index := line - start; index >= 0 && index < len(lines) {
+		if column := fieldColumn(lines[index], e.field); column > 0 {
+			result.Line, result.Column = line, column
+		}
+	}
+	return result
+}
+
+// fieldColumn returns the 1-based column of the whitespace separated field of a line
+func fieldColumn(line []byte, field int) int {
+	inField := false
+	for i, c := range line {
+		space := c == ' ' || c == '\t' || c == '\r'
+		if !space && !inField {
+			if field == 0 {
+				return i + 1
+			}
+			field--
+		}
+		inField = !space
+	}
+	return 0
+}
+
+// findColumn returns the column of the scalar on line whose value starts with prefix
+func findColumn(node *yaml.Node, line int, prefix string) int {
+	if node.Kind == yaml.ScalarNode && node.Line == line && strings.HasPrefix(node.Value, prefix) {
+		return node.Column
+	}
+	for _, child := range node.Content {
+		if column := findColumn(child, line, prefix); column > 0 {
+			return column
+		}
+	}
+	return 0
+}
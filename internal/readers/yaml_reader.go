@@ -3,17 +3,16 @@ package readers
 import (
 	"fmt"
 	"io"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/spf13/viper"
-	"gopkg.in/yaml.v3"
 	"labours-go/internal/burndown"
 	"labours-go/internal/progress"
 )
 
+// YamlReader reads the YAML output of hercules, see yamlDocument
 type YamlReader struct {
-	data map[string]interface{}
+	doc yamlDocument
 }
 
 func (r *YamlReader) Read(file io.Reader) error {
@@ -23,10 +22,10 @@ func (r *YamlReader) Read(file io.Reader) error {
 	
 	progEstimator.StartOperation("Reading YAML data", 1)
 	
-	decoder := yaml.NewDecoder(file)
-	if err := decoder.Decode(&r.data); err != nil {
+	r.doc = yamlDocument{}
+	if err := decodeYAML(file, &r.doc); err != nil {
 		progEstimator.FinishOperation()
-		return fmt.Errorf("error decoding YAML: %v", err)
+		return fmt.Errorf("error decoding YAML: %w", err)
 	}
 	
 	progEstimator.UpdateProgress(1)
@@ -35,215 +34,160 @@ func (r *YamlReader) Read(file io.Reader) error {
 }
 
 func (r *YamlReader) GetName() string {
-	herculesData, ok := r.data["hercules"].(map[string]interface{})
-	if !ok {
+	if r.doc.Header == nil {
 		return ""
 	}
-	return herculesData["repository"].(string)
+	return r.doc.Header.Repository
 }
 
 func (r *YamlReader) GetHeader() (int64, int64) {
-	herculesData, ok := r.data["hercules"].(map[string]interface{})
-	if !ok {
+	if r.doc.Header == nil {
 		return 0, 0
 	}
-	begin := int64(herculesData["begin_unix_time"].(int))
-	end := int64(herculesData["end_unix_time"].(int))
-	return begin, end
+	return r.doc.Header.BeginUnixTime, r.doc.Header.EndUnixTime
+}
+
+// GetMetadata retrieves the description of the hercules run from the "hercules" section
+func (r *YamlReader) GetMetadata() (Metadata, error) {
+	header := r.doc.Header
+	if header == nil {
+		return Metadata{}, fmt.Errorf("missing hercules metadata in YAML")
+	}
+	metadata := Metadata{
+		Version:      header.Version,
+		HerculesHash: header.Hash,
+		Repository:   header.Repository,
+		Commits:      header.Commits,
+		RunTime:      time.Duration(header.RunTime) * time.Millisecond,
+	}
+	if header.BeginUnixTime > 0 {
+		metadata.BeginTime = time.Unix(header.BeginUnixTime, 0)
+	}
+	if header.EndUnixTime > 0 {
+		metadata.EndTime = time.Unix(header.EndUnixTime, 0)
+	}
+	return metadata, nil
 }
 
 func (r *YamlReader) GetProjectBurndown() (string, [][]int) {
-	burndownData, ok := r.data["Burndown"].(map[string]interface{})
-	if !ok {
+	if r.doc.Burndown == nil || len(r.doc.Burndown.Project) == 0 {
 		return "", nil
 	}
-	repo := r.GetName()
-	matrix := parseBurndownMatrix(burndownData["project"].(string))
-	return repo, transposeMatrix(matrix)
+	return r.GetName(), transposeMatrix(r.doc.Burndown.Project)
 }
 
 func (r *YamlReader) GetFilesBurndown() ([]FileBurndown, error) {
-	burndownData, ok := r.data["Burndown"].(map[string]interface{})
-	if !ok {
+	burndownData := r.doc.Burndown
+	if burndownData == nil {
 		return nil, fmt.Errorf("missing Burndown data in YAML")
 	}
-	filesData, ok := burndownData["files"].(map[string]interface{})
-	if !ok {
+	if burndownData.Files == nil {
 		return nil, fmt.Errorf("missing files data in Burndown")
 	}
 
-	var fileBurndowns []FileBurndown
-	for filename, matrixData := range filesData {
-		matrix := parseBurndownMatrix(matrixData.(string))
+	fileBurndowns := make([]FileBurndown, 0, len(burndownData.Files))
+	for _, file := range burndownData.Files {
 		fileBurndowns = append(fileBurndowns, FileBurndown{
-			Filename: filename,
-			Matrix:   transposeMatrix(matrix),
+			Filename: file.Name,
+			Matrix:   transposeMatrix(file.Matrix),
 		})
 	}
 	return fileBurndowns, nil
 }
 
 func (r *YamlReader) GetPeopleBurndown() ([]PeopleBurndown, error) {
-	burndownData, ok := r.data["Burndown"].(map[string]interface{})
-	if !ok {
+	burndownData := r.doc.Burndown
+	if burndownData == nil {
 		return nil, fmt.Errorf("missing Burndown data in YAML")
 	}
-	peopleData, ok := burndownData["people"].(map[string]interface{})
-	if !ok {
+	if burndownData.People == nil {
 		return nil, fmt.Errorf("missing people data in Burndown")
 	}
 
-	var peopleBurndowns []PeopleBurndown
-	for person, matrixData := range peopleData {
-		matrix := parseBurndownMatrix(matrixData.(string))
+	peopleBurndowns := make([]PeopleBurndown, 0, len(burndownData.People))
+	for _, person := range burndownData.People {
 		peopleBurndowns = append(peopleBurndowns, PeopleBurndown{
-			Person: person,
-			Matrix: transposeMatrix(matrix),
+			Person: person.Name,
+			Matrix: transposeMatrix(person.Matrix),
 		})
 	}
 	return peopleBurndowns, nil
 }
 
 func (r *YamlReader) GetOwnershipBurndown() ([]string, map[string][][]int, error) {
-	burndownData, ok := r.data["Burndown"].(map[string]interface{})
-	if !ok {
+	burndownData := r.doc.Burndown
+	if burndownData == nil {
 		return nil, nil, fmt.Errorf("missing Burndown data in YAML")
 	}
-	peopleSequence, ok := burndownData["people_sequence"].([]string)
-	if !ok {
+	if burndownData.PeopleSequence == nil {
 		return nil, nil, fmt.Errorf("missing people_sequence in Burndown")
 	}
-
-	peopleData, ok := burndownData["people"].(map[string]interface{})
-	if !ok {
+	if burndownData.People == nil {
 		return nil, nil, fmt.Errorf("missing people data in Burndown")
 	}
 
-	ownership := make(map[string][][]int)
-	for person, matrixData := range peopleData {
-		matrix := parseBurndownMatrix(matrixData.(string))
-		ownership[person] = matrix
+	ownership := make(map[string][][]int, len(burndownData.People))
+	for _, person := range burndownData.People {
+		ownership[person.Name] = person.Matrix
 	}
 
-	return peopleSequence, ownership, nil
+	return burndownData.PeopleSequence, ownership, nil
 }
 
 func (r *YamlReader) GetPeopleInteraction() ([]string, [][]int, error) {
-	burndownData, ok := r.data["Burndown"].(map[string]interface{})
-	if !ok {
+	burndownData := r.doc.Burndown
+	if burndownData == nil {
 		return nil, nil, fmt.Errorf("missing Burndown data in YAML")
 	}
-	peopleSequence, ok := burndownData["people_sequence"].([]string)
-	if !ok {
+	if burndownData.PeopleSequence == nil {
 		return nil, nil, fmt.Errorf("missing people_sequence in Burndown")
 	}
-	interactionData, ok := burndownData["people_interaction"].(string)
-	if !ok {
+	if burndownData.PeopleInteraction == nil {
 		return nil, nil, fmt.Errorf("missing people_interaction data")
 	}
-
-	matrix := parseBurndownMatrix(interactionData)
-	return peopleSequence, matrix, nil
+	return burndownData.PeopleSequence, burndownData.PeopleInteraction, nil
 }
 
 func (r *YamlReader) GetFileCooccurrence() ([]string, [][]int, error) {
-	couplesData, ok := r.data["Couples"].(map[string]interface{})
-	if !ok {
+	couplesData := r.doc.Couples
+	if couplesData == nil {
 		return nil, nil, fmt.Errorf("missing Couples data in YAML")
 	}
 	
-	// Try Python-style nested structure first: files_coocc["index"] and files_coocc["matrix"]
-	if filesCoocc, exists := couplesData["files_coocc"].(map[string]interface{}); exists {
-		fileIndex, indexOk := filesCoocc["index"].([]string)
-		if !indexOk {
-			// Try []interface{} and convert to []string
-			if indexIntf, ok := filesCoocc["index"].([]interface{}); ok {
-				fileIndex = make([]string, len(indexIntf))
-				for i, v := range indexIntf {
-					if str, ok := v.(string); ok {
-						fileIndex[i] = str
-					}
-				}
-				indexOk = true
-			}
-		}
-		
-		if indexOk {
-			// Handle both string matrix and map-based sparse matrix format
-			if matrixStr, ok := filesCoocc["matrix"].(string); ok {
-				// Dense matrix as string
-				matrix := parseBurndownMatrix(matrixStr)
-				return fileIndex, matrix, nil
-			} else if matrixData, ok := filesCoocc["matrix"].([]interface{}); ok {
-				// Sparse matrix as array of maps (Python format)
-				matrix := parseCoooccurrenceMatrix(matrixData)
-				return fileIndex, matrix, nil
-			}
-		}
+	// Python-style nested structure: files_coocc["index"] and files_coocc["matrix"]
+	if coocc := couplesData.FilesCoocc; coocc != nil && coocc.Index != nil {
+		return coocc.Index, coocc.Matrix, nil
 	}
 	
 	// Fallback to flat structure (original Go format)
-	fileIndex, ok := couplesData["file_couples_index"].([]string)
-	if !ok {
+	if couplesData.FileCouplesIndex == nil {
 		return nil, nil, fmt.Errorf("missing file_couples_index in Couples")
 	}
-	matrixData, ok := couplesData["file_couples_matrix"].(string)
-	if !ok {
+	if couplesData.FileCouplesMatrix == nil {
 		return nil, nil, fmt.Errorf("missing file_couples_matrix in Couples")
 	}
-
-	matrix := parseBurndownMatrix(matrixData)
-	return fileIndex, matrix, nil
+	return couplesData.FileCouplesIndex, couplesData.FileCouplesMatrix, nil
 }
 
 func (r *YamlReader) GetPeopleCooccurrence() ([]string, [][]int, error) {
-	couplesData, ok := r.data["Couples"].(map[string]interface{})
-	if !ok {
+	couplesData := r.doc.Couples
+	if couplesData == nil {
 		return nil, nil, fmt.Errorf("missing Couples data in YAML")
 	}
 	
-	// Try Python-style nested structure first: people_coocc["index"] and people_coocc["matrix"]
-	if peopleCoocc, exists := couplesData["people_coocc"].(map[string]interface{}); exists {
-		peopleIndex, indexOk := peopleCoocc["index"].([]string)
-		if !indexOk {
-			// Try []interface{} and convert to []string
-			if indexIntf, ok := peopleCoocc["index"].([]interface{}); ok {
-				peopleIndex = make([]string, len(indexIntf))
-				for i, v := range indexIntf {
-					if str, ok := v.(string); ok {
-						peopleIndex[i] = str
-					}
-				}
-				indexOk = true
-			}
-		}
-		
-		if indexOk {
-			// Handle both string matrix and map-based sparse matrix format
-			if matrixStr, ok := peopleCoocc["matrix"].(string); ok {
-				// Dense matrix as string
-				matrix := parseBurndownMatrix(matrixStr)
-				return peopleIndex, matrix, nil
-			} else if matrixData, ok := peopleCoocc["matrix"].([]interface{}); ok {
-				// Sparse matrix as array of maps (Python format)
-				matrix := parseCoooccurrenceMatrix(matrixData)
-				return peopleIndex, matrix, nil
-			}
-		}
+	// Python-style nested structure: people_coocc["index"] and people_coocc["matrix"]
+	if coocc := couplesData.PeopleCoocc; coocc != nil && coocc.Index != nil {
+		return coocc.Index, coocc.Matrix, nil
 	}
 	
 	// Fallback to flat structure (original Go format)
-	peopleIndex, ok := couplesData["people_couples_index"].([]string)
-	if !ok {
+	if couplesData.PeopleCouplesIndex == nil {
 		return nil, nil, fmt.Errorf("missing people_couples_index in Couples")
 	}
-	matrixData, ok := couplesData["people_couples_matrix"].(string)
-	if !ok {
+	if couplesData.PeopleCouplesMatrix == nil {
 		return nil, nil, fmt.Errorf("missing people_couples_matrix in Couples")
 	}
-
-	matrix := parseBurndownMatrix(matrixData)
-	return peopleIndex, matrix, nil
+	return couplesData.PeopleCouplesIndex, couplesData.PeopleCouplesMatrix, nil
 }
 
 func (r *YamlReader) GetShotnessCooccurrence() ([]string, [][]int, error) {
@@ -301,69 +245,22 @@ func min32(a, b int32) int32 {
 }
 
 func (r *YamlReader) GetShotnessRecords() ([]ShotnessRecord, error) {
-	shotnessData, ok := r.data["Shotness"].([]interface{})
-	if !ok {
+	if r.doc.Shotness == nil {
 		return []ShotnessRecord{}, fmt.Errorf("missing Shotness data in YAML")
 	}
 
-	var records []ShotnessRecord
-	for _, recordInterface := range shotnessData {
-		record, ok := recordInterface.(map[string]interface{})
-		if !ok {
-			continue // Skip invalid records
-		}
-		
-		counters := make(map[int32]int32)
-		if countData, ok := record["counters"].(map[interface{}]interface{}); ok {
-			for timeKey, count := range countData {
-				// Convert time key and count to int32
-				var timeInt int32
-				var countInt int32
-				
-				switch t := timeKey.(type) {
-				case int:
-					timeInt = int32(t)
-				case int32:
-					timeInt = t
-				case int64:
-					timeInt = int32(t)
-				default:
-					continue // Skip invalid time keys
-				}
-				
-				switch c := count.(type) {
-				case int:
-					countInt = int32(c)
-				case int32:
-					countInt = c
-				case int64:
-					countInt = int32(c)
-				default:
-					continue // Skip invalid counts
-				}
-				
-				counters[timeInt] = countInt
-			}
-		}
-
-		// Safely extract string values
-		var recordType, recordName, recordFile string
-		if t, ok := record["type"].(string); ok {
-			recordType = t
-		}
-		if n, ok := record["name"].(string); ok {
-			recordName = n
+	records := make([]ShotnessRecord, len(r.doc.Shotness))
+	for i, record := range r.doc.Shotness {
+		counters := record.Counters
+		if counters == nil {
+			counters = make(map[int32]int32)
 		}
-		if f, ok := record["file"].(string); ok {
-			recordFile = f
-		}
-
-		records = append(records, ShotnessRecord{
-			Type:     recordType,
-			Name:     recordName,
-			File:     recordFile,
+		records[i] = ShotnessRecord{
+			Type:     record.InternalRole,
+			Name:     record.Name,
+			File:     record.File,
 			Counters: counters,
-		})
+		}
 	}
 	return records, nil
 }
@@ -411,254 +308,85 @@ func (r *YamlReader) GetDeveloperStats() ([]DeveloperStat, error) {
 // GetDeveloperTimeSeriesData returns Python-compatible time series data: (people, days)
 // where days is {day: {dev: DevDay}}
 func (r *YamlReader) GetDeveloperTimeSeriesData() (*DeveloperTimeSeriesData, error) {
-	devsData, ok := r.data["Devs"].(map[string]interface{})
-	if !ok {
+	devsData := r.doc.Devs
+	if devsData == nil {
 		return nil, fmt.Errorf("missing Devs data in YAML")
 	}
 	
-	// Get people list (dev names)
-	var people []string
-	if peopleData, ok := devsData["people"].([]interface{}); ok {
-		for _, p := range peopleData {
-			if str, ok := p.(string); ok {
-				people = append(people, str)
-			}
-		}
-	} else if devIndex, ok := devsData["dev_index"].([]interface{}); ok {
-		for _, p := range devIndex {
-			if str, ok := p.(string); ok {
-				people = append(people, str)
-			}
-		}
-	} else {
+	people := devsData.People
+	if people == nil {
+		people = devsData.DevIndex
+	}
+	if people == nil {
 		return nil, fmt.Errorf("missing people/dev_index in Devs")
 	}
-	
-	// Get ticks (time series data)
-	ticks, ok := devsData["ticks"].(map[interface{}]interface{})
-	if !ok {
+	if devsData.Ticks == nil {
 		return nil, fmt.Errorf("missing ticks in Devs")
 	}
 	
-	days := make(map[int]map[int]DevDay)
-	
-	for dayKey, dayData := range ticks {
-		dayInt, ok := convertToInt(dayKey)
-		if !ok {
-			continue
-		}
-		
-		dayMap, ok := dayData.(map[interface{}]interface{})
-		if !ok {
-			continue
-		}
-		
-		devs, ok := dayMap["devs"].(map[interface{}]interface{})
-		if !ok {
-			continue
-		}
-		
-		dayDevs := make(map[int]DevDay)
-		
-		for devKey, devData := range devs {
-			devInt, ok := convertToInt(devKey)
-			if !ok {
-				continue
-			}
-			
-			devMap, ok := devData.(map[interface{}]interface{})
-			if !ok {
-				continue
-			}
-			
-			// Parse DevDay data
-			var commits, added, removed, changed int
-			var languages map[string][]int
-			
-			if c, ok := convertToInt(devMap["commits"]); ok {
-				commits = c
-			}
-			if a, ok := convertToInt(devMap["added"]); ok {
-				added = a
-			}
-			if r, ok := convertToInt(devMap["removed"]); ok {
-				removed = r
-			}
-			if c, ok := convertToInt(devMap["changed"]); ok {
-				changed = c
-			}
-			
-			// Parse languages if present
-			if langData, ok := devMap["languages"].(map[interface{}]interface{}); ok {
-				languages = make(map[string][]int)
-				for langKey, langStats := range langData {
-					if langStr, ok := langKey.(string); ok {
-						if langList, ok := langStats.([]interface{}); ok && len(langList) >= 3 {
-							var langStats []int
-							for _, stat := range langList {
-								if statInt, ok := convertToInt(stat); ok {
-									langStats = append(langStats, statInt)
-								}
-							}
-							if len(langStats) >= 3 {
-								languages[langStr] = langStats
-							}
-						}
-					}
-				}
-			}
-			
-			dayDevs[devInt] = DevDay{
-				Commits:       commits,
-				LinesAdded:    added,
-				LinesRemoved:  removed,
-				LinesModified: changed,
-				Languages:     languages,
-			}
+	days := make(map[int]map[int]DevDay, len(devsData.Ticks))
+	for day, devs := range devsData.Ticks {
+		dayDevs := make(map[int]DevDay, len(devs))
+		for dev, tick := range devs {
+			dayDevs[dev] = DevDay(tick)
 		}
-		
-		days[dayInt] = dayDevs
+		days[day] = dayDevs
 	}
-	
+
 	return &DeveloperTimeSeriesData{
-		People: people,
-		Days:   days,
+		People:   people,
+		Days:     days,
+		TickSize: devsData.TickSize,
 	}, nil
 }
 
+func (r *YamlReader) GetCommentSentiment() (map[int]SentimentTick, error) {
+	if len(r.doc.Sentiment) == 0 {
+		return nil, fmt.Errorf("missing Sentiment data in YAML - run hercules with --sentiment")
+	}
+	ticks := make(map[int]SentimentTick, len(r.doc.Sentiment))
+	for tick, sentiment := range r.doc.Sentiment {
+		ticks[tick] = SentimentTick{Value: sentiment.Value, Comments: sentiment.Comments, Commits: sentiment.Commits}
+	}
+	return ticks, nil
+}
+
 func (r *YamlReader) GetLanguageStats() ([]LanguageStat, error) {
 	// Stub: Language stats data is typically not present in YAML files.
 	return nil, fmt.Errorf("language stats not implemented for YAML")
 }
 
 func (r *YamlReader) GetRuntimeStats() (map[string]float64, error) {
-	// Stub: Runtime stats are typically not present in YAML files.
-	return nil, fmt.Errorf("runtime stats not implemented for YAML")
-}
-
-// Helper function to parse burndown matrices
-func parseBurndownMatrix(data string) [][]int {
-	lines := strings.Split(data, "\n")
-	var matrix [][]int
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		numbers := strings.Fields(line)
-		var row []int
-		for _, num := range numbers {
-			val, err := strconv.Atoi(strings.TrimSpace(num))
-			if err != nil {
-				continue
-			}
-			row = append(row, val)
-		}
-		if len(row) > 0 {
-			matrix = append(matrix, row)
-		}
+	// hercules does not write them to YAML, but files converted from Protobuf keep them
+	if r.doc.Header == nil || len(r.doc.Header.RunTimePerItem) == 0 {
+		return nil, fmt.Errorf("runtime stats not implemented for YAML")
 	}
-	return matrix
-}
-
-// parseCoooccurrenceMatrix converts Python's sparse matrix format to dense matrix
-// Python format: array of maps where each map represents non-zero values in that row
-func parseCoooccurrenceMatrix(data []interface{}) [][]int {
-	if len(data) == 0 {
-		return [][]int{}
-	}
-	
-	// Find maximum column index to determine matrix size
-	maxCol := 0
-	for _, rowData := range data {
-		if rowMap, ok := rowData.(map[interface{}]interface{}); ok {
-			for colKey := range rowMap {
-				if colInt, ok := convertToInt(colKey); ok && colInt > maxCol {
-					maxCol = colInt
-				}
-			}
-		}
-	}
-	
-	// Create dense matrix
-	matrix := make([][]int, len(data))
-	for i := range matrix {
-		matrix[i] = make([]int, maxCol+1)
-	}
-	
-	// Fill in non-zero values
-	for rowIdx, rowData := range data {
-		if rowMap, ok := rowData.(map[interface{}]interface{}); ok {
-			for colKey, valKey := range rowMap {
-				if colInt, ok := convertToInt(colKey); ok {
-					if valInt, ok := convertToInt(valKey); ok {
-						if colInt < len(matrix[rowIdx]) {
-							matrix[rowIdx][colInt] = valInt
-						}
-					}
-				}
-			}
-		}
-	}
-	
-	return matrix
-}
-
-// convertToInt safely converts various number types to int
-func convertToInt(val interface{}) (int, bool) {
-	switch v := val.(type) {
-	case int:
-		return v, true
-	case int32:
-		return int(v), true
-	case int64:
-		return int(v), true
-	case float64:
-		return int(v), true
-	case string:
-		if i, err := strconv.Atoi(v); err == nil {
-			return i, true
-		}
-	}
-	return 0, false
+	return r.doc.Header.RunTimePerItem, nil
 }
 
 // GetBurndownParameters retrieves burndown parameters for YAML reader
 func (r *YamlReader) GetBurndownParameters() (burndown.BurndownParameters, error) {
-	burndownData, ok := r.data["Burndown"].(map[string]interface{})
-	if !ok {
+	burndownData := r.doc.Burndown
+	if burndownData == nil {
 		return burndown.BurndownParameters{}, fmt.Errorf("missing Burndown data in YAML")
 	}
 	
-	// Extract parameters from YAML - these ARE present in hercules YAML output
-	var sampling int = 1      // Default
-	var granularity int = 1   // Default  
-	var tickSize float64 = 86400 // Default (24 hours)
-	
-	if val, exists := burndownData["sampling"]; exists {
-		if intVal, ok := val.(int); ok {
-			sampling = intVal
-		}
+	// These ARE present in hercules YAML output; the defaults cover hand-written files
+	params := burndown.BurndownParameters{
+		Sampling:    burndownData.Sampling,
+		Granularity: burndownData.Granularity,
+		TickSize:    burndownData.TickSize,
 	}
-	
-	if val, exists := burndownData["granularity"]; exists {
-		if intVal, ok := val.(int); ok {
-			granularity = intVal
-		}
+	if params.Sampling == 0 {
+		params.Sampling = 1
 	}
-	
-	if val, exists := burndownData["tick_size"]; exists {
-		if intVal, ok := val.(int); ok {
-			tickSize = float64(intVal)
-		} else if floatVal, ok := val.(float64); ok {
-			tickSize = floatVal
-		}
+	if params.Granularity == 0 {
+		params.Granularity = 1
 	}
-	
-	return burndown.BurndownParameters{
-		Sampling:    sampling,
-		Granularity: granularity,
-		TickSize:    tickSize,
-	}, nil
+	if params.TickSize == 0 {
+		params.TickSize = 86400 // 24 hours
+	}
+	return params, nil
 }
 
 // GetProjectBurndownWithHeader retrieves project burndown with header for YAML reader
@@ -683,3 +411,57 @@ func (r *YamlReader) GetProjectBurndownWithHeader() (burndown.BurndownHeader, st
 	
 	return header, name, matrix, nil
 }
+
+// releaseVersion returns the version of the hercules header, see herculesSource
+func (r *YamlReader) releaseVersion() int {
+	if r.doc.Header == nil {
+		return 0
+	}
+	return r.doc.Header.Version
+}
+
+// burndownParameters returns the burndown parameters as stored
+func (r *YamlReader) burndownParameters() (int, int, float64, bool) {
+	if r.doc.Burndown == nil || r.doc.Burndown.Granularity <= 0 {
+		return 0, 0, 0, false
+	}
+	return r.doc.Burndown.Granularity, r.doc.Burndown.Sampling, r.doc.Burndown.TickSize, true
+}
+
+func (r *YamlReader) filesOwnership() []map[int]int {
+	if r.doc.Burndown == nil {
+		return nil
+	}
+	return r.doc.Burndown.FilesOwnership
+}
+
+func (r *YamlReader) filesLines() []int {
+	if r.doc.Couples == nil || r.doc.Couples.FilesCoocc == nil {
+		return nil
+	}
+	return r.doc.Couples.FilesCoocc.Lines
+}
+
+// peopleFiles resolves the file names of author_files in the files index
+func (r *YamlReader) peopleFiles() [][]int {
+	couples := r.doc.Couples
+	if couples == nil || couples.FilesCoocc == nil || couples.PeopleCoocc == nil {
+		return nil
+	}
+	files := make(map[string]int, len(couples.FilesCoocc.Index))
+	for i, name := range couples.FilesCoocc.Index {
+		files[name] = i
+	}
+	result := make([][]int, len(couples.PeopleCoocc.AuthorFiles))
+	for person, authorFiles := range couples.PeopleCoocc.AuthorFiles {
+		result[person] = []int{}
+		for _, names := range authorFiles {
+			for _, name := range names {
+				if file, ok := files[name]; ok {
+					result[person] = append(result[person], file)
+				}
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,372 @@
+package readers
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readYAML(t *testing.T, text string) (*YamlReader, error) {
+	t.Helper()
+	reader := &YamlReader{}
+	return reader, reader.Read(strings.NewReader(text))
+}
+
+func readYAMLFile(t *testing.T, path string) *YamlReader {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	reader := &YamlReader{}
+	require.NoError(t, reader.Read(file))
+	return reader
+}
+
+func TestYamlReaderHerculesDevs(t *testing.T) {
+	reader := readYAMLFile(t, "../../example_data/hercules_devs.yaml")
+
+	data, err := reader.GetDeveloperTimeSeriesData()
+	require.NoError(t, err)
+	assert.Len(t, data.People, 1)
+	assert.Equal(t, 86400.0, data.TickSize)
+	require.Len(t, data.Days, 2)
+	day := data.Days[233][0]
+	assert.Equal(t, 4, day.Commits)
+	assert.Equal(t, 5108, day.LinesAdded)
+	assert.Equal(t, 1663, day.LinesRemoved)
+	assert.Equal(t, 955, day.LinesModified)
+	assert.Equal(t, []int{2715, 1431, 649}, day.Languages["Go"])
+	assert.Equal(t, []int{73, 0, 0}, day.Languages["Protocol Buffer"])
+
+	metadata, err := reader.GetMetadata()
+	require.NoError(t, err)
+	assert.Equal(t, 8, metadata.Commits)
+	assert.Equal(t, int64(1734315181), metadata.BeginTime.Unix())
+}
+
+func TestYamlReaderHerculesBurndown(t *testing.T) {
+	reader := readYAMLFile(t, "../../data/labours-go_burndown.yaml")
+
+	header, name, project, err := reader.GetProjectBurndownWithHeader()
+	require.NoError(t, err)
+	assert.Equal(t, ".", name)
+	assert.Equal(t, 30, header.Granularity)
+	assert.Len(t, project, 8)
+	assert.Len(t, project[0], 8)
+
+	files, err := reader.GetFilesBurndown()
+	require.NoError(t, err)
+	assert.Equal(t, ".github/workflows/test.yml", files[0].Filename, "files are kept in document order")
+
+	sequence, ownership, err := reader.GetOwnershipBurndown()
+	require.NoError(t, err)
+	require.Len(t, sequence, 1)
+	assert.Equal(t, 11623, ownership[sequence[0]][7][7])
+
+	people, interaction, err := reader.GetPeopleInteraction()
+	require.NoError(t, err)
+	assert.Equal(t, sequence, people)
+	assert.Equal(t, 16528, interaction[0][0])
+}
+
+func TestYamlReaderHerculesCouples(t *testing.T) {
+	reader := readYAMLFile(t, "../../data/labours-go_couples.yaml")
+
+	index, matrix, err := reader.GetFileCooccurrence()
+	require.NoError(t, err)
+	require.Len(t, matrix, len(index))
+	assert.Len(t, matrix[0], len(index))
+	assert.Equal(t, 1, matrix[0][5])
+
+	// hercules adds a row for unmatched authors, the matrix stays square like in Python
+	people, matrix, err := reader.GetPeopleCooccurrence()
+	require.NoError(t, err)
+	assert.Len(t, people, 1)
+	assert.Equal(t, [][]int{{126, 0}, {0, 0}}, matrix)
+}
+
+const yamlSchemaDocument = `hercules:
+  version: 2
+  repository: "test"
+  begin_unix_time: 1500000000
+  end_unix_time: 1600000000
+Couples:
+  file_couples_index:
+    - "a.go"
+    - "b.go"
+  file_couples_matrix: |-
+    2 1
+    1 3
+Shotness:
+  - name: "main"
+    file: "main.go"
+    internal_role: "Function"
+    counters: {0: 3, 2: 1}
+Sentiment:
+  4: [0.6250, [abc123, def456], "nice|fine"]
+Unknown:
+  ignored: [1, 2
+    , 3]
+`
+
+func TestYamlReaderSchema(t *testing.T) {
+	reader, err := readYAML(t, yamlSchemaDocument)
+	require.NoError(t, err)
+
+	index, matrix, err := reader.GetFileCooccurrence()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.go", "b.go"}, index)
+	assert.Equal(t, [][]int{{2, 1}, {1, 3}}, matrix)
+
+	records, err := reader.GetShotnessRecords()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, ShotnessRecord{Type: "Function", Name: "main", File: "main.go",
+		Counters: map[int32]int32{0: 3, 2: 1}}, records[0])
+
+	assert.Equal(t, yamlSentimentTick{Value: 0.625, Commits: []string{"abc123", "def456"},
+		Comments: []string{"nice", "fine"}}, reader.doc.Sentiment[4])
+	sentiment, err := reader.GetCommentSentiment()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]SentimentTick{4: {Value: 0.625, Comments: []string{"nice", "fine"},
+		Commits: []string{"abc123", "def456"}}}, sentiment)
+
+	_, err = reader.GetDeveloperTimeSeriesData()
+	assert.EqualError(t, err, "missing Devs data in YAML")
+}
+
+func TestYamlReaderErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name: "matrix value",
+			document: `hercules:
+  repository: x
+Burndown:
+  "project": |-
+    10  0
+    7 x3
+`,
+			want: `line 6, column 7: expected an integer, got "x3"`,
+		},
+		{
+			name: "ragged matrix",
+			document: `Burndown:
+  people_interaction: |-
+    1 2 3
+    4 5
+`,
+			want: "line 4, column 5: matrix row has 2 values, expected 3",
+		},
+		{
+			name: "dev tick",
+			document: `Devs:
+  ticks:
+    0:
+      0: [4, 10, 0, 2, {Go: [3, 0, zero]}]
+  people: ["a"]
+`,
+			want: `line 4, column 36: expected an integer, got "zero"`,
+		},
+		{
+			name: "short dev tick",
+			document: `Devs:
+  ticks:
+    0:
+      0: [4, 10]
+`,
+			want: "line 4, column 10: expected [commits, added, removed, changed, {languages}]",
+		},
+		{
+			name: "coocc column",
+			document: `Couples:
+  files_coocc:
+    index: ["a", "b"]
+    matrix:
+      - {0: 1, 5: 2}
+      - {}
+`,
+			want: "line 5, column 16: column 5 is out of range for a 2x2 matrix",
+		},
+		{
+			name: "type error",
+			document: `hercules:
+  version: 1
+Burndown:
+  granularity: thirty
+`,
+			want: "line 4, column 16: cannot unmarshal !!str `thirty` into int",
+		},
+		{
+			name: "syntax error",
+			document: `hercules:
+  version: 1
+Devs:
+  people: a: b
+`,
+			want: "line 4: mapping values are not allowed in this context",
+		},
+		{
+			name: "duplicate section",
+			document: `Devs:
+  people: [a]
+hercules:
+  version: 1
+Devs:
+  people: [b]
+`,
+			want: "line 5, column 1: section Devs is already defined at line 1",
+		},
+		{
+			name:     "empty",
+			document: "# nothing\n",
+			want:     "empty YAML document",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readYAML(t, tt.document)
+			require.Error(t, err)
+			assert.Equal(t, "error decoding YAML: "+tt.want, err.Error())
+		})
+	}
+
+	_, err := readYAML(t, tests[0].document)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 6, parseErr.Line)
+}
+
+func TestYamlReaderSections(t *testing.T) {
+	// Document markers and comments between sections keep the line numbers
+	_, err := readYAML(t, `---
+# hercules output
+hercules:
+  repository: "marked"
+...
+---
+Burndown:
+  "project": |-
+    1 2
+    3 y
+`)
+	assert.EqualError(t, err, `error decoding YAML: line 10, column 7: expected an integer, got "y"`)
+
+	// Flow style documents are decoded whole
+	reader, err := readYAML(t, `{"hercules": {"repository": "flow"},
+"Burndown": {"project": "1 0\n2 3"}}
+`)
+	require.NoError(t, err)
+	name, matrix := reader.GetProjectBurndown()
+	assert.Equal(t, "flow", name)
+	assert.Equal(t, [][]int{{1, 2}, {0, 3}}, matrix)
+}
+
+func TestYamlReaderBurndownEntries(t *testing.T) {
+	// The entries of Burndown are decoded one at a time and give the same document as
+	// decoding the section whole
+	text, err := os.ReadFile("../../data/labours-go_burndown.yaml")
+	require.NoError(t, err)
+	var streamed, whole yamlDocument
+	require.NoError(t, decodeYAML(strings.NewReader(string(text)), &streamed))
+	require.NoError(t, decodeYAMLSection(text, 1, &whole, make(map[string]int)))
+	assert.Equal(t, whole, streamed)
+	assert.NotEmpty(t, streamed.Burndown.Files)
+
+	reader, err := readYAML(t, `Burndown:
+  granularity: 30
+  files:
+    # comment between files
+    "a.go": |-
+      1 0
+      2 3
+
+    b.go: "4 5"
+  people_sequence:
+  - alice
+  people: {"alice": "6"}
+Devs:
+  people: [alice]
+`)
+	require.NoError(t, err)
+	files, err := reader.GetFilesBurndown()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "b.go", files[1].Filename)
+	assert.Equal(t, [][]int{{4}, {5}}, files[1].Matrix)
+	people, err := reader.GetPeopleBurndown()
+	require.NoError(t, err)
+	assert.Equal(t, "alice", people[0].Person)
+	assert.Equal(t, []string{"Burndown", "Devs"}, reader.doc.Sections)
+
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name: "file matrix",
+			document: `Burndown:
+  files:
+    "a.go": |-
+      1 2
+    "b.go": |-
+      3 4
+      5 x6
+`,
+			want: `line 7, column 9: expected an integer, got "x6"`,
+		},
+		{
+			name: "duplicate file",
+			document: `Burndown:
+  people:
+    "alice": "1"
+    "alice": "2"
+`,
+			want: "line 4, column 5: key alice is already defined at line 3",
+		},
+		{
+			name: "duplicate entry",
+			document: `Burndown:
+  files:
+    "a.go": "1"
+  granularity: 30
+  files: {}
+`,
+			want: "line 5, column 3: key files is already defined at line 2",
+		},
+		{
+			name: "duplicate section",
+			document: `Burndown:
+  granularity: 30
+Burndown:
+  granularity: 30
+`,
+			want: "line 3, column 1: section Burndown is already defined at line 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readYAML(t, tt.document)
+			require.Error(t, err)
+			assert.Equal(t, "error decoding YAML: "+tt.want, err.Error())
+		})
+	}
+}
+
+func TestYamlReaderDetectAndRead(t *testing.T) {
+	reader, err := DetectAndReadInput("../../example_data/hercules_burndown.yaml", "auto")
+	require.NoError(t, err)
+	_, ok := reader.(*YamlReader)
+	assert.True(t, ok)
+	name, matrix := reader.GetProjectBurndown()
+	assert.NotEmpty(t, name)
+	assert.NotEmpty(t, matrix)
+}
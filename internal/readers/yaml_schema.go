@@ -0,0 +1,284 @@
+package readers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The types in this file mirror the YAML written by hercules' analyses (see the
+// serializeText methods of its leaves). Matrices and ticks are converted while decoding,
+// so that malformed values are reported with their position in the document.
+
+// yamlDocument holds the sections of a hercules YAML document that labours plots;
+// sections missing from the input stay nil
+type yamlDocument struct {
+	Header    *yamlHeader               // "hercules"
+	Burndown  *yamlBurndown             // "Burndown"
+	Couples   *yamlCouples              // "Couples"
+	Devs      *yamlDevs                 // "Devs"
+	Shotness  []yamlShotness            // "Shotness"
+	Sentiment map[int]yamlSentimentTick // "Sentiment"
+
+	Sections []string // decoded top-level keys in document order
+}
+
+// decodeSection decodes the top-level section key and records it in Sections. Sections of
+// analyses labours does not plot are only recorded.
+func (d *yamlDocument) decodeSection(key string, node *yaml.Node) error {
+	if err := d.decodeKnownSection(key, node); err != nil {
+		return err
+	}
+	d.Sections = append(d.Sections, key)
+	return nil
+}
+
+func (d *yamlDocument) decodeKnownSection(key string, node *yaml.Node) error {
+	switch key {
+	case "hercules":
+		return node.Decode(&d.Header)
+	case "Burndown":
+		return node.Decode(&d.Burndown)
+	case "Couples":
+		return node.Decode(&d.Couples)
+	case "Devs":
+		return node.Decode(&d.Devs)
+	case "Shotness":
+		if node.Kind != yaml.SequenceNode {
+			return nodeError(node, "expected a sequence of shotness records")
+		}
+		return node.Decode(&d.Shotness)
+	case "Sentiment":
+		return node.Decode(&d.Sentiment)
+	}
+	return nil
+}
+
+type yamlHeader struct {
+	Version       int    `yaml:"version"`
+	Hash          string `yaml:"hash"`
+	Repository    string `yaml:"repository"`
+	BeginUnixTime int64  `yaml:"begin_unix_time"`
+	EndUnixTime   int64  `yaml:"end_unix_time"`
+	Commits       int    `yaml:"commits"`
+	RunTime       int64  `yaml:"run_time"` // milliseconds
+
+	RunTimePerItem map[string]float64 `yaml:"run_time_per_item"` // seconds, written by labours convert
+}
+
+type yamlBurndown struct {
+	Granularity       int           `yaml:"granularity"`
+	Sampling          int           `yaml:"sampling"`
+	TickSize          float64       `yaml:"tick_size"`
+	Project           yamlMatrix    `yaml:"project"`
+	Files             yamlMatrices  `yaml:"files"`
+	FilesOwnership    []map[int]int `yaml:"files_ownership"` // per file: {person: lines}
+	PeopleSequence    []string      `yaml:"people_sequence"`
+	People            yamlMatrices  `yaml:"people"`
+	PeopleInteraction yamlMatrix    `yaml:"people_interaction"`
+}
+
+type yamlCouples struct {
+	FilesCoocc  *yamlCoocc `yaml:"files_coocc"`
+	PeopleCoocc *yamlCoocc `yaml:"people_coocc"`
+
+	// Flat layout of the original Go format
+	FileCouplesIndex    []string   `yaml:"file_couples_index"`
+	FileCouplesMatrix   yamlMatrix `yaml:"file_couples_matrix"`
+	PeopleCouplesIndex  []string   `yaml:"people_couples_index"`
+	PeopleCouplesMatrix yamlMatrix `yaml:"people_couples_matrix"`
+}
+
+type yamlCoocc struct {
+	Index       []string              `yaml:"index"`
+	Lines       []int                 `yaml:"lines"` // files only
+	Matrix      yamlCooccMatrix       `yaml:"matrix"`
+	AuthorFiles []map[string][]string `yaml:"author_files"` // people only
+}
+
+type yamlDevs struct {
+	Ticks    map[int]map[int]yamlDevTick `yaml:"ticks"`
+	People   []string                    `yaml:"people"`
+	DevIndex []string                    `yaml:"dev_index"` // name of people in the protobuf format
+	TickSize float64                     `yaml:"tick_size"`
+}
+
+type yamlShotness struct {
+	Name         string          `yaml:"name"`
+	File         string          `yaml:"file"`
+	InternalRole string          `yaml:"internal_role"`
+	Counters     map[int32]int32 `yaml:"counters"`
+}
+
+// yamlMatrix is a dense integer matrix written as a text block of whitespace separated rows
+type yamlMatrix [][]int
+
+func (m *yamlMatrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return nodeError(node, "expected a matrix of whitespace separated integers")
+	}
+	matrix, err := parseMatrixText(node.Value)
+	if err != nil {
+		err.node = node
+		return err
+	}
+	*m = matrix
+	return nil
+}
+
+// parseMatrixText parses the rows of a text matrix; blank lines are skipped
+func parseMatrixText(text string) ([][]int, *matrixError) {
+	var matrix [][]int
+	for row, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		values := make([]int, len(fields))
+		for i, field := range fields {
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, &matrixError{row: row, field: i, msg: fmt.Sprintf("expected an integer, got %q", field)}
+			}
+			values[i] = value
+		}
+		if len(matrix) > 0 && len(values) != len(matrix[0]) {
+			return nil, &matrixError{row: row, msg: fmt.Sprintf("matrix row has %d values, expected %d", len(values), len(matrix[0]))}
+		}
+		matrix = append(matrix, values)
+	}
+	return matrix, nil
+}
+
+// yamlMatrices are named matrices, such as the burndowns of files, in document order
+type yamlMatrices []namedMatrix
+
+type namedMatrix struct {
+	Name   string
+	Matrix [][]int
+}
+
+func (m *yamlMatrices) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return nodeError(node, "expected a mapping of names to matrices")
+	}
+	matrices := make(yamlMatrices, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var matrix yamlMatrix
+		if err := node.Content[i+1].Decode(&matrix); err != nil {
+			return err
+		}
+		matrices = append(matrices, namedMatrix{Name: node.Content[i].Value, Matrix: matrix})
+	}
+	*m = matrices
+	return nil
+}
+
+// yamlCooccMatrix is a square co-occurrence matrix. hercules writes one {column: value}
+// mapping per row; the original Go format used a dense text matrix.
+type yamlCooccMatrix [][]int
+
+func (m *yamlCooccMatrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode((*yamlMatrix)(m))
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nodeError(node, "expected a sequence of {column: value} rows")
+	}
+	size := len(node.Content)
+	matrix := make([][]int, size)
+	for i, row := range node.Content {
+		if row.Kind != yaml.MappingNode {
+			return nodeError(row, "expected a {column: value} row")
+		}
+		matrix[i] = make([]int, size)
+		for j := 0; j+1 < len(row.Content); j += 2 {
+			column, err := scalarInt(row.Content[j])
+			if err != nil {
+				return err
+			}
+			if column < 0 || column >= size {
+				return nodeError(row.Content[j], "column %d is out of range for a %dx%d matrix", column, size, size)
+			}
+			if matrix[i][column], err = scalarInt(row.Content[j+1]); err != nil {
+				return err
+			}
+		}
+	}
+	*m = matrix
+	return nil
+}
+
+// yamlDevTick is the activity of a developer in a tick:
+// [commits, added, removed, changed, {language: [added, removed, changed]}]
+type yamlDevTick DevDay
+
+func (t *yamlDevTick) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode || len(node.Content) < 4 || len(node.Content) > 5 {
+		return nodeError(node, "expected [commits, added, removed, changed, {languages}]")
+	}
+	var stats [4]int
+	for i := range stats {
+		value, err := scalarInt(node.Content[i])
+		if err != nil {
+			return err
+		}
+		stats[i] = value
+	}
+	tick := yamlDevTick{Commits: stats[0], LinesAdded: stats[1], LinesRemoved: stats[2], LinesModified: stats[3]}
+	if len(node.Content) == 5 {
+		languages := node.Content[4]
+		if languages.Kind != yaml.MappingNode {
+			return nodeError(languages, "expected a mapping of languages to [added, removed, changed]")
+		}
+		tick.Languages = make(map[string][]int, len(languages.Content)/2)
+		for i := 0; i+1 < len(languages.Content); i += 2 {
+			counts := languages.Content[i+1]
+			if counts.Kind != yaml.SequenceNode || len(counts.Content) != 3 {
+				return nodeError(counts, "expected [added, removed, changed] for language %s", languages.Content[i].Value)
+			}
+			values := make([]int, 3)
+			for j, count := range counts.Content {
+				value, err := scalarInt(count)
+				if err != nil {
+					return err
+				}
+				values[j] = value
+			}
+			tick.Languages[languages.Content[i].Value] = values
+		}
+	}
+	*t = tick
+	return nil
+}
+
+// yamlSentimentTick is the comment sentiment of a tick: [value, [commits], "comment|comment"]
+type yamlSentimentTick struct {
+	Value    float64
+	Commits  []string
+	Comments []string
+}
+
+func (t *yamlSentimentTick) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode || len(node.Content) != 3 {
+		return nodeError(node, "expected [value, [commits], comments]")
+	}
+	var tick yamlSentimentTick
+	if err := node.Content[0].Decode(&tick.Value); err != nil {
+		return nodeError(node.Content[0], "expected a sentiment value, got %q", node.Content[0].Value)
+	}
+	if err := node.Content[1].Decode(&tick.Commits); err != nil {
+		return err
+	}
+	if comments := node.Content[2]; comments.Kind == yaml.ScalarNode {
+		if comments.Value != "" {
+			tick.Comments = strings.Split(comments.Value, "|")
+		}
+	} else if err := comments.Decode(&tick.Comments); err != nil {
+		return err
+	}
+	*t = tick
+	return nil
+}
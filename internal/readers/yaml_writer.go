@@ -0,0 +1,314 @@
+package readers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"labours-go/internal/pb"
+)
+
+// WriteYAML writes the analysis results of a reader in the YAML format of hercules, so
+// that the Python labours and older pipelines can read them
+func WriteYAML(w io.Writer, reader Reader) error {
+	results, err := collectResults(reader)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(w)
+	writeYAMLHeader(out, results.header)
+	if results.burndown != nil {
+		writeYAMLBurndown(out, results.burndown)
+	}
+	if results.couples != nil {
+		writeYAMLCouples(out, results.couples)
+	}
+	if results.devs != nil {
+		writeYAMLDevs(out, results.devs)
+	}
+	if results.shotness != nil {
+		writeYAMLShotness(out, results.shotness)
+	}
+	if results.sentiment != nil {
+		writeYAMLSentiment(out, results.sentiment)
+	}
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("error writing YAML: %v", err)
+	}
+	return nil
+}
+
+func writeYAMLHeader(w *bufio.Writer, header *pb.Metadata) {
+	fmt.Fprintln(w, "hercules:")
+	fmt.Fprintf(w, "  version: %d\n", header.Version)
+	fmt.Fprintf(w, "  hash: %s\n", yamlScalar(header.Hash))
+	fmt.Fprintf(w, "  repository: %s\n", yamlScalar(header.Repository))
+	fmt.Fprintf(w, "  begin_unix_time: %d\n", header.BeginUnixTime)
+	fmt.Fprintf(w, "  end_unix_time: %d\n", header.EndUnixTime)
+	fmt.Fprintf(w, "  commits: %d\n", header.Commits)
+	fmt.Fprintf(w, "  run_time: %d\n", header.RunTime)
+	if len(header.RunTimePerItem) > 0 {
+		items := make([]string, 0, len(header.RunTimePerItem))
+		for item := range header.RunTimePerItem {
+			items = append(items, item)
+		}
+		sort.Strings(items)
+		fmt.Fprintln(w, "  run_time_per_item:")
+		for _, item := range items {
+			fmt.Fprintf(w, "    %s: %s\n", yamlScalar(item), strconv.FormatFloat(header.RunTimePerItem[item], 'g', -1, 64))
+		}
+	}
+}
+
+func writeYAMLBurndown(w *bufio.Writer, burndownData *pb.BurndownAnalysisResults) {
+	fmt.Fprintln(w, "Burndown:")
+	fmt.Fprintf(w, "  granularity: %d\n", burndownData.Granularity)
+	fmt.Fprintf(w, "  sampling: %d\n", burndownData.Sampling)
+	fmt.Fprintf(w, "  tick_size: %s\n", yamlSeconds(burndownData.TickSize))
+	if burndownData.Project != nil {
+		writeYAMLMatrix(w, 2, strconv.Quote(burndownData.Project.Name), parseBurndownSparseMatrix(burndownData.Project))
+	}
+	if len(burndownData.Files) > 0 {
+		fmt.Fprintln(w, "  files:")
+		for _, file := range burndownData.Files {
+			writeYAMLMatrix(w, 4, strconv.Quote(file.Name), parseBurndownSparseMatrix(file))
+		}
+	}
+	if len(burndownData.FilesOwnership) > 0 {
+		fmt.Fprintln(w, "  files_ownership:")
+		for _, ownership := range burndownData.FilesOwnership {
+			if len(ownership.Value) == 0 {
+				fmt.Fprintln(w, "    - {}")
+				continue
+			}
+			people := make([]int, 0, len(ownership.Value))
+			for person := range ownership.Value {
+				people = append(people, int(person))
+			}
+			sort.Ints(people)
+			for i, person := range people {
+				prefix := "      "
+				if i == 0 {
+					prefix = "    - "
+				}
+				fmt.Fprintf(w, "%s%d: %d\n", prefix, person, ownership.Value[int32(person)])
+			}
+		}
+	}
+	if len(burndownData.People) > 0 {
+		fmt.Fprintln(w, "  people_sequence:")
+		for _, person := range burndownData.People {
+			fmt.Fprintf(w, "    - %s\n", strconv.Quote(person.Name))
+		}
+		fmt.Fprintln(w, "  people:")
+		for _, person := range burndownData.People {
+			writeYAMLMatrix(w, 4, strconv.Quote(person.Name), parseBurndownSparseMatrix(person))
+		}
+	}
+	if burndownData.PeopleInteraction != nil {
+		writeYAMLMatrix(w, 2, "people_interaction", parseCompressedSparseRowMatrix(burndownData.PeopleInteraction))
+	}
+}
+
+func writeYAMLCouples(w *bufio.Writer, couplesData *pb.CouplesAnalysisResults) {
+	fmt.Fprintln(w, "Couples:")
+	var files []string
+	if couples := couplesData.FileCouples; couples != nil {
+		files = couples.Index
+		fmt.Fprintln(w, "  files_coocc:")
+		writeYAMLIndex(w, couples.Index)
+		if len(couplesData.FilesLines) > 0 {
+			fmt.Fprintln(w, "    lines:")
+			for _, lines := range couplesData.FilesLines {
+				fmt.Fprintf(w, "      - %d\n", lines)
+			}
+		}
+		writeYAMLSparseRows(w, couples.Matrix)
+	}
+	if couples := couplesData.PeopleCouples; couples != nil {
+		fmt.Fprintln(w, "  people_coocc:")
+		writeYAMLIndex(w, couples.Index)
+		writeYAMLSparseRows(w, couples.Matrix)
+		if len(couplesData.PeopleFiles) > 0 {
+			fmt.Fprintln(w, "    author_files:")
+			for person, touched := range couplesData.PeopleFiles {
+				name := fmt.Sprint(person)
+				if person < len(couples.Index) {
+					name = couples.Index[person]
+				}
+				if len(touched.Files) == 0 {
+					fmt.Fprintf(w, "      - %s: []\n", strconv.Quote(name))
+					continue
+				}
+				fmt.Fprintf(w, "      - %s:\n", strconv.Quote(name))
+				for _, file := range touched.Files {
+					if int(file) < len(files) {
+						fmt.Fprintf(w, "        - %s\n", strconv.Quote(files[file]))
+					}
+				}
+			}
+		}
+	}
+}
+
+func writeYAMLIndex(w *bufio.Writer, index []string) {
+	if len(index) == 0 {
+		fmt.Fprintln(w, "    index: []")
+		return
+	}
+	fmt.Fprintln(w, "    index:")
+	for _, name := range index {
+		fmt.Fprintf(w, "      - %s\n", strconv.Quote(name))
+	}
+}
+
+// writeYAMLSparseRows writes a co-occurrence matrix as one {column: value} mapping per row
+func writeYAMLSparseRows(w *bufio.Writer, matrix *pb.CompressedSparseRowMatrix) {
+	if matrix == nil || matrix.NumberOfRows == 0 {
+		fmt.Fprintln(w, "    matrix: []")
+		return
+	}
+	fmt.Fprintln(w, "    matrix:")
+	for y := 0; y < int(matrix.NumberOfRows); y++ {
+		var values []string
+		if y+1 < len(matrix.Indptr) {
+			for i := matrix.Indptr[y]; i < matrix.Indptr[y+1] && int(i) < len(matrix.Data); i++ {
+				values = append(values, fmt.Sprintf("%d: %d", matrix.Indices[i], matrix.Data[i]))
+			}
+		}
+		fmt.Fprintf(w, "      - {%s}\n", strings.Join(values, ", "))
+	}
+}
+
+func writeYAMLDevs(w *bufio.Writer, devsData *pb.DevsAnalysisResults) {
+	fmt.Fprintln(w, "Devs:")
+	ticks := make(map[int]*pb.TickDevs, len(devsData.Ticks))
+	for tick, tickDevs := range devsData.Ticks {
+		ticks[int(tick)] = tickDevs
+	}
+	fmt.Fprintln(w, "  ticks:")
+	for _, tick := range sortedKeys(ticks) {
+		fmt.Fprintf(w, "    %d:\n", tick)
+		devs := make(map[int]*pb.DevTick, len(ticks[tick].GetDevs()))
+		for dev, devTick := range ticks[tick].GetDevs() {
+			devs[int(dev)] = devTick
+		}
+		for _, dev := range sortedKeys(devs) {
+			devTick := devs[dev]
+			stats := devTick.GetStats()
+			languages := make(map[string]*pb.LineStats, len(devTick.Languages))
+			for language, languageStats := range devTick.Languages {
+				if language == "" {
+					language = noLanguage
+				}
+				languages[language] = languageStats
+			}
+			names := make([]string, 0, len(languages))
+			for language := range languages {
+				names = append(names, language)
+			}
+			sort.Strings(names)
+			values := make([]string, len(names))
+			for i, language := range names {
+				values[i] = fmt.Sprintf("%s: [%d, %d, %d]", yamlScalar(language),
+					languages[language].GetAdded(), languages[language].GetRemoved(), languages[language].GetChanged())
+			}
+			fmt.Fprintf(w, "      %d: [%d, %d, %d, %d, {%s}]\n", dev, devTick.GetCommits(),
+				stats.GetAdded(), stats.GetRemoved(), stats.GetChanged(), strings.Join(values, ", "))
+		}
+	}
+	fmt.Fprintln(w, "  people:")
+	for _, person := range devsData.DevIndex {
+		fmt.Fprintf(w, "  - %s\n", strconv.Quote(person))
+	}
+	fmt.Fprintf(w, "  tick_size: %s\n", yamlSeconds(devsData.TickSize))
+}
+
+func writeYAMLShotness(w *bufio.Writer, shotnessData *pb.ShotnessAnalysisResults) {
+	fmt.Fprintln(w, "Shotness:")
+	for _, record := range shotnessData.Records {
+		counters := make(map[int]int32, len(record.Counters))
+		for tick, count := range record.Counters {
+			counters[int(tick)] = count
+		}
+		values := make([]string, 0, len(counters))
+		for _, tick := range sortedKeys(counters) {
+			values = append(values, fmt.Sprintf("%d: %d", tick, counters[tick]))
+		}
+		fmt.Fprintf(w, "  - name: %s\n", strconv.Quote(record.Name))
+		fmt.Fprintf(w, "    file: %s\n", strconv.Quote(record.File))
+		fmt.Fprintf(w, "    internal_role: %s\n", strconv.Quote(record.Type))
+		fmt.Fprintf(w, "    counters: {%s}\n", strings.Join(values, ", "))
+	}
+}
+
+// writeYAMLSentiment writes the ticks like hercules: [value, [commits], "comment|comment"]
+func writeYAMLSentiment(w *bufio.Writer, sentimentData *pb.CommentSentimentResults) {
+	fmt.Fprintln(w, "Sentiment:")
+	ticks := make(map[int]*pb.Sentiment, len(sentimentData.SentimentByTick))
+	for tick, sentiment := range sentimentData.SentimentByTick {
+		ticks[int(tick)] = sentiment
+	}
+	for _, tick := range sortedKeys(ticks) {
+		sentiment := ticks[tick]
+		fmt.Fprintf(w, "  %d: [%.4f, [%s], %s]\n", tick, sentiment.GetValue(), strings.Join(sentiment.GetCommits(), ","),
+			strconv.Quote(strings.Join(sentiment.GetComments(), "|")))
+	}
+}
+
+// writeYAMLMatrix writes a dense matrix as a literal block like hercules: the values are
+// right-aligned, and every row but the first has an extra leading space so that the
+// first row sets the indentation of the block
+func writeYAMLMatrix(w *bufio.Writer, indent int, key string, matrix [][]int) {
+	if len(matrix) == 0 {
+		fmt.Fprintf(w, "%s%s: \"\"\n", strings.Repeat(" ", indent), key)
+		return
+	}
+	fmt.Fprintf(w, "%s%s: |-\n", strings.Repeat(" ", indent), key)
+	width, columns := 1, 0
+	for _, row := range matrix {
+		columns = max(columns, len(row))
+		for _, value := range row {
+			width = max(width, len(strconv.Itoa(value)))
+		}
+	}
+	prefix := strings.Repeat(" ", indent+2)
+	for y, row := range matrix {
+		if y == 0 {
+			w.WriteString(prefix)
+		} else {
+			w.WriteString(prefix[1:])
+		}
+		for x := 0; x < columns; x++ {
+			value := 0
+			if x < len(row) {
+				value = row[x]
+			}
+			if x == 0 && y == 0 {
+				fmt.Fprintf(w, "%-*d", width, value)
+			} else {
+				fmt.Fprintf(w, " %*d", width, value)
+			}
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// yamlScalar writes a string unquoted if YAML reads it back as the same string
+func yamlScalar(text string) string {
+	var value any
+	if text != "" && !strings.ContainsAny(text, "\"'#:{}[],&*!|>%@`\n\t\\") &&
+		yaml.Unmarshal([]byte(text), &value) == nil && value == text {
+		return text
+	}
+	return strconv.Quote(text)
+}
+
+// yamlSeconds formats a duration in nanoseconds as seconds
+func yamlSeconds(nanoseconds int64) string {
+	return strconv.FormatFloat(float64(nanoseconds)/1e9, 'f', -1, 64)
+}
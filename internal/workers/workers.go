@@ -0,0 +1,72 @@
+// Package workers runs independent jobs, such as modes or per-file charts, on a bounded
+// pool of goroutines while reporting their results in submission order.
+package workers
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Count returns the number of workers for the --jobs value n: all CPUs for n <= 0
+func Count(n int) int {
+	if n <= 0 {
+		return runtime.NumCPU()
+	}
+	return n
+}
+
+// Run calls job for the indexes 0 to n-1 with at most jobs calls running at once and
+// returns their errors by index. Once ctx is cancelled no further jobs are started;
+// their error is the context's error. done, if not nil, is called on the calling
+// goroutine for every index in order, as soon as that job and all jobs before it have
+// returned, so that logs and progress do not depend on the scheduling. With a single
+// worker the jobs run on the calling goroutine.
+func Run(ctx context.Context, n, jobs int, job func(ctx context.Context, i int) error, done func(i int, err error)) []error {
+	errs := make([]error, n)
+	if done == nil {
+		done = func(int, error) {}
+	}
+	jobs = min(Count(jobs), n)
+	if jobs <= 1 {
+		for i := 0; i < n; i++ {
+			if errs[i] = ctx.Err(); errs[i] == nil {
+				errs[i] = job(ctx, i)
+			}
+			done(i, errs[i])
+		}
+		return errs
+	}
+
+	indexes := make(chan int)
+	finished := make([]chan struct{}, n)
+	for i := range finished {
+		finished[i] = make(chan struct{})
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if errs[i] = ctx.Err(); errs[i] == nil {
+					errs[i] = job(ctx, i)
+				}
+				close(finished[i])
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			indexes <- i
+		}
+		close(indexes)
+	}()
+
+	for i := 0; i < n; i++ {
+		<-finished[i]
+		done(i, errs[i])
+	}
+	wg.Wait()
+	return errs
+}
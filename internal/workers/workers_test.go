@@ -0,0 +1,74 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunOrder(t *testing.T) {
+	const n = 20
+	var running, peak atomic.Int32
+	var order []int
+	errs := Run(context.Background(), n, 4, func(_ context.Context, i int) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		// Later jobs finish first
+		time.Sleep(time.Duration(n-i) * time.Millisecond)
+		if i%5 == 0 {
+			return errors.New("failed")
+		}
+		return nil
+	}, func(i int, err error) {
+		order = append(order, i)
+	})
+
+	if peak.Load() > 4 {
+		t.Errorf("%d jobs ran at once, want at most 4", peak.Load())
+	}
+	for i := range order {
+		if order[i] != i {
+			t.Fatalf("done called out of order: %v", order)
+		}
+	}
+	if len(order) != n {
+		t.Errorf("done called %d times, want %d", len(order), n)
+	}
+	for i, err := range errs {
+		if (err != nil) != (i%5 == 0) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	for _, jobs := range []int{1, 3} {
+		ctx, cancel := context.WithCancel(context.Background())
+		var started atomic.Int32
+		errs := Run(ctx, 10, jobs, func(ctx context.Context, i int) error {
+			started.Add(1)
+			if i == 0 {
+				cancel()
+			}
+			// The other running jobs wait for the cancellation, so no job can start late
+			<-ctx.Done()
+			return nil
+		}, nil)
+		cancel()
+
+		if started.Load() > int32(jobs) {
+			t.Errorf("jobs=%d: %d jobs started after the cancellation", jobs, started.Load())
+		}
+		if !errors.Is(errs[9], context.Canceled) {
+			t.Errorf("jobs=%d: expected the last job to be cancelled, got %v", jobs, errs[9])
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
-	"log"
+	"fmt"
+	"os"
 
 	"labours-go/cmd"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		log.Fatalf("Error: %v", err)
+		// Not through log or slog: with the default WARN level they would drop the error
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestMain runs the command line instead of the tests when LABOURS_RUN_MAIN is set, so
+// that the tests can check what a failing command prints and how it exits
+func TestMain(m *testing.M) {
+	if os.Getenv("LABOURS_RUN_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func runLabours(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), "LABOURS_RUN_MAIN=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stderr.String(), err
+}
+
+func TestFailingSubcommandPrintsError(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"theme check", []string{"theme", "check", "--bands", "1"}, "Error: "},
+		{"info", []string{"info", "-i", "/nonexistent/input.pb"}, "/nonexistent/input.pb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr, err := runLabours(t, tt.args...)
+			exitErr, ok := err.(*exec.ExitError)
+			if !ok || exitErr.ExitCode() != 1 {
+				t.Fatalf("expected exit code 1, got %v", err)
+			}
+			if !strings.Contains(stderr, tt.want) {
+				t.Errorf("expected %q on stderr, got %q", tt.want, stderr)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package visual
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -95,7 +96,7 @@ func (cg *ChartGenerator) generateBurndownProject(reader readers.Reader, outputP
 	viper.Set("resample", "year") // Default resampling for consistency
 	
 	// Call the actual burndown project generation using Python-compatible version
-	return modes.GenerateBurndownProjectPython(reader, outputPath, relative, "year")
+	return modes.GenerateBurndownProjectPython(context.Background(), reader, outputPath, relative, "year")
 }
 
 // generateBurndownFile creates file-level burndown charts
@@ -103,37 +104,37 @@ func (cg *ChartGenerator) generateBurndownFile(reader readers.Reader, outputPath
 	// Use Python-compatible file burndown generation
 	viper.Set("relative", false) // Default to absolute
 	viper.Set("resample", "year")
-	return modes.GenerateBurndownFilePython(reader, outputPath, false, "year")
+	return modes.GenerateBurndownFilePython(context.Background(), reader, outputPath, false, "year")
 }
 
 // generateBurndownPerson creates person-level burndown charts
 func (cg *ChartGenerator) generateBurndownPerson(reader readers.Reader, outputPath string) error {
 	// Use regular burndown person function with nil time parameters for defaults
-	return modes.BurndownPerson(reader, outputPath, false, nil, nil, "year")
+	return modes.BurndownPerson(context.Background(), reader, outputPath, false, nil, nil, "year")
 }
 
 // generateOwnership creates code ownership visualization
 func (cg *ChartGenerator) generateOwnership(reader readers.Reader, outputPath string) error {
 	// Call the ownership mode
-	return modes.OwnershipBurndown(reader, outputPath)
+	return modes.OwnershipBurndown(context.Background(), reader, outputPath)
 }
 
 // generateDevs creates developer statistics visualization
 func (cg *ChartGenerator) generateDevs(reader readers.Reader, outputPath string) error {
 	// Call the devs mode with default max people (20)
-	return modes.Devs(reader, outputPath, 20)
+	return modes.Devs(context.Background(), reader, outputPath, 20)
 }
 
 // generateCouplesPeople creates people coupling visualization
 func (cg *ChartGenerator) generateCouplesPeople(reader readers.Reader, outputPath string) error {
 	// Call the couples-people mode
-	return modes.CouplesPeople(reader, outputPath)
+	return modes.CouplesPeople(context.Background(), reader, outputPath)
 }
 
 // generateCouplesFiles creates file coupling visualization
 func (cg *ChartGenerator) generateCouplesFiles(reader readers.Reader, outputPath string) error {
 	// Call the couples-files mode
-	return modes.CouplesFiles(reader, outputPath)
+	return modes.CouplesFiles(context.Background(), reader, outputPath)
 }
 
 // GenerateReferenceSet creates a complete set of reference images for golden file testing